@@ -0,0 +1,45 @@
+package opcua
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// backoff computes the delays between reconnection attempts. The delay
+// starts at initial and is multiplied by factor after every attempt up
+// to max. jitter is the fraction of the delay by which it is randomly
+// reduced so that clients which lost the connection at the same time
+// do not reconnect at the same time.
+type backoff struct {
+	initial time.Duration
+	max     time.Duration
+	factor  float64
+	jitter  float64
+
+	// cur is the delay before jitter for the next attempt.
+	// Zero means initial.
+	cur time.Duration
+}
+
+// fixedBackoff returns a backoff with a constant delay of d.
+func fixedBackoff(d time.Duration) *backoff {
+	return &backoff{initial: d, max: d, factor: 1}
+}
+
+// next returns the delay before the next attempt.
+func (b *backoff) next() time.Duration {
+	if b.cur == 0 {
+		b.cur = b.initial
+	}
+	d := b.cur
+	b.cur = min(time.Duration(float64(b.cur)*b.factor), b.max)
+	if b.jitter > 0 {
+		d -= time.Duration(b.jitter * rand.Float64() * float64(d))
+	}
+	return d
+}
+
+// reset restarts the backoff at the initial delay.
+func (b *backoff) reset() {
+	b.cur = 0
+}
@@ -0,0 +1,71 @@
+package opcua
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Run("fixed", func(t *testing.T) {
+		b := fixedBackoff(time.Second)
+		for range 3 {
+			require.Equal(t, time.Second, b.next())
+		}
+	})
+
+	t.Run("exponential", func(t *testing.T) {
+		b := &backoff{initial: time.Second, max: 5 * time.Second, factor: 2}
+		var got []time.Duration
+		for range 5 {
+			got = append(got, b.next())
+		}
+		want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+		require.Equal(t, want, got)
+
+		b.reset()
+		require.Equal(t, time.Second, b.next())
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		b := &backoff{initial: time.Second, max: 8 * time.Second, factor: 2, jitter: 0.5}
+		for _, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second} {
+			d := b.next()
+			require.LessOrEqual(t, d, want)
+			require.GreaterOrEqual(t, d, want/2)
+		}
+	})
+}
+
+func TestClient_ReconnectBackoff(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840", ReconnectBackoff(time.Second, time.Minute, 2, 0))
+	require.NoError(t, err)
+
+	b := c.reconnectBackoff()
+	require.Equal(t, time.Second, b.next())
+	require.Equal(t, 2*time.Second, b.next())
+
+	// every reconnect starts with the initial interval
+	require.Equal(t, time.Second, c.reconnectBackoff().next())
+
+	c, err = NewClient("opc.tcp://example.com:4840", ReconnectBackoff(time.Second, time.Minute, 2, 0), ReconnectInterval(3*time.Second))
+	require.NoError(t, err)
+	b = c.reconnectBackoff()
+	require.Equal(t, 3*time.Second, b.next())
+	require.Equal(t, 3*time.Second, b.next())
+}
+
+func TestClient_NextReconnectDelay(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840", ReconnectBackoff(time.Second, 4*time.Second, 2, 0))
+	require.NoError(t, err)
+
+	// the delay grows across reconnect sequences until it is reset
+	require.Equal(t, time.Second, c.nextReconnectDelay())
+	require.Equal(t, 2*time.Second, c.nextReconnectDelay())
+	require.Equal(t, 4*time.Second, c.nextReconnectDelay())
+	require.Equal(t, 4*time.Second, c.nextReconnectDelay())
+
+	c.resetReconnectBackoff()
+	require.Equal(t, time.Second, c.nextReconnectDelay())
+}
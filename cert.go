@@ -0,0 +1,99 @@
+package opcua
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gopcua/opcua/errors"
+)
+
+const (
+	// DefaultCertKeyBits is the size of the RSA key generated by
+	// GenerateCert if no key size is given.
+	DefaultCertKeyBits = 2048
+
+	// DefaultCertValidity is the validity of the certificate generated
+	// by GenerateCert if no validity is given.
+	DefaultCertValidity = 365 * 24 * time.Hour
+)
+
+// GenerateCert creates a self-signed application instance certificate
+// and the matching RSA private key for a client with the given
+// application URI. The certificate is DER encoded.
+//
+// The application URI is stored as URI in the subject alternative name
+// of the certificate as required by OPC UA. The host name of the machine
+// is added as DNS name if it can be determined. If keyBits or validFor
+// are zero then DefaultCertKeyBits and DefaultCertValidity are used.
+func GenerateCert(appURI string, keyBits int, validFor time.Duration) (certDER []byte, key *rsa.PrivateKey, err error) {
+	uri, err := url.Parse(appURI)
+	if err != nil || uri.Scheme == "" {
+		return nil, nil, errors.Errorf("invalid application uri %q", appURI)
+	}
+	if keyBits == 0 {
+		keyBits = DefaultCertKeyBits
+	}
+	if validFor == 0 {
+		validFor = DefaultCertValidity
+	}
+
+	key, err = rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, nil, errors.Errorf("failed to generate private key: %s", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, errors.Errorf("failed to generate serial number: %s", err)
+	}
+
+	notBefore := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName:   "gopcua client",
+			Organization: []string{"gopcua"},
+		},
+		NotBefore: notBefore,
+		NotAfter:  notBefore.Add(validFor),
+
+		KeyUsage: x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment |
+			x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		URIs:                  []*url.URL{uri},
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		template.DNSNames = []string{host}
+	}
+
+	certDER, err = x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, errors.Errorf("failed to create certificate: %s", err)
+	}
+	return certDER, key, nil
+}
+
+// WithEphemeralCert generates a self-signed client certificate and
+// private key with GenerateCert and sets them in the secure channel
+// configuration. The application URI of the session is taken from the
+// certificate and is therefore appURI. An ApplicationURI option with a
+// different uri is rejected with ErrApplicationURIMismatch regardless
+// of the order of the options.
+func WithEphemeralCert(appURI string, keyBits int, validFor time.Duration) Option {
+	return func(cfg *Config) error {
+		cert, key, err := GenerateCert(appURI, keyBits, validFor)
+		if err != nil {
+			return err
+		}
+		cfg.sechan.LocalKey = key
+		cfg.ephemeralURI = appURI
+		return setCertificate(cert, cfg)
+	}
+}
@@ -0,0 +1,97 @@
+package opcua
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua/uapolicy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCert(t *testing.T) {
+	der, key, err := GenerateCert("urn:gopcua:client", 2048, time.Hour)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	require.Len(t, cert.URIs, 1)
+	require.Equal(t, "urn:gopcua:client", cert.URIs[0].String())
+	require.True(t, key.PublicKey.Equal(cert.PublicKey))
+	require.NotZero(t, cert.KeyUsage&x509.KeyUsageDigitalSignature)
+	require.NotZero(t, cert.KeyUsage&x509.KeyUsageKeyEncipherment)
+	require.NotZero(t, cert.KeyUsage&x509.KeyUsageDataEncipherment)
+	require.Contains(t, cert.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+	require.WithinDuration(t, time.Now().Add(time.Hour), cert.NotAfter, time.Minute)
+
+	_, _, err = GenerateCert("no uri", 0, 0)
+	require.Error(t, err)
+}
+
+func TestWithEphemeralCert(t *testing.T) {
+	cfg, err := ApplyConfig(WithEphemeralCert("urn:gopcua:client", 0, 0))
+	require.NoError(t, err)
+	require.NotNil(t, cfg.sechan.LocalKey)
+	require.NotEmpty(t, cfg.sechan.Certificate)
+	require.Equal(t, "urn:gopcua:client", cfg.session.ClientDescription.ApplicationURI)
+
+	// a matching uri is accepted regardless of the order
+	cfg, err = ApplyConfig(ApplicationURI("urn:gopcua:client"), WithEphemeralCert("urn:gopcua:client", 0, 0))
+	require.NoError(t, err)
+	require.Equal(t, "urn:gopcua:client", cfg.session.ClientDescription.ApplicationURI)
+
+	// a conflicting uri is rejected regardless of the order
+	_, err = ApplyConfig(ApplicationURI("urn:other"), WithEphemeralCert("urn:gopcua:client", 0, 0))
+	require.ErrorIs(t, err, ErrApplicationURIMismatch)
+	_, err = ApplyConfig(WithEphemeralCert("urn:gopcua:client", 0, 0), ApplicationURI("urn:other"))
+	require.ErrorIs(t, err, ErrApplicationURIMismatch)
+}
+
+func TestApplicationURIPrecedence(t *testing.T) {
+	cert, _, err := GenerateCert("urn:gopcua:cert", 0, 0)
+	require.NoError(t, err)
+
+	cfg, err := ApplyConfig(Certificate(cert))
+	require.NoError(t, err)
+	require.Equal(t, "urn:gopcua:cert", cfg.session.ClientDescription.ApplicationURI)
+
+	// an explicit uri wins regardless of the order
+	cfg, err = ApplyConfig(ApplicationURI("urn:other"), Certificate(cert))
+	require.NoError(t, err)
+	require.Equal(t, "urn:other", cfg.session.ClientDescription.ApplicationURI)
+
+	cfg, err = ApplyConfig(Certificate(cert), ApplicationURI("urn:other"))
+	require.NoError(t, err)
+	require.Equal(t, "urn:other", cfg.session.ClientDescription.ApplicationURI)
+}
+
+func TestCertificateChain(t *testing.T) {
+	leaf, key, err := GenerateCert("urn:gopcua:leaf", 0, 0)
+	require.NoError(t, err)
+	ca, _, err := GenerateCert("urn:gopcua:ca", 0, 0)
+	require.NoError(t, err)
+	chain := append(append([]byte{}, leaf...), ca...)
+
+	cfg, err := ApplyConfig(CertificateChain([][]byte{leaf, ca}), PrivateKey(key))
+	require.NoError(t, err)
+	require.Equal(t, chain, cfg.sechan.Certificate)
+	require.Equal(t, "urn:gopcua:leaf", cfg.session.ClientDescription.ApplicationURI)
+
+	pemChain := append(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca})...,
+	)
+	cfg, err = ApplyConfig(CertificatePEM(pemChain))
+	require.NoError(t, err)
+	require.Equal(t, chain, cfg.sechan.Certificate)
+
+	// thumbprint and public key are taken from the leaf certificate
+	require.Equal(t, uapolicy.Thumbprint(leaf), uapolicy.Thumbprint(chain))
+	pub, err := uapolicy.PublicKey(chain)
+	require.NoError(t, err)
+	require.True(t, key.PublicKey.Equal(pub))
+
+	_, err = ApplyConfig(CertificateChain([][]byte{leaf, []byte("x")}))
+	require.Error(t, err)
+}
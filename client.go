@@ -7,11 +7,16 @@ package opcua
 import (
 	"context"
 	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
 	"expvar"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"reflect"
+	"slices"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -28,6 +33,9 @@ import (
 )
 
 // FindServers returns the servers known to a server or discovery server.
+// The application descriptions contain the application type and the
+// discovery URLs of the servers which can be passed to GetEndpoints.
+// The connection to the discovery endpoint is closed before returning.
 func FindServers(ctx context.Context, endpoint string, opts ...Option) ([]*ua.ApplicationDescription, error) {
 	opts = append(opts, AutoReconnect(false))
 	c, err := NewClient(endpoint, opts...)
@@ -45,7 +53,10 @@ func FindServers(ctx context.Context, endpoint string, opts ...Option) ([]*ua.Ap
 	return res.Servers, nil
 }
 
-// FindServersOnNetwork returns the servers known to a server or discovery server. Unlike FindServers, this service is only implemented by discovery servers.
+// FindServersOnNetwork returns the servers known to a server or discovery server.
+// Unlike FindServers, this service is only implemented by discovery servers
+// with multicast support. Other servers return StatusBadServiceUnsupported.
+// The records contain the discovery URL and the capabilities of the servers.
 func FindServersOnNetwork(ctx context.Context, endpoint string, opts ...Option) ([]*ua.ServerOnNetwork, error) {
 	opts = append(opts, AutoReconnect(false))
 	c, err := NewClient(endpoint, opts...)
@@ -141,12 +152,21 @@ type Client struct {
 	// atomicSession is the active atomicSession.
 	atomicSession atomic.Value // *Session
 
-	// subMux guards subs and pendingAcks.
+	// atomicUser is the user identity of new sessions. It is replaced by
+	// ChangeUser and nil until then.
+	atomicUser atomic.Value // *sessionUser
+
+	// subMux guards subs, attaching and pendingAcks.
 	subMux sync.RWMutex
 
 	// subs is the set of active subscriptions by id.
 	subs map[uint32]*Subscription
 
+	// attaching contains the publish responses of the subscriptions
+	// which AttachSubscriptions is transferring by id. They are
+	// delivered when the subscriptions have been registered.
+	attaching map[uint32][]*ua.PublishResponse
+
 	// pendingAcks contains the pending subscription acknowledgements
 	// for all active subscriptions.
 	pendingAcks []*ua.SubscriptionAcknowledgement
@@ -172,11 +192,55 @@ type Client struct {
 	// stateFunc is an optional func for connection state changes. May be nil.
 	stateFunc func(ConnState)
 
+	// stateHandler is an optional func for connection state transitions. May be nil.
+	stateHandler func(oldState, newState ConnState)
+
+	// stateMu serializes connection state changes and guards stateWaiters,
+	// stateEvents and stateDispatching.
+	stateMu sync.Mutex
+
+	// stateWaiters contains the channels of the WaitForState calls
+	// which are closed when the client reaches the awaited state.
+	stateWaiters map[ConnState][]chan struct{}
+
+	// stateEvents contains the state changes which have not been
+	// delivered to stateCh, stateFunc and stateHandler yet.
+	stateEvents []stateEvent
+
+	// stateDispatching is set while a goroutine delivers stateEvents.
+	stateDispatching bool
+
 	// list of cached atomicNamespaces on the server
 	atomicNamespaces atomic.Value // []string
 
+	// cached operation limits of the server. May be nil.
+	atomicOperationLimits atomic.Value // *OperationLimits
+
 	// monitorOnce ensures only one connection monitor is running
 	monitorOnce sync.Once
+
+	// reconnectGaveUp is set when the monitor stopped reconnecting
+	// after MaxReconnectAttempts failed attempts.
+	reconnectGaveUp atomic.Bool
+
+	// backoff spaces the attempts to recreate the secure channel. It is
+	// reset after a successful reconnect or Ping. May be nil.
+	backoff   *backoff
+	backoffMu sync.Mutex
+
+	// attrCache caches the values of ReadCachedAttributes. May be nil.
+	attrCache *attributeCache
+
+	// enums caches the labels of the enumerations read by ReadEnum
+	// by the node id of the data type.
+	enums   map[string]map[int64]*ua.LocalizedText
+	enumsMu sync.Mutex
+
+	// logger receives the structured log records of the client.
+	logger *slog.Logger
+
+	// tracer creates the spans of the service requests. May be nil.
+	tracer RequestTracer
 }
 
 // NewClient creates a new Client.
@@ -195,16 +259,26 @@ func NewClient(endpoint string, opts ...Option) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	logger := cfg.sechan.Logger
+	if logger == nil {
+		logger = debug.NewLogger()
+	}
 	c := Client{
-		endpointURL: endpoint,
-		cfg:         cfg,
-		sechanErr:   make(chan error, 1),
-		subs:        make(map[uint32]*Subscription),
-		pendingAcks: make([]*ua.SubscriptionAcknowledgement, 0),
-		pausech:     make(chan struct{}, 2),
-		resumech:    make(chan struct{}, 2),
-		stateCh:     cfg.stateCh,
-		stateFunc:   cfg.stateFunc,
+		endpointURL:  endpoint,
+		logger:       logger.With("endpoint", endpoint),
+		tracer:       cfg.tracer,
+		cfg:          cfg,
+		sechanErr:    make(chan error, 1),
+		subs:         make(map[uint32]*Subscription),
+		pendingAcks:  make([]*ua.SubscriptionAcknowledgement, 0),
+		pausech:      make(chan struct{}, 2),
+		resumech:     make(chan struct{}, 2),
+		stateCh:      cfg.stateCh,
+		stateFunc:    cfg.stateFunc,
+		stateHandler: cfg.stateHandler,
+	}
+	if len(cfg.attrCache) > 0 {
+		c.attrCache = newAttributeCache(cfg.attrCache, cfg.attrCacheTTL)
 	}
 	c.pauseSubscriptions(context.Background())
 	c.setPublishTimeout(uasc.MaxTimeout)
@@ -213,9 +287,23 @@ func NewClient(endpoint string, opts ...Option) (*Client, error) {
 	c.setSecureChannel(nil)
 	c.setSession(nil)
 	c.setNamespaces([]string{})
+	c.setOperationLimits(nil)
 	return &c, nil
 }
 
+// ErrReconnectGaveUp is returned by operations of a client which stopped
+// reconnecting after MaxReconnectAttempts failed attempts.
+var ErrReconnectGaveUp = errors.New("reconnect gave up")
+
+// notConnectedError returns the error for operations which require a
+// secure channel while the client has none.
+func (c *Client) notConnectedError() error {
+	if c.reconnectGaveUp.Load() {
+		return ErrReconnectGaveUp
+	}
+	return ua.StatusBadServerNotConnected
+}
+
 // reconnectAction is a list of actions for the client reconnection logic.
 type reconnectAction uint8
 
@@ -239,14 +327,34 @@ func (c *Client) Connect(ctx context.Context) error {
 		return errors.Errorf("already connected")
 	}
 
+	c.reconnectGaveUp.Store(false)
+	c.resetReconnectBackoff()
 	c.setState(ctx, Connecting)
 	if err := c.Dial(ctx); err != nil {
 		stats.RecordError(err)
+		c.logger.Warn("connect failed", "error", err)
 
 		return err
 	}
 
-	s, err := c.CreateSession(ctx, c.cfg.session)
+	// the requests are sent without a session and without the
+	// SessionlessInvoke envelope. See Sessionless.
+	if c.cfg.sessionless {
+		err := c.UpdateNamespaces(ctx)
+		switch {
+		case err == nil:
+			c.setState(ctx, Connected)
+			c.logger.Info("connected without session")
+			return nil
+		case !requiresSession(err):
+			c.Close(ctx)
+			stats.RecordError(err)
+			return err
+		}
+		c.logger.Info("server requires a session", "error", err)
+	}
+
+	s, err := c.CreateSession(ctx, c.sessionUser().cfg)
 	if err != nil {
 		c.Close(ctx)
 		stats.RecordError(err)
@@ -261,6 +369,7 @@ func (c *Client) Connect(ctx context.Context) error {
 		return err
 	}
 	c.setState(ctx, Connected)
+	c.logger.Info("connected", "sessionID", s.resp.SessionID)
 
 	mctx, mcancel := context.WithCancel(context.Background())
 	c.mcancel = mcancel
@@ -283,6 +392,60 @@ func (c *Client) Connect(ctx context.Context) error {
 	return nil
 }
 
+// requiresSession returns true if the server rejected a request without
+// a session since it requires one.
+func requiresSession(err error) bool {
+	var status ua.StatusCode
+	if !errors.As(err, &status) {
+		return false
+	}
+	switch status {
+	case ua.StatusBadSessionIDInvalid,
+		ua.StatusBadSessionNotActivated,
+		ua.StatusBadSessionClosed,
+		ua.StatusBadServiceUnsupported,
+		ua.StatusBadUserAccessDenied,
+		ua.StatusBadIdentityTokenInvalid,
+		ua.StatusBadIdentityTokenRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// reconnectBackoff returns a new backoff for a sequence of reconnection
+// attempts. Without a configured backoff the attempts are spaced by
+// the fixed ReconnectInterval.
+func (c *Client) reconnectBackoff() *backoff {
+	if c.cfg.reconnectBackoff == nil {
+		return fixedBackoff(c.cfg.sechan.ReconnectInterval)
+	}
+	b := *c.cfg.reconnectBackoff
+	b.reset()
+	return &b
+}
+
+// nextReconnectDelay returns the delay before the next attempt to
+// recreate the secure channel. The delay grows with every attempt until
+// the backoff is reset, even if the secure channel could be recreated
+// but the session could not.
+func (c *Client) nextReconnectDelay() time.Duration {
+	c.backoffMu.Lock()
+	defer c.backoffMu.Unlock()
+	if c.backoff == nil {
+		c.backoff = c.reconnectBackoff()
+	}
+	return c.backoff.next()
+}
+
+// resetReconnectBackoff restarts the delay between reconnection attempts
+// at the initial delay.
+func (c *Client) resetReconnectBackoff() {
+	c.backoffMu.Lock()
+	defer c.backoffMu.Unlock()
+	c.backoff = nil
+}
+
 // monitor manages connection alteration
 func (c *Client) monitor(ctx context.Context) {
 	dlog := debug.NewPrefixLogger("client: monitor: ")
@@ -291,7 +454,12 @@ func (c *Client) monitor(ctx context.Context) {
 	defer dlog.Printf("done")
 
 	defer c.mcancel()
-	defer c.setState(ctx, Closed)
+	defer func() {
+		// a client which gave up reconnecting stays disconnected
+		if !c.reconnectGaveUp.Load() {
+			c.setState(ctx, Closed)
+		}
+	}()
 
 	action := none
 	for {
@@ -326,6 +494,7 @@ func (c *Client) monitor(ctx context.Context) {
 			}
 
 			dlog.Print("auto-reconnecting")
+			c.logger.Warn("connection lost, reconnecting", "error", err)
 
 			switch {
 			case errors.Is(err, io.EOF):
@@ -364,8 +533,17 @@ func (c *Client) monitor(ctx context.Context) {
 				subsToRecreate  []uint32            // subscription ids which need to be recreated as new subscriptions
 				availableSeqs   map[uint32][]uint32 // available sequence numbers per subscription
 				activeSubs      int                 // number of active subscriptions to resume/recreate
+				sessionAttempts int                 // number of attempts to recreate the session
 			)
 
+			// giveUp stops reconnecting after too many failed attempts.
+			giveUp := func(attempts int, err error) {
+				dlog.Printf("giving up after %d reconnect attempts: %v", attempts, err)
+				c.logger.Error("giving up reconnecting", "attempts", attempts, "error", err)
+				c.reconnectGaveUp.Store(true)
+				c.setState(ctx, Disconnected)
+			}
+
 			for action != none {
 
 				select {
@@ -399,12 +577,16 @@ func (c *Client) monitor(ctx context.Context) {
 						c.setState(ctx, Reconnecting)
 
 						dlog.Printf("trying to recreate secure channel")
-						for {
+						for attempts := 1; ; attempts++ {
 							if err := c.Dial(ctx); err != nil {
+								if max := c.cfg.maxReconnectAttempts; max > 0 && attempts >= max {
+									giveUp(attempts, err)
+									return
+								}
 								select {
 								case <-ctx.Done():
 									return
-								case <-time.After(c.cfg.sechan.ReconnectInterval):
+								case <-time.After(c.nextReconnectDelay()):
 									dlog.Printf("trying to recreate secure channel")
 									continue
 								}
@@ -455,12 +637,36 @@ func (c *Client) monitor(ctx context.Context) {
 						c.setState(ctx, Reconnecting)
 						// create a new session to replace the previous one
 
-						// clear any previous session as we know the server has closed it
-						// this also prevents any unnecessary calls to CloseSession
+						// space the attempts since a session which cannot be
+						// restored is usually caused by a persistent error.
+						sessionAttempts++
+						if sessionAttempts > 1 {
+							if max := c.cfg.maxReconnectAttempts; max > 0 && sessionAttempts > max {
+								giveUp(sessionAttempts-1, errors.Errorf("session could not be restored"))
+								return
+							}
+							select {
+							case <-ctx.Done():
+								return
+							case <-time.After(c.nextReconnectDelay()):
+							}
+						}
+
+						// close the previous session if the server still has it.
+						// Its subscriptions are kept so that they can be
+						// transferred to the new session.
+						if s := c.Session(); s != nil {
+							dlog.Printf("closing abandoned session")
+							if err := c.closeSession(ctx, s, false); err != nil && isConnectionError(err) {
+								dlog.Printf("closing abandoned session failed: %v", err)
+								action = createSecureChannel
+								continue
+							}
+						}
 						c.setSession(nil)
 
 						dlog.Printf("trying to recreate session")
-						s, err := c.CreateSession(ctx, c.cfg.session)
+						s, err := c.CreateSession(ctx, c.sessionUser().cfg)
 						if err != nil {
 							dlog.Printf("recreate session failed: %v", err)
 							action = createSecureChannel
@@ -473,6 +679,10 @@ func (c *Client) monitor(ctx context.Context) {
 						}
 						dlog.Print("session recreated")
 
+						// the server may have been restarted or replaced
+						// with different operation limits.
+						c.setOperationLimits(nil)
+
 						// todo(fs): see comment about guarding this with an option in Connect()
 						dlog.Printf("trying to update namespaces")
 						if err := c.UpdateNamespaces(ctx); err != nil {
@@ -499,7 +709,7 @@ func (c *Client) monitor(ctx context.Context) {
 
 						// try to transfer all subscriptions to the new session and
 						// recreate them all if that fails.
-						res, err := c.transferSubscriptions(ctx, subIDs)
+						res, err := c.TransferSubscriptions(ctx, subIDs, false)
 						switch {
 
 						case errors.Is(err, ua.StatusBadServiceUnsupported):
@@ -518,13 +728,13 @@ func (c *Client) monitor(ctx context.Context) {
 							for i := range res.Results {
 								transferResult := res.Results[i]
 								switch transferResult.StatusCode {
-								case ua.StatusBadSubscriptionIDInvalid:
-									dlog.Printf("sub %d: transfer subscription failed", subIDs[i])
-									subsToRecreate = append(subsToRecreate, subIDs[i])
-
-								default:
+								case ua.StatusOK:
 									subsToRepublish = append(subsToRepublish, subIDs[i])
 									availableSeqs[subIDs[i]] = transferResult.AvailableSequenceNumbers
+
+								default:
+									dlog.Printf("sub %d: transfer subscription failed: %v", subIDs[i], transferResult.StatusCode)
+									subsToRecreate = append(subsToRecreate, subIDs[i])
 								}
 							}
 						}
@@ -544,21 +754,39 @@ func (c *Client) monitor(ctx context.Context) {
 							if err := c.republishSubscription(ctx, subID, availableSeqs[subID]); err != nil {
 								dlog.Printf("republish of subscription %d failed", subID)
 								subsToRecreate = append(subsToRecreate, subID)
+								continue
 							}
 							activeSubs++
 						}
 
+						action = none
 						for _, subID := range subsToRecreate {
-							if err := c.recreateSubscription(ctx, subID); err != nil {
-								dlog.Printf("recreate subscripitions failed: %v", err)
+							err := c.recreateSubscription(ctx, subID)
+							switch {
+							case err == nil:
+								activeSubs++
+								continue
+							case isSessionError(err):
+								dlog.Printf("recreate subscriptions failed: %v", err)
 								action = recreateSession
+							default:
+								// the server rejected the subscription, e.g. since it
+								// has no resources left. Retrying does not help.
+								dlog.Printf("sub %d: recreate subscription failed: %v", subID, err)
+								c.logger.Warn("subscription could not be recreated", "subscriptionID", subID, "error", err)
+								c.notifySubscriptionOfError(ctx, subID, errors.Errorf("recreate subscription %d: %w", subID, err))
+								c.forgetSubscription(ctx, subID)
 								continue
 							}
-							activeSubs++
+							break
+						}
+						if action != none {
+							continue
 						}
 
+						c.resetReconnectBackoff()
 						c.setState(ctx, Connected)
-						action = none
+						c.logger.Info("reconnected")
 
 					case abortReconnect:
 						dlog.Printf("action: abortReconnect")
@@ -568,6 +796,7 @@ func (c *Client) monitor(ctx context.Context) {
 
 						// todo(unknownet): should we store the error?
 						dlog.Printf("reconnection not recoverable")
+						c.logger.Error("reconnect not possible")
 						return
 					}
 				}
@@ -590,6 +819,41 @@ func (c *Client) monitor(ctx context.Context) {
 	}
 }
 
+// isConnectionError returns true if err is caused by a broken connection
+// or secure channel. Errors which are not status codes are transport
+// errors.
+func isConnectionError(err error) bool {
+	var status ua.StatusCode
+	if !errors.As(err, &status) {
+		return true
+	}
+	switch status {
+	case ua.StatusBadSecureChannelIDInvalid,
+		ua.StatusBadSecureChannelClosed,
+		ua.StatusBadConnectionClosed,
+		ua.StatusBadNotConnected,
+		ua.StatusBadServerNotConnected,
+		ua.StatusBadCommunicationError,
+		ua.StatusBadTimeout:
+		return true
+	}
+	return false
+}
+
+// isSessionError returns true if the session has to be recreated after
+// err, i.e. for connection errors and errors of the session itself.
+func isSessionError(err error) bool {
+	switch {
+	case isConnectionError(err):
+		return true
+	case errors.Is(err, ua.StatusBadSessionIDInvalid),
+		errors.Is(err, ua.StatusBadSessionClosed),
+		errors.Is(err, ua.StatusBadSessionNotActivated):
+		return true
+	}
+	return false
+}
+
 // Dial establishes a secure channel.
 func (c *Client) Dial(ctx context.Context) error {
 	stats.Client().Add("Dial", 1)
@@ -598,6 +862,12 @@ func (c *Client) Dial(ctx context.Context) error {
 		return errors.Errorf("secure channel already connected")
 	}
 
+	if !c.cfg.skipApplicationURICheck {
+		if err := checkApplicationURI(c.cfg.sechan.Certificate, c.cfg.session.ClientDescription.ApplicationURI); err != nil {
+			return err
+		}
+	}
+
 	var err error
 	c.conn, err = c.cfg.dialer.Dial(ctx, c.endpointURL)
 	if err != nil {
@@ -620,17 +890,81 @@ func (c *Client) Dial(ctx context.Context) error {
 }
 
 // Close closes the session and the secure channel.
+//
+// Close is a hard close which tears down the connection as fast as
+// possible. The subscriptions are deleted and the session is closed but
+// all errors are ignored, in-flight publish responses are dropped and
+// received notifications are not acknowledged. Use Shutdown to close
+// the client gracefully.
 func (c *Client) Close(ctx context.Context) error {
 	stats.Client().Add("Close", 1)
 
+	// delete all subscriptions with a single request instead of relying
+	// on the server to delete them with the session. Errors are ignored
+	// since the session is closed anyway.
+	if ids := c.SubscriptionIDs(); len(ids) > 0 && c.Session() != nil {
+		c.deleteSubscriptions(ctx, ids)
+	}
+
 	// try to close the session but ignore any error
 	// so that we close the underlying channel and connection.
 	c.CloseSession(ctx)
+	c.close(ctx)
+	return nil
+}
+
+// Shutdown closes the client gracefully.
+//
+// Unlike Close, Shutdown stops the publish loop first and sends the
+// acknowledgements for the notifications which have been received but
+// not yet acknowledged. Only then it deletes the subscriptions, closes
+// the session and finally closes the secure channel and the connection.
+// The acknowledgements are sent with a last publish request which the
+// server receives before the request to delete the subscriptions. Its
+// response is discarded.
+//
+// All requests are bound by ctx. The secure channel and the connection
+// are closed even if ctx is done or a request fails. The errors of
+// acknowledging the notifications, deleting the subscriptions and
+// closing the session are returned.
+func (c *Client) Shutdown(ctx context.Context) error {
+	stats.Client().Add("Shutdown", 1)
+
+	var errs []error
+	if c.Session() != nil {
+		c.pauseSubscriptions(ctx)
+		if err := c.acknowledge(ctx); err != nil {
+			errs = append(errs, errors.Errorf("acknowledge: %w", err))
+		}
+		if ids := c.SubscriptionIDs(); len(ids) > 0 {
+			if err := c.DeleteSubscriptions(ctx, ids...); err != nil {
+				errs = append(errs, errors.Errorf("delete subscriptions: %w", err))
+			}
+		}
+		if err := c.CloseSession(ctx); err != nil {
+			errs = append(errs, errors.Errorf("close session: %w", err))
+		}
+	}
+	c.close(ctx)
+	return errors.Join(errs...)
+}
+
+// close stops the publish loop and the delivery of notifications and
+// closes the secure channel and the connection.
+func (c *Client) close(ctx context.Context) {
 	c.setState(ctx, Closed)
 
 	if c.mcancel != nil {
 		c.mcancel()
 	}
+
+	// stop forwarding notifications
+	c.subMux.RLock()
+	for _, s := range c.subs {
+		s.stopDelivery()
+	}
+	c.subMux.RUnlock()
+
 	if sc := c.SecureChannel(); sc != nil {
 		sc.Close()
 		c.setSecureChannel(nil)
@@ -649,8 +983,6 @@ func (c *Client) Close(ctx context.Context) error {
 	if c.conn != nil {
 		c.conn.Close()
 	}
-
-	return nil
 }
 
 // State returns the current connection state.
@@ -658,23 +990,127 @@ func (c *Client) State() ConnState {
 	return c.atomicState.Load().(ConnState)
 }
 
-func (c *Client) setState(ctx context.Context, s ConnState) {
-	c.atomicState.Store(s)
-	if c.stateCh != nil {
+// WaitForState blocks until the client reaches the given connection state
+// or the context is done. It returns immediately if the client is already
+// in that state. A state which the client only passes through while
+// WaitForState is waiting is also considered as reached.
+//
+// WaitForState returns ErrReconnectGaveUp if the client has given up
+// reconnecting and will therefore not reach the state.
+func (c *Client) WaitForState(ctx context.Context, s ConnState) error {
+	c.stateMu.Lock()
+	if cur, _ := c.atomicState.Load().(ConnState); cur == s {
+		c.stateMu.Unlock()
+		return nil
+	}
+	if c.reconnectGaveUp.Load() {
+		c.stateMu.Unlock()
+		return ErrReconnectGaveUp
+	}
+	ch := make(chan struct{})
+	if c.stateWaiters == nil {
+		c.stateWaiters = make(map[ConnState][]chan struct{})
+	}
+	c.stateWaiters[s] = append(c.stateWaiters[s], ch)
+	c.stateMu.Unlock()
+
+	select {
+	case <-ch:
+		if c.reconnectGaveUp.Load() && c.State() != s {
+			return ErrReconnectGaveUp
+		}
+		return nil
+	case <-ctx.Done():
+		c.stateMu.Lock()
+		defer c.stateMu.Unlock()
 		select {
-		case <-ctx.Done():
-		case c.stateCh <- s:
+		case <-ch:
+			// the state was reached while we were waiting for the lock
+			return nil
+		default:
 		}
+		c.stateWaiters[s] = slices.DeleteFunc(c.stateWaiters[s], func(w chan struct{}) bool { return w == ch })
+		return ctx.Err()
 	}
-	if c.stateFunc != nil {
-		c.stateFunc(s)
+}
+
+// stateEvent is a state change which is delivered to the state
+// callbacks of the client.
+type stateEvent struct {
+	ctx      context.Context
+	from, to ConnState
+}
+
+// setState changes the connection state and wakes the WaitForState calls
+// which wait for it. The state change is then delivered to the state
+// channel and callbacks without holding stateMu so that they can call
+// WaitForState, Close or other methods which change the state.
+func (c *Client) setState(ctx context.Context, s ConnState) {
+	c.stateMu.Lock()
+	old, _ := c.atomicState.Load().(ConnState)
+	c.atomicState.Store(s)
+	for _, ch := range c.stateWaiters[s] {
+		close(ch)
+	}
+	delete(c.stateWaiters, s)
+	if c.reconnectGaveUp.Load() {
+		// the client will not reach any other state
+		for _, chs := range c.stateWaiters {
+			for _, ch := range chs {
+				close(ch)
+			}
+		}
+		clear(c.stateWaiters)
 	}
 	n := new(expvar.Int)
 	n.Set(int64(s))
 	stats.Client().Set("State", n)
+
+	c.stateEvents = append(c.stateEvents, stateEvent{ctx: ctx, from: old, to: s})
+	if c.stateDispatching {
+		// the state change is delivered by the goroutine which is
+		// already delivering the previous ones. This keeps the order
+		// of the state changes when a callback changes the state.
+		c.stateMu.Unlock()
+		return
+	}
+	c.stateDispatching = true
+	c.stateMu.Unlock()
+
+	c.dispatchStateEvents()
+}
+
+// dispatchStateEvents delivers the queued state changes in order until
+// the queue is empty.
+func (c *Client) dispatchStateEvents() {
+	for {
+		c.stateMu.Lock()
+		if len(c.stateEvents) == 0 {
+			c.stateDispatching = false
+			c.stateMu.Unlock()
+			return
+		}
+		ev := c.stateEvents[0]
+		c.stateEvents = c.stateEvents[1:]
+		c.stateMu.Unlock()
+
+		if c.stateCh != nil {
+			select {
+			case <-ev.ctx.Done():
+			case c.stateCh <- ev.to:
+			}
+		}
+		if c.stateFunc != nil {
+			c.stateFunc(ev.to)
+		}
+		if c.stateHandler != nil && ev.from != ev.to {
+			c.stateHandler(ev.from, ev.to)
+		}
+	}
 }
 
-// Namespaces returns the currently cached list of namespaces.
+// Namespaces returns the currently cached list of namespaces. The list is
+// updated from the server when the session is created or restored.
 func (c *Client) Namespaces() []string {
 	return c.atomicNamespaces.Load().([]string)
 }
@@ -702,6 +1138,110 @@ func (c *Client) SecureChannel() *uasc.SecureChannel {
 	return s
 }
 
+// SecureChannelInfo describes the security token of the secure channel.
+type SecureChannelInfo struct {
+	ChannelID       uint32
+	TokenID         uint32
+	CreatedAt       time.Time
+	RevisedLifetime time.Duration
+}
+
+// ExpiresAt returns the time when the security token expires.
+func (i SecureChannelInfo) ExpiresAt() time.Time {
+	return i.CreatedAt.Add(i.RevisedLifetime)
+}
+
+// SecureChannelInfo returns the security token of the active secure
+// channel. The client renews the token after 75% of its lifetime.
+func (c *Client) SecureChannelInfo() (SecureChannelInfo, error) {
+	sc := c.SecureChannel()
+	if sc == nil {
+		return SecureChannelInfo{}, c.notConnectedError()
+	}
+	t, err := sc.SecurityToken()
+	if err != nil {
+		return SecureChannelInfo{}, err
+	}
+	return SecureChannelInfo(t), nil
+}
+
+// PendingRequests returns the number of requests on the active secure
+// channel which are still waiting for their response. Requests which
+// do not receive a response are removed after their timeout. A number
+// which keeps growing indicates requests that are never answered.
+//
+// The total over all clients is also published as the PendingRequests
+// counter of stats.Client.
+func (c *Client) PendingRequests() int {
+	sc := c.SecureChannel()
+	if sc == nil {
+		return 0
+	}
+	return sc.PendingRequests()
+}
+
+// MaxRequestSize returns the maximum size of an encoded request which
+// the server accepts as negotiated for the secure channel. Larger
+// requests fail with BadRequestTooLarge before they are sent. Callers
+// can use the limit to split large values, e.g. with WriteIndexRange.
+// Zero means that the server did not announce a limit.
+func (c *Client) MaxRequestSize() (uint32, error) {
+	sc := c.SecureChannel()
+	if sc == nil {
+		return 0, c.notConnectedError()
+	}
+	return sc.MaxSendMessageSize(), nil
+}
+
+// Endpoint returns the endpoint description of the server which matches
+// the security policy and the security mode of the secure channel. It is
+// taken from the endpoints which the server returned when the session was
+// created. Without a session or a matching endpoint, e.g. for a
+// session-less client, the description only contains the endpoint url,
+// the security parameters and the server certificate of the client
+// configuration. Endpoint returns nil if the client is not connected.
+func (c *Client) Endpoint() *ua.EndpointDescription {
+	if c.SecureChannel() == nil {
+		return nil
+	}
+	if s := c.Session(); s != nil {
+		if ep := sessionEndpoint(s.resp.ServerEndpoints, c.cfg.sechan.SecurityPolicyURI, c.cfg.sechan.SecurityMode); ep != nil {
+			return ep
+		}
+	}
+	return &ua.EndpointDescription{
+		EndpointURL:       c.endpointURL,
+		ServerCertificate: c.cfg.sechan.RemoteCertificate,
+		SecurityMode:      c.cfg.sechan.SecurityMode,
+		SecurityPolicyURI: c.cfg.sechan.SecurityPolicyURI,
+	}
+}
+
+// ServerCertificate returns the certificate of the server. It is the
+// certificate which the server returned when the session was created or
+// the server certificate of the client configuration if there is no
+// session. If the server sent a certificate chain then the first
+// certificate is returned. ServerCertificate returns nil if the client
+// is not connected or the server did not send a valid certificate, e.g.
+// for the security policy None.
+func (c *Client) ServerCertificate() *x509.Certificate {
+	if c.SecureChannel() == nil {
+		return nil
+	}
+	cert := c.cfg.sechan.RemoteCertificate
+	if s := c.Session(); s != nil && len(s.serverCertificate) > 0 {
+		cert = s.serverCertificate
+	}
+	if len(cert) == 0 {
+		return nil
+	}
+	certs, err := x509.ParseCertificates(cert)
+	if err != nil || len(certs) == 0 {
+		return nil
+	}
+	return certs[0]
+}
+
 func (c *Client) setSecureChannel(sc *uasc.SecureChannel) {
 	c.atomicSechan.Store(sc)
 	stats.Client().Add("SecureChannel", 1)
@@ -742,6 +1282,24 @@ type Session struct {
 
 	// revisedTimeout is the actual maximum time that a Session shall remain open without activity.
 	revisedTimeout time.Duration
+
+	// userKey signs the X509 user identity token. May be nil.
+	userKey *rsa.PrivateKey
+}
+
+// sessionUser is the user identity of a session.
+type sessionUser struct {
+	cfg *uasc.SessionConfig
+	key *rsa.PrivateKey
+}
+
+// sessionUser returns the user identity for new sessions which is the
+// configured one unless it has been replaced by ChangeUser.
+func (c *Client) sessionUser() *sessionUser {
+	if u, ok := c.atomicUser.Load().(*sessionUser); ok && u != nil {
+		return u
+	}
+	return &sessionUser{cfg: c.cfg.session, key: c.cfg.sechan.UserKey}
 }
 
 // RevisedTimeout return actual maximum time that a Session shall remain open without activity.
@@ -763,7 +1321,7 @@ func (s *Session) RevisedTimeout() time.Duration {
 func (c *Client) CreateSession(ctx context.Context, cfg *uasc.SessionConfig) (*Session, error) {
 	sc := c.SecureChannel()
 	if sc == nil {
-		return nil, ua.StatusBadServerNotConnected
+		return nil, c.notConnectedError()
 	}
 
 	nonce := make([]byte, 32)
@@ -822,6 +1380,7 @@ func (c *Client) CreateSession(ctx context.Context, cfg *uasc.SessionConfig) (*S
 			serverNonce:       res.ServerNonce,
 			serverCertificate: res.ServerCertificate,
 			revisedTimeout:    time.Duration(res.RevisedSessionTimeout) * time.Millisecond,
+			userKey:           c.sessionUser().key,
 		}
 
 		return nil
@@ -853,11 +1412,31 @@ func anonymousPolicyID(endpoints []*ua.EndpointDescription) string {
 //
 // See Part 4, 5.6.3
 func (c *Client) ActivateSession(ctx context.Context, s *Session) error {
+	stats.Client().Add("ActivateSession", 1)
+	if err := c.activateSession(ctx, s); err != nil {
+		return err
+	}
+
+	// close the previous session
+	//
+	// https://github.com/gopcua/opcua/issues/474
+	//
+	// We decided not to check the error of CloseSession() since we
+	// can't do much about it anyway and it creates a race in the
+	// re-connection logic.
+	c.CloseSession(ctx)
+
+	c.setSession(s)
+	return nil
+}
+
+// activateSession sends the ActivateSession request for the session with
+// the user identity token of the session configuration.
+func (c *Client) activateSession(ctx context.Context, s *Session) error {
 	sc := c.SecureChannel()
 	if sc == nil {
-		return ua.StatusBadServerNotConnected
+		return c.notConnectedError()
 	}
-	stats.Client().Add("ActivateSession", 1)
 	sig, sigAlg, err := sc.NewSessionSignature(s.serverCertificate, s.serverNonce)
 	if err != nil {
 		log.Printf("error creating session signature: %s", err)
@@ -878,7 +1457,7 @@ func (c *Client) ActivateSession(ctx context.Context, s *Session) error {
 		tok.EncryptionAlgorithm = passAlg
 
 	case *ua.X509IdentityToken:
-		tokSig, tokSigAlg, err := sc.NewUserTokenSignature(s.cfg.AuthPolicyURI, s.serverCertificate, s.serverNonce)
+		tokSig, tokSigAlg, err := sc.NewUserTokenSignatureWithKey(s.cfg.AuthPolicyURI, s.userKey, s.serverCertificate, s.serverNonce)
 		if err != nil {
 			log.Printf("error creating session signature: %s", err)
 			return err
@@ -889,7 +1468,17 @@ func (c *Client) ActivateSession(ctx context.Context, s *Session) error {
 		}
 
 	case *ua.IssuedIdentityToken:
-		tok.EncryptionAlgorithm = ""
+		if s.cfg.AuthTokenData == nil {
+			tok.EncryptionAlgorithm = ""
+			break
+		}
+		data, dataAlg, err := sc.EncryptUserTokenData(s.cfg.AuthPolicyURI, s.cfg.AuthTokenData, s.serverCertificate, s.serverNonce)
+		if err != nil {
+			log.Printf("error encrypting issued token: %s", err)
+			return err
+		}
+		tok.TokenData = data
+		tok.EncryptionAlgorithm = dataAlg
 	}
 
 	req := &ua.ActivateSessionRequest{
@@ -911,38 +1500,132 @@ func (c *Client) ActivateSession(ctx context.Context, s *Session) error {
 		// save the nonce for the next request
 		s.serverNonce = res.ServerNonce
 
-		// close the previous session
-		//
-		// https://github.com/gopcua/opcua/issues/474
-		//
-		// We decided not to check the error of CloseSession() since we
-		// can't do much about it anyway and it creates a race in the
-		// re-connection logic.
-		c.CloseSession(ctx)
-
-		c.setSession(s)
+		c.logger.Info("session activated",
+			"sessionID", s.resp.SessionID,
+			"userTokenType", fmt.Sprintf("%T", s.cfg.UserIdentityToken),
+		)
 		return nil
 	})
 }
 
+// ChangeUser activates the current session again with a new user identity
+// token of the given type. The user identity is configured with the Auth
+// options, e.g. AuthUsername, and AuthPolicyID. Other options are
+// rejected. The policy id of the token is taken from the endpoint of the
+// session which matches the security policy and mode of the secure
+// channel unless it is set by the options.
+//
+// The secure channel and the session, including its subscriptions, are
+// retained. If the session has to be recreated after a reconnect the new
+// user identity is used.
+//
+// See Part 4, 5.6.3
+func (c *Client) ChangeUser(ctx context.Context, tokenType ua.UserTokenType, opts ...Option) error {
+	stats.Client().Add("ChangeUser", 1)
+
+	s := c.Session()
+	if s == nil {
+		return ua.StatusBadSessionClosed
+	}
+
+	// apply the options to copies of the configuration so that
+	// the current configuration is unchanged if this fails.
+	sechan, session := uasc.Config{UserKey: s.userKey}, *s.cfg
+	session.UserIdentityToken = nil
+	session.UserTokenSignature = &ua.SignatureData{}
+	session.AuthPassword = ""
+	session.AuthTokenData = nil
+	session.AuthPolicyURI = ""
+	cfg := &Config{sechan: &sechan, session: &session}
+
+	ep := sessionEndpoint(s.resp.ServerEndpoints, c.cfg.sechan.SecurityPolicyURI, c.cfg.sechan.SecurityMode)
+	switch {
+	case ep == nil:
+		session.UserIdentityToken = newUserIdentityToken(tokenType)
+	case !slices.ContainsFunc(ep.UserIdentityTokens, func(t *ua.UserTokenPolicy) bool { return t.TokenType == tokenType }):
+		return errors.Errorf("user token type %s not supported by endpoint", tokenType)
+	default:
+		if err := SecurityFromEndpoint(ep, tokenType)(cfg); err != nil {
+			return err
+		}
+	}
+	for _, opt := range opts {
+		before := userConfig(cfg)
+		if err := opt(cfg); err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(before, userConfig(cfg)) {
+			return errors.Errorf("option does not configure the user identity")
+		}
+	}
+	if session.UserIdentityToken == nil {
+		return errors.Errorf("invalid user token type %s", tokenType)
+	}
+
+	ns := &Session{
+		cfg:               &session,
+		resp:              s.resp,
+		serverCertificate: s.serverCertificate,
+		serverNonce:       s.serverNonce,
+		revisedTimeout:    s.revisedTimeout,
+		userKey:           sechan.UserKey,
+	}
+	if err := c.activateSession(ctx, ns); err != nil {
+		return err
+	}
+	c.atomicUser.Store(&sessionUser{cfg: ns.cfg, key: ns.userKey})
+	c.setSession(ns)
+	return nil
+}
+
+// userConfig returns a copy of the configuration without the user
+// identity. It changes if an option configures more than the user
+// identity.
+func userConfig(cfg *Config) Config {
+	sechan, session := *cfg.sechan, *cfg.session
+	sechan.UserKey = nil
+	session.UserIdentityToken = nil
+	session.UserTokenSignature = nil
+	session.AuthPassword = ""
+	session.AuthTokenData = nil
+	session.AuthPolicyURI = ""
+
+	c := *cfg
+	c.sechan, c.session = &sechan, &session
+	return c
+}
+
+// sessionEndpoint returns the endpoint with the given security policy and
+// security mode or nil.
+func sessionEndpoint(endpoints []*ua.EndpointDescription, policyURI string, mode ua.MessageSecurityMode) *ua.EndpointDescription {
+	for _, ep := range endpoints {
+		if ep.SecurityPolicyURI == policyURI && ep.SecurityMode == mode {
+			return ep
+		}
+	}
+	return nil
+}
+
 // CloseSession closes the current session.
 //
 // See Part 4, 5.6.4
 func (c *Client) CloseSession(ctx context.Context) error {
 	stats.Client().Add("CloseSession", 1)
-	if err := c.closeSession(ctx, c.Session()); err != nil {
+	if err := c.closeSession(ctx, c.Session(), true); err != nil {
 		return err
 	}
 	c.setSession(nil)
 	return nil
 }
 
-// closeSession closes the given session.
-func (c *Client) closeSession(ctx context.Context, s *Session) error {
+// closeSession closes the given session. The subscriptions of the
+// session are kept on the server for a transfer to another session
+// unless deleteSubscriptions is set.
+func (c *Client) closeSession(ctx context.Context, s *Session, deleteSubscriptions bool) error {
 	if s == nil {
 		return nil
 	}
-	req := &ua.CloseSessionRequest{DeleteSubscriptions: true}
+	req := &ua.CloseSessionRequest{DeleteSubscriptions: deleteSubscriptions}
 	var res *ua.CloseSessionResponse
 	return c.Send(ctx, req, func(v ua.Response) error {
 		return safeAssign(v, &res)
@@ -959,31 +1642,104 @@ func (c *Client) DetachSession(ctx context.Context) (*Session, error) {
 	return s, nil
 }
 
+// Cancel asks the server to cancel the outstanding requests with the
+// given request handle and returns the number of cancelled requests.
+// The cancelled requests complete with BadRequestCancelledByClient.
+//
+// See CancelOnContextDone to cancel requests whose context is done.
+//
+// See Part 4, 5.6.5
+func (c *Client) Cancel(ctx context.Context, requestHandle uint32) (uint32, error) {
+	stats.Client().Add("Cancel", 1)
+
+	req := &ua.CancelRequest{RequestHandle: requestHandle}
+	var res *ua.CancelResponse
+	err := c.Send(ctx, req, func(v ua.Response) error {
+		return safeAssign(v, &res)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.CancelCount, nil
+}
+
 // Send sends the request via the secure channel and registers a handler for
 // the response. If the client has an active session it injects the
 // authentication token.
 func (c *Client) Send(ctx context.Context, req ua.Request, h func(ua.Response) error) error {
 	stats.Client().Add("Send", 1)
 
-	err := c.sendWithTimeout(ctx, req, c.cfg.sechan.RequestTimeout, h)
+	err := c.sendWithTimeout(ctx, req, c.requestTimeout(ctx), h)
 	stats.RecordError(err)
 
 	return err
 }
 
+// requestTimeoutKey is the context key for a per-call request timeout.
+type requestTimeoutKey struct{}
+
+// withRequestTimeout returns a context which overrides the request
+// timeout of the client for the requests sent with it. Values below
+// one are ignored.
+func withRequestTimeout(ctx context.Context, d time.Duration) context.Context {
+	if d <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, requestTimeoutKey{}, d)
+}
+
+// requestTimeout returns the timeout for a request sent with ctx.
+func (c *Client) requestTimeout(ctx context.Context) time.Duration {
+	if d, ok := ctx.Value(requestTimeoutKey{}).(time.Duration); ok {
+		return d
+	}
+	return c.cfg.sechan.RequestTimeout
+}
+
 // sendWithTimeout sends the request via the secure channel with a custom timeout and registers a handler for
 // the response. If the client has an active session it injects the
 // authentication token.
 func (c *Client) sendWithTimeout(ctx context.Context, req ua.Request, timeout time.Duration, h uasc.ResponseHandler) error {
+	ctx, h, end := c.traceRequest(ctx, req, h)
+
 	sc := c.SecureChannel()
 	if sc == nil {
-		return ua.StatusBadServerNotConnected
+		err := c.notConnectedError()
+		end(err)
+		return err
 	}
 	var authToken *ua.NodeID
 	if s := c.Session(); s != nil {
 		authToken = s.resp.AuthenticationToken
 	}
-	return sc.SendRequestWithTimeout(ctx, req, authToken, timeout, h)
+	err := sc.SendRequestWithTimeout(ctx, req, authToken, timeout, h)
+	if err != nil && c.cfg.cancelOnContextDone && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		c.cancelRequest(ctx, req)
+	}
+	end(err)
+	return err
+}
+
+// cancelRequest sends a Cancel request in the background for a request
+// which has been abandoned since its context is done. The request handle
+// is taken from the header which the secure channel has set.
+func (c *Client) cancelRequest(ctx context.Context, req ua.Request) {
+	switch req.(type) {
+	case *ua.CancelRequest, *ua.PublishRequest:
+		return
+	}
+	hdr := req.Header()
+	if hdr == nil || hdr.RequestHandle == 0 || c.Session() == nil {
+		return
+	}
+	go func() {
+		n, err := c.Cancel(context.WithoutCancel(ctx), hdr.RequestHandle)
+		if err != nil {
+			debug.Printf("cancelling request %d failed: %s", hdr.RequestHandle, err)
+			return
+		}
+		debug.Printf("cancelled request %d: %d requests cancelled", hdr.RequestHandle, n)
+	}()
 }
 
 // Node returns a node object which accesses its attributes
@@ -1133,7 +1889,8 @@ func cloneBrowseRequest(req *ua.BrowseRequest) *ua.BrowseRequest {
 	return reqc
 }
 
-// Browse executes a synchronous browse request.
+// Browse executes a synchronous browse request. Use
+// BrowseOptions.BrowseRequest to create the request.
 func (c *Client) Browse(ctx context.Context, req *ua.BrowseRequest) (*ua.BrowseResponse, error) {
 	stats.Client().Add("Browse", 1)
 	stats.Client().Add("NodesToBrowse", int64(len(req.NodesToBrowse)))
@@ -1180,6 +1937,72 @@ func (c *Client) BrowseNext(ctx context.Context, req *ua.BrowseNextRequest) (*ua
 	return res, err
 }
 
+// TranslateBrowsePaths executes a synchronous TranslateBrowsePathsToNodeIDs request.
+//
+// Part 4, Section 5.8.4
+func (c *Client) TranslateBrowsePaths(ctx context.Context, paths []*ua.BrowsePath) (*ua.TranslateBrowsePathsToNodeIDsResponse, error) {
+	stats.Client().Add("TranslateBrowsePaths", 1)
+	stats.Client().Add("BrowsePaths", int64(len(paths)))
+
+	req := &ua.TranslateBrowsePathsToNodeIDsRequest{
+		BrowsePaths: paths,
+	}
+	var res *ua.TranslateBrowsePathsToNodeIDsResponse
+	err := c.Send(ctx, req, func(v ua.Response) error {
+		return safeAssign(v, &res)
+	})
+	return res, err
+}
+
+// TranslateBrowsePath resolves the relative path in text format starting
+// at startNode and returns the node id of the first target. If startNode
+// is nil the path is resolved from the root folder.
+//
+// See ua.ParseRelativePath for the format of the relative path and
+// TranslateBrowsePathTargets to get all targets of an ambiguous path.
+func (c *Client) TranslateBrowsePath(ctx context.Context, startNode *ua.NodeID, relativePath string) (*ua.NodeID, error) {
+	ids, err := c.TranslateBrowsePathTargets(ctx, startNode, relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return ids[0], nil
+}
+
+// TranslateBrowsePathTargets resolves the relative path in text format
+// starting at startNode and returns the node ids of all matching targets.
+// If startNode is nil the path is resolved from the root folder.
+//
+// See ua.ParseRelativePath for the format of the relative path.
+func (c *Client) TranslateBrowsePathTargets(ctx context.Context, startNode *ua.NodeID, relativePath string) ([]*ua.NodeID, error) {
+	p, err := ua.ParseRelativePath(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	if startNode == nil {
+		startNode = ua.NewNumericNodeID(0, id.RootFolder)
+	}
+
+	res, err := c.TranslateBrowsePaths(ctx, []*ua.BrowsePath{{StartingNode: startNode, RelativePath: p}})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Results) != 1 {
+		return nil, ua.StatusBadUnknownResponse
+	}
+	if status := res.Results[0].StatusCode; status != ua.StatusOK {
+		return nil, status
+	}
+	if len(res.Results[0].Targets) == 0 {
+		return nil, ua.StatusBadNoMatch
+	}
+
+	ids := make([]*ua.NodeID, len(res.Results[0].Targets))
+	for i, t := range res.Results[0].Targets {
+		ids[i] = ua.NewNodeIDFromExpandedNodeID(t.TargetID)
+	}
+	return ids, nil
+}
+
 // RegisterNodes registers node ids for more efficient reads.
 //
 // Part 4, Section 5.8.5
@@ -1231,6 +2054,12 @@ func (c *Client) HistoryReadEvent(ctx context.Context, nodes []*ua.HistoryReadVa
 	return res, err
 }
 
+// HistoryReadRawModified executes a synchronous HistoryRead request for
+// raw or modified values. The caller has to follow the continuation
+// points of the results. Use HistoryReadRawStream to read all values
+// of a time range.
+//
+// Part 11, 6.4.3
 func (c *Client) HistoryReadRawModified(ctx context.Context, nodes []*ua.HistoryReadValueID, details *ua.ReadRawModifiedDetails) (*ua.HistoryReadResponse, error) {
 	stats.Client().Add("HistoryReadRawModified", 1)
 	stats.Client().Add("HistoryReadValueID", int64(len(nodes)))
@@ -1254,6 +2083,12 @@ func (c *Client) HistoryReadRawModified(ctx context.Context, nodes []*ua.History
 	return res, err
 }
 
+// HistoryReadProcessed executes a synchronous HistoryRead request for
+// aggregated values. AggregateType of the details must contain one
+// aggregate for every node. Use HistoryReadAggregate to read all values
+// of a time range.
+//
+// Part 11, 6.4.4
 func (c *Client) HistoryReadProcessed(ctx context.Context, nodes []*ua.HistoryReadValueID, details *ua.ReadProcessedDetails) (*ua.HistoryReadResponse, error) {
 	stats.Client().Add("HistoryReadProcessed", 1)
 	stats.Client().Add("HistoryReadValueID", int64(len(nodes)))
@@ -1342,6 +2177,102 @@ func (c *Client) UpdateNamespaces(ctx context.Context) error {
 	return nil
 }
 
+// NamespaceIndex returns the index of the namespace with the given uri.
+// The index is looked up in the cached namespaces which are updated
+// from the server if the uri is not found.
+func (c *Client) NamespaceIndex(ctx context.Context, uri string) (uint16, error) {
+	find := func() (uint16, bool) {
+		for i, ns := range c.Namespaces() {
+			if ns == uri {
+				return uint16(i), true
+			}
+		}
+		return 0, false
+	}
+	if idx, ok := find(); ok {
+		return idx, nil
+	}
+	if err := c.UpdateNamespaces(ctx); err != nil {
+		return 0, err
+	}
+	if idx, ok := find(); ok {
+		return idx, nil
+	}
+	return 0, errors.Errorf("namespace not found. uri=%s", uri)
+}
+
+// NamespaceURI returns the uri of the namespace with the given index.
+// The uri is looked up in the cached namespaces which are updated from
+// the server if the index is unknown.
+func (c *Client) NamespaceURI(ctx context.Context, idx uint16) (string, error) {
+	if ns := c.Namespaces(); int(idx) < len(ns) {
+		return ns[idx], nil
+	}
+	if err := c.UpdateNamespaces(ctx); err != nil {
+		return "", err
+	}
+	if ns := c.Namespaces(); int(idx) < len(ns) {
+		return ns[idx], nil
+	}
+	return "", errors.Errorf("namespace not found. index=%d", idx)
+}
+
+// ResolveNodeID returns the node id with the given identifier in the
+// namespace with the given uri. The identifier is either a uint32 for a
+// numeric node id, a string, a *ua.GUID or a []byte for an opaque node id.
+func (c *Client) ResolveNodeID(ctx context.Context, nsURI string, identifier interface{}) (*ua.NodeID, error) {
+	ns, err := c.NamespaceIndex(ctx, nsURI)
+	if err != nil {
+		return nil, err
+	}
+	switch x := identifier.(type) {
+	case uint32:
+		return ua.NewNumericNodeID(ns, x), nil
+	case string:
+		return ua.NewStringNodeID(ns, x), nil
+	case *ua.GUID:
+		return ua.NewGUIDNodeID(ns, x.String()), nil
+	case []byte:
+		return ua.NewByteStringNodeID(ns, x), nil
+	default:
+		return nil, errors.Errorf("invalid node id identifier type %T", identifier)
+	}
+}
+
+// ResolveExpandedNodeID converts an expanded node id into a node id of
+// the session. A namespace URI is resolved to the index of the namespace
+// on the server, e.g. for node ids from ua.ParseExpandedNodeID with the
+// 'nsu=' prefix. Node ids on other servers cannot be resolved.
+func (c *Client) ResolveExpandedNodeID(ctx context.Context, eid *ua.ExpandedNodeID) (*ua.NodeID, error) {
+	if eid == nil || eid.NodeID == nil {
+		return nil, errors.Errorf("invalid expanded node id")
+	}
+	if eid.ServerIndex != 0 {
+		return nil, errors.Errorf("node id %s is on server %d", eid.NodeID, eid.ServerIndex)
+	}
+	if eid.NamespaceURI == "" {
+		return eid.NodeID, nil
+	}
+
+	n := eid.NodeID
+	switch n.Type() {
+	case ua.NodeIDTypeTwoByte, ua.NodeIDTypeFourByte, ua.NodeIDTypeNumeric:
+		return c.ResolveNodeID(ctx, eid.NamespaceURI, n.IntID())
+	case ua.NodeIDTypeString:
+		return c.ResolveNodeID(ctx, eid.NamespaceURI, n.StringID())
+	case ua.NodeIDTypeGUID:
+		return c.ResolveNodeID(ctx, eid.NamespaceURI, ua.NewGUID(n.StringID()))
+	case ua.NodeIDTypeByteString:
+		b, err := base64.StdEncoding.DecodeString(n.StringID())
+		if err != nil {
+			return nil, err
+		}
+		return c.ResolveNodeID(ctx, eid.NamespaceURI, b)
+	default:
+		return nil, errors.Errorf("invalid node id type %d", n.Type())
+	}
+}
+
 // safeAssign implements a type-safe assign from T to *T.
 func safeAssign(t, ptrT interface{}) error {
 	if reflect.TypeOf(t) != reflect.TypeOf(ptrT).Elem() {
@@ -0,0 +1,58 @@
+package opcua
+
+import (
+	"context"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// ConditionRefresh asks the server to send the current state of all
+// conditions as events to the subscription with the given id. The
+// refreshed events are delivered between a RefreshStartEvent and a
+// RefreshEndEvent.
+func (c *Client) ConditionRefresh(ctx context.Context, subscriptionID uint32) error {
+	return c.callCondition(ctx, conditionRefreshRequest(subscriptionID))
+}
+
+// Acknowledge acknowledges the condition state identified by eventID
+// with an optional comment. The eventID is the EventId field of the
+// event notification for the condition.
+func (c *Client) Acknowledge(ctx context.Context, conditionID *ua.NodeID, eventID []byte, comment *ua.LocalizedText) error {
+	return c.callCondition(ctx, acknowledgeRequest(conditionID, eventID, comment))
+}
+
+// callCondition calls an alarms and conditions method and returns the
+// status code of the method result if it is not good.
+func (c *Client) callCondition(ctx context.Context, req *ua.CallMethodRequest) error {
+	res, err := c.Call(ctx, req)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != ua.StatusOK {
+		return res.StatusCode
+	}
+	return nil
+}
+
+func conditionRefreshRequest(subscriptionID uint32) *ua.CallMethodRequest {
+	return &ua.CallMethodRequest{
+		ObjectID:       ua.NewNumericNodeID(0, id.ConditionType),
+		MethodID:       ua.NewNumericNodeID(0, id.ConditionType_ConditionRefresh),
+		InputArguments: []*ua.Variant{ua.MustVariant(subscriptionID)},
+	}
+}
+
+func acknowledgeRequest(conditionID *ua.NodeID, eventID []byte, comment *ua.LocalizedText) *ua.CallMethodRequest {
+	if comment == nil {
+		comment = &ua.LocalizedText{}
+	}
+	return &ua.CallMethodRequest{
+		ObjectID: conditionID,
+		MethodID: ua.NewNumericNodeID(0, id.AcknowledgeableConditionType_Acknowledge),
+		InputArguments: []*ua.Variant{
+			ua.MustVariant(eventID),
+			ua.MustVariant(comment),
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package opcua
+
+import (
+	"testing"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionRefreshRequest(t *testing.T) {
+	want := &ua.CallMethodRequest{
+		ObjectID:       ua.NewNumericNodeID(0, id.ConditionType),
+		MethodID:       ua.NewNumericNodeID(0, id.ConditionType_ConditionRefresh),
+		InputArguments: []*ua.Variant{ua.MustVariant(uint32(7))},
+	}
+	require.Equal(t, want, conditionRefreshRequest(7))
+}
+
+func TestAcknowledgeRequest(t *testing.T) {
+	cond := ua.NewStringNodeID(2, "alarm")
+	eventID := []byte{1, 2, 3}
+
+	tests := []struct {
+		name    string
+		comment *ua.LocalizedText
+		want    *ua.LocalizedText
+	}{
+		{"no comment", nil, &ua.LocalizedText{}},
+		{"comment", ua.NewLocalizedText("ok"), ua.NewLocalizedText("ok")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := &ua.CallMethodRequest{
+				ObjectID:       cond,
+				MethodID:       ua.NewNumericNodeID(0, id.AcknowledgeableConditionType_Acknowledge),
+				InputArguments: []*ua.Variant{ua.MustVariant(eventID), ua.MustVariant(tt.want)},
+			}
+			require.Equal(t, want, acknowledgeRequest(cond, eventID, tt.comment))
+		})
+	}
+}
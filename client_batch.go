@@ -0,0 +1,270 @@
+package opcua
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/stats"
+	"github.com/gopcua/opcua/ua"
+)
+
+const (
+	// DefaultBatchReadChunkSize is the maximum number of nodes per
+	// ReadRequest sent by BatchRead if neither the caller nor the
+	// server define a lower limit.
+	DefaultBatchReadChunkSize = 1000
+
+	// DefaultBatchWriteChunkSize is the maximum number of nodes per
+	// WriteRequest sent by BatchWrite if neither the caller nor the
+	// server define a lower limit.
+	DefaultBatchWriteChunkSize = 1000
+
+	// DefaultBatchConcurrency is the maximum number of requests
+	// a batch operation sends to the server in parallel.
+	DefaultBatchConcurrency = 4
+)
+
+// ReadOption is an option function type to modify a BatchRead call.
+// MonitorWithCallback accepts the Timestamps option as well.
+type ReadOption func(*readConfig)
+
+type readConfig struct {
+	chunkSize    int
+	concurrency  int
+	timeout      time.Duration
+	dataEncoding string
+	timestamps   ua.TimestampsToReturn
+}
+
+func newReadConfig(opts ...ReadOption) *readConfig {
+	cfg := &readConfig{
+		chunkSize:   DefaultBatchReadChunkSize,
+		concurrency: DefaultBatchConcurrency,
+		timestamps:  ua.TimestampsToReturnBoth,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// ReadChunkSize sets the maximum number of nodes per ReadRequest.
+// If the server announces a lower MaxNodesPerRead limit then the
+// limit of the server is used instead. Values below one are ignored.
+func ReadChunkSize(n int) ReadOption {
+	return func(cfg *readConfig) {
+		if n > 0 {
+			cfg.chunkSize = n
+		}
+	}
+}
+
+// ReadConcurrency sets the maximum number of ReadRequests which are
+// sent to the server in parallel. Values below one are ignored.
+func ReadConcurrency(n int) ReadOption {
+	return func(cfg *readConfig) {
+		if n > 0 {
+			cfg.concurrency = n
+		}
+	}
+}
+
+// ReadTimeout overrides the request timeout of the client for the
+// ReadRequests of the call. Values below one are ignored.
+func ReadTimeout(d time.Duration) ReadOption {
+	return func(cfg *readConfig) {
+		if d > 0 {
+			cfg.timeout = d
+		}
+	}
+}
+
+// WithDataEncoding requests the values of structured data types in the
+// given encoding, e.g. DataEncodingJSON. The name is set as DataEncoding
+// of the ReadValueIDs of the value attribute which do not have one.
+//
+// BatchRead verifies that the data types of the nodes offer the encoding
+// before the values are read and returns an error which wraps
+// StatusBadDataEncodingUnsupported otherwise. The default is the binary
+// encoding.
+func WithDataEncoding(name string) ReadOption {
+	return func(cfg *readConfig) {
+		cfg.dataEncoding = name
+	}
+}
+
+// Timestamps selects the timestamps which the server returns with the
+// values, e.g. ua.TimestampsToReturnSource to reduce the size of the
+// responses or to ignore inconsistent server timestamps. The default is
+// ua.TimestampsToReturnBoth.
+func Timestamps(ts ua.TimestampsToReturn) ReadOption {
+	return func(cfg *readConfig) {
+		cfg.timestamps = ts
+	}
+}
+
+// BatchRead reads the given nodes and splits them into multiple
+// ReadRequests so that the MaxNodesPerRead limit of the server is not
+// exceeded. The requests are sent concurrently and the results are
+// returned in the same order as nodesToRead.
+//
+// The status codes of the individual nodes are preserved in the
+// returned data values. An error is only returned if one or more
+// requests could not be completed. In that case the results of the
+// failed requests are nil.
+func (c *Client) BatchRead(ctx context.Context, nodesToRead []*ua.ReadValueID, opts ...ReadOption) ([]*ua.DataValue, error) {
+	stats.Client().Add("BatchRead", 1)
+
+	cfg := newReadConfig(opts...)
+	ctx = withRequestTimeout(ctx, cfg.timeout)
+	size := cfg.chunkSize
+	if n := c.maxNodesPerRead(ctx); n > 0 && n < size {
+		size = n
+	}
+
+	if cfg.dataEncoding != "" && cfg.dataEncoding != DataEncodingBinary {
+		var err error
+		nodesToRead, err = c.withDataEncoding(ctx, nodesToRead, cfg.dataEncoding)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]*ua.DataValue, len(nodesToRead))
+	err := runBatches(ctx, len(nodesToRead), size, cfg.concurrency, func(ctx context.Context, lo, hi int) error {
+		req := &ua.ReadRequest{
+			TimestampsToReturn: cfg.timestamps,
+			NodesToRead:        nodesToRead[lo:hi],
+		}
+		res, err := c.Read(ctx, req)
+		if err == nil && len(res.Results) != hi-lo {
+			err = ua.StatusBadUnknownResponse
+		}
+		if err != nil {
+			return errors.Errorf("read nodes %d..%d: %w", lo, hi-1, err)
+		}
+		copy(results[lo:hi], res.Results)
+		return nil
+	})
+	return results, err
+}
+
+// maxNodesPerRead returns the MaxNodesPerRead operation limit of the
+// server or zero if the limit is unknown.
+func (c *Client) maxNodesPerRead(ctx context.Context) int {
+	l, err := c.OperationLimits(ctx)
+	if err != nil {
+		return 0
+	}
+	return int(l.MaxNodesPerRead)
+}
+
+// BatchWrite writes the given values and splits them into multiple
+// WriteRequests so that the MaxNodesPerWrite limit of the server is not
+// exceeded. The requests are sent concurrently and the status codes are
+// returned in the same order as nodesToWrite.
+//
+// A bad status code for a single node, e.g. StatusBadNodeIDUnknown, is
+// only reported in its status code and does not affect the other nodes.
+// An error is only returned if one or more requests could not be
+// completed. In that case the status codes of the nodes of the failed
+// requests are set to the status code of the error or to
+// StatusBadRequestNotComplete.
+func (c *Client) BatchWrite(ctx context.Context, nodesToWrite []*ua.WriteValue, opts ...WriteOption) ([]ua.StatusCode, error) {
+	stats.Client().Add("BatchWrite", 1)
+
+	cfg := newWriteConfig(opts...)
+	ctx = withRequestTimeout(ctx, cfg.timeout)
+	size := cfg.chunkSize
+	if n := c.maxNodesPerWrite(ctx); n > 0 && n < size {
+		size = n
+	}
+
+	results := make([]ua.StatusCode, len(nodesToWrite))
+	for i := range results {
+		results[i] = ua.StatusBadRequestNotComplete
+	}
+	err := runBatches(ctx, len(nodesToWrite), size, cfg.concurrency, func(ctx context.Context, lo, hi int) error {
+		req := &ua.WriteRequest{NodesToWrite: nodesToWrite[lo:hi]}
+		res, err := c.Write(ctx, req)
+		if err == nil && len(res.Results) != hi-lo {
+			err = ua.StatusBadUnknownResponse
+		}
+		if err != nil {
+			var status ua.StatusCode
+			if errors.As(err, &status) {
+				for i := lo; i < hi; i++ {
+					results[i] = status
+				}
+			}
+			return errors.Errorf("write nodes %d..%d: %w", lo, hi-1, err)
+		}
+		copy(results[lo:hi], res.Results)
+		return nil
+	})
+	return results, err
+}
+
+// maxNodesPerWrite returns the MaxNodesPerWrite operation limit of the
+// server or zero if the limit is unknown.
+func (c *Client) maxNodesPerWrite(ctx context.Context) int {
+	l, err := c.OperationLimits(ctx)
+	if err != nil {
+		return 0
+	}
+	return int(l.MaxNodesPerWrite)
+}
+
+// runBatches splits the range [0, n) into chunks of at most size
+// elements and calls fn for every chunk with at most concurrency
+// calls running in parallel. The errors of all failed calls are
+// joined.
+func runBatches(ctx context.Context, n, size, concurrency int, fn func(ctx context.Context, lo, hi int) error) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, concurrency)
+	)
+
+	for _, r := range chunkRanges(n, size) {
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			errs = append(errs, ctx.Err())
+			mu.Unlock()
+			wg.Wait()
+			return errors.Join(errs...)
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(ctx, lo, hi); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(r[0], r[1])
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// chunkRanges returns the [lo, hi) index ranges which split n elements
+// into chunks of at most size elements.
+func chunkRanges(n, size int) [][2]int {
+	if size <= 0 {
+		size = n
+	}
+	var r [][2]int
+	for lo := 0; lo < n; lo += size {
+		r = append(r, [2]int{lo, min(lo+size, n)})
+	}
+	return r
+}
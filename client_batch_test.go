@@ -0,0 +1,73 @@
+package opcua
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkRanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		n, size int
+		want    [][2]int
+	}{
+		{name: "empty", n: 0, size: 10, want: nil},
+		{name: "single chunk", n: 5, size: 10, want: [][2]int{{0, 5}}},
+		{name: "exact", n: 10, size: 5, want: [][2]int{{0, 5}, {5, 10}}},
+		{name: "remainder", n: 11, size: 5, want: [][2]int{{0, 5}, {5, 10}, {10, 11}}},
+		{name: "no size", n: 3, size: 0, want: [][2]int{{0, 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, chunkRanges(tt.n, tt.size))
+		})
+	}
+}
+
+func TestRunBatches(t *testing.T) {
+	t.Run("all chunks", func(t *testing.T) {
+		var (
+			mu  sync.Mutex
+			got = make([]int, 10)
+		)
+		err := runBatches(context.Background(), len(got), 3, 2, func(ctx context.Context, lo, hi int) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for i := lo; i < hi; i++ {
+				got[i]++
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, got)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		err := runBatches(context.Background(), 10, 5, 1, func(ctx context.Context, lo, hi int) error {
+			if lo == 5 {
+				return ua.StatusBadTimeout
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, ua.StatusBadTimeout)
+	})
+}
+
+func TestBatchReadErrors(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840")
+	require.NoError(t, err)
+
+	nodes := []*ua.ReadValueID{
+		{NodeID: ua.NewNumericNodeID(0, 2255)},
+		{NodeID: ua.NewNumericNodeID(0, 2256)},
+		{NodeID: ua.NewNumericNodeID(0, 2258)},
+	}
+	res, err := c.BatchRead(context.Background(), nodes, ReadChunkSize(2))
+	require.Len(t, res, 3)
+	require.ErrorContains(t, err, "read nodes 0..1: ")
+	require.ErrorContains(t, err, "read nodes 2..2: ")
+}
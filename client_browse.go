@@ -0,0 +1,385 @@
+package opcua
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/gopcua/opcua/debug"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// BrowseOptions configures which references of a node are returned by
+// the Browse service. The zero value returns the forward hierarchical
+// references with all fields of the reference descriptions.
+//
+// Use BrowseRequest to create a request for Browse or BrowseStream:
+//
+//	opts := opcua.BrowseOptions{
+//		ReferenceTypeID: ua.NewNumericNodeID(0, id.Organizes),
+//		IncludeSubtypes: true,
+//		NodeClassMask:   ua.NodeClassObject | ua.NodeClassVariable,
+//	}
+//	for ref, err := range c.BrowseStream(ctx, opts.BrowseRequest(nodeID)) {
+//		...
+//	}
+type BrowseOptions struct {
+	// Direction is the direction of the references. The default is
+	// BrowseDirectionForward.
+	Direction ua.BrowseDirection
+
+	// ReferenceTypeID is the type of the returned references. If it is
+	// nil then all hierarchical references are returned and
+	// IncludeSubtypes is ignored.
+	ReferenceTypeID *ua.NodeID
+
+	// IncludeSubtypes returns the references of the subtypes of
+	// ReferenceTypeID as well.
+	IncludeSubtypes bool
+
+	// NodeClassMask limits the references to target nodes of the given
+	// classes. Zero means all node classes.
+	NodeClassMask ua.NodeClass
+
+	// ResultMask selects the fields of the reference descriptions which
+	// are returned. Zero means BrowseResultMaskAll.
+	ResultMask ua.BrowseResultMask
+}
+
+// Description returns the browse description for nodeID.
+func (o BrowseOptions) Description(nodeID *ua.NodeID) *ua.BrowseDescription {
+	refType, subtypes := o.ReferenceTypeID, o.IncludeSubtypes
+	if refType == nil {
+		refType, subtypes = ua.NewNumericNodeID(0, id.HierarchicalReferences), true
+	}
+	resultMask := o.ResultMask
+	if resultMask == ua.BrowseResultMaskNone {
+		resultMask = ua.BrowseResultMaskAll
+	}
+	return &ua.BrowseDescription{
+		NodeID:          nodeID,
+		BrowseDirection: o.Direction,
+		ReferenceTypeID: refType,
+		IncludeSubtypes: subtypes,
+		NodeClassMask:   uint32(o.NodeClassMask),
+		ResultMask:      uint32(resultMask),
+	}
+}
+
+// BrowseRequest returns a browse request for the given nodes.
+func (o BrowseOptions) BrowseRequest(nodeIDs ...*ua.NodeID) *ua.BrowseRequest {
+	req := &ua.BrowseRequest{
+		NodesToBrowse: make([]*ua.BrowseDescription, len(nodeIDs)),
+	}
+	for i, nodeID := range nodeIDs {
+		req.NodesToBrowse[i] = o.Description(nodeID)
+	}
+	return req
+}
+
+// BrowseStream returns an iterator over the references of all nodes in
+// the browse request. The references are yielded lazily in the order of
+// NodesToBrowse and continuation points are followed with BrowseNext.
+//
+// A browse result with a bad status code yields the status code as
+// error and the iteration continues with the next node. Service errors
+// end the iteration. Continuation points which have not been consumed
+// when the iteration ends early, e.g. since the loop was left or the
+// context was cancelled, are released on the server.
+func (c *Client) BrowseStream(ctx context.Context, req *ua.BrowseRequest) iter.Seq2[*ua.ReferenceDescription, error] {
+	return func(yield func(*ua.ReferenceDescription, error) bool) {
+		res, err := c.Browse(ctx, req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if len(res.Results) != len(req.NodesToBrowse) {
+			yield(nil, ua.StatusBadUnknownResponse)
+			return
+		}
+
+		// cps contains the outstanding continuation point of every
+		// browse result which is released when the iteration ends.
+		cps := make([][]byte, len(res.Results))
+		for i, r := range res.Results {
+			cps[i] = r.ContinuationPoint
+		}
+		defer c.releaseContinuationPoints(ctx, cps)
+
+		for i, r := range res.Results {
+			for {
+				if r.StatusCode != ua.StatusOK {
+					if !yield(nil, r.StatusCode) {
+						return
+					}
+					break
+				}
+				for _, ref := range r.References {
+					if !yield(ref, nil) {
+						return
+					}
+				}
+				if len(cps[i]) == 0 {
+					break
+				}
+				if err := ctx.Err(); err != nil {
+					yield(nil, err)
+					return
+				}
+
+				next, err := c.BrowseNext(ctx, &ua.BrowseNextRequest{
+					ContinuationPoints: [][]byte{cps[i]},
+				})
+				cps[i] = nil
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if len(next.Results) != 1 {
+					yield(nil, ua.StatusBadUnknownResponse)
+					return
+				}
+				r = next.Results[0]
+				cps[i] = r.ContinuationPoint
+			}
+		}
+	}
+}
+
+// releaseContinuationPoints releases all non-empty continuation points
+// on the server. The request is sent even if ctx has been cancelled.
+func (c *Client) releaseContinuationPoints(ctx context.Context, cps [][]byte) {
+	var release [][]byte
+	for _, cp := range cps {
+		if len(cp) > 0 {
+			release = append(release, cp)
+		}
+	}
+	if len(release) == 0 {
+		return
+	}
+
+	_, err := c.BrowseNext(context.WithoutCancel(ctx), &ua.BrowseNextRequest{
+		ReleaseContinuationPoints: true,
+		ContinuationPoints:        release,
+	})
+	if err != nil {
+		debug.Printf("releasing %d continuation points failed: %s", len(release), err)
+	}
+}
+
+// Children browses the forward references of parent and returns the
+// referenced nodes by their browse name in the form "ns:name", e.g.
+// "2:Temperature". The references are limited to refType and its
+// subtypes. A nil refType selects all hierarchical references.
+//
+// Children returns an error if two different children have the same
+// browse name. Use ChildrenMulti for nodes with duplicate browse names.
+func (c *Client) Children(ctx context.Context, parent, refType *ua.NodeID) (map[string]*ua.ReferenceDescription, error) {
+	multi, err := c.ChildrenMulti(ctx, parent, refType)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[string]*ua.ReferenceDescription, len(multi))
+	for name, refs := range multi {
+		if len(refs) > 1 {
+			return nil, errors.Errorf("duplicate browse name %s below %s", name, parent)
+		}
+		children[name] = refs[0]
+	}
+	return children, nil
+}
+
+// ChildrenMulti is like Children but returns all children with the
+// same browse name in browse order. A node which is referenced more
+// than once, e.g. with Organizes and HasComponent, is returned only
+// once.
+func (c *Client) ChildrenMulti(ctx context.Context, parent, refType *ua.NodeID) (map[string][]*ua.ReferenceDescription, error) {
+	opts := BrowseOptions{ReferenceTypeID: refType, IncludeSubtypes: true}
+	req := opts.BrowseRequest(parent)
+
+	children := make(map[string][]*ua.ReferenceDescription)
+	seen := make(map[string]bool)
+	for ref, err := range c.BrowseStream(ctx, req) {
+		if err != nil {
+			return nil, err
+		}
+		if ref.BrowseName == nil || ref.NodeID == nil {
+			continue
+		}
+		if seen[ref.NodeID.String()] {
+			continue
+		}
+		seen[ref.NodeID.String()] = true
+
+		name := fmt.Sprintf("%d:%s", ref.BrowseName.NamespaceIndex, ref.BrowseName.Name)
+		children[name] = append(children[name], ref)
+	}
+	return children, nil
+}
+
+// Properties returns the values of the properties of a node, i.e. the
+// nodes referenced with HasProperty, by their browse name in the form
+// "ns:name", e.g. "0:EURange". The values are read with BatchRead and
+// keep their status codes, i.e. a property which cannot be read is
+// returned with a bad status code and without an error.
+func (c *Client) Properties(ctx context.Context, nodeID *ua.NodeID) (map[string]*ua.DataValue, error) {
+	props, err := c.Children(ctx, nodeID, ua.NewNumericNodeID(0, id.HasProperty))
+	if err != nil {
+		return nil, err
+	}
+	if len(props) == 0 {
+		return map[string]*ua.DataValue{}, nil
+	}
+
+	names := make([]string, 0, len(props))
+	nodes := make([]*ua.ReadValueID, 0, len(props))
+	for name, ref := range props {
+		names = append(names, name)
+		nodes = append(nodes, &ua.ReadValueID{NodeID: ref.NodeID.NodeID, AttributeID: ua.AttributeIDValue})
+	}
+	results, err := c.BatchRead(ctx, nodes, Timestamps(ua.TimestampsToReturnNeither))
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]*ua.DataValue, len(results))
+	for i, dv := range results {
+		values[names[i]] = dv
+	}
+	return values, nil
+}
+
+// WalkOptions configures the traversal of the address space by Walk.
+type WalkOptions struct {
+	// DepthFirst selects a depth-first traversal. The default is a
+	// breadth-first traversal.
+	DepthFirst bool
+
+	// MaxDepth is the maximum depth of the returned nodes relative to
+	// the root node. Zero means no limit.
+	MaxDepth int
+
+	// ReferenceTypeID is the type of the references which are followed
+	// including its subtypes. The default is HierarchicalReferences.
+	ReferenceTypeID *ua.NodeID
+
+	// NodeClassMask limits the traversal to nodes of the given classes.
+	// Nodes of other classes are neither returned nor traversed. Zero
+	// means all node classes.
+	NodeClassMask ua.NodeClass
+}
+
+// WalkNode is a node which has been visited by Walk.
+type WalkNode struct {
+	NodeID     *ua.NodeID
+	BrowseName *ua.QualifiedName
+	NodeClass  ua.NodeClass
+
+	// Path contains the browse names of the nodes from the root node
+	// to this node. It is empty for the root node.
+	Path []*ua.QualifiedName
+}
+
+// Depth returns the distance of the node to the root node.
+func (n *WalkNode) Depth() int {
+	return len(n.Path)
+}
+
+// Walk returns an iterator which traverses the address space starting
+// at root. Every node is visited only once even if the address space
+// contains cycles. The root node is returned first. References to nodes
+// on other servers are not followed.
+//
+// Errors for a single node are yielded and the traversal continues
+// with the next node unless the loop is left.
+func (c *Client) Walk(ctx context.Context, root *ua.NodeID, opts WalkOptions) iter.Seq2[*WalkNode, error] {
+	refType := opts.ReferenceTypeID
+	if refType == nil {
+		refType = ua.NewNumericNodeID(0, id.HierarchicalReferences)
+	}
+
+	return func(yield func(*WalkNode, error) bool) {
+		attrs, err := c.Node(root).Attributes(ctx, ua.AttributeIDBrowseName, ua.AttributeIDNodeClass)
+		if err == nil && len(attrs) != 2 {
+			err = ua.StatusBadUnknownResponse
+		}
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		start := &WalkNode{NodeID: root}
+		if v := attrs[0].Value; v != nil {
+			start.BrowseName, _ = v.Value().(*ua.QualifiedName)
+		}
+		if v := attrs[1].Value; v != nil {
+			nc, _ := v.Value().(int32)
+			start.NodeClass = ua.NodeClass(nc)
+		}
+
+		visited := map[string]bool{root.String(): true}
+		queue := []*WalkNode{start}
+		for len(queue) > 0 {
+			var n *WalkNode
+			if opts.DepthFirst {
+				n, queue = queue[len(queue)-1], queue[:len(queue)-1]
+			} else {
+				n, queue = queue[0], queue[1:]
+			}
+			if !yield(n, nil) {
+				return
+			}
+			if opts.MaxDepth > 0 && n.Depth() >= opts.MaxDepth {
+				continue
+			}
+
+			req := &ua.BrowseRequest{
+				NodesToBrowse: []*ua.BrowseDescription{{
+					NodeID:          n.NodeID,
+					BrowseDirection: ua.BrowseDirectionForward,
+					ReferenceTypeID: refType,
+					IncludeSubtypes: true,
+					NodeClassMask:   uint32(opts.NodeClassMask),
+					ResultMask:      uint32(ua.BrowseResultMaskBrowseName | ua.BrowseResultMaskNodeClass),
+				}},
+			}
+			var children []*WalkNode
+			for ref, err := range c.BrowseStream(ctx, req) {
+				if err != nil {
+					if !yield(nil, err) {
+						return
+					}
+					if ctx.Err() != nil {
+						return
+					}
+					continue
+				}
+				if ref.NodeID == nil || ref.NodeID.ServerIndex != 0 {
+					continue
+				}
+				if visited[ref.NodeID.NodeID.String()] {
+					continue
+				}
+				visited[ref.NodeID.NodeID.String()] = true
+
+				path := make([]*ua.QualifiedName, len(n.Path), len(n.Path)+1)
+				copy(path, n.Path)
+				children = append(children, &WalkNode{
+					NodeID:     ref.NodeID.NodeID,
+					BrowseName: ref.BrowseName,
+					NodeClass:  ref.NodeClass,
+					Path:       append(path, ref.BrowseName),
+				})
+			}
+
+			// push the children in reverse order so that a depth-first
+			// traversal visits them in browse order.
+			if opts.DepthFirst {
+				slices.Reverse(children)
+			}
+			queue = append(queue, children...)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package opcua
+
+import (
+	"testing"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowseOptions(t *testing.T) {
+	nodeID := ua.NewNumericNodeID(0, id.ObjectsFolder)
+
+	tests := []struct {
+		name string
+		opts BrowseOptions
+		want *ua.BrowseDescription
+	}{
+		{
+			name: "defaults",
+			want: &ua.BrowseDescription{
+				NodeID:          nodeID,
+				BrowseDirection: ua.BrowseDirectionForward,
+				ReferenceTypeID: ua.NewNumericNodeID(0, id.HierarchicalReferences),
+				IncludeSubtypes: true,
+				ResultMask:      uint32(ua.BrowseResultMaskAll),
+			},
+		},
+		{
+			name: "reference type",
+			opts: BrowseOptions{
+				Direction:       ua.BrowseDirectionInverse,
+				ReferenceTypeID: ua.NewNumericNodeID(0, id.Organizes),
+				NodeClassMask:   ua.NodeClassObject | ua.NodeClassVariable,
+				ResultMask:      ua.BrowseResultMaskBrowseName,
+			},
+			want: &ua.BrowseDescription{
+				NodeID:          nodeID,
+				BrowseDirection: ua.BrowseDirectionInverse,
+				ReferenceTypeID: ua.NewNumericNodeID(0, id.Organizes),
+				NodeClassMask:   uint32(ua.NodeClassObject | ua.NodeClassVariable),
+				ResultMask:      uint32(ua.BrowseResultMaskBrowseName),
+			},
+		},
+		{
+			name: "include subtypes",
+			opts: BrowseOptions{
+				ReferenceTypeID: ua.NewNumericNodeID(0, id.Organizes),
+				IncludeSubtypes: true,
+			},
+			want: &ua.BrowseDescription{
+				NodeID:          nodeID,
+				ReferenceTypeID: ua.NewNumericNodeID(0, id.Organizes),
+				IncludeSubtypes: true,
+				ResultMask:      uint32(ua.BrowseResultMaskAll),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.opts.Description(nodeID))
+		})
+	}
+
+	req := BrowseOptions{}.BrowseRequest(nodeID, ua.NewNumericNodeID(0, id.Server))
+	require.Len(t, req.NodesToBrowse, 2)
+	require.Equal(t, "i=2253", req.NodesToBrowse[1].NodeID.String())
+}
@@ -0,0 +1,161 @@
+package opcua
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gopcua/opcua/stats"
+	"github.com/gopcua/opcua/ua"
+)
+
+// DefaultCachedAttributes contains the attributes which are cached by
+// ReadCachedAttributes if the AttributeCache option is used without
+// attributes. These attributes do not change for the lifetime of a node.
+var DefaultCachedAttributes = []ua.AttributeID{
+	ua.AttributeIDNodeClass,
+	ua.AttributeIDBrowseName,
+	ua.AttributeIDDisplayName,
+	ua.AttributeIDDataType,
+}
+
+type attributeKey struct {
+	nodeID string
+	attrID ua.AttributeID
+}
+
+type attributeEntry struct {
+	dv      *ua.DataValue
+	expires time.Time
+}
+
+// attributeCache memoizes the values of node attributes.
+type attributeCache struct {
+	attrs map[ua.AttributeID]bool
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[attributeKey]attributeEntry
+}
+
+func newAttributeCache(attrs []ua.AttributeID, ttl time.Duration) *attributeCache {
+	m := make(map[ua.AttributeID]bool)
+	for _, a := range attrs {
+		m[a] = true
+	}
+	return &attributeCache{
+		attrs:   m,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[attributeKey]attributeEntry),
+	}
+}
+
+// key returns the cache key for the read value id and false if the value
+// cannot be cached.
+func (a *attributeCache) key(rv *ua.ReadValueID) (attributeKey, bool) {
+	if rv == nil || rv.NodeID == nil || !a.attrs[rv.AttributeID] || rv.IndexRange != "" {
+		return attributeKey{}, false
+	}
+	if rv.DataEncoding != nil && rv.DataEncoding.Name != "" {
+		return attributeKey{}, false
+	}
+	return attributeKey{rv.NodeID.String(), rv.AttributeID}, true
+}
+
+func (a *attributeCache) get(k attributeKey) (*ua.DataValue, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	e, ok := a.entries[k]
+	if !ok {
+		return nil, false
+	}
+	if !e.expires.IsZero() && !a.now().Before(e.expires) {
+		delete(a.entries, k)
+		return nil, false
+	}
+	return e.dv, true
+}
+
+func (a *attributeCache) put(k attributeKey, dv *ua.DataValue) {
+	e := attributeEntry{dv: dv}
+	if a.ttl > 0 {
+		e.expires = a.now().Add(a.ttl)
+	}
+	a.mu.Lock()
+	a.entries[k] = e
+	a.mu.Unlock()
+}
+
+func (a *attributeCache) clear() {
+	a.mu.Lock()
+	a.entries = make(map[attributeKey]attributeEntry)
+	a.mu.Unlock()
+}
+
+// ReadCachedAttributes reads the attributes of nodes and returns the values
+// in the order of ids. Values of the attributes configured with the
+// AttributeCache option are served from the cache and only the missing
+// values are read from the server. Only values with a good status code
+// are cached. Without the AttributeCache option all values are read.
+//
+// The returned data values are shared with the cache and must not be
+// modified.
+func (c *Client) ReadCachedAttributes(ctx context.Context, ids []*ua.ReadValueID) ([]*ua.DataValue, error) {
+	stats.Client().Add("ReadCachedAttributes", 1)
+
+	results := make([]*ua.DataValue, len(ids))
+	keys := make([]attributeKey, len(ids))
+	cacheable := make([]bool, len(ids))
+
+	var misses []*ua.ReadValueID
+	var idx []int
+	for i, rv := range ids {
+		if c.attrCache != nil {
+			keys[i], cacheable[i] = c.attrCache.key(rv)
+			if cacheable[i] {
+				if dv, ok := c.attrCache.get(keys[i]); ok {
+					results[i] = dv
+					continue
+				}
+			}
+		}
+		misses = append(misses, rv)
+		idx = append(idx, i)
+	}
+	stats.Client().Add("AttributeCacheHits", int64(len(ids)-len(misses)))
+	if len(misses) == 0 {
+		return results, nil
+	}
+
+	res, err := c.Read(ctx, &ua.ReadRequest{
+		NodesToRead:        misses,
+		TimestampsToReturn: ua.TimestampsToReturnNeither,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Results) != len(misses) {
+		return nil, ua.StatusBadUnknownResponse
+	}
+	for j, dv := range res.Results {
+		i := idx[j]
+		results[i] = dv
+		if cacheable[i] && dv.Status == ua.StatusOK {
+			c.attrCache.put(keys[i], dv)
+		}
+	}
+	return results, nil
+}
+
+// ClearCache removes all values from the attribute cache and the
+// enumeration definitions cached by ReadEnum.
+func (c *Client) ClearCache() {
+	if c.attrCache != nil {
+		c.attrCache.clear()
+	}
+	c.enumsMu.Lock()
+	c.enums = nil
+	c.enumsMu.Unlock()
+}
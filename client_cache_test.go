@@ -0,0 +1,70 @@
+package opcua
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCachedAttributes(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	c, err := NewClient("opc.tcp://example.com:4840", AttributeCache(), AttributeCacheTTL(time.Minute))
+	require.NoError(t, err)
+	c.attrCache.now = func() time.Time { return now }
+
+	browseName := &ua.ReadValueID{NodeID: ua.NewNumericNodeID(0, 85), AttributeID: ua.AttributeIDBrowseName}
+	value := &ua.ReadValueID{NodeID: ua.NewNumericNodeID(0, 85), AttributeID: ua.AttributeIDValue}
+	dv := &ua.DataValue{Value: ua.MustVariant(&ua.QualifiedName{Name: "Objects"})}
+
+	k, ok := c.attrCache.key(browseName)
+	require.True(t, ok)
+	c.attrCache.put(k, dv)
+
+	// served from the cache without a connection
+	got, err := c.ReadCachedAttributes(ctx, []*ua.ReadValueID{browseName})
+	require.NoError(t, err)
+	require.Equal(t, []*ua.DataValue{dv}, got)
+
+	// values of other attributes are read from the server
+	_, err = c.ReadCachedAttributes(ctx, []*ua.ReadValueID{browseName, value})
+	require.ErrorIs(t, err, ua.StatusBadServerNotConnected)
+
+	// expired values are read from the server
+	now = now.Add(time.Minute)
+	_, err = c.ReadCachedAttributes(ctx, []*ua.ReadValueID{browseName})
+	require.ErrorIs(t, err, ua.StatusBadServerNotConnected)
+
+	now = now.Add(-time.Minute)
+	c.attrCache.put(k, dv)
+	c.ClearCache()
+	_, err = c.ReadCachedAttributes(ctx, []*ua.ReadValueID{browseName})
+	require.ErrorIs(t, err, ua.StatusBadServerNotConnected)
+}
+
+func TestAttributeCacheKey(t *testing.T) {
+	a := newAttributeCache([]ua.AttributeID{ua.AttributeIDBrowseName}, 0)
+	id := ua.NewNumericNodeID(0, 85)
+
+	tests := []struct {
+		name string
+		rv   *ua.ReadValueID
+		ok   bool
+	}{
+		{"cached attribute", &ua.ReadValueID{NodeID: id, AttributeID: ua.AttributeIDBrowseName}, true},
+		{"other attribute", &ua.ReadValueID{NodeID: id, AttributeID: ua.AttributeIDValue}, false},
+		{"index range", &ua.ReadValueID{NodeID: id, AttributeID: ua.AttributeIDBrowseName, IndexRange: "1"}, false},
+		{"data encoding", &ua.ReadValueID{NodeID: id, AttributeID: ua.AttributeIDBrowseName, DataEncoding: &ua.QualifiedName{Name: "Default Binary"}}, false},
+		{"no node id", &ua.ReadValueID{AttributeID: ua.AttributeIDBrowseName}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := a.key(tt.rv)
+			require.Equal(t, tt.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package opcua
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/ua"
+)
+
+// CallMethod calls a method of an object with the given input arguments
+// and returns the output arguments.
+//
+// The inputs can be *ua.Variant values, values of the built-in types or
+// extension object values of registered types. If the method has an
+// InputArguments property then the number of inputs and their types are
+// validated before the method is called. Go numbers are converted to the
+// built-in number type of the argument, e.g. an int to an Int32.
+func (c *Client) CallMethod(ctx context.Context, objectID, methodID *ua.NodeID, inputs ...interface{}) ([]*ua.Variant, error) {
+	args, err := c.methodArguments(ctx, methodID)
+	if err != nil {
+		return nil, err
+	}
+	if args != nil && len(args) != len(inputs) {
+		return nil, errors.Errorf("method %s: got %d input arguments, want %d", methodID, len(inputs), len(args))
+	}
+
+	vals := make([]*ua.Variant, len(inputs))
+	for i, in := range inputs {
+		var arg *ua.Argument
+		if args != nil {
+			arg = args[i]
+		}
+		v, err := callArgument(in, arg)
+		if err != nil {
+			return nil, errors.Errorf("method %s: input argument %d: %w", methodID, i, err)
+		}
+		vals[i] = v
+	}
+
+	res, err := c.Call(ctx, &ua.CallMethodRequest{
+		ObjectID:       objectID,
+		MethodID:       methodID,
+		InputArguments: vals,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != ua.StatusOK {
+		for i, s := range res.InputArgumentResults {
+			if s != ua.StatusOK {
+				return nil, errors.Errorf("method %s: input argument %d: %w", methodID, i, s)
+			}
+		}
+		return nil, res.StatusCode
+	}
+	return res.OutputArguments, nil
+}
+
+// methodArguments returns the InputArguments property of the method or
+// nil if the method does not have one or the server does not provide it.
+func (c *Client) methodArguments(ctx context.Context, methodID *ua.NodeID) ([]*ua.Argument, error) {
+	nodeID, err := c.TranslateBrowsePath(ctx, methodID, ".InputArguments")
+	if _, ok := err.(ua.StatusCode); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := c.Node(nodeID).Value(ctx)
+	if _, ok := err.(ua.StatusCode); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	eos, ok := v.Value().([]*ua.ExtensionObject)
+	if !ok {
+		return nil, nil
+	}
+	args := make([]*ua.Argument, len(eos))
+	for i, eo := range eos {
+		arg, ok := eo.Value.(*ua.Argument)
+		if !ok {
+			return nil, nil
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// numberTypes contains the Go types of the built-in number types.
+var numberTypes = map[ua.TypeID]reflect.Type{
+	ua.TypeIDSByte:  reflect.TypeOf(int8(0)),
+	ua.TypeIDByte:   reflect.TypeOf(uint8(0)),
+	ua.TypeIDInt16:  reflect.TypeOf(int16(0)),
+	ua.TypeIDUint16: reflect.TypeOf(uint16(0)),
+	ua.TypeIDInt32:  reflect.TypeOf(int32(0)),
+	ua.TypeIDUint32: reflect.TypeOf(uint32(0)),
+	ua.TypeIDInt64:  reflect.TypeOf(int64(0)),
+	ua.TypeIDUint64: reflect.TypeOf(uint64(0)),
+	ua.TypeIDFloat:  reflect.TypeOf(float32(0)),
+	ua.TypeIDDouble: reflect.TypeOf(float64(0)),
+}
+
+// argumentType returns the built-in type of an argument or zero if the
+// data type is not a built-in type.
+func argumentType(arg *ua.Argument) ua.TypeID {
+	if arg == nil || arg.DataType == nil || arg.DataType.Namespace() != 0 {
+		return 0
+	}
+	n := arg.DataType.IntID()
+	if n < uint32(ua.TypeIDBoolean) || n > uint32(ua.TypeIDDiagnosticInfo) {
+		return 0
+	}
+	return ua.TypeID(n)
+}
+
+// isNumberKind returns true for the Go kinds of integers and floats.
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// callArgument converts a Go value into a variant and validates it
+// against the argument definition if arg is not nil.
+func callArgument(in interface{}, arg *ua.Argument) (*ua.Variant, error) {
+	want := argumentType(arg)
+
+	v, ok := in.(*ua.Variant)
+	if !ok {
+		// convert Go numbers to the number type of the argument
+		if t, ok := numberTypes[want]; ok && in != nil {
+			if rv := reflect.ValueOf(in); isNumberKind(rv.Kind()) {
+				in = rv.Convert(t).Interface()
+			}
+		}
+
+		var err error
+		v, err = ua.NewVariant(in)
+		if err != nil {
+			// wrap values of registered extension object types
+			eo := ua.NewExtensionObject(in)
+			if eo.TypeID.NodeID.IntID() == 0 && eo.TypeID.NodeID.Namespace() == 0 {
+				return nil, err
+			}
+			v = ua.MustVariant(eo)
+		}
+	}
+
+	if arg == nil {
+		return v, nil
+	}
+	if want != 0 && want != ua.TypeIDVariant && v.Type() != want {
+		return nil, errors.Errorf("%s: got type %s, want %s", arg.Name, v.Type(), want)
+	}
+	isArray := v.Has(ua.VariantArrayValues)
+	switch {
+	case arg.ValueRank == -1 && isArray:
+		return nil, errors.Errorf("%s: got array, want scalar", arg.Name)
+	case arg.ValueRank >= 1 && !isArray:
+		return nil, errors.Errorf("%s: got scalar, want array", arg.Name)
+	}
+	return v, nil
+}
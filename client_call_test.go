@@ -0,0 +1,46 @@
+package opcua
+
+import (
+	"testing"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallArgument(t *testing.T) {
+	int32Arg := &ua.Argument{Name: "a", DataType: ua.NewNumericNodeID(0, id.Int32), ValueRank: -1}
+	doubleArrayArg := &ua.Argument{Name: "b", DataType: ua.NewNumericNodeID(0, id.Double), ValueRank: 1}
+	anyArg := &ua.Argument{Name: "c", DataType: ua.NewNumericNodeID(0, id.BaseDataType), ValueRank: -2}
+
+	tests := []struct {
+		name string
+		in   interface{}
+		arg  *ua.Argument
+		want *ua.Variant
+		err  string
+	}{
+		{name: "no definition", in: "x", want: ua.MustVariant("x")},
+		{name: "variant", in: ua.MustVariant(int32(1)), arg: int32Arg, want: ua.MustVariant(int32(1))},
+		{name: "convert int", in: 5, arg: int32Arg, want: ua.MustVariant(int32(5))},
+		{name: "convert float", in: 2, arg: &ua.Argument{DataType: ua.NewNumericNodeID(0, id.Double), ValueRank: -1}, want: ua.MustVariant(float64(2))},
+		{name: "any", in: true, arg: anyArg, want: ua.MustVariant(true)},
+		{name: "array", in: []float64{1, 2}, arg: doubleArrayArg, want: ua.MustVariant([]float64{1, 2})},
+		{name: "extension object", in: &ua.Range{Low: 1, High: 2}, want: ua.MustVariant(ua.NewExtensionObject(&ua.Range{Low: 1, High: 2}))},
+		{name: "wrong type", in: "x", arg: int32Arg, err: "a: got type TypeIDString, want TypeIDInt32"},
+		{name: "want scalar", in: []int32{1}, arg: int32Arg, err: "a: got array, want scalar"},
+		{name: "want array", in: 1.5, arg: doubleArrayArg, err: "b: got scalar, want array"},
+		{name: "unsupported", in: struct{}{}, arg: anyArg, err: "not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := callArgument(tt.in, tt.arg)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
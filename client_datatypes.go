@@ -0,0 +1,343 @@
+package opcua
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gopcua/opcua/debug"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/stats"
+	"github.com/gopcua/opcua/ua"
+)
+
+// LoadTypeDefinitions reads the data type hierarchy of the server and
+// registers the structured data types with ua.RegisterStructure. Values
+// of these types are then decoded into a *ua.Structure instead of an
+// extension object without a value. Enumerations and subtypes of
+// built-in types are registered with ua.RegisterBuiltinDataType so that
+// they can be used as structure fields.
+//
+// The layout of a structure is read from the DataTypeDefinition
+// attribute which requires a server which supports OPC UA 1.04 or
+// later. Structures without a definition are skipped.
+//
+// LoadTypeDefinitions should be called once after Connect.
+func (c *Client) LoadTypeDefinitions(ctx context.Context) error {
+	types, parents, err := c.browseDataTypes(ctx)
+	if err != nil {
+		return err
+	}
+
+	var structs []*ua.NodeID
+	for _, n := range types {
+		if isBuiltinDataType(n) {
+			continue
+		}
+		switch base := baseDataType(n, parents); {
+		case base == id.Structure:
+			structs = append(structs, n)
+		case base == id.Enumeration:
+			ua.RegisterBuiltinDataType(n, ua.TypeIDInt32)
+		case base > 0:
+			ua.RegisterBuiltinDataType(n, ua.TypeID(base))
+		default:
+			debug.Printf("no base type for data type %s", n)
+		}
+	}
+
+	if len(structs) == 0 {
+		return nil
+	}
+
+	nodes := make([]*ua.ReadValueID, len(structs))
+	for i, n := range structs {
+		nodes[i] = &ua.ReadValueID{NodeID: n, AttributeID: ua.AttributeIDDataTypeDefinition}
+	}
+	results, err := c.BatchRead(ctx, nodes)
+	if err != nil {
+		return err
+	}
+	for i, res := range results {
+		if res.Status != ua.StatusOK || res.Value == nil {
+			continue
+		}
+		eo, ok := res.Value.Value().(*ua.ExtensionObject)
+		if !ok {
+			continue
+		}
+		def, ok := eo.Value.(*ua.StructureDefinition)
+		if !ok {
+			continue
+		}
+		// abstract structures have no encoding and fields of these
+		// types are encoded as extension objects.
+		if def.DefaultEncodingID == nil || def.DefaultEncodingID.Equal(ua.NewTwoByteNodeID(0)) {
+			ua.RegisterBuiltinDataType(structs[i], ua.TypeIDExtensionObject)
+			continue
+		}
+		if err := ua.RegisterStructure(structs[i], def); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// browseDataTypes returns all subtypes of BaseDataType and their
+// parents indexed by the node id of the data type.
+func (c *Client) browseDataTypes(ctx context.Context) ([]*ua.NodeID, map[string]*ua.NodeID, error) {
+	var types []*ua.NodeID
+	parents := map[string]*ua.NodeID{}
+	level := []*ua.NodeID{ua.NewNumericNodeID(0, id.BaseDataType)}
+	for len(level) > 0 {
+		var next []*ua.NodeID
+		for _, ch := range chunkRanges(len(level), c.maxNodesPerBrowse(ctx)) {
+			nodes := level[ch[0]:ch[1]]
+			desc := make([]*ua.BrowseDescription, len(nodes))
+			for i, n := range nodes {
+				desc[i] = &ua.BrowseDescription{
+					NodeID:          n,
+					BrowseDirection: ua.BrowseDirectionForward,
+					ReferenceTypeID: ua.NewNumericNodeID(0, id.HasSubtype),
+					IncludeSubtypes: false,
+					NodeClassMask:   uint32(ua.NodeClassDataType),
+					ResultMask:      uint32(ua.BrowseResultMaskNone),
+				}
+			}
+			res, err := c.Browse(ctx, &ua.BrowseRequest{
+				View:                          &ua.ViewDescription{ViewID: ua.NewTwoByteNodeID(0)},
+				RequestedMaxReferencesPerNode: 0,
+				NodesToBrowse:                 desc,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(res.Results) != len(nodes) {
+				return nil, nil, ua.StatusBadUnknownResponse
+			}
+			for i, r := range res.Results {
+				refs, err := c.browseAll(ctx, r)
+				if err != nil {
+					return nil, nil, err
+				}
+				for _, ref := range refs {
+					child := ref.NodeID.NodeID
+					if _, ok := parents[child.String()]; ok {
+						continue
+					}
+					parents[child.String()] = nodes[i]
+					types = append(types, child)
+					next = append(next, child)
+				}
+			}
+		}
+		level = next
+	}
+	return types, parents, nil
+}
+
+// browseAll returns the references of the browse result and follows
+// the continuation points.
+func (c *Client) browseAll(ctx context.Context, r *ua.BrowseResult) ([]*ua.ReferenceDescription, error) {
+	if r.StatusCode != ua.StatusOK {
+		return nil, r.StatusCode
+	}
+	refs := r.References
+	for len(r.ContinuationPoint) > 0 {
+		res, err := c.BrowseNext(ctx, &ua.BrowseNextRequest{
+			ContinuationPoints: [][]byte{r.ContinuationPoint},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(res.Results) != 1 {
+			return nil, ua.StatusBadUnknownResponse
+		}
+		r = res.Results[0]
+		if r.StatusCode != ua.StatusOK {
+			return nil, r.StatusCode
+		}
+		refs = append(refs, r.References...)
+	}
+	return refs, nil
+}
+
+// maxNodesPerBrowse returns the number of nodes per BrowseRequest.
+func (c *Client) maxNodesPerBrowse(ctx context.Context) int {
+	l, err := c.OperationLimits(ctx)
+	if err != nil || l.MaxNodesPerBrowse == 0 || l.MaxNodesPerBrowse > DefaultBatchReadChunkSize {
+		return DefaultBatchReadChunkSize
+	}
+	return int(l.MaxNodesPerBrowse)
+}
+
+// isBuiltinDataType returns true if n is one of the data types in
+// namespace zero which the ua package encodes without a registration.
+func isBuiltinDataType(n *ua.NodeID) bool {
+	if n.Namespace() != 0 {
+		return false
+	}
+	v := n.IntID()
+	return v >= uint32(ua.TypeIDBoolean) && v <= id.Enumeration
+}
+
+// baseDataType walks up the data type hierarchy and returns the id of
+// the first built-in data type, Structure or Enumeration. It returns
+// zero if there is none.
+func baseDataType(n *ua.NodeID, parents map[string]*ua.NodeID) uint32 {
+	for n != nil {
+		if n.Namespace() == 0 {
+			switch v := n.IntID(); {
+			case v == id.Structure, v == id.Enumeration:
+				return v
+			case v >= uint32(ua.TypeIDBoolean) && v <= uint32(ua.TypeIDDiagnosticInfo):
+				return v
+			}
+		}
+		n = parents[n.String()]
+	}
+	return 0
+}
+
+// EnumValue is the value of an enumeration together with its label.
+type EnumValue struct {
+	// Value is the integer value of the enumeration.
+	Value int64
+
+	// Label is the display name of the value from the EnumStrings or
+	// EnumValues property of the data type. It is nil if the data type
+	// does not define the value.
+	Label *ua.LocalizedText
+}
+
+// String returns the text of the label or the integer value if the
+// label is unknown.
+func (e *EnumValue) String() string {
+	if e.Label == nil || e.Label.Text == "" {
+		return strconv.FormatInt(e.Value, 10)
+	}
+	return e.Label.Text
+}
+
+// ReadEnum reads the value of a variable with an enumeration data type
+// and returns it together with its label, e.g. "Running" for the state
+// of the server. The labels are read from the EnumStrings or EnumValues
+// property of the data type and are cached per data type until
+// ClearCache is called. Errors are returned like in ReadAttribute.
+func (c *Client) ReadEnum(ctx context.Context, nodeID *ua.NodeID) (*EnumValue, error) {
+	stats.Client().Add("ReadEnum", 1)
+
+	attrs := []ua.AttributeID{ua.AttributeIDValue, ua.AttributeIDDataType}
+	ids := make([]*ua.ReadValueID, len(attrs))
+	for i, a := range attrs {
+		ids[i] = &ua.ReadValueID{NodeID: nodeID, AttributeID: a}
+	}
+	results, err := c.ReadCachedAttributes(ctx, ids)
+	if err != nil {
+		return nil, errors.Errorf("read enum %s: %w", nodeID, err)
+	}
+	for i, dv := range results {
+		if uint32(dv.Status)&0x80000000 != 0 {
+			return nil, errors.Errorf("read %s of %s: %w", attrs[i], nodeID, dv.Status)
+		}
+	}
+
+	v := results[0].Value
+	if v == nil {
+		return nil, errors.Errorf("read enum %s: no value", nodeID)
+	}
+	switch v.Type() {
+	case ua.TypeIDSByte, ua.TypeIDInt16, ua.TypeIDInt32, ua.TypeIDInt64:
+		if v.ArrayLength() > 0 {
+			return nil, errors.Errorf("read enum %s: value is an array", nodeID)
+		}
+	default:
+		return nil, errors.Errorf("read enum %s: value has type %s", nodeID, v.Type())
+	}
+	var dt *ua.NodeID
+	if results[1].Value != nil {
+		switch x := results[1].Value.Value().(type) {
+		case *ua.NodeID:
+			dt = x
+		case *ua.ExpandedNodeID:
+			dt = x.NodeID
+		}
+	}
+	if dt == nil {
+		return nil, errors.Errorf("read enum %s: no data type", nodeID)
+	}
+
+	labels, err := c.enumLabels(ctx, dt)
+	if err != nil {
+		return nil, errors.Errorf("read enum %s: %w", nodeID, err)
+	}
+	return &EnumValue{Value: v.Int(), Label: labels[v.Int()]}, nil
+}
+
+// enumLabels returns the labels of the enumeration data type dt from
+// the cache or reads them from the server.
+func (c *Client) enumLabels(ctx context.Context, dt *ua.NodeID) (map[int64]*ua.LocalizedText, error) {
+	c.enumsMu.Lock()
+	labels, ok := c.enums[dt.String()]
+	c.enumsMu.Unlock()
+	if ok {
+		return labels, nil
+	}
+
+	props, err := c.Properties(ctx, dt)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"0:EnumStrings", "0:EnumValues"} {
+		dv, ok := props[name]
+		if !ok {
+			continue
+		}
+		if uint32(dv.Status)&0x80000000 != 0 {
+			return nil, errors.Errorf("read %s of %s: %w", name, dt, dv.Status)
+		}
+		labels, err = parseEnumLabels(dv.Value)
+		if err != nil {
+			return nil, errors.Errorf("data type %s: %w", dt, err)
+		}
+		break
+	}
+	if labels == nil {
+		return nil, errors.Errorf("data type %s has no EnumStrings or EnumValues property", dt)
+	}
+
+	c.enumsMu.Lock()
+	if c.enums == nil {
+		c.enums = make(map[string]map[int64]*ua.LocalizedText)
+	}
+	c.enums[dt.String()] = labels
+	c.enumsMu.Unlock()
+	return labels, nil
+}
+
+// parseEnumLabels returns the labels of an enumeration indexed by value
+// from the value of the EnumStrings or EnumValues property. The value of
+// an EnumStrings entry is its index.
+func parseEnumLabels(v *ua.Variant) (map[int64]*ua.LocalizedText, error) {
+	if v == nil {
+		return nil, errors.New("missing enum definition")
+	}
+	labels := make(map[int64]*ua.LocalizedText)
+	switch x := v.Value().(type) {
+	case []*ua.LocalizedText:
+		for i, lt := range x {
+			labels[int64(i)] = lt
+		}
+	case []*ua.ExtensionObject:
+		for _, eo := range x {
+			ev, ok := eo.Value.(*ua.EnumValueType)
+			if !ok {
+				return nil, errors.Errorf("invalid enum value %T", eo.Value)
+			}
+			labels[ev.Value] = ev.DisplayName
+		}
+	default:
+		return nil, errors.Errorf("invalid enum definition %T", v.Value())
+	}
+	return labels, nil
+}
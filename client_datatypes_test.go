@@ -0,0 +1,65 @@
+package opcua
+
+import (
+	"testing"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseDataType(t *testing.T) {
+	parents := map[string]*ua.NodeID{
+		"i=22":       ua.NewNumericNodeID(0, id.BaseDataType),
+		"i=29":       ua.NewNumericNodeID(0, id.BaseDataType),
+		"i=290":      ua.NewNumericNodeID(0, id.Double),
+		"ns=2;i=1":   ua.NewNumericNodeID(0, id.Structure),
+		"ns=2;i=2":   ua.NewNumericNodeID(2, 1),
+		"ns=2;i=3":   ua.NewNumericNodeID(0, id.Enumeration),
+		"ns=2;i=4":   ua.NewNumericNodeID(0, id.Duration),
+		"ns=2;s=abc": ua.NewNumericNodeID(3, 1),
+	}
+
+	tests := []struct {
+		n    *ua.NodeID
+		want uint32
+	}{
+		{ua.NewNumericNodeID(2, 1), id.Structure},
+		{ua.NewNumericNodeID(2, 2), id.Structure},
+		{ua.NewNumericNodeID(2, 3), id.Enumeration},
+		{ua.NewNumericNodeID(2, 4), id.Double},
+		{ua.NewStringNodeID(2, "abc"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.n.String(), func(t *testing.T) {
+			require.Equal(t, tt.want, baseDataType(tt.n, parents))
+		})
+	}
+}
+
+func TestParseEnumLabels(t *testing.T) {
+	strs := ua.MustVariant([]*ua.LocalizedText{ua.NewLocalizedText("Off"), ua.NewLocalizedText("On")})
+	labels, err := parseEnumLabels(strs)
+	require.NoError(t, err)
+	require.Equal(t, map[int64]*ua.LocalizedText{0: ua.NewLocalizedText("Off"), 1: ua.NewLocalizedText("On")}, labels)
+
+	vals := ua.MustVariant([]*ua.ExtensionObject{
+		ua.NewExtensionObject(&ua.EnumValueType{Value: 1, DisplayName: ua.NewLocalizedText("Low")}),
+		ua.NewExtensionObject(&ua.EnumValueType{Value: 10, DisplayName: ua.NewLocalizedText("High")}),
+	})
+	labels, err = parseEnumLabels(vals)
+	require.NoError(t, err)
+	require.Equal(t, map[int64]*ua.LocalizedText{1: ua.NewLocalizedText("Low"), 10: ua.NewLocalizedText("High")}, labels)
+
+	_, err = parseEnumLabels(nil)
+	require.Error(t, err)
+	_, err = parseEnumLabels(ua.MustVariant(int32(1)))
+	require.Error(t, err)
+	_, err = parseEnumLabels(ua.MustVariant([]*ua.ExtensionObject{ua.NewExtensionObject(&ua.Argument{})}))
+	require.Error(t, err)
+}
+
+func TestEnumValueString(t *testing.T) {
+	require.Equal(t, "Running", (&EnumValue{Value: 0, Label: ua.NewLocalizedText("Running")}).String())
+	require.Equal(t, "7", (&EnumValue{Value: 7}).String())
+}
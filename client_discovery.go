@@ -0,0 +1,145 @@
+package opcua
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/gopcua/opcua/debug"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/stats"
+	"github.com/gopcua/opcua/ua"
+)
+
+// DefaultRegisterServerInterval is the interval in which
+// RegisterServerPeriodically renews the registration of a server.
+// Discovery servers remove registrations which have not been renewed
+// for some time and the specification recommends to register at least
+// every ten minutes.
+const DefaultRegisterServerInterval = 10 * time.Minute
+
+// RegisterServer registers a server with a discovery server, e.g. a
+// Local Discovery Server (LDS). The RegisterServer2 service is used
+// if the discovery server supports it.
+//
+// The registration is sent as is. Set IsOnline to false to remove the
+// server from the discovery server. If SemaphoreFilePath is set then the
+// discovery server removes the registration as soon as the file no
+// longer exists. The file must exist when the server registers itself
+// as online.
+func RegisterServer(ctx context.Context, discoveryURL string, app *ua.RegisteredServer, opts ...Option) error {
+	if err := checkRegisteredServer(app); err != nil {
+		return err
+	}
+
+	opts = append(opts, AutoReconnect(false))
+	c, err := NewClient(discoveryURL, opts...)
+	if err != nil {
+		return err
+	}
+	if err := c.Dial(ctx); err != nil {
+		return err
+	}
+	defer c.Close(ctx)
+	return c.registerServer(ctx, app)
+}
+
+// RegisterServerPeriodically registers a server as online with a
+// discovery server and renews the registration in the given interval
+// until ctx is cancelled. Then the server is registered as offline so
+// that the discovery server removes it immediately. If interval is not
+// positive then DefaultRegisterServerInterval is used.
+//
+// An error is returned if the first registration fails. Failed renewals
+// are retried in the next interval. The result of the final offline
+// registration is returned when ctx is cancelled.
+func RegisterServerPeriodically(ctx context.Context, discoveryURL string, app *ua.RegisteredServer, interval time.Duration, opts ...Option) error {
+	if app == nil {
+		return checkRegisteredServer(app)
+	}
+	if interval <= 0 {
+		interval = DefaultRegisterServerInterval
+	}
+
+	online, offline := *app, *app
+	online.IsOnline, offline.IsOnline = true, false
+
+	if err := RegisterServer(ctx, discoveryURL, &online, opts...); err != nil {
+		return err
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultDialTimeout)
+			defer cancel()
+			return RegisterServer(ctx, discoveryURL, &offline, opts...)
+
+		case <-t.C:
+			if err := RegisterServer(ctx, discoveryURL, &online, opts...); err != nil && ctx.Err() == nil {
+				debug.Printf("renewing the registration with %s failed: %s", discoveryURL, err)
+			}
+		}
+	}
+}
+
+// checkRegisteredServer returns an error if the registration is
+// missing required fields or the semaphore file of an online server
+// does not exist.
+func checkRegisteredServer(app *ua.RegisteredServer) error {
+	switch {
+	case app == nil:
+		return errors.Errorf("registered server is nil")
+	case app.ServerURI == "":
+		return errors.Errorf("registered server has no server uri")
+	}
+	if app.IsOnline && app.SemaphoreFilePath != "" {
+		if _, err := os.Stat(app.SemaphoreFilePath); err != nil {
+			return errors.Errorf("semaphore file of server %s: %w", app.ServerURI, err)
+		}
+	}
+	return nil
+}
+
+// registerServer registers the server with RegisterServer2 and falls
+// back to RegisterServer if the discovery server does not support it.
+func (c *Client) registerServer(ctx context.Context, app *ua.RegisteredServer) error {
+	_, err := c.RegisterServer2(ctx, app)
+	if errors.Is(err, ua.StatusBadServiceUnsupported) {
+		_, err = c.RegisterServer(ctx, app)
+	}
+	return err
+}
+
+// RegisterServer registers a server with the discovery server.
+func (c *Client) RegisterServer(ctx context.Context, app *ua.RegisteredServer) (*ua.RegisterServerResponse, error) {
+	stats.Client().Add("RegisterServer", 1)
+
+	req := &ua.RegisterServerRequest{
+		Server: app,
+	}
+	var res *ua.RegisterServerResponse
+	err := c.Send(ctx, req, func(v ua.Response) error {
+		return safeAssign(v, &res)
+	})
+	return res, err
+}
+
+// RegisterServer2 registers a server with the discovery server and
+// passes additional discovery configurations, e.g. a
+// ua.MdnsDiscoveryConfiguration to announce the server via mDNS.
+func (c *Client) RegisterServer2(ctx context.Context, app *ua.RegisteredServer, cfgs ...*ua.ExtensionObject) (*ua.RegisterServer2Response, error) {
+	stats.Client().Add("RegisterServer2", 1)
+
+	req := &ua.RegisterServer2Request{
+		Server:                 app,
+		DiscoveryConfiguration: cfgs,
+	}
+	var res *ua.RegisterServer2Response
+	err := c.Send(ctx, req, func(v ua.Response) error {
+		return safeAssign(v, &res)
+	})
+	return res, err
+}
@@ -0,0 +1,39 @@
+package opcua
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRegisteredServer(t *testing.T) {
+	sem := filepath.Join(t.TempDir(), "server.sem")
+	require.NoError(t, os.WriteFile(sem, nil, 0644))
+	missing := filepath.Join(t.TempDir(), "missing.sem")
+
+	tests := []struct {
+		name string
+		app  *ua.RegisteredServer
+		err  bool
+	}{
+		{name: "nil", app: nil, err: true},
+		{name: "no server uri", app: &ua.RegisteredServer{IsOnline: true}, err: true},
+		{name: "online", app: &ua.RegisteredServer{ServerURI: "urn:a", IsOnline: true}},
+		{name: "semaphore file", app: &ua.RegisteredServer{ServerURI: "urn:a", IsOnline: true, SemaphoreFilePath: sem}},
+		{name: "missing semaphore file", app: &ua.RegisteredServer{ServerURI: "urn:a", IsOnline: true, SemaphoreFilePath: missing}, err: true},
+		{name: "offline without semaphore file", app: &ua.RegisteredServer{ServerURI: "urn:a", SemaphoreFilePath: missing}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRegisteredServer(tt.app)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package opcua
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/gopcua/opcua/debug"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/stats"
+	"github.com/gopcua/opcua/ua"
+)
+
+// DefaultHistoryMinWindow is the smallest time window into which
+// HistoryReadRawStream splits a request when the server has no free
+// continuation points.
+const DefaultHistoryMinWindow = time.Second
+
+// HistoryReadOption is an option function type to modify a history read
+// stream.
+type HistoryReadOption func(*historyReadConfig)
+
+type historyReadConfig struct {
+	numValuesPerNode uint32
+	returnBounds     bool
+	minWindow        time.Duration
+}
+
+func newHistoryReadConfig(opts ...HistoryReadOption) *historyReadConfig {
+	cfg := &historyReadConfig{minWindow: DefaultHistoryMinWindow}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// HistoryNumValuesPerNode sets the maximum number of values the server
+// returns per response. Zero lets the server decide.
+func HistoryNumValuesPerNode(n uint32) HistoryReadOption {
+	return func(cfg *historyReadConfig) {
+		cfg.numValuesPerNode = n
+	}
+}
+
+// HistoryReturnBounds requests the bounding values of the time range.
+func HistoryReturnBounds(b bool) HistoryReadOption {
+	return func(cfg *historyReadConfig) {
+		cfg.returnBounds = b
+	}
+}
+
+// HistoryMinWindow sets the smallest time window into which a request
+// is split when the server reports BadNoContinuationPoints. Values
+// below or equal to zero are ignored.
+func HistoryMinWindow(d time.Duration) HistoryReadOption {
+	return func(cfg *historyReadConfig) {
+		if d > 0 {
+			cfg.minWindow = d
+		}
+	}
+}
+
+// HistoryReadRawStream returns an iterator over the raw historical values
+// of a node between start and end. The values are read page by page and
+// the continuation points are followed until the time range is
+// exhausted.
+//
+// If the server has no free continuation points the time range is split
+// in two halves which are read one after the other until the window is
+// smaller than the minimum window. The bounding values are only returned
+// for the start and end of the whole time range and not for the split
+// points. Continuation points which have not been consumed when the
+// iteration ends early are released.
+func (c *Client) HistoryReadRawStream(ctx context.Context, nodeID *ua.NodeID, start, end time.Time, opts ...HistoryReadOption) iter.Seq2[*ua.DataValue, error] {
+	cfg := newHistoryReadConfig(opts...)
+	return func(yield func(*ua.DataValue, error) bool) {
+		windows := []historyWindow{{start: start, end: end}}
+		for len(windows) > 0 {
+			w := windows[0]
+			windows = windows[1:]
+
+			split, ok := c.historyReadRawWindow(ctx, nodeID, w, cfg, yield)
+			if !ok {
+				return
+			}
+			if split {
+				mid := w.start.Add(w.end.Sub(w.start) / 2)
+				debug.Printf("history read of %s: no continuation points, splitting at %s", nodeID, mid)
+				windows = append([]historyWindow{
+					{start: w.start, end: mid, splitStart: w.splitStart, splitEnd: true},
+					{start: mid, end: w.end, splitStart: true, splitEnd: w.splitEnd},
+				}, windows...)
+			}
+		}
+	}
+}
+
+// historyWindow is a time window of a raw history read. splitStart and
+// splitEnd are set if the window starts or ends at a point where a
+// larger window has been split.
+type historyWindow struct {
+	start, end           time.Time
+	splitStart, splitEnd bool
+}
+
+// skip returns true for the bounding values at the split points of the
+// window. The start time of a window is part of the window while the
+// end time belongs to the next one. Hence a value at or beyond the end
+// of the window and a value before its start are returned as values of
+// the adjacent window. A missing bound at the start is dropped as well.
+func (w historyWindow) skip(v *ua.DataValue) bool {
+	if v == nil {
+		return false
+	}
+	t := v.SourceTimestamp
+	if t.IsZero() {
+		t = v.ServerTimestamp
+	}
+	if t.IsZero() {
+		return false
+	}
+	before := func(a, b time.Time) bool { return a.Before(b) }
+	if w.end.Before(w.start) {
+		// values are returned in reverse order
+		before = func(a, b time.Time) bool { return a.After(b) }
+	}
+	switch {
+	case w.splitEnd && !before(t, w.end):
+		return true
+	case w.splitStart && before(t, w.start):
+		return true
+	case w.splitStart && t.Equal(w.start):
+		return v.Status == ua.StatusBadBoundNotFound || v.Status == ua.StatusBadBoundNotSupported
+	}
+	return false
+}
+
+// historyReadRawWindow reads all values of a single time window and
+// yields them. It returns split=true if the window should be split
+// since the server has no free continuation points and ok=false if
+// the iteration has ended.
+func (c *Client) historyReadRawWindow(ctx context.Context, nodeID *ua.NodeID, w historyWindow, cfg *historyReadConfig, yield func(*ua.DataValue, error) bool) (split, ok bool) {
+	details := &ua.ReadRawModifiedDetails{
+		StartTime:        w.start,
+		EndTime:          w.end,
+		NumValuesPerNode: cfg.numValuesPerNode,
+		ReturnBounds:     cfg.returnBounds,
+	}
+	window := w.end.Sub(w.start)
+	if window < 0 {
+		window = -window
+	}
+	canSplit := window > cfg.minWindow
+
+	var cp []byte
+	for first := true; first || len(cp) > 0; first = false {
+		if err := ctx.Err(); err != nil {
+			c.releaseHistoryContinuationPoint(ctx, nodeID, details, cp)
+			yield(nil, err)
+			return false, false
+		}
+
+		res, err := c.HistoryReadRawModified(ctx, []*ua.HistoryReadValueID{{NodeID: nodeID, DataEncoding: &ua.QualifiedName{}, ContinuationPoint: cp}}, details)
+		if err == nil && len(res.Results) != 1 {
+			err = ua.StatusBadUnknownResponse
+		}
+		if err == nil && res.Results[0].StatusCode == ua.StatusBadNoContinuationPoints {
+			err = ua.StatusBadNoContinuationPoints
+		}
+		if err == ua.StatusBadNoContinuationPoints && first && canSplit {
+			return true, true
+		}
+		if err != nil {
+			yield(nil, err)
+			return false, false
+		}
+
+		r := res.Results[0]
+		cp = r.ContinuationPoint
+		if uint32(r.StatusCode)&0x80000000 != 0 {
+			c.releaseHistoryContinuationPoint(ctx, nodeID, details, cp)
+			yield(nil, r.StatusCode)
+			return false, false
+		}
+		if r.HistoryData == nil {
+			continue
+		}
+		data, ok := r.HistoryData.Value.(*ua.HistoryData)
+		if !ok {
+			continue
+		}
+		for _, v := range data.DataValues {
+			if cfg.returnBounds && w.skip(v) {
+				continue
+			}
+			if !yield(v, nil) {
+				c.releaseHistoryContinuationPoint(ctx, nodeID, details, cp)
+				return false, false
+			}
+		}
+	}
+	return false, true
+}
+
+// releaseHistoryContinuationPoint releases a continuation point of a
+// raw history read. The request is sent even if ctx has been cancelled.
+func (c *Client) releaseHistoryContinuationPoint(ctx context.Context, nodeID *ua.NodeID, details *ua.ReadRawModifiedDetails, cp []byte) {
+	if len(cp) == 0 {
+		return
+	}
+	stats.Client().Add("HistoryReadReleaseContinuationPoints", 1)
+
+	req := &ua.HistoryReadRequest{
+		TimestampsToReturn:        ua.TimestampsToReturnBoth,
+		ReleaseContinuationPoints: true,
+		NodesToRead:               []*ua.HistoryReadValueID{{NodeID: nodeID, DataEncoding: &ua.QualifiedName{}, ContinuationPoint: cp}},
+		HistoryReadDetails: &ua.ExtensionObject{
+			TypeID:       ua.NewFourByteExpandedNodeID(0, id.ReadRawModifiedDetails_Encoding_DefaultBinary),
+			EncodingMask: ua.ExtensionObjectBinary,
+			Value:        details,
+		},
+	}
+	err := c.Send(context.WithoutCancel(ctx), req, func(v ua.Response) error {
+		return nil
+	})
+	if err != nil {
+		debug.Printf("releasing history continuation point of %s failed: %s", nodeID, err)
+	}
+}
+
+// DefaultAggregateConfiguration returns an aggregate configuration which
+// uses the default settings of the server.
+func DefaultAggregateConfiguration() *ua.AggregateConfiguration {
+	return &ua.AggregateConfiguration{UseServerCapabilitiesDefaults: true}
+}
+
+// AggregateConfiguration returns an aggregate configuration which
+// overrides the default settings of the server. percentDataBad and
+// percentDataGood are percentages between 0 and 100.
+//
+// See Part 13, 4.2.1.2
+func AggregateConfiguration(treatUncertainAsBad bool, percentDataBad, percentDataGood uint8, useSlopedExtrapolation bool) *ua.AggregateConfiguration {
+	return &ua.AggregateConfiguration{
+		TreatUncertainAsBad:    treatUncertainAsBad,
+		PercentDataBad:         percentDataBad,
+		PercentDataGood:        percentDataGood,
+		UseSlopedExtrapolation: useSlopedExtrapolation,
+	}
+}
+
+// AggregateFunction returns the node id of the standard aggregate
+// function with the given name, e.g. "Average", "Minimum", "Maximum"
+// or "TimeAverage".
+func AggregateFunction(name string) (*ua.NodeID, error) {
+	v, ok := id.AggregateFunctionID(name)
+	if !ok {
+		return nil, errors.Errorf("unknown aggregate function %q", name)
+	}
+	return ua.NewNumericNodeID(0, v), nil
+}
+
+// HistoryReadAggregate reads the processed values of the aggregate for
+// all nodes between start and end with one value per interval. The
+// continuation points are followed until all values have been read.
+//
+// The values of nodeIDs[i] are returned in result[i]. If cfg is nil the
+// default aggregate configuration of the server is used.
+func (c *Client) HistoryReadAggregate(ctx context.Context, nodeIDs []*ua.NodeID, aggregate *ua.NodeID, start, end time.Time, interval time.Duration, cfg *ua.AggregateConfiguration) ([][]*ua.DataValue, error) {
+	if cfg == nil {
+		cfg = DefaultAggregateConfiguration()
+	}
+
+	result := make([][]*ua.DataValue, len(nodeIDs))
+	cps := make([][]byte, len(nodeIDs))
+	pending := make([]int, len(nodeIDs))
+	for i := range nodeIDs {
+		pending[i] = i
+	}
+
+	for len(pending) > 0 {
+		nodes := make([]*ua.HistoryReadValueID, len(pending))
+		aggregates := make([]*ua.NodeID, len(pending))
+		for i, idx := range pending {
+			nodes[i] = &ua.HistoryReadValueID{NodeID: nodeIDs[idx], DataEncoding: &ua.QualifiedName{}, ContinuationPoint: cps[idx]}
+			aggregates[i] = aggregate
+		}
+		details := &ua.ReadProcessedDetails{
+			StartTime:              start,
+			EndTime:                end,
+			ProcessingInterval:     float64(interval) / float64(time.Millisecond),
+			AggregateType:          aggregates,
+			AggregateConfiguration: cfg,
+		}
+
+		res, err := c.HistoryReadProcessed(ctx, nodes, details)
+		if err != nil {
+			return nil, err
+		}
+		if len(res.Results) != len(nodes) {
+			return nil, ua.StatusBadUnknownResponse
+		}
+
+		var next []int
+		for i, r := range res.Results {
+			idx := pending[i]
+			if uint32(r.StatusCode)&0x80000000 != 0 {
+				return nil, errors.Errorf("history read of %s failed: %w", nodeIDs[idx], r.StatusCode)
+			}
+			if r.HistoryData != nil {
+				if data, ok := r.HistoryData.Value.(*ua.HistoryData); ok {
+					result[idx] = append(result[idx], data.DataValues...)
+				}
+			}
+			cps[idx] = r.ContinuationPoint
+			if len(r.ContinuationPoint) > 0 {
+				next = append(next, idx)
+			}
+		}
+		pending = next
+	}
+	return result, nil
+}
+
+// HistoryUpdateData executes a synchronous HistoryUpdate request which
+// inserts, replaces or removes raw historical values. Use
+// HistoryUpdateValueResults to match the operation results to the
+// values.
+//
+// Part 11, 6.9.2
+func (c *Client) HistoryUpdateData(ctx context.Context, updates []*ua.UpdateDataDetails) (*ua.HistoryUpdateResponse, error) {
+	stats.Client().Add("HistoryUpdateData", 1)
+	stats.Client().Add("UpdateDataDetails", int64(len(updates)))
+
+	req := &ua.HistoryUpdateRequest{
+		HistoryUpdateDetails: make([]*ua.ExtensionObject, len(updates)),
+	}
+	for i, u := range updates {
+		req.HistoryUpdateDetails[i] = &ua.ExtensionObject{
+			TypeID:       ua.NewFourByteExpandedNodeID(0, id.UpdateDataDetails_Encoding_DefaultBinary),
+			EncodingMask: ua.ExtensionObjectBinary,
+			Value:        u,
+		}
+	}
+
+	var res *ua.HistoryUpdateResponse
+	err := c.Send(ctx, req, func(v ua.Response) error {
+		return safeAssign(v, &res)
+	})
+	return res, err
+}
+
+// NewInsertDataDetails returns the details to insert new values into the
+// history of a node. Values which already exist are rejected with
+// BadEntryExists.
+func NewInsertDataDetails(nodeID *ua.NodeID, values ...*ua.DataValue) *ua.UpdateDataDetails {
+	return &ua.UpdateDataDetails{NodeID: nodeID, PerformInsertReplace: ua.PerformUpdateTypeInsert, UpdateValues: values}
+}
+
+// NewReplaceDataDetails returns the details to replace existing values in
+// the history of a node. Values which do not exist are rejected with
+// BadNoEntryExists.
+func NewReplaceDataDetails(nodeID *ua.NodeID, values ...*ua.DataValue) *ua.UpdateDataDetails {
+	return &ua.UpdateDataDetails{NodeID: nodeID, PerformInsertReplace: ua.PerformUpdateTypeReplace, UpdateValues: values}
+}
+
+// NewUpdateDataDetails returns the details to insert or replace values
+// in the history of a node.
+func NewUpdateDataDetails(nodeID *ua.NodeID, values ...*ua.DataValue) *ua.UpdateDataDetails {
+	return &ua.UpdateDataDetails{NodeID: nodeID, PerformInsertReplace: ua.PerformUpdateTypeUpdate, UpdateValues: values}
+}
+
+// HistoryUpdateValueResult is the result of the update of a single
+// historical value.
+type HistoryUpdateValueResult struct {
+	NodeID *ua.NodeID
+	Value  *ua.DataValue
+	Status ua.StatusCode
+}
+
+// HistoryUpdateValueResults matches the operation results of a
+// HistoryUpdate response to the values of the updates. The results are
+// returned in the order of the updates and their values. If the server
+// rejected an update as a whole then all of its values get the status
+// code of the update.
+func HistoryUpdateValueResults(updates []*ua.UpdateDataDetails, res *ua.HistoryUpdateResponse) ([]*HistoryUpdateValueResult, error) {
+	if res == nil || len(res.Results) != len(updates) {
+		return nil, ua.StatusBadUnknownResponse
+	}
+
+	var results []*HistoryUpdateValueResult
+	for i, u := range updates {
+		r := res.Results[i]
+		if len(r.OperationResults) != 0 && len(r.OperationResults) != len(u.UpdateValues) {
+			return nil, ua.StatusBadUnknownResponse
+		}
+		for j, v := range u.UpdateValues {
+			status := r.StatusCode
+			if len(r.OperationResults) > 0 {
+				status = r.OperationResults[j]
+			}
+			results = append(results, &HistoryUpdateValueResult{NodeID: u.NodeID, Value: v, Status: status})
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,110 @@
+package opcua
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateFunction(t *testing.T) {
+	tests := []struct {
+		name string
+		want *ua.NodeID
+	}{
+		{"Average", ua.NewNumericNodeID(0, id.AggregateFunction_Average)},
+		{"timeaverage", ua.NewNumericNodeID(0, id.AggregateFunction_TimeAverage)},
+		{"AggregateFunction_Minimum", ua.NewNumericNodeID(0, id.AggregateFunction_Minimum)},
+		{"aggregatefunction_maximum", ua.NewNumericNodeID(0, id.AggregateFunction_Maximum)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AggregateFunction(tt.name)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := AggregateFunction("Server")
+	require.Error(t, err)
+}
+
+func TestHistoryUpdateValueResults(t *testing.T) {
+	n1, n2 := ua.NewNumericNodeID(1, 1), ua.NewNumericNodeID(1, 2)
+	v1, v2, v3 := &ua.DataValue{}, &ua.DataValue{}, &ua.DataValue{}
+	updates := []*ua.UpdateDataDetails{
+		NewInsertDataDetails(n1, v1, v2),
+		NewReplaceDataDetails(n2, v3),
+	}
+
+	t.Run("operation results", func(t *testing.T) {
+		res := &ua.HistoryUpdateResponse{
+			Results: []*ua.HistoryUpdateResult{
+				{StatusCode: ua.StatusOK, OperationResults: []ua.StatusCode{ua.StatusOK, ua.StatusBadEntryExists}},
+				{StatusCode: ua.StatusOK, OperationResults: []ua.StatusCode{ua.StatusOK}},
+			},
+		}
+		got, err := HistoryUpdateValueResults(updates, res)
+		require.NoError(t, err)
+		want := []*HistoryUpdateValueResult{
+			{NodeID: n1, Value: v1, Status: ua.StatusOK},
+			{NodeID: n1, Value: v2, Status: ua.StatusBadEntryExists},
+			{NodeID: n2, Value: v3, Status: ua.StatusOK},
+		}
+		require.Equal(t, want, got)
+	})
+
+	t.Run("update rejected", func(t *testing.T) {
+		res := &ua.HistoryUpdateResponse{
+			Results: []*ua.HistoryUpdateResult{
+				{StatusCode: ua.StatusOK, OperationResults: []ua.StatusCode{ua.StatusOK, ua.StatusOK}},
+				{StatusCode: ua.StatusBadNodeIDUnknown},
+			},
+		}
+		got, err := HistoryUpdateValueResults(updates, res)
+		require.NoError(t, err)
+		require.Equal(t, ua.StatusBadNodeIDUnknown, got[2].Status)
+	})
+
+	t.Run("result mismatch", func(t *testing.T) {
+		_, err := HistoryUpdateValueResults(updates, &ua.HistoryUpdateResponse{})
+		require.Equal(t, ua.StatusBadUnknownResponse, err)
+	})
+}
+
+func TestHistoryWindowSkip(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(sec int, status ua.StatusCode) *ua.DataValue {
+		return &ua.DataValue{SourceTimestamp: t0.Add(time.Duration(sec) * time.Second), Status: status}
+	}
+	ok, notFound := ua.StatusOK, ua.StatusBadBoundNotFound
+
+	tests := []struct {
+		name string
+		w    historyWindow
+		v    *ua.DataValue
+		want bool
+	}{
+		{"whole range", historyWindow{start: t0, end: t0.Add(10 * time.Second)}, at(-1, ok), false},
+		{"whole range end bound", historyWindow{start: t0, end: t0.Add(10 * time.Second)}, at(11, ok), false},
+		{"first half value", historyWindow{start: t0, end: t0.Add(5 * time.Second), splitEnd: true}, at(4, ok), false},
+		{"first half value at split", historyWindow{start: t0, end: t0.Add(5 * time.Second), splitEnd: true}, at(5, ok), true},
+		{"first half end bound", historyWindow{start: t0, end: t0.Add(5 * time.Second), splitEnd: true}, at(6, ok), true},
+		{"first half start bound", historyWindow{start: t0, end: t0.Add(5 * time.Second), splitEnd: true}, at(-1, ok), false},
+		{"second half start bound", historyWindow{start: t0.Add(5 * time.Second), end: t0.Add(10 * time.Second), splitStart: true}, at(4, ok), true},
+		{"second half value at split", historyWindow{start: t0.Add(5 * time.Second), end: t0.Add(10 * time.Second), splitStart: true}, at(5, ok), false},
+		{"second half bound not found", historyWindow{start: t0.Add(5 * time.Second), end: t0.Add(10 * time.Second), splitStart: true}, at(5, notFound), true},
+		{"second half end bound", historyWindow{start: t0.Add(5 * time.Second), end: t0.Add(10 * time.Second), splitStart: true}, at(11, ok), false},
+		{"reverse first half end bound", historyWindow{start: t0.Add(10 * time.Second), end: t0.Add(5 * time.Second), splitEnd: true}, at(4, ok), true},
+		{"reverse first half value", historyWindow{start: t0.Add(10 * time.Second), end: t0.Add(5 * time.Second), splitEnd: true}, at(6, ok), false},
+		{"reverse second half start bound", historyWindow{start: t0.Add(5 * time.Second), end: t0, splitStart: true}, at(6, ok), true},
+		{"no timestamp", historyWindow{start: t0, end: t0.Add(5 * time.Second), splitStart: true, splitEnd: true}, &ua.DataValue{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.w.skip(tt.v))
+		})
+	}
+}
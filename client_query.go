@@ -0,0 +1,239 @@
+package opcua
+
+import (
+	"context"
+	"iter"
+
+	"github.com/gopcua/opcua/debug"
+	"github.com/gopcua/opcua/stats"
+	"github.com/gopcua/opcua/ua"
+)
+
+// Query executes a synchronous QueryFirst request which returns the
+// nodes of the given types which match the filter. Use QueryNext with
+// the continuation point of the response to get the remaining data sets
+// or QueryStream to page through all of them.
+//
+// Most servers do not implement the Query service and return
+// BadServiceUnsupported.
+//
+// See Part 4, 5.9.3
+func (c *Client) Query(ctx context.Context, req *ua.QueryFirstRequest) (*ua.QueryFirstResponse, error) {
+	stats.Client().Add("QueryFirst", 1)
+
+	// query the whole address space and all nodes of
+	// the types if the view or the filter are missing.
+	r := *req
+	if r.View == nil {
+		r.View = &ua.ViewDescription{ViewID: ua.NewTwoByteNodeID(0)}
+	}
+	if r.Filter == nil {
+		r.Filter = &ua.ContentFilter{}
+	}
+
+	var res *ua.QueryFirstResponse
+	err := c.Send(ctx, &r, func(v ua.Response) error {
+		return safeAssign(v, &res)
+	})
+	return res, err
+}
+
+// QueryNext executes a synchronous QueryNext request which returns
+// the next data sets of a query or releases its continuation point.
+//
+// See Part 4, 5.9.4
+func (c *Client) QueryNext(ctx context.Context, req *ua.QueryNextRequest) (*ua.QueryNextResponse, error) {
+	stats.Client().Add("QueryNext", 1)
+
+	var res *ua.QueryNextResponse
+	err := c.Send(ctx, req, func(v ua.Response) error {
+		return safeAssign(v, &res)
+	})
+	return res, err
+}
+
+// QueryStream returns an iterator over the data sets of a query. The
+// data sets are yielded lazily and continuation points are followed
+// with QueryNext. Errors end the iteration. A continuation point which
+// has not been consumed when the iteration ends early, e.g. since the
+// loop was left or the context was cancelled, is released on the server.
+func (c *Client) QueryStream(ctx context.Context, req *ua.QueryFirstRequest) iter.Seq2[*ua.QueryDataSet, error] {
+	return func(yield func(*ua.QueryDataSet, error) bool) {
+		res, err := c.Query(ctx, req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		sets, cp := res.QueryDataSets, res.ContinuationPoint
+		defer func() { c.releaseQueryContinuationPoint(ctx, cp) }()
+
+		for {
+			for _, ds := range sets {
+				if !yield(ds, nil) {
+					return
+				}
+			}
+			if len(cp) == 0 {
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			next, err := c.QueryNext(ctx, &ua.QueryNextRequest{ContinuationPoint: cp})
+			cp = nil
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			sets, cp = next.QueryDataSets, next.RevisedContinuationPoint
+		}
+	}
+}
+
+// releaseQueryContinuationPoint releases a non-empty continuation point
+// of a query on the server. The request is sent even if ctx has been
+// cancelled.
+func (c *Client) releaseQueryContinuationPoint(ctx context.Context, cp []byte) {
+	if len(cp) == 0 {
+		return
+	}
+	_, err := c.QueryNext(context.WithoutCancel(ctx), &ua.QueryNextRequest{
+		ReleaseContinuationPoint: true,
+		ContinuationPoint:        cp,
+	})
+	if err != nil {
+		debug.Printf("releasing the query continuation point failed: %s", err)
+	}
+}
+
+// NewNodeTypeDescription returns the description of a node type for a
+// query. The query returns the nodes of the type and, if
+// includeSubtypes is set, of its subtypes together with the values
+// described by dataToReturn.
+func NewNodeTypeDescription(typeDef *ua.NodeID, includeSubtypes bool, dataToReturn ...*ua.QueryDataDescription) *ua.NodeTypeDescription {
+	return &ua.NodeTypeDescription{
+		TypeDefinitionNode: ua.NewExpandedNodeID(typeDef, "", 0),
+		IncludeSubTypes:    includeSubtypes,
+		DataToReturn:       dataToReturn,
+	}
+}
+
+// NewQueryDataDescription returns the description of an attribute which
+// a query returns for each node. The attribute belongs to the node at
+// the relative path from the node, e.g. ".2:Temperature" for a property,
+// or to the node itself if relativePath is empty.
+//
+// See ua.ParseRelativePath for the format of the relative path.
+func NewQueryDataDescription(relativePath string, attr ua.AttributeID) (*ua.QueryDataDescription, error) {
+	p, err := ua.ParseRelativePath(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return &ua.QueryDataDescription{
+		RelativePath: p,
+		AttributeID:  attr,
+	}, nil
+}
+
+// NewLiteralOperand returns a filter operand with a literal value.
+// It panics if v cannot be stored in a variant.
+func NewLiteralOperand(v interface{}) *ua.ExtensionObject {
+	return ua.NewExtensionObject(&ua.LiteralOperand{Value: ua.MustVariant(v)})
+}
+
+// NewAttributeOperand returns a filter operand which refers to an
+// attribute of the node at the relative path from the nodes of the
+// given type, e.g. the value of the property ".2:Temperature".
+//
+// See ua.ParseRelativePath for the format of the relative path.
+func NewAttributeOperand(typeDef *ua.NodeID, relativePath string, attr ua.AttributeID) (*ua.ExtensionObject, error) {
+	p, err := ua.ParseRelativePath(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return ua.NewExtensionObject(&ua.AttributeOperand{
+		NodeID:      typeDef,
+		BrowsePath:  p,
+		AttributeID: attr,
+	}), nil
+}
+
+// ContentFilterBuilder builds a content filter from nested expressions.
+// Each expression adds an element to the filter and returns an operand
+// which refers to it. The last added element is the root of the filter.
+// The zero value is ready to use.
+//
+// For example, the filter for all nodes of the type pumpType with a
+// temperature greater than 80 is
+//
+//	var b opcua.ContentFilterBuilder
+//	temp, _ := opcua.NewAttributeOperand(pumpType, ".2:Temperature", ua.AttributeIDValue)
+//	b.And(
+//		b.OfType(pumpType),
+//		b.Add(ua.FilterOperatorGreaterThan, temp, opcua.NewLiteralOperand(80.0)),
+//	)
+//	filter := b.Filter()
+//
+// See Part 4, 7.7
+type ContentFilterBuilder struct {
+	elements []*ua.ContentFilterElement
+}
+
+// Add adds an element with the operator and the operands
+// and returns an operand which refers to the element.
+func (b *ContentFilterBuilder) Add(op ua.FilterOperator, operands ...*ua.ExtensionObject) *ua.ExtensionObject {
+	b.elements = append(b.elements, &ua.ContentFilterElement{
+		FilterOperator: op,
+		FilterOperands: operands,
+	})
+	return ua.NewExtensionObject(&ua.ElementOperand{Index: uint32(len(b.elements) - 1)})
+}
+
+// And adds an element which is true if both operands are true.
+func (b *ContentFilterBuilder) And(x, y *ua.ExtensionObject) *ua.ExtensionObject {
+	return b.Add(ua.FilterOperatorAnd, x, y)
+}
+
+// Or adds an element which is true if one of the operands is true.
+func (b *ContentFilterBuilder) Or(x, y *ua.ExtensionObject) *ua.ExtensionObject {
+	return b.Add(ua.FilterOperatorOr, x, y)
+}
+
+// Not adds an element which is true if the operand is false.
+func (b *ContentFilterBuilder) Not(x *ua.ExtensionObject) *ua.ExtensionObject {
+	return b.Add(ua.FilterOperatorNot, x)
+}
+
+// OfType adds an element which is true if the node is of
+// the given type or one of its subtypes.
+func (b *ContentFilterBuilder) OfType(typeDef *ua.NodeID) *ua.ExtensionObject {
+	return b.Add(ua.FilterOperatorOfType, NewLiteralOperand(typeDef))
+}
+
+// Filter returns the content filter with the last added element as
+// root. The elements are stored in reverse order since the root must
+// be the first element and elements may only refer to elements with a
+// higher index.
+func (b *ContentFilterBuilder) Filter() *ua.ContentFilter {
+	n := len(b.elements)
+	f := &ua.ContentFilter{Elements: make([]*ua.ContentFilterElement, n)}
+	for i, e := range b.elements {
+		operands := make([]*ua.ExtensionObject, len(e.FilterOperands))
+		for j, o := range e.FilterOperands {
+			if o != nil {
+				if eo, ok := o.Value.(*ua.ElementOperand); ok {
+					o = ua.NewExtensionObject(&ua.ElementOperand{Index: uint32(n-1) - eo.Index})
+				}
+			}
+			operands[j] = o
+		}
+		f.Elements[n-1-i] = &ua.ContentFilterElement{
+			FilterOperator: e.FilterOperator,
+			FilterOperands: operands,
+		}
+	}
+	return f
+}
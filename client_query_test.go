@@ -0,0 +1,59 @@
+package opcua
+
+import (
+	"testing"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentFilterBuilder(t *testing.T) {
+	pumpType := ua.NewNumericNodeID(2, 1000)
+	temp, err := NewAttributeOperand(pumpType, ".2:Temperature", ua.AttributeIDValue)
+	require.NoError(t, err)
+
+	var b ContentFilterBuilder
+	b.And(
+		b.OfType(pumpType),
+		b.Add(ua.FilterOperatorGreaterThan, temp, NewLiteralOperand(80.0)),
+	)
+
+	element := func(i uint32) *ua.ExtensionObject {
+		return ua.NewExtensionObject(&ua.ElementOperand{Index: i})
+	}
+	want := &ua.ContentFilter{
+		Elements: []*ua.ContentFilterElement{
+			{FilterOperator: ua.FilterOperatorAnd, FilterOperands: []*ua.ExtensionObject{element(2), element(1)}},
+			{FilterOperator: ua.FilterOperatorGreaterThan, FilterOperands: []*ua.ExtensionObject{temp, NewLiteralOperand(80.0)}},
+			{FilterOperator: ua.FilterOperatorOfType, FilterOperands: []*ua.ExtensionObject{NewLiteralOperand(pumpType)}},
+		},
+	}
+	require.Equal(t, want, b.Filter())
+
+	// the filter can be built more than once
+	require.Equal(t, want, b.Filter())
+	require.Equal(t, &ua.ContentFilter{Elements: []*ua.ContentFilterElement{}}, new(ContentFilterBuilder).Filter())
+}
+
+func TestNewQueryDataDescription(t *testing.T) {
+	d, err := NewQueryDataDescription(".2:Temperature", ua.AttributeIDValue)
+	require.NoError(t, err)
+	require.Equal(t, &ua.QueryDataDescription{
+		RelativePath: &ua.RelativePath{
+			Elements: []*ua.RelativePathElement{{
+				ReferenceTypeID: ua.NewNumericNodeID(0, id.Aggregates),
+				IncludeSubtypes: true,
+				TargetName:      &ua.QualifiedName{NamespaceIndex: 2, Name: "Temperature"},
+			}},
+		},
+		AttributeID: ua.AttributeIDValue,
+	}, d)
+
+	d, err = NewQueryDataDescription("", ua.AttributeIDBrowseName)
+	require.NoError(t, err)
+	require.Equal(t, &ua.QueryDataDescription{RelativePath: &ua.RelativePath{}, AttributeID: ua.AttributeIDBrowseName}, d)
+
+	_, err = NewQueryDataDescription("<", ua.AttributeIDValue)
+	require.Error(t, err)
+}
@@ -0,0 +1,293 @@
+package opcua
+
+import (
+	"context"
+	"time"
+
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/stats"
+	"github.com/gopcua/opcua/ua"
+)
+
+// ReadAttribute reads an attribute of a node. An error is returned if
+// the read fails or the attribute has a bad status code. The error wraps
+// the status code and contains the node id and the attribute. In that
+// case the data value is returned as well if the server sent one.
+// Values with an uncertain status code are returned without an error.
+func (c *Client) ReadAttribute(ctx context.Context, nodeID *ua.NodeID, attr ua.AttributeID) (*ua.DataValue, error) {
+	return c.readOne(ctx, &ua.ReadValueID{NodeID: nodeID, AttributeID: attr})
+}
+
+// ReadIndexRange reads the elements of an array value which are selected
+// by indexRange, e.g. "10:20" for the elements 10 to 20 of an array or
+// "1:3,4:6" for a part of a matrix. The returned data value contains
+// only the selected elements. The index range is validated with
+// ua.ParseIndexRange before the request is sent. Errors are returned
+// like in ReadAttribute.
+//
+// See Part 4, 7.27
+func (c *Client) ReadIndexRange(ctx context.Context, nodeID *ua.NodeID, indexRange string) (*ua.DataValue, error) {
+	if _, err := ua.ParseIndexRange(indexRange); err != nil {
+		return nil, errors.Errorf("read %s of %s: %w", ua.AttributeIDValue, nodeID, err)
+	}
+	return c.readOne(ctx, &ua.ReadValueID{NodeID: nodeID, AttributeID: ua.AttributeIDValue, IndexRange: indexRange})
+}
+
+// readOne reads a single attribute and returns
+// errors as described in ReadAttribute.
+func (c *Client) readOne(ctx context.Context, rv *ua.ReadValueID) (*ua.DataValue, error) {
+	what := rv.AttributeID.String()
+	if rv.IndexRange != "" {
+		what += "[" + rv.IndexRange + "]"
+	}
+	res, err := c.Read(ctx, &ua.ReadRequest{NodesToRead: []*ua.ReadValueID{rv}})
+	if err != nil {
+		return nil, errors.Errorf("read %s of %s: %w", what, rv.NodeID, err)
+	}
+	if len(res.Results) != 1 {
+		return nil, errors.Errorf("read %s of %s: %w", what, rv.NodeID, ua.StatusBadUnknownResponse)
+	}
+	dv := res.Results[0]
+	if uint32(dv.Status)&0x80000000 != 0 {
+		return dv, errors.Errorf("read %s of %s: %w", what, rv.NodeID, dv.Status)
+	}
+	return dv, nil
+}
+
+// ReadValue reads the value attribute of a node and returns its value.
+// Errors are returned like in ReadAttribute.
+//
+// Use Read to get the timestamps and the status code of the value.
+func (c *Client) ReadValue(ctx context.Context, nodeID *ua.NodeID) (*ua.Variant, error) {
+	dv, err := c.ReadAttribute(ctx, nodeID, ua.AttributeIDValue)
+	if err != nil {
+		return nil, err
+	}
+	return dv.Value, nil
+}
+
+// Ping checks that the connection to the server is alive by reading the
+// state of the server. It returns an error if the read fails or the
+// server is not in the Running state. Ping is a lightweight alternative
+// to reading the ServerStatus for health checks.
+//
+// A successful Ping resets the delay between reconnection attempts
+// configured with ReconnectBackoff.
+func (c *Client) Ping(ctx context.Context) error {
+	stats.Client().Add("Ping", 1)
+
+	v, err := c.ReadValue(ctx, ua.NewNumericNodeID(0, id.Server_ServerStatus_State))
+	if err != nil {
+		return err
+	}
+	if state := ua.ServerState(v.Int()); state != ua.ServerStateRunning {
+		return errors.Errorf("server is in state %s", state)
+	}
+	c.resetReconnectBackoff()
+	return nil
+}
+
+// ServerTime returns the current time of the server.
+func (c *Client) ServerTime(ctx context.Context) (time.Time, error) {
+	v, err := c.ReadValue(ctx, ua.NewNumericNodeID(0, id.Server_ServerStatus_CurrentTime))
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, ok := v.Value().(time.Time)
+	if !ok {
+		return time.Time{}, errors.Errorf("server time has type %T", v.Value())
+	}
+	return t, nil
+}
+
+// ClockSkew estimates the offset between the clock of the server and the
+// local clock. A positive value means that the clock of the server is
+// ahead of the local clock.
+//
+// The server time is assumed to have been taken in the middle of the
+// round trip of the request. The accuracy of the estimate is therefore
+// limited by half of the round-trip time and by the resolution of the
+// server clock.
+func (c *Client) ClockSkew(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	t, err := c.ServerTime(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+	return t.Sub(start.Add(rtt / 2)), nil
+}
+
+// DisplayName reads the display name of a node.
+func (c *Client) DisplayName(ctx context.Context, nodeID *ua.NodeID) (*ua.LocalizedText, error) {
+	return readAttributeAs[*ua.LocalizedText](ctx, c, nodeID, ua.AttributeIDDisplayName)
+}
+
+// DataType reads the node id of the data type of a variable node.
+func (c *Client) DataType(ctx context.Context, nodeID *ua.NodeID) (*ua.NodeID, error) {
+	dv, err := c.ReadAttribute(ctx, nodeID, ua.AttributeIDDataType)
+	if err != nil {
+		return nil, err
+	}
+	dt := variantNodeID(dv.Value)
+	if dt == nil {
+		return nil, errors.Errorf("read %s of %s: invalid data type %v: %w", ua.AttributeIDDataType, nodeID, dv.Value, ua.StatusBadTypeMismatch)
+	}
+	return dt, nil
+}
+
+// AccessLevel reads the access level of a variable node. The returned
+// value is a mask where multiple values can be set, e.g. read and write,
+// which can be checked with methods like CurrentWrite.
+func (c *Client) AccessLevel(ctx context.Context, nodeID *ua.NodeID) (ua.AccessLevelType, error) {
+	v, err := readAttributeAs[uint8](ctx, c, nodeID, ua.AttributeIDAccessLevel)
+	return ua.AccessLevelType(v), err
+}
+
+// UserAccessLevel reads the access level of a variable node for the
+// user of the session. Unlike AccessLevel it takes the permissions of
+// the user into account, e.g. to check whether a write is allowed.
+func (c *Client) UserAccessLevel(ctx context.Context, nodeID *ua.NodeID) (ua.AccessLevelType, error) {
+	v, err := readAttributeAs[uint8](ctx, c, nodeID, ua.AttributeIDUserAccessLevel)
+	return ua.AccessLevelType(v), err
+}
+
+// readAttributeAs reads an attribute of a node and converts the value
+// to T with ua.As.
+func readAttributeAs[T any](ctx context.Context, c *Client, nodeID *ua.NodeID, attr ua.AttributeID) (T, error) {
+	var zero T
+	dv, err := c.ReadAttribute(ctx, nodeID, attr)
+	if err != nil {
+		return zero, err
+	}
+	v, err := ua.As[T](dv.Value)
+	if err != nil {
+		return zero, errors.Errorf("read %s of %s: %w", attr, nodeID, err)
+	}
+	return v, nil
+}
+
+// variantNodeID returns the node id value of the variant or nil.
+// Some servers return the node ids of attributes like the data type
+// as expanded node ids.
+func variantNodeID(v *ua.Variant) *ua.NodeID {
+	if v == nil {
+		return nil
+	}
+	switch x := v.Value().(type) {
+	case *ua.NodeID:
+		return x
+	case *ua.ExpandedNodeID:
+		return x.NodeID
+	default:
+		return nil
+	}
+}
+
+// ReadAs reads the value attribute of a node and converts it to the Go
+// type T with ua.As, e.g.
+//
+//	temp, err := opcua.ReadAs[float64](ctx, c, nodeID)
+//
+// An error is returned if the read fails, the value has a bad status
+// code or the value cannot be converted to T.
+func ReadAs[T any](ctx context.Context, c *Client, nodeID *ua.NodeID) (T, error) {
+	var zero T
+	v, err := c.ReadValue(ctx, nodeID)
+	if err != nil {
+		return zero, err
+	}
+	return ua.As[T](v)
+}
+
+// The names of the standard data encodings of structured data types.
+const (
+	DataEncodingBinary = "Default Binary"
+	DataEncodingXML    = "Default XML"
+	DataEncodingJSON   = "Default JSON"
+)
+
+// withDataEncoding returns a copy of nodesToRead with the data encoding
+// set for the value attributes which do not have one. It returns an
+// error if the data type of a node does not have the encoding.
+func (c *Client) withDataEncoding(ctx context.Context, nodesToRead []*ua.ReadValueID, name string) ([]*ua.ReadValueID, error) {
+	var (
+		out     = make([]*ua.ReadValueID, len(nodesToRead))
+		dtReads []*ua.ReadValueID
+		idx     []int
+	)
+	for i, rv := range nodesToRead {
+		out[i] = rv
+		if rv.AttributeID != ua.AttributeIDValue || (rv.DataEncoding != nil && rv.DataEncoding.Name != "") {
+			continue
+		}
+		x := *rv
+		x.DataEncoding = &ua.QualifiedName{Name: name}
+		out[i] = &x
+		dtReads = append(dtReads, &ua.ReadValueID{NodeID: rv.NodeID, AttributeID: ua.AttributeIDDataType})
+		idx = append(idx, i)
+	}
+	if len(dtReads) == 0 {
+		return out, nil
+	}
+
+	res, err := c.Read(ctx, &ua.ReadRequest{NodesToRead: dtReads})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Results) != len(dtReads) {
+		return nil, ua.StatusBadUnknownResponse
+	}
+
+	checked := make(map[string]bool)
+	for i, dv := range res.Results {
+		nodeID := nodesToRead[idx[i]].NodeID
+		if dv.Status != ua.StatusOK {
+			return nil, errors.Errorf("node %s: read data type: %w", nodeID, dv.Status)
+		}
+		dt := variantNodeID(dv.Value)
+		if dt == nil {
+			return nil, errors.Errorf("node %s: invalid data type %v", nodeID, dv.Value)
+		}
+		if checked[dt.String()] {
+			continue
+		}
+		ok, err := c.hasDataEncoding(ctx, dt, name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.Errorf("node %s: data type %s has no encoding %q: %w", nodeID, dt, name, ua.StatusBadDataEncodingUnsupported)
+		}
+		checked[dt.String()] = true
+	}
+	return out, nil
+}
+
+// hasDataEncoding returns true if the data type has an encoding node
+// with the given browse name. Data types which are unknown to the server
+// do not have any encodings.
+func (c *Client) hasDataEncoding(ctx context.Context, dataType *ua.NodeID, name string) (bool, error) {
+	req := &ua.BrowseRequest{
+		NodesToBrowse: []*ua.BrowseDescription{{
+			NodeID:          dataType,
+			BrowseDirection: ua.BrowseDirectionForward,
+			ReferenceTypeID: ua.NewNumericNodeID(0, id.HasEncoding),
+			IncludeSubtypes: true,
+			ResultMask:      uint32(ua.BrowseResultMaskBrowseName),
+		}},
+	}
+	for ref, err := range c.BrowseStream(ctx, req) {
+		if err == ua.StatusBadNodeIDUnknown {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if ref.BrowseName != nil && ref.BrowseName.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
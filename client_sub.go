@@ -9,6 +9,7 @@ import (
 
 	"github.com/gopcua/opcua/debug"
 	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/id"
 	"github.com/gopcua/opcua/stats"
 	"github.com/gopcua/opcua/ua"
 	"github.com/gopcua/opcua/uasc"
@@ -50,17 +51,10 @@ func (c *Client) Subscribe(ctx context.Context, params *SubscriptionParameters,
 	// start the publish loop if it isn't already running
 	c.resumech <- struct{}{}
 
-	sub := &Subscription{
-		SubscriptionID:            res.SubscriptionID,
-		RevisedPublishingInterval: time.Duration(res.RevisedPublishingInterval) * time.Millisecond,
-		RevisedLifetimeCount:      res.RevisedLifetimeCount,
-		RevisedMaxKeepAliveCount:  res.RevisedMaxKeepAliveCount,
-		Notifs:                    notifyCh,
-		items:                     make(map[uint32]*monitoredItem),
-		params:                    params,
-		nextSeq:                   1,
-		c:                         c,
-	}
+	sub := c.newSubscription(res.SubscriptionID, params, notifyCh)
+	sub.RevisedPublishingInterval = time.Duration(res.RevisedPublishingInterval) * time.Millisecond
+	sub.RevisedLifetimeCount = res.RevisedLifetimeCount
+	sub.RevisedMaxKeepAliveCount = res.RevisedMaxKeepAliveCount
 
 	c.subMux.Lock()
 	defer c.subMux.Unlock()
@@ -73,9 +67,133 @@ func (c *Client) Subscribe(ctx context.Context, params *SubscriptionParameters,
 
 	c.subs[sub.SubscriptionID] = sub
 	c.updatePublishTimeout_NeedsSubMuxLock()
+	c.logger.Info("subscription created",
+		"subscriptionID", sub.SubscriptionID,
+		"requestedPublishingInterval", params.Interval,
+		"publishingInterval", sub.RevisedPublishingInterval,
+		"lifetimeCount", sub.RevisedLifetimeCount,
+		"maxKeepAliveCount", sub.RevisedMaxKeepAliveCount,
+	)
 	return sub, nil
 }
 
+// newSubscription returns a subscription with the given id which sends
+// its notifications to notifyCh.
+func (c *Client) newSubscription(id uint32, params *SubscriptionParameters, notifyCh chan<- *PublishNotificationData) *Subscription {
+	sub := &Subscription{
+		SubscriptionID: id,
+		Notifs:         notifyCh,
+		items:          make(map[uint32]*monitoredItem),
+		params:         params,
+		nextSeq:        1,
+		c:              c,
+	}
+	sub.resetWatchdog()
+	if n := c.cfg.notifBufferSize; n > 0 && notifyCh != nil {
+		sub.buf = newNotificationBuffer(n, c.cfg.notifDropPolicy, &sub.stats.notificationsDropped)
+		go sub.buf.forward(notifyCh)
+	}
+	return sub
+}
+
+// DeleteSubscriptions deletes the subscriptions with the given ids on the
+// server with a single request and removes them from the client.
+// Subscriptions which are not known to the client are deleted on the
+// server as well, e.g. the subscriptions of an abandoned session which
+// have been transferred. The first bad status code of the results is
+// returned as error.
+func (c *Client) DeleteSubscriptions(ctx context.Context, ids ...uint32) error {
+	stats.Client().Add("DeleteSubscriptions", 1)
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var subs []*Subscription
+	c.subMux.Lock()
+	for _, id := range ids {
+		if s, ok := c.subs[id]; ok {
+			subs = append(subs, s)
+			c.forgetSubscription_NeedsSubMuxLock(ctx, id)
+		}
+	}
+	c.subMux.Unlock()
+
+	for _, s := range subs {
+		s.stopDelivery()
+		s.itemsMu.Lock()
+		s.items = make(map[uint32]*monitoredItem)
+		s.itemsMu.Unlock()
+	}
+
+	return c.deleteSubscriptions(ctx, ids)
+}
+
+// SetPublishingMode enables or disables the publishing of notifications
+// for the subscriptions with the given ids with a single request. The
+// subscriptions which are known to the client remember the mode of a
+// successful update and restore it when they are recreated after a
+// reconnect. The first bad status code of the results is returned as
+// error.
+func (c *Client) SetPublishingMode(ctx context.Context, enabled bool, subIDs ...uint32) error {
+	stats.Client().Add("SetPublishingMode", 1)
+
+	if len(subIDs) == 0 {
+		return nil
+	}
+
+	req := &ua.SetPublishingModeRequest{
+		PublishingEnabled: enabled,
+		SubscriptionIDs:   subIDs,
+	}
+	var res *ua.SetPublishingModeResponse
+	err := c.Send(ctx, req, func(v ua.Response) error {
+		return safeAssign(v, &res)
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.Results) != len(subIDs) {
+		return ua.StatusBadUnknownResponse
+	}
+
+	c.subMux.RLock()
+	for i, id := range subIDs {
+		if s, ok := c.subs[id]; ok && res.Results[i] == ua.StatusOK {
+			s.publishingDisabled.Store(!enabled)
+		}
+	}
+	c.subMux.RUnlock()
+
+	for i, status := range res.Results {
+		if status != ua.StatusOK {
+			return errors.Errorf("subscription %d: %w", subIDs[i], status)
+		}
+	}
+	return nil
+}
+
+// deleteSubscriptions deletes the subscriptions on the server.
+func (c *Client) deleteSubscriptions(ctx context.Context, ids []uint32) error {
+	req := &ua.DeleteSubscriptionsRequest{SubscriptionIDs: ids}
+	var res *ua.DeleteSubscriptionsResponse
+	err := c.Send(ctx, req, func(v ua.Response) error {
+		return safeAssign(v, &res)
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.Results) != len(ids) {
+		return ua.StatusBadUnknownResponse
+	}
+	for i, status := range res.Results {
+		if status != ua.StatusOK {
+			return errors.Errorf("subscription %d: %w", ids[i], status)
+		}
+	}
+	return nil
+}
+
 // SubscriptionIDs gets a list of subscriptionIDs
 func (c *Client) SubscriptionIDs() []uint32 {
 	c.subMux.RLock()
@@ -100,16 +218,39 @@ func (c *Client) recreateSubscription(ctx context.Context, id uint32) error {
 	}
 
 	sub.recreate_delete(ctx)
-	c.forgetSubscription_NeedsSubMuxLock(ctx, id)
-	return sub.recreate_create(ctx)
+	if err := sub.recreate_create(ctx); err != nil {
+		return err
+	}
+	c.updatePublishTimeout_NeedsSubMuxLock()
+
+	if f := c.cfg.subRecreatedFunc; f != nil {
+		go f(id, sub)
+	}
+	return nil
 }
 
-// transferSubscriptions ask the server to transfer the given subscriptions
-// of the previous session to the current one.
-func (c *Client) transferSubscriptions(ctx context.Context, ids []uint32) (*ua.TransferSubscriptionsResponse, error) {
+// TransferSubscriptions asks the server to transfer the given subscriptions
+// from another session to the current session, e.g. from a session which
+// was abandoned after a crash of the client.
+//
+// The results contain the status and the sequence numbers of the
+// notifications which are available for retransmission for every
+// subscription in the same order as subscriptionIDs. If sendInitialValues
+// is true the server sends the current values of all monitored items
+// with the next publish response.
+//
+// Only notifications of subscriptions which are known to the client are
+// delivered. Use AttachSubscriptions to receive the notifications of the
+// subscriptions of another session. Subscriptions created by this client
+// are transferred automatically during a reconnect.
+//
+// See Part 4, 5.13.7
+func (c *Client) TransferSubscriptions(ctx context.Context, subscriptionIDs []uint32, sendInitialValues bool) (*ua.TransferSubscriptionsResponse, error) {
+	stats.Client().Add("TransferSubscriptions", 1)
+
 	req := &ua.TransferSubscriptionsRequest{
-		SubscriptionIDs:   ids,
-		SendInitialValues: false,
+		SubscriptionIDs:   subscriptionIDs,
+		SendInitialValues: sendInitialValues,
 	}
 
 	var res *ua.TransferSubscriptionsResponse
@@ -119,6 +260,171 @@ func (c *Client) transferSubscriptions(ctx context.Context, ids []uint32) (*ua.T
 	return res, err
 }
 
+// AttachSubscriptions transfers the given subscriptions from another
+// session to the current session like TransferSubscriptions and returns
+// a Subscription for each of them in the same order as subscriptionIDs.
+// The notifications of the subscriptions are sent to notifyCh and the
+// server sends the current values of all monitored items with the next
+// publish response. Subscriptions which are already known to the client
+// are returned as they are.
+//
+// The server does not report the parameters and the monitored items of
+// a transferred subscription. The revised parameters are read from the
+// subscription diagnostics of the server if it provides them. Otherwise
+// they are assumed to be params which may be nil for the defaults and
+// the keep-alive watchdog ignores the subscription until it is
+// modified. The monitored items are unknown to the returned
+// subscriptions and are lost when a subscription has to be recreated
+// after a reconnect.
+//
+// The subscriptions which could not be transferred are nil and their
+// errors are returned.
+//
+// See Part 4, 5.13.7
+func (c *Client) AttachSubscriptions(ctx context.Context, subscriptionIDs []uint32, params *SubscriptionParameters, notifyCh chan<- *PublishNotificationData) ([]*Subscription, error) {
+	if params == nil {
+		params = &SubscriptionParameters{}
+	}
+	params.setDefaults()
+
+	// keep the publish responses which arrive before the subscriptions
+	// are registered so that their first notifications are not
+	// discarded as unknown.
+	c.subMux.Lock()
+	if c.attaching == nil {
+		c.attaching = make(map[uint32][]*ua.PublishResponse)
+	}
+	var attaching []uint32
+	for _, id := range subscriptionIDs {
+		if _, ok := c.subs[id]; ok {
+			continue
+		}
+		if _, ok := c.attaching[id]; ok {
+			continue
+		}
+		c.attaching[id] = nil
+		attaching = append(attaching, id)
+	}
+	c.subMux.Unlock()
+
+	// deliver the kept publish responses after the subscriptions have
+	// been registered
+	defer func() {
+		var early []*ua.PublishResponse
+		c.subMux.Lock()
+		for _, id := range attaching {
+			early = append(early, c.attaching[id]...)
+			delete(c.attaching, id)
+		}
+		type delivery struct {
+			sub *Subscription
+			msg *ua.NotificationMessage
+		}
+		var deliver []delivery
+		for _, res := range early {
+			sub, ok := c.subs[res.SubscriptionID]
+			if !ok {
+				continue
+			}
+			c.handleNotification_NeedsSubMuxLock(sub, res)
+			deliver = append(deliver, delivery{sub, res.NotificationMessage})
+		}
+		c.subMux.Unlock()
+
+		for _, d := range deliver {
+			c.notifySubscription(ctx, d.sub, d.msg)
+		}
+	}()
+
+	// start the publish loop if it isn't already running
+	c.resumech <- struct{}{}
+
+	res, err := c.TransferSubscriptions(ctx, subscriptionIDs, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Results) != len(subscriptionIDs) {
+		return nil, ua.StatusBadUnknownResponse
+	}
+
+	var transferred []uint32
+	for i, id := range subscriptionIDs {
+		if res.Results[i].StatusCode == ua.StatusOK {
+			transferred = append(transferred, id)
+		}
+	}
+	diags := c.subscriptionDiagnostics(ctx, transferred)
+
+	c.subMux.Lock()
+	defer c.subMux.Unlock()
+
+	subs := make([]*Subscription, len(subscriptionIDs))
+	var errs []error
+	for i, id := range subscriptionIDs {
+		if status := res.Results[i].StatusCode; status != ua.StatusOK {
+			errs = append(errs, errors.Errorf("transfer subscription %d: %w", id, status))
+			continue
+		}
+		if sub, ok := c.subs[id]; ok {
+			subs[i] = sub
+			continue
+		}
+
+		p := *params
+		sub := c.newSubscription(id, &p, notifyCh)
+		if d, ok := diags[id]; ok {
+			sub.RevisedPublishingInterval = time.Duration(d.PublishingInterval) * time.Millisecond
+			sub.RevisedLifetimeCount = d.MaxLifetimeCount
+			sub.RevisedMaxKeepAliveCount = d.MaxKeepAliveCount
+		} else {
+			sub.RevisedPublishingInterval = p.Interval
+			sub.RevisedLifetimeCount = p.LifetimeCount
+			sub.RevisedMaxKeepAliveCount = p.MaxKeepAliveCount
+			sub.revisedAssumed.Store(true)
+		}
+		c.subs[id] = sub
+		stats.Subscription().Add("Count", 1)
+		subs[i] = sub
+		c.logger.Info("subscription attached", "subscriptionID", id)
+	}
+	c.updatePublishTimeout_NeedsSubMuxLock()
+	return subs, errors.Join(errs...)
+}
+
+// subscriptionDiagnostics returns the diagnostics of the subscriptions
+// with the given ids by id. Subscriptions without diagnostics are
+// missing, e.g. when the server does not provide them.
+func (c *Client) subscriptionDiagnostics(ctx context.Context, ids []uint32) map[uint32]*ua.SubscriptionDiagnosticsDataType {
+	if len(ids) == 0 {
+		return nil
+	}
+	res, err := c.Read(ctx, &ua.ReadRequest{
+		NodesToRead: []*ua.ReadValueID{{
+			NodeID:      ua.NewNumericNodeID(0, id.Server_ServerDiagnostics_SubscriptionDiagnosticsArray),
+			AttributeID: ua.AttributeIDValue,
+		}},
+	})
+	if err != nil || len(res.Results) != 1 || res.Results[0].Status != ua.StatusOK || res.Results[0].Value == nil {
+		return nil
+	}
+	eos, ok := res.Results[0].Value.Value().([]*ua.ExtensionObject)
+	if !ok {
+		return nil
+	}
+	diags := make(map[uint32]*ua.SubscriptionDiagnosticsDataType)
+	for _, eo := range eos {
+		if eo == nil {
+			continue
+		}
+		d, ok := eo.Value.(*ua.SubscriptionDiagnosticsDataType)
+		if !ok || !slices.Contains(ids, d.SubscriptionID) {
+			continue
+		}
+		diags[d.SubscriptionID] = d
+	}
+	return diags
+}
+
 // republishSubscriptions sends republish requests for the given subscription id.
 func (c *Client) republishSubscription(ctx context.Context, id uint32, availableSeq []uint32) error {
 	c.subMux.RLock()
@@ -232,6 +538,9 @@ func (c *Client) forgetSubscription(ctx context.Context, id uint32) {
 }
 
 func (c *Client) forgetSubscription_NeedsSubMuxLock(ctx context.Context, id uint32) {
+	if _, ok := c.subs[id]; !ok {
+		return
+	}
 	delete(c.subs, id)
 	c.updatePublishTimeout_NeedsSubMuxLock()
 	stats.Subscription().Add("Count", -1)
@@ -302,12 +611,22 @@ func (c *Client) notifySubscription(ctx context.Context, sub *Subscription, noti
 			continue
 		}
 
-		switch data.Value.(type) {
+		switch v := data.Value.(type) {
 		// Part 4, 7.20.2 DataChangeNotification parameter
+		case *ua.DataChangeNotification:
+			// values of items created with MonitorWithInitial
+			// are delivered to their own channel.
+			if v = sub.routeDataChanges(ctx, v); v == nil {
+				continue
+			}
+			sub.notify(ctx, &PublishNotificationData{
+				SubscriptionID: sub.SubscriptionID,
+				Value:          v,
+			})
+
 		// Part 4, 7.20.3 EventNotificationList parameter
 		// Part 4, 7.20.4 StatusChangeNotification parameter
-		case *ua.DataChangeNotification,
-			*ua.EventNotificationList,
+		case *ua.EventNotificationList,
 			*ua.StatusChangeNotification:
 			sub.notify(ctx, &PublishNotificationData{
 				SubscriptionID: sub.SubscriptionID,
@@ -369,6 +688,7 @@ publish:
 
 				case <-c.resumech:
 					dlog.Print("pause: resume")
+					c.resetWatchdogs()
 					continue publish
 
 				case <-c.pausech:
@@ -384,12 +704,67 @@ publish:
 			// is received or the context is cancelled.
 			if err := c.publish(ctx); err != nil {
 				dlog.Print("error: ", err.Error())
+				c.logger.Warn("publish failed, pausing subscriptions", "error", err)
 				c.pauseSubscriptions(ctx)
+				continue
 			}
+			c.checkKeepAlives(ctx)
 		}
 	}
 }
 
+// resetWatchdogs restarts the keep-alive watchdog of all subscriptions.
+func (c *Client) resetWatchdogs() {
+	c.subMux.RLock()
+	defer c.subMux.RUnlock()
+	for _, s := range c.subs {
+		s.resetWatchdog()
+	}
+}
+
+// checkKeepAlives marks the subscriptions as stale which have not
+// received a publish response within the watchdog interval. Since this
+// usually means that the connection is dead without TCP having noticed
+// it, the client then reconnects which also restores the subscriptions
+// if auto-reconnect is enabled.
+func (c *Client) checkKeepAlives(ctx context.Context) {
+	m := c.cfg.keepAliveMultiplier
+	if m <= 0 {
+		return
+	}
+
+	var stale []*Subscription
+	now := time.Now()
+	c.subMux.RLock()
+	for _, s := range c.subs {
+		d := time.Duration(float64(s.keepAliveInterval()) * m)
+		last := s.lastAlive.Load()
+		if d <= 0 || last == 0 || s.stale.Load() || s.revisedAssumed.Load() {
+			continue
+		}
+		if now.Sub(time.Unix(0, last)) > d {
+			s.stale.Store(true)
+			stale = append(stale, s)
+		}
+	}
+	c.subMux.RUnlock()
+
+	if len(stale) == 0 {
+		return
+	}
+	for _, s := range stale {
+		c.logger.Warn("subscription missed keep-alives", "subscriptionID", s.SubscriptionID)
+		go s.notify(ctx, &PublishNotificationData{SubscriptionID: s.SubscriptionID, Error: ErrKeepAliveTimeout})
+	}
+	if !c.cfg.sechan.AutoReconnect {
+		return
+	}
+	select {
+	case c.sechanErr <- ErrKeepAliveTimeout:
+	default:
+	}
+}
+
 // publish sends a publish request and handles the response.
 func (c *Client) publish(ctx context.Context) error {
 	dlog := debug.NewPrefixLogger("publish: ")
@@ -465,6 +840,12 @@ func (c *Client) publish(ctx context.Context) error {
 		c.handleAcks_NeedsSubMuxLock(res.Results)
 
 		sub, ok := c.subs[res.SubscriptionID]
+		if _, attaching := c.attaching[res.SubscriptionID]; !ok && attaching {
+			// delivered when AttachSubscriptions has registered it
+			c.attaching[res.SubscriptionID] = append(c.attaching[res.SubscriptionID], res)
+			c.subMux.Unlock()
+			return nil
+		}
 		if !ok {
 			c.subMux.Unlock()
 			// todo(fs): should we return an error here?
@@ -519,8 +900,13 @@ func (c *Client) handleAcks_NeedsSubMuxLock(res []ua.StatusCode) {
 func (c *Client) handleNotification_NeedsSubMuxLock(sub *Subscription, res *ua.PublishResponse) {
 	dlog := debug.NewPrefixLogger("publish: sub %d: ", res.SubscriptionID)
 
+	sub.stats.publishResponsesReceived.Add(1)
+	sub.stats.lastPublishTime.Store(time.Now().UnixNano())
+	sub.alive()
+
 	// keep-alive message
 	if len(res.NotificationMessage.NotificationData) == 0 {
+		sub.stats.keepAlivesReceived.Add(1)
 		// todo(fs): do we care about the next sequence number?
 		sub.nextSeq = res.NotificationMessage.SequenceNumber
 		return
@@ -532,12 +918,40 @@ func (c *Client) handleNotification_NeedsSubMuxLock(sub *Subscription, res *ua.P
 
 	sub.lastSeq = res.NotificationMessage.SequenceNumber
 	sub.nextSeq = sub.lastSeq + 1
+	sub.stats.notificationsReceived.Add(1)
+	sub.stats.lastSequenceNumber.Store(sub.lastSeq)
 	c.pendingAcks = append(c.pendingAcks, &ua.SubscriptionAcknowledgement{
 		SubscriptionID: res.SubscriptionID,
 		SequenceNumber: res.NotificationMessage.SequenceNumber,
 	})
 }
 
+// acknowledge sends the pending acknowledgements with a publish request
+// outside of the publish loop. It returns once the request has been
+// written to the secure channel without waiting for the response which
+// the server only sends with the next notification or keep-alive. The
+// secure channel discards the response.
+func (c *Client) acknowledge(ctx context.Context) error {
+	c.subMux.Lock()
+	acks := c.pendingAcks
+	c.pendingAcks = []*ua.SubscriptionAcknowledgement{}
+	c.subMux.Unlock()
+	if len(acks) == 0 {
+		return nil
+	}
+
+	sc := c.SecureChannel()
+	if sc == nil {
+		return c.notConnectedError()
+	}
+	var authToken *ua.NodeID
+	if s := c.Session(); s != nil {
+		authToken = s.resp.AuthenticationToken
+	}
+	req := &ua.PublishRequest{SubscriptionAcknowledgements: acks}
+	return sc.SendRequestWithTimeout(ctx, req, authToken, c.publishTimeout(), nil)
+}
+
 func (c *Client) sendPublishRequest(ctx context.Context) (*ua.PublishResponse, error) {
 	dlog := debug.NewPrefixLogger("publish: ")
 
@@ -548,6 +962,9 @@ func (c *Client) sendPublishRequest(ctx context.Context) (*ua.PublishResponse, e
 	if req.SubscriptionAcknowledgements == nil {
 		req.SubscriptionAcknowledgements = []*ua.SubscriptionAcknowledgement{}
 	}
+	for _, sub := range c.subs {
+		sub.stats.publishRequestsSent.Add(1)
+	}
 	c.subMux.RUnlock()
 
 	dlog.Printf("PublishRequest: %s", debug.ToJSON(req))
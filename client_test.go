@@ -1,11 +1,17 @@
 package opcua
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"log/slog"
 	"testing"
+	"time"
 
+	"github.com/gopcua/opcua/errors"
 	"github.com/gopcua/opcua/id"
 	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uasc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -263,6 +269,190 @@ func TestClient_SetState(t *testing.T) {
 	}
 }
 
+func TestClient_StateChangedHandler(t *testing.T) {
+	type transition struct{ from, to ConnState }
+	var got []transition
+	c, err := NewClient("opc.tcp://example.com:4840", StateChangedHandler(func(from, to ConnState) {
+		got = append(got, transition{from, to})
+	}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	c.setState(ctx, Connecting)
+	c.setState(ctx, Connected)
+	c.setState(ctx, Connected)
+	c.setState(ctx, Disconnected)
+	c.setState(ctx, Reconnecting)
+	c.setState(ctx, Connected)
+
+	want := []transition{
+		{Closed, Connecting},
+		{Connecting, Connected},
+		{Connected, Disconnected},
+		{Disconnected, Reconnecting},
+		{Reconnecting, Connected},
+	}
+	require.Equal(t, want, got)
+}
+
+func TestClient_StateChangedHandlerReentrant(t *testing.T) {
+	type transition struct{ from, to ConnState }
+	var got []transition
+	var c *Client
+	c, err := NewClient("opc.tcp://example.com:4840", StateChangedHandler(func(from, to ConnState) {
+		got = append(got, transition{from, to})
+		if to != Connected {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, c.WaitForState(ctx, Connected))
+		c.Close(ctx)
+	}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	c.setState(ctx, Connecting)
+	c.setState(ctx, Connected)
+
+	want := []transition{
+		{Closed, Connecting},
+		{Connecting, Connected},
+		{Connected, Closed},
+	}
+	require.Equal(t, want, got)
+	require.Equal(t, Closed, c.State())
+}
+
+func TestClient_StateChangedChBlocked(t *testing.T) {
+	stateCh := make(chan ConnState)
+	c, err := NewClient("opc.tcp://example.com:4840", StateChangedCh(stateCh))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		c.setState(ctx, Connected)
+		close(done)
+	}()
+
+	// the state is reached while nobody reads the state channel
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), time.Second)
+	defer waitCancel()
+	require.NoError(t, c.WaitForState(waitCtx, Connected))
+
+	require.Equal(t, Connected, <-stateCh)
+	<-done
+}
+
+func TestClient_WaitForState(t *testing.T) {
+	t.Run("current state", func(t *testing.T) {
+		c, err := NewClient("opc.tcp://example.com:4840")
+		require.NoError(t, err)
+		require.NoError(t, c.WaitForState(context.Background(), Closed))
+	})
+
+	t.Run("state reached", func(t *testing.T) {
+		c, err := NewClient("opc.tcp://example.com:4840")
+		require.NoError(t, err)
+
+		done := make(chan error)
+		go func() { done <- c.WaitForState(context.Background(), Connected) }()
+		require.Eventually(t, func() bool {
+			c.stateMu.Lock()
+			defer c.stateMu.Unlock()
+			return len(c.stateWaiters[Connected]) == 1
+		}, time.Second, time.Millisecond)
+
+		ctx := context.Background()
+		c.setState(ctx, Connecting)
+		c.setState(ctx, Connected)
+		c.setState(ctx, Disconnected)
+		require.NoError(t, <-done)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		c, err := NewClient("opc.tcp://example.com:4840")
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, c.WaitForState(ctx, Connected), context.DeadlineExceeded)
+		require.Empty(t, c.stateWaiters[Connected])
+	})
+}
+
+func TestClient_ReconnectGaveUp(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840", MaxReconnectAttempts(1))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	done := make(chan error)
+	go func() { done <- c.WaitForState(ctx, Connected) }()
+	require.Eventually(t, func() bool {
+		c.stateMu.Lock()
+		defer c.stateMu.Unlock()
+		return len(c.stateWaiters[Connected]) == 1
+	}, time.Second, time.Millisecond)
+
+	c.reconnectGaveUp.Store(true)
+	c.setState(ctx, Disconnected)
+	require.ErrorIs(t, <-done, ErrReconnectGaveUp)
+	require.ErrorIs(t, c.WaitForState(ctx, Connected), ErrReconnectGaveUp)
+	require.NoError(t, c.WaitForState(ctx, Disconnected))
+
+	_, err = c.Browse(ctx, &ua.BrowseRequest{})
+	require.ErrorIs(t, err, ErrReconnectGaveUp)
+}
+
+func TestIsSessionError(t *testing.T) {
+	tests := []struct {
+		err        error
+		session    bool
+		connection bool
+	}{
+		{io.EOF, true, true},
+		{errors.Errorf("send: %w", io.ErrUnexpectedEOF), true, true},
+		{ua.StatusBadSecureChannelClosed, true, true},
+		{ua.StatusBadTimeout, true, true},
+		{errors.Errorf("recreate: %w", ua.StatusBadConnectionClosed), true, true},
+		{ua.StatusBadSessionIDInvalid, true, false},
+		{ua.StatusBadSessionNotActivated, true, false},
+		{ua.StatusBadNodeIDUnknown, false, false},
+		{ua.StatusBadTooManySubscriptions, false, false},
+		{errors.Errorf("recreate: %w", ua.StatusBadSubscriptionIDInvalid), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			require.Equal(t, tt.session, isSessionError(tt.err), "isSessionError")
+			require.Equal(t, tt.connection, isConnectionError(tt.err), "isConnectionError")
+		})
+	}
+}
+
+func TestClient_DialApplicationURIMismatch(t *testing.T) {
+	cert, key, err := GenerateCert("urn:gopcua:client", 0, 0)
+	require.NoError(t, err)
+
+	c, err := NewClient("opc.tcp://127.0.0.1:0", Certificate(cert), PrivateKey(key), ApplicationURI("urn:other"))
+	require.NoError(t, err)
+	require.ErrorIs(t, c.Dial(context.Background()), ErrApplicationURIMismatch)
+
+	c, err = NewClient("opc.tcp://127.0.0.1:0", Certificate(cert), PrivateKey(key), ApplicationURI("urn:other"), SkipApplicationURICheck())
+	require.NoError(t, err)
+	require.NotErrorIs(t, c.Dial(context.Background()), ErrApplicationURIMismatch)
+}
+
+func TestClient_ChangeUserRejectsOptions(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840")
+	require.NoError(t, err)
+	c.setSession(&Session{cfg: &uasc.SessionConfig{}, resp: &ua.CreateSessionResponse{}})
+
+	err = c.ChangeUser(context.Background(), ua.UserTokenTypeUserName, AuthUsername("user", "pass"), RequestTimeout(time.Second))
+	require.ErrorContains(t, err, "option does not configure the user identity")
+}
+
 func TestClient_LoadNil(t *testing.T) {
 	t.Run("normal client init", func(t *testing.T) {
 		c, err := NewClient("opc.tcp://dummy")
@@ -278,3 +468,121 @@ func TestClient_LoadNil(t *testing.T) {
 		assert.Nil(t, c.Session())
 	})
 }
+
+func TestClient_NamespaceIndex(t *testing.T) {
+	ctx := context.Background()
+	c, err := NewClient("opc.tcp://example.com:4840")
+	require.NoError(t, err)
+	c.setNamespaces([]string{"http://opcfoundation.org/UA/", "urn:server", "urn:app"})
+
+	idx, err := c.NamespaceIndex(ctx, "urn:app")
+	require.NoError(t, err)
+	require.Equal(t, uint16(2), idx)
+
+	uri, err := c.NamespaceURI(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "urn:server", uri)
+
+	// unknown namespaces are looked up on the server
+	_, err = c.NamespaceIndex(ctx, "urn:other")
+	require.ErrorIs(t, err, ua.StatusBadServerNotConnected)
+	_, err = c.NamespaceURI(ctx, 3)
+	require.ErrorIs(t, err, ua.StatusBadServerNotConnected)
+
+	tests := []struct {
+		identifier interface{}
+		want       *ua.NodeID
+	}{
+		{uint32(5), ua.NewNumericNodeID(2, 5)},
+		{"a", ua.NewStringNodeID(2, "a")},
+		{ua.NewGUID("72962B91-FA75-4AE6-8D28-B404DC7DAF63"), ua.NewGUIDNodeID(2, "72962B91-FA75-4AE6-8D28-B404DC7DAF63")},
+		{[]byte{1, 2}, ua.NewByteStringNodeID(2, []byte{1, 2})},
+	}
+	for _, tt := range tests {
+		got, err := c.ResolveNodeID(ctx, "urn:app", tt.identifier)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got)
+	}
+	_, err = c.ResolveNodeID(ctx, "urn:app", 1.5)
+	require.Error(t, err)
+
+	resolveTests := []struct {
+		s    string
+		want *ua.NodeID
+	}{
+		{"nsu=urn:app;i=5", ua.NewNumericNodeID(2, 5)},
+		{"nsu=urn:app;s=a", ua.NewStringNodeID(2, "a")},
+		{"nsu=urn:app;g=72962B91-FA75-4AE6-8D28-B404DC7DAF63", ua.NewGUIDNodeID(2, "72962B91-FA75-4AE6-8D28-B404DC7DAF63")},
+		{"nsu=urn:app;b=AQI=", ua.NewByteStringNodeID(2, []byte{1, 2})},
+		{"ns=1;i=5", ua.NewFourByteNodeID(1, 5)},
+	}
+	for _, tt := range resolveTests {
+		eid, err := ua.ParseExpandedNodeID(tt.s, nil)
+		require.NoError(t, err)
+		got, err := c.ResolveExpandedNodeID(ctx, eid)
+		require.NoError(t, err, tt.s)
+		require.Equal(t, tt.want, got, tt.s)
+	}
+
+	eid, err := ua.ParseExpandedNodeID("svr=1;nsu=urn:app;i=5", nil)
+	require.NoError(t, err)
+	_, err = c.ResolveExpandedNodeID(ctx, eid)
+	require.Error(t, err)
+}
+
+func TestClient_RequestTimeout(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840", RequestTimeout(5*time.Second))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.Equal(t, 5*time.Second, c.requestTimeout(ctx))
+	require.Equal(t, 5*time.Second, c.requestTimeout(withRequestTimeout(ctx, 0)))
+	require.Equal(t, 2*time.Second, c.requestTimeout(withRequestTimeout(ctx, 2*time.Second)))
+
+	require.Equal(t, 2*time.Second, newReadConfig(ReadTimeout(2*time.Second)).timeout)
+	require.Equal(t, 2*time.Second, newWriteConfig(WriteTimeout(2*time.Second)).timeout)
+	require.Zero(t, newWriteConfig(WriteTimeout(-1)).timeout)
+}
+
+func TestReadConfigTimestamps(t *testing.T) {
+	require.Equal(t, ua.TimestampsToReturnBoth, newReadConfig().timestamps)
+	require.Equal(t, ua.TimestampsToReturnSource, newReadConfig(Timestamps(ua.TimestampsToReturnSource)).timestamps)
+}
+
+func TestClient_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "error" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	c, err := NewClient("opc.tcp://127.0.0.1:1", Logger(logger))
+	require.NoError(t, err)
+	require.Error(t, c.Connect(context.Background()))
+	require.Equal(t, "level=WARN msg=\"connect failed\" endpoint=opc.tcp://127.0.0.1:1\n", buf.String())
+}
+
+func TestRequiresSession(t *testing.T) {
+	require.True(t, requiresSession(ua.StatusBadSessionIDInvalid))
+	require.True(t, requiresSession(errors.Errorf("read: %w", ua.StatusBadServiceUnsupported)))
+	require.False(t, requiresSession(ua.StatusBadTimeout))
+	require.False(t, requiresSession(io.EOF))
+	require.False(t, requiresSession(nil))
+}
+
+func TestClient_IndexRangeInvalid(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840")
+	require.NoError(t, err, "NewClient failed")
+
+	id := ua.NewStringNodeID(1, "array")
+	_, err = c.ReadIndexRange(context.Background(), id, "20:10")
+	require.ErrorIs(t, err, ua.StatusBadIndexRangeInvalid)
+
+	status, err := c.WriteIndexRange(context.Background(), id, "1:x", []int32{1, 2})
+	require.ErrorIs(t, err, ua.StatusBadIndexRangeInvalid)
+	require.Equal(t, ua.StatusBadIndexRangeInvalid, status)
+}
@@ -0,0 +1,181 @@
+package opcua
+
+import (
+	"context"
+	"time"
+
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/ua"
+)
+
+// WriteOption is an option function type to modify a WriteValue,
+// WriteValues or BatchWrite call.
+type WriteOption func(*writeConfig)
+
+type writeConfig struct {
+	chunkSize   int
+	concurrency int
+	timeout     time.Duration
+}
+
+func newWriteConfig(opts ...WriteOption) *writeConfig {
+	cfg := &writeConfig{
+		chunkSize:   DefaultBatchWriteChunkSize,
+		concurrency: DefaultBatchConcurrency,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WriteChunkSize sets the maximum number of nodes per WriteRequest sent
+// by BatchWrite. If the server announces a lower MaxNodesPerWrite limit
+// then the limit of the server is used instead. Values below one are
+// ignored.
+func WriteChunkSize(n int) WriteOption {
+	return func(cfg *writeConfig) {
+		if n > 0 {
+			cfg.chunkSize = n
+		}
+	}
+}
+
+// WriteConcurrency sets the maximum number of WriteRequests which
+// BatchWrite sends to the server in parallel. Values below one are
+// ignored.
+func WriteConcurrency(n int) WriteOption {
+	return func(cfg *writeConfig) {
+		if n > 0 {
+			cfg.concurrency = n
+		}
+	}
+}
+
+// WriteTimeout overrides the request timeout of the client for the
+// WriteRequest of the call. Values below one are ignored.
+func WriteTimeout(d time.Duration) WriteOption {
+	return func(cfg *writeConfig) {
+		if d > 0 {
+			cfg.timeout = d
+		}
+	}
+}
+
+// WriteValue writes the value attribute of a node and returns the status
+// code of the write operation.
+//
+// The value is either a *ua.DataValue, e.g. to write a source timestamp
+// or a status code, a *ua.Variant or a value of a built-in type for
+// which the variant type is inferred. An error is returned if the
+// request failed. A bad status code of the write operation is returned
+// as status code and not as error.
+func (c *Client) WriteValue(ctx context.Context, nodeID *ua.NodeID, value interface{}, opts ...WriteOption) (ua.StatusCode, error) {
+	wv, err := newWriteValue(nodeID, value)
+	if err != nil {
+		return ua.StatusBadTypeMismatch, err
+	}
+	return c.writeOne(ctx, wv, opts...)
+}
+
+// WriteIndexRange writes the elements of an array value which are
+// selected by indexRange, e.g. "10:20" for the elements 10 to 20 of an
+// array. The value must contain exactly the selected elements, e.g. an
+// array with 11 elements for "10:20". The other elements of the array
+// are not changed. The index range is validated with ua.ParseIndexRange
+// before the request is sent. The value and the result are handled like
+// in WriteValue.
+//
+// See Part 4, 7.27
+func (c *Client) WriteIndexRange(ctx context.Context, nodeID *ua.NodeID, indexRange string, value interface{}, opts ...WriteOption) (ua.StatusCode, error) {
+	if _, err := ua.ParseIndexRange(indexRange); err != nil {
+		return ua.StatusBadIndexRangeInvalid, errors.Errorf("write %s: %w", nodeID, err)
+	}
+	wv, err := newWriteValue(nodeID, value)
+	if err != nil {
+		return ua.StatusBadTypeMismatch, err
+	}
+	wv.IndexRange = indexRange
+	return c.writeOne(ctx, wv, opts...)
+}
+
+// writeOne writes a single value and returns the status
+// code of the write operation.
+func (c *Client) writeOne(ctx context.Context, wv *ua.WriteValue, opts ...WriteOption) (ua.StatusCode, error) {
+	ctx = withRequestTimeout(ctx, newWriteConfig(opts...).timeout)
+	res, err := c.Write(ctx, &ua.WriteRequest{NodesToWrite: []*ua.WriteValue{wv}})
+	if err != nil {
+		return ua.StatusBad, err
+	}
+	if len(res.Results) != 1 {
+		return ua.StatusBad, ua.StatusBadUnknownResponse
+	}
+	return res.Results[0], nil
+}
+
+// WriteValues writes the value attributes of multiple nodes in a single
+// request and returns the status codes of the write operations by node
+// id. The values are converted like in WriteValue.
+func (c *Client) WriteValues(ctx context.Context, values map[*ua.NodeID]interface{}, opts ...WriteOption) (map[*ua.NodeID]ua.StatusCode, error) {
+	if len(values) == 0 {
+		return map[*ua.NodeID]ua.StatusCode{}, nil
+	}
+
+	ids := make([]*ua.NodeID, 0, len(values))
+	wvs := make([]*ua.WriteValue, 0, len(values))
+	for id, v := range values {
+		wv, err := newWriteValue(id, v)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+		wvs = append(wvs, wv)
+	}
+
+	ctx = withRequestTimeout(ctx, newWriteConfig(opts...).timeout)
+	res, err := c.Write(ctx, &ua.WriteRequest{NodesToWrite: wvs})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Results) != len(wvs) {
+		return nil, ua.StatusBadUnknownResponse
+	}
+	status := make(map[*ua.NodeID]ua.StatusCode, len(ids))
+	for i, id := range ids {
+		status[id] = res.Results[i]
+	}
+	return status, nil
+}
+
+// newWriteValue creates a WriteValue for the value attribute of a node.
+func newWriteValue(nodeID *ua.NodeID, value interface{}) (*ua.WriteValue, error) {
+	if nodeID == nil {
+		return nil, errors.Errorf("write: node id is nil")
+	}
+
+	var dv *ua.DataValue
+	switch x := value.(type) {
+	case *ua.DataValue:
+		if x == nil {
+			return nil, errors.Errorf("write %s: data value is nil", nodeID)
+		}
+		// copy the data value since UpdateMask modifies it
+		v := *x
+		dv = &v
+	case *ua.Variant:
+		dv = &ua.DataValue{Value: x}
+	default:
+		v, err := ua.NewVariant(value)
+		if err != nil {
+			return nil, errors.Errorf("write %s: %w", nodeID, err)
+		}
+		dv = &ua.DataValue{Value: v}
+	}
+	dv.UpdateMask()
+
+	return &ua.WriteValue{
+		NodeID:      nodeID,
+		AttributeID: ua.AttributeIDValue,
+		Value:       dv,
+	}, nil
+}
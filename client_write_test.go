@@ -0,0 +1,59 @@
+package opcua
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWriteValue(t *testing.T) {
+	id := ua.NewStringNodeID(1, "a")
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value interface{}
+		dv    *ua.DataValue
+		err   bool
+	}{
+		{
+			name:  "go value",
+			value: int32(5),
+			dv:    &ua.DataValue{EncodingMask: ua.DataValueValue, Value: ua.MustVariant(int32(5))},
+		},
+		{
+			name:  "variant",
+			value: ua.MustVariant("x"),
+			dv:    &ua.DataValue{EncodingMask: ua.DataValueValue, Value: ua.MustVariant("x")},
+		},
+		{
+			name:  "data value",
+			value: &ua.DataValue{Value: ua.MustVariant(1.5), Status: ua.StatusUncertain, SourceTimestamp: ts},
+			dv: &ua.DataValue{
+				EncodingMask:    ua.DataValueValue | ua.DataValueStatusCode | ua.DataValueSourceTimestamp,
+				Value:           ua.MustVariant(1.5),
+				Status:          ua.StatusUncertain,
+				SourceTimestamp: ts,
+			},
+		},
+		{
+			name:  "unsupported type",
+			value: struct{}{},
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wv, err := newWriteValue(id, tt.value)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, &ua.WriteValue{NodeID: id, AttributeID: ua.AttributeIDValue, Value: tt.dv}, wv)
+		})
+	}
+}
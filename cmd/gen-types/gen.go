@@ -0,0 +1,460 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/gopcua/opcua/cmd/service/goname"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/nodeset"
+	"github.com/gopcua/opcua/ua"
+)
+
+// Type is a generated struct or enumeration.
+type Type struct {
+	// Name is the Go name of the type.
+	Name string
+
+	// Doc is the description of the data type.
+	Doc string
+
+	// EncodingID is the default binary encoding of a structure.
+	EncodingID string
+
+	IsUnion     bool
+	HasOptional bool
+
+	// Fields is the list of struct fields.
+	Fields []*Field
+
+	// Values is the list of enum values.
+	Values []Value
+}
+
+// Field is a struct field with the code to encode and decode it.
+type Field struct {
+	Name string
+	Type string
+
+	// Bit is the bit of an optional field in the encoding mask.
+	Bit int
+
+	// Present is the condition for an optional field to be encoded.
+	Present string
+
+	Encode string
+	Decode string
+}
+
+// Value is an enum value.
+type Value struct {
+	Name  string
+	Value int64
+}
+
+// scalar describes how a scalar Go type is encoded.
+type scalar struct {
+	// typ is the Go type.
+	typ string
+
+	// write and read are the methods of ua.Buffer for the type or
+	// empty if the value is encoded with WriteStruct and ReadStruct.
+	write, read string
+
+	// conv is the Go type the value is converted to for write.
+	conv string
+}
+
+// builtins contains the Go types of the built-in types.
+var builtins = map[uint32]scalar{
+	id.Boolean:        {typ: "bool", write: "Bool", read: "Bool"},
+	id.SByte:          {typ: "int8", write: "Int8", read: "Int8"},
+	id.Byte:           {typ: "uint8", write: "Uint8", read: "Byte"},
+	id.Int16:          {typ: "int16", write: "Int16", read: "Int16"},
+	id.UInt16:         {typ: "uint16", write: "Uint16", read: "Uint16"},
+	id.Int32:          {typ: "int32", write: "Int32", read: "Int32"},
+	id.UInt32:         {typ: "uint32", write: "Uint32", read: "Uint32"},
+	id.Int64:          {typ: "int64", write: "Int64", read: "Int64"},
+	id.UInt64:         {typ: "uint64", write: "Uint64", read: "Uint64"},
+	id.Float:          {typ: "float32", write: "Float32", read: "Float32"},
+	id.Double:         {typ: "float64", write: "Float64", read: "Float64"},
+	id.String:         {typ: "string", write: "String", read: "String"},
+	id.DateTime:       {typ: "time.Time", write: "Time", read: "Time"},
+	id.GUID:           {typ: "*ua.GUID"},
+	id.ByteString:     {typ: "[]byte", write: "ByteString", read: "Bytes"},
+	id.XMLElement:     {typ: "ua.XMLElement"},
+	id.NodeID:         {typ: "*ua.NodeID"},
+	id.ExpandedNodeID: {typ: "*ua.ExpandedNodeID"},
+	id.StatusCode:     {typ: "ua.StatusCode"},
+	id.QualifiedName:  {typ: "*ua.QualifiedName"},
+	id.LocalizedText:  {typ: "*ua.LocalizedText"},
+	id.Structure:      {typ: "*ua.ExtensionObject"},
+	id.DataValue:      {typ: "*ua.DataValue"},
+	id.BaseDataType:   {typ: "*ua.Variant"},
+	id.DiagnosticInfo: {typ: "*ua.DiagnosticInfo"},
+}
+
+// generator resolves data types in the node sets.
+type generator struct {
+	sets []*nodeset.NodeSet
+}
+
+// Generate returns the formatted source of the Go types for the custom
+// data types of nodes. The data types of namespace zero are resolved
+// with std.
+func Generate(pkg string, nodes, std *nodeset.NodeSet) ([]byte, error) {
+	g := &generator{sets: []*nodeset.NodeSet{nodes, std}}
+
+	var enums, structs []*Type
+	for _, n := range nodes.DataTypes() {
+		if n.NodeID.Namespace() == 0 || n.Definition == nil || n.Definition.IsOptionSet {
+			continue
+		}
+		if g.baseDataType(n.NodeID) == id.Enumeration {
+			enums = append(enums, g.enum(n))
+		}
+	}
+	for _, d := range nodes.DataTypeDefinitions() {
+		t, err := g.structure(nodes.Node(d.DataTypeID), d.Definition)
+		if err != nil {
+			return nil, err
+		}
+		structs = append(structs, t)
+	}
+
+	var b bytes.Buffer
+	err := tmplFile.Execute(&b, map[string]interface{}{
+		"Package": pkg,
+		"Time":    usesTime(structs),
+		"Enums":   enums,
+		"Structs": structs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, errors.Errorf("invalid generated code: %s", err)
+	}
+	return src, nil
+}
+
+func (g *generator) node(n *ua.NodeID) *nodeset.Node {
+	for _, s := range g.sets {
+		if x := s.Node(n); x != nil {
+			return x
+		}
+	}
+	return nil
+}
+
+func (g *generator) superType(n *ua.NodeID) *ua.NodeID {
+	for _, s := range g.sets {
+		if x := s.SuperType(n); x != nil {
+			return x
+		}
+	}
+	return nil
+}
+
+// baseDataType walks up the data type hierarchy and returns the id of
+// the first built-in data type, Structure or Enumeration. It returns
+// zero if there is none.
+func (g *generator) baseDataType(n *ua.NodeID) uint32 {
+	for seen := map[string]bool{}; n != nil && !seen[n.String()]; n = g.superType(n) {
+		seen[n.String()] = true
+		if n.Namespace() != 0 {
+			continue
+		}
+		switch v := n.IntID(); {
+		case v == id.Structure, v == id.Enumeration:
+			return v
+		case v >= uint32(ua.TypeIDBoolean) && v <= uint32(ua.TypeIDDiagnosticInfo):
+			return v
+		}
+	}
+	return 0
+}
+
+func (g *generator) enum(n *nodeset.Node) *Type {
+	t := &Type{Name: typeName(n), Doc: n.Description}
+	for _, f := range n.Definition.Fields {
+		t.Values = append(t.Values, Value{Name: t.Name + goName(f.Name), Value: f.Value})
+	}
+	return t
+}
+
+func (g *generator) structure(n *nodeset.Node, def *ua.StructureDefinition) (*Type, error) {
+	t := &Type{
+		Name:       typeName(n),
+		Doc:        n.Description,
+		EncodingID: def.DefaultEncodingID.String(),
+		IsUnion:    n.Definition.IsUnion,
+	}
+	for i, f := range n.Definition.Fields {
+		s, err := g.scalar(f)
+		if err != nil {
+			return nil, errors.Errorf("data type %s: field %s: %s", n.NodeID, f.Name, err)
+		}
+		field, err := newField(goName(f.Name), s, f)
+		if err != nil {
+			return nil, errors.Errorf("data type %s: field %s: %s", n.NodeID, f.Name, err)
+		}
+		if t.IsUnion {
+			field.Bit = i + 1
+		}
+		t.Fields = append(t.Fields, field)
+	}
+
+	bit := 0
+	for i, f := range n.Definition.Fields {
+		if !t.IsUnion && f.IsOptional {
+			t.Fields[i].Bit = bit
+			bit++
+			t.HasOptional = true
+		}
+	}
+	return t, nil
+}
+
+// scalar returns the Go type of a field value.
+func (g *generator) scalar(f *nodeset.Field) (scalar, error) {
+	dt := f.DataType
+	if dt.Namespace() == 0 {
+		if s, ok := builtins[dt.IntID()]; ok {
+			return s, nil
+		}
+	}
+
+	n := g.node(dt)
+	switch base := g.baseDataType(dt); {
+	case base == id.Enumeration:
+		if dt.Namespace() != 0 && n != nil && n.Definition != nil {
+			name := typeName(n)
+			return scalar{typ: name, write: "Int32", read: "Int32", conv: "int32"}, nil
+		}
+		return builtins[id.Int32], nil
+
+	case base == id.Structure:
+		if f.AllowSubTypes || n == nil || n.IsAbstract {
+			return builtins[id.Structure], nil
+		}
+		if dt.Namespace() != 0 {
+			return scalar{typ: "*" + typeName(n)}, nil
+		}
+		return scalar{typ: "*ua." + typeName(n)}, nil
+
+	case base > 0:
+		if f.AllowSubTypes {
+			return builtins[id.BaseDataType], nil
+		}
+		return builtins[base], nil
+
+	default:
+		return scalar{}, errors.Errorf("unknown data type %s", dt)
+	}
+}
+
+// newField returns the field with the code to encode and decode it.
+// The code uses the variables t for the value and buf for the buffer.
+// Optional fields of value types are pointers.
+func newField(name string, s scalar, f *nodeset.Field) (*Field, error) {
+	x := "t." + name
+	fd := &Field{Name: name, Type: s.typ}
+	if f.IsOptional {
+		fd.Present = x + " != nil"
+	}
+
+	switch {
+	case f.ValueRank == 1:
+		fd.Type = "[]" + s.typ
+		fd.Encode = fmt.Sprintf("buf.WriteStruct(%s)", x)
+		fd.Decode = fmt.Sprintf("buf.ReadStruct(&%s)", x)
+		return fd, nil
+
+	case f.ValueRank != -1:
+		return nil, errors.Errorf("unsupported value rank %d", f.ValueRank)
+
+	case strings.HasPrefix(s.typ, "*"):
+		fd.Encode = fmt.Sprintf("buf.WriteStruct(%s)", x)
+		fd.Decode = fmt.Sprintf("%s = new(%s)\nbuf.ReadStruct(%s)", x, s.typ[1:], x)
+		return fd, nil
+	}
+
+	// v is the value of the field which is
+	// dereferenced for optional fields.
+	v := x
+	if f.IsOptional && s.typ != "[]byte" {
+		fd.Type = "*" + s.typ
+		v = "*" + x
+	}
+
+	switch {
+	case s.write == "":
+		fd.Encode = fmt.Sprintf("buf.WriteStruct(%s)", x)
+	case s.conv != "":
+		fd.Encode = fmt.Sprintf("buf.Write%s(%s(%s))", s.write, s.conv, v)
+	default:
+		fd.Encode = fmt.Sprintf("buf.Write%s(%s)", s.write, v)
+	}
+
+	switch {
+	case s.read == "" && v != x:
+		fd.Decode = fmt.Sprintf("buf.ReadStruct(%s)", x)
+	case s.read == "":
+		fd.Decode = fmt.Sprintf("buf.ReadStruct(&%s)", x)
+	case s.conv != "":
+		fd.Decode = fmt.Sprintf("%s = %s(buf.Read%s())", v, s.typ, s.read)
+	default:
+		fd.Decode = fmt.Sprintf("%s = buf.Read%s()", v, s.read)
+	}
+	if v != x {
+		fd.Decode = fmt.Sprintf("%s = new(%s)\n%s", x, s.typ, fd.Decode)
+	}
+	return fd, nil
+}
+
+// usesTime returns true if one of the structs has a time.Time field.
+func usesTime(types []*Type) bool {
+	for _, t := range types {
+		for _, f := range t.Fields {
+			if strings.Contains(f.Type, "time.Time") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// typeName returns the Go name of a data type.
+func typeName(n *nodeset.Node) string {
+	if n.SymbolicName != "" {
+		return goName(n.SymbolicName)
+	}
+	return goName(n.BrowseName.Name)
+}
+
+// goName returns an exported Go identifier for an OPC/UA name.
+func goName(s string) string {
+	var b strings.Builder
+	for _, r := range goname.Format(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		name = "X" + name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+var tmplFile = template.Must(template.New("").Parse(`
+// Code generated by cmd/gen-types. DO NOT EDIT!
+
+package {{.Package}}
+
+import (
+	{{if .Time}}"time"{{end}}
+
+	"github.com/gopcua/opcua/ua"
+)
+{{range .Enums}}
+{{if .Doc}}// {{.Name}} {{.Doc}}
+{{end -}}
+type {{.Name}} int32
+
+{{$name := .Name -}}
+const (
+	{{range .Values}}{{.Name}} {{$name}} = {{.Value}}
+	{{end}}
+)
+{{end}}
+{{range .Structs}}
+{{if .Doc}}// {{.Name}} {{.Doc}}
+{{end -}}
+type {{.Name}} struct {
+	{{- if .IsUnion}}
+	SwitchField uint32
+	{{- end}}
+	{{range .Fields}}{{.Name}} {{.Type}}
+	{{end}}
+}
+
+func (t *{{.Name}}) Encode() ([]byte, error) {
+	buf := ua.NewBuffer(nil)
+	{{- if .IsUnion}}
+	buf.WriteUint32(t.SwitchField)
+	switch t.SwitchField {
+	{{- range .Fields}}
+	case {{.Bit}}:
+		{{.Encode}}
+	{{- end}}
+	}
+	{{- else}}
+	{{- if .HasOptional}}
+	var mask uint32
+	{{- range .Fields}}{{if .Present}}
+	if {{.Present}} {
+		mask |= 1 << {{.Bit}}
+	}
+	{{- end}}{{end}}
+	buf.WriteUint32(mask)
+	{{- end}}
+	{{- range .Fields}}
+	{{- if .Present}}
+	if {{.Present}} {
+		{{.Encode}}
+	}
+	{{- else}}
+	{{.Encode}}
+	{{- end}}
+	{{- end}}
+	{{- end}}
+	return buf.Bytes(), buf.Error()
+}
+
+func (t *{{.Name}}) Decode(b []byte) (int, error) {
+	buf := ua.NewBuffer(b)
+	{{- if .IsUnion}}
+	t.SwitchField = buf.ReadUint32()
+	switch t.SwitchField {
+	{{- range .Fields}}
+	case {{.Bit}}:
+		{{.Decode}}
+	{{- end}}
+	}
+	{{- else}}
+	{{- if .HasOptional}}
+	mask := buf.ReadUint32()
+	{{- end}}
+	{{- range .Fields}}
+	{{- if .Present}}
+	if mask&(1<<{{.Bit}}) != 0 {
+		{{.Decode}}
+	}
+	{{- else}}
+	{{.Decode}}
+	{{- end}}
+	{{- end}}
+	{{- end}}
+	return buf.Pos(), buf.Error()
+}
+{{end}}
+{{- if .Structs}}
+func init() {
+	{{- range .Structs}}
+	ua.RegisterExtensionObject(ua.MustParseNodeID("{{.EncodingID}}"), new({{.Name}}))
+	{{- end}}
+}
+{{- end}}
+`))
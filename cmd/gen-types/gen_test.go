@@ -0,0 +1,54 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gopcua/opcua/nodeset"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	f, err := os.Open("testdata/Pumps.NodeSet2.xml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	nodes, err := nodeset.Parse(f)
+	require.NoError(t, err, "Parse failed")
+	std, err := nodeset.Standard()
+	require.NoError(t, err, "Standard failed")
+
+	got, err := Generate("pumps", nodes, std)
+	require.NoError(t, err, "Generate failed")
+
+	want, err := os.ReadFile("testdata/types_gen.go.golden")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+}
+
+func TestGenerateUnsupported(t *testing.T) {
+	xml := `<UANodeSet>
+  <UADataType NodeId="ns=1;i=1" BrowseName="1:Matrix">
+    <References>
+      <Reference ReferenceType="HasSubtype" IsForward="false">i=22</Reference>
+      <Reference ReferenceType="HasEncoding">ns=1;i=2</Reference>
+    </References>
+    <Definition Name="1:Matrix">
+      <Field Name="Values" DataType="i=11" ValueRank="2" />
+    </Definition>
+  </UADataType>
+  <UAObject NodeId="ns=1;i=2" BrowseName="Default Binary" />
+</UANodeSet>`
+	nodes, err := nodeset.Parse(strings.NewReader(xml))
+	require.NoError(t, err, "Parse failed")
+	std, err := nodeset.Standard()
+	require.NoError(t, err, "Standard failed")
+
+	_, err = Generate("pumps", nodes, std)
+	require.ErrorContains(t, err, "unsupported value rank 2")
+}
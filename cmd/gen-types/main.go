@@ -0,0 +1,72 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command gen-types generates Go types for the custom data types of a
+// NodeSet2 file.
+//
+// For each structured data type with a default binary encoding it emits
+// a struct with Encode and Decode methods and registers it with
+// ua.RegisterExtensionObject in an init function. Values of these types
+// are then decoded into the generated structs instead of an extension
+// object without a value. Enumerations are emitted as int32 types with
+// a constant for each value.
+//
+// The generated code uses the node ids of the NodeSet2 file, i.e. the
+// namespace indexes of the file must match the namespace indexes of the
+// server.
+//
+// Usage:
+//
+//	//go:generate go run github.com/gopcua/opcua/cmd/gen-types -in Pumps.NodeSet2.xml -out types_gen.go -pkg pumps
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/gopcua/opcua/nodeset"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	in := flag.String("in", "", "Path to the NodeSet2 file")
+	out := flag.String("out", "types_gen.go", "Path to the generated file")
+	pkg := flag.String("pkg", "", "Go package name")
+	flag.Parse()
+
+	if *in == "" {
+		log.Fatal("-in is required")
+	}
+	if *pkg == "" {
+		log.Fatal("-pkg is required")
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", *in, err)
+	}
+	defer f.Close()
+
+	nodes, err := nodeset.Parse(f)
+	if err != nil {
+		log.Fatalf("Error parsing %s: %v", *in, err)
+	}
+
+	// the standard nodeset resolves the data types of namespace zero
+	std, err := nodeset.Standard()
+	if err != nil {
+		log.Fatalf("Error parsing the standard nodeset: %v", err)
+	}
+
+	src, err := Generate(*pkg, nodes, std)
+	if err != nil {
+		log.Fatalf("Error generating types: %v", err)
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+	log.Printf("Wrote %s", *out)
+}
@@ -227,6 +227,15 @@ func (t Type) IsResponse() bool {
 	return len(t.Fields) > 0 && t.Fields[0].Type == "*ResponseHeader"
 }
 
+func (t Type) HasDiagnosticInfos() bool {
+	for _, f := range t.Fields {
+		if f.Name == "DiagnosticInfos" && f.Type == "[]*DiagnosticInfo" {
+			return true
+		}
+	}
+	return false
+}
+
 type Value struct {
 	Name      string
 	ShortName string
@@ -326,6 +335,13 @@ func (t *{{.Name}}) Header() *ResponseHeader {
 func (t *{{.Name}}) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
+{{- if .HasDiagnosticInfos}}
+
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *{{.Name}}) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+{{- end}}
 {{- end}}
 `))
 
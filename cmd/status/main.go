@@ -51,7 +51,9 @@ func main() {
 	}
 
 	// goify the name and prefix with Status
+	// and keep the symbolic name of the specification
 	for i := range rows {
+		rows[i] = append(rows[i], rows[i][0])
 		rows[i][0] = "Status" + goname.Format(rows[i][0])
 	}
 
@@ -86,8 +88,8 @@ import "fmt"
 type StatusCode uint32
 
 func (n StatusCode) Error() string {
-	if d, ok := StatusCodes[n]; ok {
-		return fmt.Sprintf("%s %s (0x%X)", d.Text, d.Name, uint32(n))
+	if d, ok := StatusCodes[n&0xffff0000]; ok {
+		return fmt.Sprintf("%s %s (0x%X)", d.Text, d.Symbol, uint32(n))
 	}
 	return fmt.Sprintf("0x%X", uint32(n))
 }
@@ -99,16 +101,22 @@ var (
 )
 
 type StatusCodeDesc struct {
+	// Name is the name of the Go constant.
 	Name string
+
+	// Symbol is the symbolic name of the specification.
+	Symbol string
+
+	// Text is the description of the status code.
 	Text string
 }
 
 // StatusCodes maps status codes to the status code error types.
 var StatusCodes = map[StatusCode]StatusCodeDesc{
-	StatusOK: {Name: "OK", Text: ""},
-	StatusUncertain: {Name: "Uncertain", Text: ""},
-	StatusBad: {Name: "Bad", Text: ""},
-	{{range .}}{{index . 0}}: { Name: "{{index . 0}}", Text: {{index . 2}} },
+	StatusOK: {Name: "OK", Symbol: "Good", Text: ""},
+	StatusUncertain: {Name: "Uncertain", Symbol: "Uncertain", Text: ""},
+	StatusBad: {Name: "Bad", Symbol: "Bad", Text: ""},
+	{{range .}}{{index . 0}}: { Name: "{{index . 0}}", Symbol: "{{index . 3}}", Text: {{index . 2}} },
 	{{end}}
 }
 `))
@@ -10,9 +10,12 @@ import (
 	"encoding/pem"
 	"fmt"
 	"log"
+	"log/slog"
 	"math/rand"
 	"net"
+	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -58,19 +61,59 @@ func DefaultSessionConfig() *uasc.SessionConfig {
 
 // Config contains all config options.
 type Config struct {
-	dialer    *uacp.Dialer
-	sechan    *uasc.Config
-	session   *uasc.SessionConfig
-	stateCh   chan<- ConnState
-	stateFunc func(ConnState)
+	dialer       *uacp.Dialer
+	sechan       *uasc.Config
+	session      *uasc.SessionConfig
+	stateCh      chan<- ConnState
+	stateFunc    func(ConnState)
+	stateHandler func(oldState, newState ConnState)
+
+	skipApplicationURICheck bool
+
+	sessionless bool
+
+	// appURISet is true if the application uri has been set with
+	// ApplicationURI and must not be taken from the certificate.
+	appURISet bool
+
+	// ephemeralURI is the application uri of the certificate which has
+	// been generated with WithEphemeralCert.
+	ephemeralURI string
+
+	attrCache    []ua.AttributeID
+	attrCacheTTL time.Duration
+
+	reconnectBackoff     *backoff
+	maxReconnectAttempts int
+
+	tracer RequestTracer
+
+	reverseServerURIs []string
+
+	trustStore      *trustStore
+	ocsp            bool
+	allowMissingCRL bool
+
+	subRecreatedFunc func(oldID uint32, sub *Subscription)
+
+	notifBufferSize int
+	notifDropPolicy DropPolicy
+
+	// keepAliveMultiplier is the multiplier of the keep-alive watchdog.
+	// Zero disables it.
+	keepAliveMultiplier float64
+
+	cancelOnContextDone bool
 }
 
 func DefaultDialer() *uacp.Dialer {
+	// copy the ACK since the options modify it
+	ack := *uacp.DefaultClientACK
 	return &uacp.Dialer{
 		Dialer: &net.Dialer{
 			Timeout: DefaultDialTimeout,
 		},
-		ClientACK: uacp.DefaultClientACK,
+		ClientACK: &ack,
 	}
 }
 
@@ -93,6 +136,16 @@ func ApplyConfig(opts ...Option) (*Config, error) {
 			errs = append(errs, err)
 		}
 	}
+	if err := checkKeyPair(cfg.sechan.Certificate, cfg.sechan.LocalKey); err != nil {
+		errs = append(errs, err)
+	}
+	if uri := cfg.session.ClientDescription.ApplicationURI; cfg.ephemeralURI != "" && uri != cfg.ephemeralURI {
+		errs = append(errs, errors.Errorf("%w: ApplicationURI is %q but the ephemeral certificate has %q", ErrApplicationURIMismatch, uri, cfg.ephemeralURI))
+	}
+	if cfg.trustStore != nil {
+		cfg.trustStore.ocsp = cfg.ocsp
+		cfg.trustStore.allowMissingCRL = cfg.allowMissingCRL
+	}
 	return cfg, errors.Join(errs...)
 }
 
@@ -100,6 +153,8 @@ func ApplyConfig(opts ...Option) (*Config, error) {
 type Option func(*Config) error
 
 // ApplicationName sets the application name in the session configuration.
+// It is sent with the application description of the client when the
+// session is created and some servers use it to log or filter clients.
 func ApplicationName(s string) Option {
 	return func(cfg *Config) error {
 		cfg.session.ClientDescription.ApplicationName = ua.NewLocalizedText(s)
@@ -108,9 +163,15 @@ func ApplicationName(s string) Option {
 }
 
 // ApplicationURI sets the application uri in the session configuration.
+//
+// The uri takes precedence over the uri of the client certificate
+// regardless of the order of the options. It must match a uri in the
+// subject alternative name of the certificate. Otherwise, Dial returns
+// ErrApplicationURIMismatch unless SkipApplicationURICheck is set.
 func ApplicationURI(s string) Option {
 	return func(cfg *Config) error {
 		cfg.session.ClientDescription.ApplicationURI = s
+		cfg.appURISet = true
 		return nil
 	}
 }
@@ -124,9 +185,48 @@ func AutoReconnect(b bool) Option {
 }
 
 // ReconnectInterval is interval duration between each reconnection attempt.
+//
+// It replaces a backoff strategy configured with ReconnectBackoff.
 func ReconnectInterval(d time.Duration) Option {
 	return func(cfg *Config) error {
 		cfg.sechan.ReconnectInterval = d
+		cfg.reconnectBackoff = nil
+		return nil
+	}
+}
+
+// ReconnectBackoff configures an exponential backoff between reconnection
+// attempts. The first attempt is delayed by initial and the delay is
+// multiplied by factor after every failed attempt up to max. Every delay
+// is randomly reduced by up to jitter (0..1) of its value so that clients
+// do not reconnect at the same time after a server restart. The delay is
+// reset to initial after a successful reconnect or Client.Ping.
+func ReconnectBackoff(initial, max time.Duration, factor float64, jitter float64) Option {
+	return func(cfg *Config) error {
+		switch {
+		case initial <= 0:
+			return errors.Errorf("reconnect backoff: initial interval must be positive")
+		case max < initial:
+			return errors.Errorf("reconnect backoff: max interval %s is less than initial interval %s", max, initial)
+		case factor < 1:
+			return errors.Errorf("reconnect backoff: factor %v is less than 1", factor)
+		case jitter < 0 || jitter > 1:
+			return errors.Errorf("reconnect backoff: jitter %v is not between 0 and 1", jitter)
+		}
+		cfg.sechan.ReconnectInterval = initial
+		cfg.reconnectBackoff = &backoff{initial: initial, max: max, factor: factor, jitter: jitter}
+		return nil
+	}
+}
+
+// MaxReconnectAttempts limits the number of consecutive failed attempts
+// to recreate the secure channel after the connection was lost. When the
+// limit is reached the client stays in the Disconnected state and its
+// operations return ErrReconnectGaveUp. n <= 0 means unlimited which is
+// the default.
+func MaxReconnectAttempts(n int) Option {
+	return func(cfg *Config) error {
+		cfg.maxReconnectAttempts = max(n, 0)
 		return nil
 	}
 }
@@ -147,7 +247,9 @@ func Locales(locale ...string) Option {
 	}
 }
 
-// ProductURI sets the product uri in the session configuration.
+// ProductURI sets the product uri in the session configuration. It is
+// sent with the application description of the client when the session
+// is created.
 func ProductURI(s string) Option {
 	return func(cfg *Config) error {
 		cfg.session.ClientDescription.ProductURI = s
@@ -199,6 +301,135 @@ func RemoteCertificateFile(filename string) Option {
 	}
 }
 
+// VerifyServerCertificate sets the function which decides whether the
+// certificate presented by the server in the OpenSecureChannel response
+// is trusted. cert is the server certificate and chain contains the
+// complete certificate chain presented by the server starting with cert.
+// If f returns an error the secure channel is not opened.
+//
+// This can be used to pin the server certificate or to implement a
+// custom trust list. Without it the client does not verify the server
+// certificate. See TrustStore for a trust list in a directory.
+func VerifyServerCertificate(f func(cert *x509.Certificate, chain [][]*x509.Certificate) error) Option {
+	return func(cfg *Config) error {
+		cfg.sechan.VerifyCertificate = f
+		return nil
+	}
+}
+
+// TrustStore verifies the server certificate against the certificate
+// store in dir which uses the directory layout of Part 12, F.1:
+//
+//	dir/trusted/certs  trusted CA and server certificates
+//	dir/trusted/crl    revocation lists of the trusted CAs
+//	dir/issuers/certs  CA certificates to build the chain
+//	dir/issuers/crl    revocation lists of the issuer CAs
+//	dir/rejected/certs untrusted server certificates
+//
+// The server certificate is trusted if it or one of its issuers is in
+// the trusted folder, the chain is valid and no certificate of the chain
+// is on a revocation list of its issuer. Certificates and revocation
+// lists are read as DER or, with the .pem extension, as PEM encoded
+// files. Missing directories are created.
+//
+// The revocation status of a certificate is taken from a current CRL of
+// its issuer. If there is none, the OCSP responders of the certificate
+// are queried when enabled with the OCSP option. Otherwise, the status
+// is unknown. Every CA of the chain must therefore have a CRL in the
+// store unless AllowMissingCRL is set. The secure channel is not opened
+// and the error wraps ua.StatusBadCertificateRevoked if a certificate
+// has been revoked and ua.StatusBadCertificateRevocationUnknown if its
+// status cannot be determined.
+//
+// Untrusted server certificates are written to the rejected folder and
+// the secure channel is not opened. The operator approves a server by
+// moving its certificate to the trusted folder. The store is read every
+// time a secure channel is opened.
+//
+// TrustStore replaces a function set with VerifyServerCertificate.
+func TrustStore(dir string) Option {
+	return func(cfg *Config) error {
+		s, err := newTrustStore(dir)
+		if err != nil {
+			return err
+		}
+		cfg.sechan.VerifyCertificate = s.verify
+		cfg.trustStore = s
+		return nil
+	}
+}
+
+// OCSP enables OCSP queries to check the revocation status of server
+// and CA certificates for which the trust store has no CRL of their
+// issuer. The responders listed in the certificate are queried with a
+// timeout of DefaultOCSPTimeout. If none of them reports the status the
+// certificate is rejected with ua.StatusBadCertificateRevocationUnknown.
+//
+// OCSP requires outbound HTTP connections to the responders of the
+// certificate authorities and is disabled by default. It has no effect
+// without TrustStore.
+func OCSP(enable bool) Option {
+	return func(cfg *Config) error {
+		cfg.ocsp = enable
+		return nil
+	}
+}
+
+// AllowMissingCRL accepts certificates whose issuer has no revocation
+// list in the trust store if OCSP is disabled. Certificates whose issuer
+// has only an expired revocation list are still rejected.
+//
+// Without revocation lists revoked certificates are not detected. It has
+// no effect without TrustStore.
+func AllowMissingCRL() Option {
+	return func(cfg *Config) error {
+		cfg.allowMissingCRL = true
+		return nil
+	}
+}
+
+// SecureChannelRenewedHandler sets a function which is called with the
+// ids of the old and the new security token every time the client has
+// renewed the security token of the secure channel. The function must
+// not block.
+func SecureChannelRenewedHandler(f func(old, new uint32)) Option {
+	return func(cfg *Config) error {
+		cfg.sechan.TokenRenewedFunc = f
+		return nil
+	}
+}
+
+// Logger sets the structured logger of the client. It receives the key
+// events of the connection, the secure channel, the session and the
+// subscriptions. Without it the events are logged to the debug logger
+// when debug logging is enabled with OPC_DEBUG=debug.
+func Logger(l *slog.Logger) Option {
+	return func(cfg *Config) error {
+		cfg.sechan.Logger = l
+		return nil
+	}
+}
+
+// Tracer sets the tracer which creates a span for every service request
+// of the client, e.g. Read, Write, Browse, Call and Publish. The spans
+// are children of the span in the context of the request.
+func Tracer(t RequestTracer) Option {
+	return func(cfg *Config) error {
+		cfg.tracer = t
+		return nil
+	}
+}
+
+// ReverseServerURIs sets the application uris of the servers from which
+// ListenReverse accepts reverse connections. By default connections from
+// all servers are accepted.
+func ReverseServerURIs(uris ...string) Option {
+	return func(cfg *Config) error {
+		cfg.reverseServerURIs = uris
+		return nil
+	}
+}
+
 // SecurityMode sets the security mode for the secure channel.
 func SecurityMode(m ua.MessageSecurityMode) Option {
 	return func(cfg *Config) error {
@@ -217,9 +448,16 @@ func SecurityModeString(s string) Option {
 }
 
 // SecurityPolicy sets the security policy uri for the secure channel.
+// The policy is either a uri or the name of a policy, e.g.
+// Basic256Sha256 or Aes256_Sha256_RsaPss. An error is returned for
+// policies which are not supported. See uapolicy.SupportedPolicies.
 func SecurityPolicy(s string) Option {
 	return func(cfg *Config) error {
-		cfg.sechan.SecurityPolicyURI = ua.FormatSecurityPolicyURI(s)
+		uri := ua.FormatSecurityPolicyURI(s)
+		if uri != "" && !slices.Contains(uapolicy.SupportedPolicies(), uri) {
+			return errors.Errorf("unsupported security policy %s", s)
+		}
+		cfg.sechan.SecurityPolicyURI = uri
 		return nil
 	}
 }
@@ -232,6 +470,30 @@ func SessionName(s string) Option {
 	}
 }
 
+// Sessionless connects without a session for discovery-only use, e.g.
+// to probe a server with a few calls over a short-lived connection.
+//
+// If enabled, Connect only opens the secure channel and reads the
+// namespace array without creating a session. If the server answers the
+// request then the client sends all requests without a session. If the
+// server rejects it because it requires a session then Connect creates
+// one as usual.
+//
+// This is not the SessionlessInvoke service invocation of Part 4, 5.4.
+// The requests are sent with a null authentication token and are not
+// wrapped in a SessionlessInvokeRequest, i.e. the client sends neither
+// the namespace and server uris nor an access token. Servers usually
+// only answer the discovery services like this and some also answer
+// Read and Browse. Subscriptions and all other services which need a
+// session fail and the client does not reconnect automatically without
+// one.
+func Sessionless(b bool) Option {
+	return func(cfg *Config) error {
+		cfg.sessionless = b
+		return nil
+	}
+}
+
 // SessionTimeout sets the timeout in the session configuration.
 func SessionTimeout(d time.Duration) Option {
 	return func(cfg *Config) error {
@@ -264,6 +526,47 @@ func PrivateKeyFile(filename string) Option {
 	}
 }
 
+// PrivateKeyPEM sets the RSA private key in the secure channel configuration
+// from a PEM encoded PKCS #1 or PKCS #8 key.
+func PrivateKeyPEM(b []byte) Option {
+	return func(cfg *Config) error {
+		key, err := parsePrivateKeyPEM(b)
+		if err != nil {
+			return err
+		}
+		cfg.sechan.LocalKey = key
+		return nil
+	}
+}
+
+func parsePrivateKeyPEM(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.Errorf("Failed to decode PEM block with private key")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		pk, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Errorf("Failed to parse private key: %s", err)
+		}
+		return pk, nil
+	case "PRIVATE KEY":
+		k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Errorf("Failed to parse private key: %s", err)
+		}
+		pk, ok := k.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.Errorf("Failed to parse private key: got %T, want RSA private key", k)
+		}
+		return pk, nil
+	default:
+		return nil, errors.Errorf("Failed to decode PEM block with private key: unsupported type %q", block.Type)
+	}
+}
+
 func loadPrivateKey(filename string) (*rsa.PrivateKey, error) {
 	b, err := os.ReadFile(filename)
 	if err != nil {
@@ -287,7 +590,8 @@ func loadPrivateKey(filename string) (*rsa.PrivateKey, error) {
 }
 
 // Certificate sets the client X509 certificate in the secure channel configuration.
-// It also detects and sets the ApplicationURI from the URI within the certificate.
+// It also detects and sets the ApplicationURI from the URI within the certificate
+// unless it has been set with the ApplicationURI option.
 func Certificate(cert []byte) Option {
 	return func(cfg *Config) error {
 		return setCertificate(cert, cfg)
@@ -296,7 +600,8 @@ func Certificate(cert []byte) Option {
 
 // CertificateFile sets the client X509 certificate in the secure channel configuration
 // from the PEM or DER encoded file. It also detects and sets the ApplicationURI
-// from the URI within the certificate.
+// from the URI within the certificate unless it has been set with the
+// ApplicationURI option.
 func CertificateFile(filename string) Option {
 	return func(cfg *Config) error {
 		if filename == "" {
@@ -311,6 +616,47 @@ func CertificateFile(filename string) Option {
 	}
 }
 
+// CertificatePEM sets the client X509 certificate in the secure channel configuration
+// from a PEM encoded certificate. It also detects and sets the ApplicationURI
+// from the URI within the certificate unless it has been set with the
+// ApplicationURI option. If b contains more than one certificate then they
+// are used as certificate chain. See CertificateChain.
+func CertificatePEM(b []byte) Option {
+	return func(cfg *Config) error {
+		cert, err := decodeCertificatePEM(b)
+		if err != nil {
+			return err
+		}
+		return setCertificate(cert, cfg)
+	}
+}
+
+// CertificateChain sets the client X509 certificate and the certificates
+// of the intermediate CAs which issued it in the secure channel
+// configuration. The DER encoded leaf certificate must be the first
+// element followed by its issuers. The client sends the concatenated
+// chain as its certificate so that the server can validate the leaf
+// certificate. It also detects and sets the ApplicationURI from the URI
+// within the leaf certificate unless it has been set with the
+// ApplicationURI option.
+//
+// See Part 6, 6.7.2.3
+func CertificateChain(leafAndIntermediates [][]byte) Option {
+	return func(cfg *Config) error {
+		if len(leafAndIntermediates) == 0 {
+			return errors.Errorf("certificate chain is empty")
+		}
+		var chain []byte
+		for i, cert := range leafAndIntermediates {
+			if _, err := x509.ParseCertificate(cert); err != nil {
+				return errors.Errorf("certificate %d of chain: %s", i, err)
+			}
+			chain = append(chain, cert...)
+		}
+		return setCertificate(chain, cfg)
+	}
+}
+
 func loadCertificate(filename string) ([]byte, error) {
 	b, err := os.ReadFile(filename)
 	if err != nil {
@@ -320,23 +666,36 @@ func loadCertificate(filename string) ([]byte, error) {
 	if !strings.HasSuffix(filename, ".pem") {
 		return b, nil
 	}
+	return decodeCertificatePEM(b)
+}
 
-	block, _ := pem.Decode(b)
-	if block == nil || block.Type != "CERTIFICATE" {
+// decodeCertificatePEM returns the concatenated DER encoded certificates
+// of all certificate PEM blocks in b.
+func decodeCertificatePEM(b []byte) ([]byte, error) {
+	var chain []byte
+	for {
+		block, rest := pem.Decode(b)
+		if block == nil || block.Type != "CERTIFICATE" {
+			break
+		}
+		chain = append(chain, block.Bytes...)
+		b = rest
+	}
+	if len(chain) == 0 {
 		return nil, errors.Errorf("Failed to decode PEM block with certificate")
 	}
-	return block.Bytes, nil
+	return chain, nil
 }
 
 func setCertificate(cert []byte, cfg *Config) error {
 	cfg.sechan.Certificate = cert
 
-	// Extract the application URI from the certificate.
-	x509cert, err := x509.ParseCertificate(cert)
+	// Extract the application URI from the leaf certificate.
+	x509cert, err := uapolicy.LeafCertificate(cert)
 	if err != nil {
 		return fmt.Errorf("failed to parse certificate: %s", err)
 	}
-	if len(x509cert.URIs) == 0 {
+	if len(x509cert.URIs) == 0 || cfg.appURISet {
 		return nil
 	}
 	appURI := x509cert.URIs[0].String()
@@ -347,6 +706,59 @@ func setCertificate(cert []byte, cfg *Config) error {
 	return nil
 }
 
+// checkKeyPair returns an error if both the client certificate and the
+// private key are set but the key does not belong to the certificate.
+func checkKeyPair(cert []byte, key *rsa.PrivateKey) error {
+	if len(cert) == 0 || key == nil {
+		return nil
+	}
+	x509cert, err := uapolicy.LeafCertificate(cert)
+	if err != nil {
+		// already reported when the certificate was set
+		return nil
+	}
+	pub, ok := x509cert.PublicKey.(*rsa.PublicKey)
+	if !ok || !pub.Equal(&key.PublicKey) {
+		return errors.Errorf("private key does not match the public key of the client certificate")
+	}
+	return nil
+}
+
+// ErrApplicationURIMismatch is returned when the client is connected and
+// the application uri of the client does not match the uri in the
+// subject alternative name of the client certificate. It is also
+// returned by the options if the uri conflicts with WithEphemeralCert.
+var ErrApplicationURIMismatch = errors.New("application uri does not match the client certificate")
+
+// checkApplicationURI returns ErrApplicationURIMismatch if the certificate
+// is set and does not contain appURI as uri in its subject alternative name.
+func checkApplicationURI(cert []byte, appURI string) error {
+	if len(cert) == 0 {
+		return nil
+	}
+	x509cert, err := uapolicy.LeafCertificate(cert)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate: %s", err)
+	}
+	var uris []string
+	for _, u := range x509cert.URIs {
+		if u.String() == appURI {
+			return nil
+		}
+		uris = append(uris, u.String())
+	}
+	return fmt.Errorf("%w: ApplicationURI is %q but certificate has %q", ErrApplicationURIMismatch, appURI, uris)
+}
+
+// SkipApplicationURICheck disables the check that the application uri
+// matches the uri in the client certificate before the client connects.
+func SkipApplicationURICheck() Option {
+	return func(cfg *Config) error {
+		cfg.skipApplicationURICheck = true
+		return nil
+	}
+}
+
 // SecurityFromEndpoint sets the server-related security parameters from
 // a chosen endpoint (received from GetEndpoints())
 func SecurityFromEndpoint(ep *ua.EndpointDescription, authType ua.UserTokenType) Option {
@@ -362,16 +774,7 @@ func SecurityFromEndpoint(ep *ua.EndpointDescription, authType ua.UserTokenType)
 			}
 
 			if cfg.session.UserIdentityToken == nil {
-				switch authType {
-				case ua.UserTokenTypeAnonymous:
-					cfg.session.UserIdentityToken = &ua.AnonymousIdentityToken{}
-				case ua.UserTokenTypeUserName:
-					cfg.session.UserIdentityToken = &ua.UserNameIdentityToken{}
-				case ua.UserTokenTypeCertificate:
-					cfg.session.UserIdentityToken = &ua.X509IdentityToken{}
-				case ua.UserTokenTypeIssuedToken:
-					cfg.session.UserIdentityToken = &ua.IssuedIdentityToken{}
-				}
+				cfg.session.UserIdentityToken = newUserIdentityToken(authType)
 			}
 
 			setPolicyID(cfg.session.UserIdentityToken, t.PolicyID)
@@ -391,6 +794,23 @@ func SecurityFromEndpoint(ep *ua.EndpointDescription, authType ua.UserTokenType)
 	}
 }
 
+// newUserIdentityToken returns an empty user identity token of the given
+// type or nil if the type is invalid.
+func newUserIdentityToken(t ua.UserTokenType) interface{} {
+	switch t {
+	case ua.UserTokenTypeAnonymous:
+		return &ua.AnonymousIdentityToken{}
+	case ua.UserTokenTypeUserName:
+		return &ua.UserNameIdentityToken{}
+	case ua.UserTokenTypeCertificate:
+		return &ua.X509IdentityToken{}
+	case ua.UserTokenTypeIssuedToken:
+		return &ua.IssuedIdentityToken{}
+	default:
+		return nil
+	}
+}
+
 func setPolicyID(t interface{}, policy string) {
 	switch tok := t.(type) {
 	case *ua.AnonymousIdentityToken:
@@ -493,28 +913,35 @@ func AuthPrivateKey(key *rsa.PrivateKey) Option {
 	}
 }
 
-// AuthIssuedToken sets the client's authentication data based on an externally-issued token
+// AuthIssuedToken sets the client's authentication data based on an externally-issued
+// token, e.g. a JWT or an OAuth2 access token. The token data is encrypted with the
+// server nonce according to the security policy of the user token policy before it
+// is sent in ActivateSession.
+//
 // Note: PolicyID still needs to be set outside of this method, typically through
-// the SecurityFromEndpoint() Option
+// the SecurityFromEndpoint() or AuthPolicyID() Option
 func AuthIssuedToken(tokenData []byte) Option {
 	return func(cfg *Config) error {
 		if cfg.session.UserIdentityToken == nil {
 			cfg.session.UserIdentityToken = &ua.IssuedIdentityToken{}
 		}
 
-		t, ok := cfg.session.UserIdentityToken.(*ua.IssuedIdentityToken)
-		if !ok {
+		if _, ok := cfg.session.UserIdentityToken.(*ua.IssuedIdentityToken); !ok {
 			log.Printf("non-issued token authentication already configured, ignoring")
 			return nil
 		}
 
-		// todo(dw): not correct; need to read spec
-		t.TokenData = tokenData
+		cfg.session.AuthTokenData = tokenData
 		return nil
 	}
 }
 
-// RequestTimeout sets the timeout for all requests over SecureChannel
+// RequestTimeout sets the timeout for all requests over SecureChannel.
+//
+// The timeout is sent to the server as TimeoutHint and the client stops
+// waiting for the response when it expires, even if the context of the
+// call has no deadline. Use ReadTimeout and WriteTimeout to override it
+// for a single call.
 func RequestTimeout(t time.Duration) Option {
 	return func(cfg *Config) error {
 		cfg.sechan.RequestTimeout = t
@@ -522,10 +949,71 @@ func RequestTimeout(t time.Duration) Option {
 	}
 }
 
-// Dialer sets the uacp.Dialer to establish the connection to the server.
-func Dialer(d *uacp.Dialer) Option {
+// AuditEntryID sets a function which returns the AuditEntryId of the
+// request header. It is called for every request and the server writes
+// the id to its audit log which makes the requests traceable, e.g. by
+// the name of the user of the client application.
+//
+// A request header with an AuditEntryId is sent as is.
+func AuditEntryID(f func() string) Option {
+	return func(cfg *Config) error {
+		cfg.sechan.AuditEntryID = f
+		return nil
+	}
+}
+
+// ReturnDiagnostics sets the diagnostics the server shall return for
+// every request, e.g. ua.ReturnDiagnosticsAll. The diagnostic infos of
+// the responses can be resolved with the Diagnostic method of the
+// response. A non-zero ReturnDiagnostics mask in the header of a
+// request overrides it for that request.
+func ReturnDiagnostics(mask uint32) Option {
+	return func(cfg *Config) error {
+		cfg.sechan.ReturnDiagnostics = mask
+		return nil
+	}
+}
+
+// CancelOnContextDone sends a Cancel request for a request whose context
+// is cancelled or exceeds its deadline before the response arrives. This
+// allows the server to stop long-running operations, e.g. large history
+// reads, instead of processing a request whose response the client
+// discards.
+//
+// The Cancel request is sent in the background and requires a session.
+// Servers are not required to support cancellation and the result is
+// ignored. Publish requests are not cancelled.
+//
+// See Part 4, 5.6.5
+func CancelOnContextDone(b bool) Option {
+	return func(cfg *Config) error {
+		cfg.cancelOnContextDone = b
+		return nil
+	}
+}
+
+// ContextDialer establishes the network connection to the server, e.g.
+// through a SOCKS proxy or a tunnel. It is implemented by net.Dialer.
+type ContextDialer = uacp.ContextDialer
+
+// Dialer sets the dialer which establishes the connection to the server.
+//
+// A *uacp.Dialer replaces the complete UACP dialer configuration. Any other
+// ContextDialer only establishes the network connection and the UACP
+// handshake is performed by the client as usual. Such a dialer overrides
+// the LocalAddr, LocalInterface and DialTimeout options, which only apply
+// to the default net.Dialer. The context passed to Connect is passed to
+// the dialer.
+func Dialer(d ContextDialer) Option {
 	return func(cfg *Config) error {
-		cfg.dialer = d
+		switch x := d.(type) {
+		case nil:
+			return errors.Errorf("dialer is nil")
+		case *uacp.Dialer:
+			cfg.dialer = x
+		default:
+			cfg.dialer.ContextDialer = d
+		}
 		return nil
 	}
 }
@@ -558,7 +1046,55 @@ func LocalAddr(addr string) Option {
 	}
 }
 
+// EndpointURLOverride sets the endpoint url whose host and port the client
+// connects to instead of the ones of the endpoint url passed to NewClient.
+// This is required for servers behind a load balancer or NAT which return
+// endpoints with an internal address from GetEndpoints. The endpoint url
+// passed to NewClient is still sent to the server in the Hello message and
+// the CreateSession request. An empty url disables the override.
+//
+//	// the endpoints contain the internal address opc.tcp://10.0.0.5:4840
+//	eps, err := opcua.GetEndpoints(ctx, "opc.tcp://gateway:4840")
+//	...
+//	ep, err := opcua.SelectEndpoint(eps, "None", ua.MessageSecurityModeNone)
+//	...
+//	c, err := opcua.NewClient(ep.EndpointURL,
+//		opcua.SecurityFromEndpoint(ep, ua.UserTokenTypeAnonymous),
+//		opcua.EndpointURLOverride("opc.tcp://gateway:4840"),
+//	)
+func EndpointURLOverride(endpoint string) Option {
+	return func(cfg *Config) error {
+		if endpoint != "" {
+			u, err := url.Parse(endpoint)
+			if err != nil {
+				return errors.Errorf("invalid endpoint url %s: %w", endpoint, err)
+			}
+			if u.Scheme != "opc.tcp" || u.Host == "" {
+				return errors.Errorf("invalid endpoint url %s", endpoint)
+			}
+		}
+		cfg.dialer.DialEndpoint = endpoint
+		return nil
+	}
+}
+
+// LocalInterface sets the name of the network interface, e.g. "eth1",
+// to use for the connection. The local address is resolved from the
+// addresses of the interface every time the client connects so that
+// addresses assigned by DHCP may change. On Linux the socket is also
+// bound to the interface with SO_BINDTODEVICE if the process has the
+// privileges. It overrides LocalAddr.
+func LocalInterface(name string) Option {
+	return func(cfg *Config) error {
+		cfg.dialer.LocalInterface = name
+		return nil
+	}
+}
+
 // MaxMessageSize sets the maximum message size for the UACP handshake.
+//
+// Responses which are larger fail with BadResponseTooLarge. Zero means
+// that the client accepts the message size of the server.
 func MaxMessageSize(n uint32) Option {
 	return func(cfg *Config) error {
 		cfg.dialer.ClientACK.MaxMessageSize = n
@@ -567,6 +1103,11 @@ func MaxMessageSize(n uint32) Option {
 }
 
 // MaxChunkCount sets the maximum chunk count for the UACP handshake.
+//
+// Responses which are split into more chunks fail with
+// BadResponseTooLarge. Zero means that the client accepts the chunk count
+// of the server. Together with ReceiveBufferSize this limits the size of
+// a response, e.g. of a large Browse or Read.
 func MaxChunkCount(n uint32) Option {
 	return func(cfg *Config) error {
 		cfg.dialer.ClientACK.MaxChunkCount = n
@@ -575,6 +1116,9 @@ func MaxChunkCount(n uint32) Option {
 }
 
 // ReceiveBufferSize sets the receive buffer size for the UACP handshake.
+//
+// This is the maximum size of a single message chunk the server may send.
+// The OPC UA specification requires at least 8192 bytes.
 func ReceiveBufferSize(n uint32) Option {
 	return func(cfg *Config) error {
 		cfg.dialer.ClientACK.ReceiveBufSize = n
@@ -608,3 +1152,119 @@ func StateChangedFunc(f func(ConnState)) Option {
 		return nil
 	}
 }
+
+// StateChangedHandler sets the function which is called on every
+// transition of the client connection state, including the transitions
+// during an automatic reconnect.
+//
+// The calls are serialized and made in the order of the transitions.
+// The handler may call WaitForState, Close and other methods of the
+// client. The transitions which they cause are delivered after the
+// handler has returned.
+func StateChangedHandler(f func(oldState, newState ConnState)) Option {
+	return func(cfg *Config) error {
+		cfg.stateHandler = f
+		return nil
+	}
+}
+
+// AttributeCache enables the cache of ReadCachedAttributes for the given
+// attributes. If no attributes are given then DefaultCachedAttributes are
+// cached.
+func AttributeCache(attrs ...ua.AttributeID) Option {
+	return func(cfg *Config) error {
+		if len(attrs) == 0 {
+			attrs = DefaultCachedAttributes
+		}
+		cfg.attrCache = attrs
+		return nil
+	}
+}
+
+// AttributeCacheTTL sets the duration after which cached attribute values
+// expire. The default is zero which means that values do not expire.
+func AttributeCacheTTL(d time.Duration) Option {
+	return func(cfg *Config) error {
+		cfg.attrCacheTTL = d
+		return nil
+	}
+}
+
+// SubscriptionRecreatedFunc sets the function which is called when a
+// subscription has been recreated after a reconnect since the server
+// could neither transfer nor republish it.
+//
+// The recreated subscription has a new subscription id and new monitored
+// item ids but the client handles of the monitored items are retained.
+// oldID is the id of the subscription before it was recreated. Since
+// notifications may have been lost during the reconnect the function
+// can be used to re-read the initial values of the monitored items.
+// Monitored items which the server rejected are returned by
+// Subscription.FailedItems.
+func SubscriptionRecreatedFunc(f func(oldID uint32, sub *Subscription)) Option {
+	return func(cfg *Config) error {
+		cfg.subRecreatedFunc = f
+		return nil
+	}
+}
+
+// KeepAliveWatchdog enables the keep-alive watchdog of the subscriptions
+// with the given multiplier, e.g. DefaultKeepAliveWatchdogMultiplier.
+// The watchdog is disabled by default.
+//
+// The server sends either a notification or a keep-alive message for a
+// subscription at least once per keep-alive interval which is the
+// revised publishing interval times the revised max keep-alive count.
+// If the client has not received either within multiplier times that
+// interval, e.g. since the network drops the packets without TCP
+// noticing it, the subscription is marked as stale and
+// ErrKeepAliveTimeout is sent to its notification channel. If
+// AutoReconnect is enabled the client then reconnects and restores the
+// subscriptions. A multiplier of zero or less disables the watchdog.
+func KeepAliveWatchdog(multiplier float64) Option {
+	return func(cfg *Config) error {
+		switch {
+		case multiplier <= 0:
+			cfg.keepAliveMultiplier = 0
+		case multiplier < 1:
+			return errors.Errorf("keep-alive watchdog multiplier %v is less than 1", multiplier)
+		default:
+			cfg.keepAliveMultiplier = multiplier
+		}
+		return nil
+	}
+}
+
+// NotificationBuffer places a buffer of the given size between the
+// publish loop and the notification channel of every subscription.
+// The policy defines what happens when the buffer is full.
+//
+// Without a buffer, or with BlockOnFull, a slow handler applies
+// backpressure: the publish loop waits for the handler and stops sending
+// publish requests for all subscriptions of the session. No data is lost
+// on the client but the server queues the notifications and discards
+// them once the queues of the monitored items overflow. A stalled
+// handler can also cause the subscription to time out on the server.
+//
+// DropOldest and DropNewest never block the publish loop and keep the
+// memory bounded but discard notifications on the client. The number
+// of discarded notifications is reported in
+// SubscriptionStats.NotificationsDropped.
+//
+// Notifications which are still in the buffer when the subscription is
+// cancelled or the client is closed are discarded.
+func NotificationBuffer(size int, policy DropPolicy) Option {
+	return func(cfg *Config) error {
+		if size < 0 {
+			return errors.Errorf("notification buffer size %d is negative", size)
+		}
+		switch policy {
+		case BlockOnFull, DropOldest, DropNewest:
+		default:
+			return errors.Errorf("invalid drop policy %d", policy)
+		}
+		cfg.notifBufferSize = size
+		cfg.notifDropPolicy = policy
+		return nil
+	}
+}
@@ -1,17 +1,23 @@
 package opcua
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"io"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 	"time"
 
+	"github.com/gopcua/opcua/errors"
 	"github.com/gopcua/opcua/ua"
 	"github.com/gopcua/opcua/uacp"
 	"github.com/gopcua/opcua/uapolicy"
@@ -19,6 +25,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+var testDialer = &net.Dialer{KeepAlive: time.Minute}
+
 // test certificate generated with
 // go run ~/sdk/gotip/src/crypto/tls/generate_cert.go -rsa-bits 1024 -host localhost
 // expires Jun  5 20:10:13 2020 GMT
@@ -174,6 +184,8 @@ func TestOptions(t *testing.T) {
 
 	connStateCh := make(chan ConnState)
 	connStateFunc := func(ConnState) {}
+	connStateHandler := func(ConnState, ConnState) {}
+	verifyCert := func(*x509.Certificate, [][]*x509.Certificate) error { return nil }
 
 	tests := []struct {
 		name string
@@ -181,6 +193,17 @@ func TestOptions(t *testing.T) {
 		cfg  *Config
 		err  error
 	}{
+		{
+			name: `AuditEntryID(f)`,
+			opt:  AuditEntryID(func() string { return "a" }),
+			cfg: &Config{
+				sechan: func() *uasc.Config {
+					c := DefaultClientConfig()
+					c.AuditEntryID = func() string { return "a" }
+					return c
+				}(),
+			},
+		},
 		{
 			name: `ApplicationName("a")`,
 			opt:  ApplicationName("a"),
@@ -201,6 +224,7 @@ func TestOptions(t *testing.T) {
 					sc.ClientDescription.ApplicationURI = "a"
 					return sc
 				}(),
+				appURISet: true,
 			},
 		},
 		{
@@ -244,9 +268,8 @@ func TestOptions(t *testing.T) {
 			cfg: &Config{
 				session: func() *uasc.SessionConfig {
 					sc := DefaultSessionConfig()
-					sc.UserIdentityToken = &ua.IssuedIdentityToken{
-						TokenData: []byte("a"),
-					}
+					sc.UserIdentityToken = &ua.IssuedIdentityToken{}
+					sc.AuthTokenData = []byte("a")
 					return sc
 				}(),
 			},
@@ -289,6 +312,40 @@ func TestOptions(t *testing.T) {
 				}(),
 			},
 		},
+		{
+			name: `CertificatePEM`,
+			opt:  CertificatePEM(certPEM),
+			cfg: &Config{
+				sechan: func() *uasc.Config {
+					c := DefaultClientConfig()
+					c.Certificate = certDER
+					return c
+				}(),
+			},
+		},
+		{
+			name: `CertificatePEM() error`,
+			opt:  CertificatePEM(keyPEM),
+			cfg:  &Config{},
+			err:  errors.New("Failed to decode PEM block with certificate"),
+		},
+		{
+			name: `CertificateChain`,
+			opt:  CertificateChain([][]byte{certDER, certDER}),
+			cfg: &Config{
+				sechan: func() *uasc.Config {
+					c := DefaultClientConfig()
+					c.Certificate = append(append([]byte{}, certDER...), certDER...)
+					return c
+				}(),
+			},
+		},
+		{
+			name: `CertificateChain() error`,
+			opt:  CertificateChain(nil),
+			cfg:  &Config{},
+			err:  errors.New("certificate chain is empty"),
+		},
 		{
 			name: `CertificateFile("cert.der")`,
 			opt:  CertificateFile(certDERFile),
@@ -335,6 +392,94 @@ func TestOptions(t *testing.T) {
 				stateFunc: connStateFunc,
 			},
 		},
+		{
+			name: `VerifyServerCertificate`,
+			opt:  VerifyServerCertificate(verifyCert),
+			cfg: &Config{
+				sechan: func() *uasc.Config {
+					c := DefaultClientConfig()
+					c.VerifyCertificate = verifyCert
+					return c
+				}(),
+			},
+		},
+		{
+			name: `TrustStore`,
+			opt:  TrustStore(filepath.Join(d, "pki")),
+			cfg: &Config{
+				sechan: func() *uasc.Config {
+					c := DefaultClientConfig()
+					c.VerifyCertificate = verifyCert
+					return c
+				}(),
+				trustStore: &trustStore{
+					dir:        filepath.Join(d, "pki"),
+					httpClient: &http.Client{Timeout: DefaultOCSPTimeout},
+				},
+			},
+		},
+		{
+			name: `OCSP(true)`,
+			opt:  OCSP(true),
+			cfg: &Config{
+				ocsp: true,
+			},
+		},
+		{
+			name: `AllowMissingCRL()`,
+			opt:  AllowMissingCRL(),
+			cfg: &Config{
+				allowMissingCRL: true,
+			},
+		},
+		{
+			name: `TrustStore() error`,
+			opt:  TrustStore(""),
+			cfg:  &Config{},
+			err:  errors.New("trust store directory is empty"),
+		},
+		{
+			name: `AttributeCache()`,
+			opt:  AttributeCache(),
+			cfg: &Config{
+				attrCache: DefaultCachedAttributes,
+			},
+		},
+		{
+			name: `AttributeCache(BrowseName)`,
+			opt:  AttributeCache(ua.AttributeIDBrowseName),
+			cfg: &Config{
+				attrCache: []ua.AttributeID{ua.AttributeIDBrowseName},
+			},
+		},
+		{
+			name: `AttributeCacheTTL(time.Minute)`,
+			opt:  AttributeCacheTTL(time.Minute),
+			cfg: &Config{
+				attrCacheTTL: time.Minute,
+			},
+		},
+		{
+			name: `Sessionless(true)`,
+			opt:  Sessionless(true),
+			cfg: &Config{
+				sessionless: true,
+			},
+		},
+		{
+			name: `SkipApplicationURICheck`,
+			opt:  SkipApplicationURICheck(),
+			cfg: &Config{
+				skipApplicationURICheck: true,
+			},
+		},
+		{
+			name: `StateChangedHandler`,
+			opt:  StateChangedHandler(connStateHandler),
+			cfg: &Config{
+				stateHandler: connStateHandler,
+			},
+		},
 		{
 			name: `Lifetime(10ms)`,
 			opt:  Lifetime(10 * time.Millisecond),
@@ -368,6 +513,38 @@ func TestOptions(t *testing.T) {
 				}(),
 			},
 		},
+		{
+			name: `PrivateKeyPEM(PKCS1)`,
+			opt:  PrivateKeyPEM(keyPEM),
+			cfg: &Config{
+				sechan: func() *uasc.Config {
+					c := DefaultClientConfig()
+					c.LocalKey = cert.PrivateKey.(*rsa.PrivateKey)
+					return c
+				}(),
+			},
+		},
+		{
+			name: `PrivateKeyPEM(PKCS8)`,
+			opt: func() Option {
+				b, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+				require.NoError(t, err)
+				return PrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: b}))
+			}(),
+			cfg: &Config{
+				sechan: func() *uasc.Config {
+					c := DefaultClientConfig()
+					c.LocalKey = cert.PrivateKey.(*rsa.PrivateKey)
+					return c
+				}(),
+			},
+		},
+		{
+			name: `PrivateKeyPEM() error`,
+			opt:  PrivateKeyPEM(certPEM),
+			cfg:  &Config{},
+			err:  errors.New(`Failed to decode PEM block with private key: unsupported type "CERTIFICATE"`),
+		},
 		{
 			name: `PrivateKeyFile("key.der")`,
 			opt:  PrivateKeyFile(keyDERFile),
@@ -429,6 +606,40 @@ func TestOptions(t *testing.T) {
 				}(),
 			},
 		},
+		{
+			name: `MaxReconnectAttempts()`,
+			opt:  MaxReconnectAttempts(3),
+			cfg:  &Config{maxReconnectAttempts: 3},
+		},
+		{
+			name: `MaxReconnectAttempts() unlimited`,
+			opt:  MaxReconnectAttempts(-1),
+			cfg:  &Config{},
+		},
+		{
+			name: `ReconnectBackoff()`,
+			opt:  ReconnectBackoff(time.Second, time.Minute, 2, 0.2),
+			cfg: &Config{
+				sechan: func() *uasc.Config {
+					c := DefaultClientConfig()
+					c.ReconnectInterval = time.Second
+					return c
+				}(),
+				reconnectBackoff: &backoff{initial: time.Second, max: time.Minute, factor: 2, jitter: 0.2},
+			},
+		},
+		{
+			name: `ReconnectBackoff() max less than initial`,
+			opt:  ReconnectBackoff(time.Minute, time.Second, 2, 0),
+			cfg:  &Config{},
+			err:  errors.New("reconnect backoff: max interval 1s is less than initial interval 1m0s"),
+		},
+		{
+			name: `ReconnectBackoff() invalid jitter`,
+			opt:  ReconnectBackoff(time.Second, time.Minute, 2, 1.5),
+			cfg:  &Config{},
+			err:  errors.New("reconnect backoff: jitter 1.5 is not between 0 and 1"),
+		},
 		{
 			name: `RemoteCertificate`,
 			opt:  RemoteCertificate(certDER),
@@ -468,6 +679,17 @@ func TestOptions(t *testing.T) {
 			cfg:  &Config{},
 			err:  notFoundError("certificate", "x"),
 		},
+		{
+			name: `ReturnDiagnostics(ua.ServiceLevelAll)`,
+			opt:  ReturnDiagnostics(ua.ServiceLevelAll),
+			cfg: &Config{
+				sechan: func() *uasc.Config {
+					c := DefaultClientConfig()
+					c.ReturnDiagnostics = ua.ServiceLevelAll
+					return c
+				}(),
+			},
+		},
 		{
 			name: `RequestTimeout(5s)`,
 			opt:  RequestTimeout(5 * time.Second),
@@ -616,6 +838,107 @@ func TestOptions(t *testing.T) {
 				}(),
 			},
 		},
+		{
+			name: `LocalInterface()`,
+			opt:  LocalInterface("eth1"),
+			cfg: &Config{
+				dialer: func() *uacp.Dialer {
+					d := DefaultDialer()
+					d.LocalInterface = "eth1"
+					return d
+				}(),
+			},
+		},
+		{
+			name: `EndpointURLOverride()`,
+			opt:  EndpointURLOverride("opc.tcp://gateway:4840"),
+			cfg: &Config{
+				dialer: func() *uacp.Dialer {
+					d := DefaultDialer()
+					d.DialEndpoint = "opc.tcp://gateway:4840"
+					return d
+				}(),
+			},
+		},
+		{
+			name: `EndpointURLOverride(invalid)`,
+			opt:  EndpointURLOverride("http://gateway:4840"),
+			cfg:  &Config{},
+			err:  errors.New("invalid endpoint url http://gateway:4840"),
+		},
+		{
+			name: `Logger()`,
+			opt:  Logger(testLogger),
+			cfg: &Config{
+				sechan: func() *uasc.Config {
+					c := DefaultClientConfig()
+					c.Logger = testLogger
+					return c
+				}(),
+			},
+		},
+		{
+			name: `KeepAliveWatchdog()`,
+			opt:  KeepAliveWatchdog(3),
+			cfg:  &Config{keepAliveMultiplier: 3},
+		},
+		{
+			name: `KeepAliveWatchdog() disabled`,
+			opt:  KeepAliveWatchdog(0),
+			cfg:  &Config{},
+		},
+		{
+			name: `KeepAliveWatchdog() too small`,
+			opt:  KeepAliveWatchdog(0.5),
+			cfg:  &Config{},
+			err:  errors.New("keep-alive watchdog multiplier 0.5 is less than 1"),
+		},
+		{
+			name: `CancelOnContextDone()`,
+			opt:  CancelOnContextDone(true),
+			cfg:  &Config{cancelOnContextDone: true},
+		},
+		{
+			name: `NotificationBuffer()`,
+			opt:  NotificationBuffer(100, DropOldest),
+			cfg: &Config{
+				notifBufferSize: 100,
+				notifDropPolicy: DropOldest,
+			},
+		},
+		{
+			name: `NotificationBuffer() negative size`,
+			opt:  NotificationBuffer(-1, DropOldest),
+			cfg:  &Config{},
+			err:  errors.New("notification buffer size -1 is negative"),
+		},
+		{
+			name: `NotificationBuffer() invalid policy`,
+			opt:  NotificationBuffer(1, DropPolicy(42)),
+			cfg:  &Config{},
+			err:  errors.New("invalid drop policy 42"),
+		},
+		{
+			name: `Tracer()`,
+			opt:  Tracer(&testTracer{}),
+			cfg:  &Config{tracer: &testTracer{}},
+		},
+		{
+			name: `ReverseServerURIs()`,
+			opt:  ReverseServerURIs("urn:a", "urn:b"),
+			cfg:  &Config{reverseServerURIs: []string{"urn:a", "urn:b"}},
+		},
+		{
+			name: `SecureChannelRenewedHandler()`,
+			opt:  SecureChannelRenewedHandler(func(old, new uint32) {}),
+			cfg: &Config{
+				sechan: func() *uasc.Config {
+					c := DefaultClientConfig()
+					c.TokenRenewedFunc = func(old, new uint32) {}
+					return c
+				}(),
+			},
+		},
 		{
 			name: `SecurityMode(Sign)`,
 			opt:  SecurityMode(ua.MessageSecurityModeSign),
@@ -726,6 +1049,12 @@ func TestOptions(t *testing.T) {
 				}(),
 			},
 		},
+		{
+			name: `SecurityPolicy("Basic512") error`,
+			opt:  SecurityPolicy("Basic512"),
+			cfg:  &Config{},
+			err:  errors.New("unsupported security policy Basic512"),
+		},
 		{
 			name: `SessionName()`,
 			opt:  SessionName("a"),
@@ -771,6 +1100,17 @@ func TestOptions(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: `Dialer(ContextDialer)`,
+			opt:  Dialer(testDialer),
+			cfg: &Config{
+				dialer: func() *uacp.Dialer {
+					d := DefaultDialer()
+					d.ContextDialer = testDialer
+					return d
+				}(),
+			},
+		},
 		{
 			name: `DialTimeout(5s)`,
 			opt:  DialTimeout(5 * time.Second),
@@ -858,7 +1198,50 @@ func TestOptions(t *testing.T) {
 			} else {
 				require.Nil(t, cfg.stateFunc)
 			}
+			if tt.cfg.sechan.VerifyCertificate != nil {
+				require.NotNil(t, cfg.sechan.VerifyCertificate)
+				tt.cfg.sechan.VerifyCertificate = nil
+				cfg.sechan.VerifyCertificate = nil
+			} else {
+				require.Nil(t, cfg.sechan.VerifyCertificate)
+			}
+			if tt.cfg.sechan.TokenRenewedFunc != nil {
+				require.NotNil(t, cfg.sechan.TokenRenewedFunc)
+				tt.cfg.sechan.TokenRenewedFunc = nil
+				cfg.sechan.TokenRenewedFunc = nil
+			} else {
+				require.Nil(t, cfg.sechan.TokenRenewedFunc)
+			}
+			if tt.cfg.sechan.AuditEntryID != nil {
+				require.NotNil(t, cfg.sechan.AuditEntryID)
+				require.Equal(t, tt.cfg.sechan.AuditEntryID(), cfg.sechan.AuditEntryID())
+				tt.cfg.sechan.AuditEntryID = nil
+				cfg.sechan.AuditEntryID = nil
+			} else {
+				require.Nil(t, cfg.sechan.AuditEntryID)
+			}
+			if tt.cfg.stateHandler != nil {
+				require.NotNil(t, cfg.stateHandler)
+				tt.cfg.stateHandler = nil
+				cfg.stateHandler = nil
+			} else {
+				require.Nil(t, cfg.stateHandler)
+			}
 			require.Equal(t, tt.cfg, cfg)
 		})
 	}
 }
+
+func TestKeyPair(t *testing.T) {
+	t.Run("match", func(t *testing.T) {
+		_, err := ApplyConfig(CertificatePEM(certPEM), PrivateKeyPEM(keyPEM))
+		require.NoError(t, err)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = ApplyConfig(CertificatePEM(certPEM), PrivateKey(key))
+		require.EqualError(t, err, "opcua: private key does not match the public key of the client certificate")
+	})
+}
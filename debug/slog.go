@@ -0,0 +1,53 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package debug
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewLogger returns a structured logger which writes all records to
+// Logger when debug logging is enabled. Otherwise, the records are
+// discarded.
+func NewLogger() *slog.Logger {
+	h := slog.NewTextHandler(logWriter{}, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// Logger does not print timestamps either
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	return slog.New(handler{h})
+}
+
+// handler is a slog.Handler which is only enabled when debug logging
+// is enabled.
+type handler struct {
+	slog.Handler
+}
+
+func (h handler) Enabled(ctx context.Context, l slog.Level) bool {
+	return Enable && h.Handler.Enabled(ctx, l)
+}
+
+func (h handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return handler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h handler) WithGroup(name string) slog.Handler {
+	return handler{h.Handler.WithGroup(name)}
+}
+
+// logWriter writes to the current Logger.
+type logWriter struct{}
+
+func (logWriter) Write(p []byte) (int, error) {
+	Logger.Print(string(p))
+	return len(p), nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package debug
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	enable, logger := Enable, Logger
+	defer func() { Enable, Logger = enable, logger }()
+
+	var buf bytes.Buffer
+	Logger = log.New(&buf, "debug: ", 0)
+
+	Enable = false
+	NewLogger().Info("hidden")
+	require.Empty(t, buf.String())
+
+	Enable = true
+	NewLogger().With("channelID", 1).Debug("channel open", "tokenID", 2)
+	require.Equal(t, "debug: level=DEBUG msg=\"channel open\" channelID=1 tokenID=2\n", buf.String())
+}
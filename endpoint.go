@@ -0,0 +1,83 @@
+package opcua
+
+import (
+	"sort"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// policyStrength ranks the security policies from weakest to strongest.
+// Unknown policies rank below None.
+var policyStrength = map[string]int{
+	ua.SecurityPolicyURINone:                1,
+	ua.SecurityPolicyURIBasic128Rsa15:       2,
+	ua.SecurityPolicyURIBasic256:            3,
+	ua.SecurityPolicyURIBasic256Sha256:      4,
+	ua.SecurityPolicyURIAes128Sha256RsaOaep: 5,
+	ua.SecurityPolicyURIAes256Sha256RsaPss:  6,
+}
+
+// EndpointSecurity is a combination of security policy and security mode
+// which is offered by a server.
+type EndpointSecurity struct {
+	SecurityPolicyURI string
+	SecurityMode      ua.MessageSecurityMode
+
+	// SecurityLevel is the relative security level the server assigned
+	// to the endpoint.
+	SecurityLevel uint8
+
+	// ServerCertificate is the DER encoded certificate of the server.
+	ServerCertificate []byte
+
+	// Endpoint is the endpoint which offers the combination.
+	Endpoint *ua.EndpointDescription
+}
+
+// EndpointMatrix returns the combinations of security policy and security
+// mode offered by the endpoints with the strongest combination first.
+// Combinations are ordered by the strength of the security policy, then
+// by the security mode and then by the security level of the server.
+// If several endpoints offer the same combination then only the one with
+// the highest security level is returned.
+//
+// The endpoints are not modified.
+func EndpointMatrix(endpoints []*ua.EndpointDescription) []*EndpointSecurity {
+	type key struct {
+		policy string
+		mode   ua.MessageSecurityMode
+	}
+
+	var matrix []*EndpointSecurity
+	seen := map[key]*EndpointSecurity{}
+	for _, ep := range endpoints {
+		k := key{ep.SecurityPolicyURI, ep.SecurityMode}
+		if s, ok := seen[k]; ok {
+			if ep.SecurityLevel > s.SecurityLevel {
+				s.SecurityLevel, s.ServerCertificate, s.Endpoint = ep.SecurityLevel, ep.ServerCertificate, ep
+			}
+			continue
+		}
+		s := &EndpointSecurity{
+			SecurityPolicyURI: ep.SecurityPolicyURI,
+			SecurityMode:      ep.SecurityMode,
+			SecurityLevel:     ep.SecurityLevel,
+			ServerCertificate: ep.ServerCertificate,
+			Endpoint:          ep,
+		}
+		seen[k] = s
+		matrix = append(matrix, s)
+	}
+
+	sort.SliceStable(matrix, func(i, j int) bool {
+		a, b := matrix[i], matrix[j]
+		if pa, pb := policyStrength[a.SecurityPolicyURI], policyStrength[b.SecurityPolicyURI]; pa != pb {
+			return pa > pb
+		}
+		if a.SecurityMode != b.SecurityMode {
+			return a.SecurityMode > b.SecurityMode
+		}
+		return a.SecurityLevel > b.SecurityLevel
+	})
+	return matrix
+}
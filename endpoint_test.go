@@ -0,0 +1,29 @@
+package opcua
+
+import (
+	"testing"
+
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointMatrix(t *testing.T) {
+	endpoints := []*ua.EndpointDescription{
+		{EndpointURL: "a", SecurityPolicyURI: ua.SecurityPolicyURINone, SecurityMode: ua.MessageSecurityModeNone, SecurityLevel: 0},
+		{EndpointURL: "a", SecurityPolicyURI: ua.SecurityPolicyURIBasic256Sha256, SecurityMode: ua.MessageSecurityModeSign, SecurityLevel: 5},
+		{EndpointURL: "a", SecurityPolicyURI: ua.SecurityPolicyURIBasic256Sha256, SecurityMode: ua.MessageSecurityModeSignAndEncrypt, SecurityLevel: 10},
+		{EndpointURL: "a", SecurityPolicyURI: ua.SecurityPolicyURIBasic128Rsa15, SecurityMode: ua.MessageSecurityModeSignAndEncrypt, SecurityLevel: 20},
+		{EndpointURL: "b", SecurityPolicyURI: ua.SecurityPolicyURIBasic256Sha256, SecurityMode: ua.MessageSecurityModeSignAndEncrypt, SecurityLevel: 11, ServerCertificate: []byte{1}},
+	}
+	orig := append([]*ua.EndpointDescription{}, endpoints...)
+
+	got := EndpointMatrix(endpoints)
+	want := []*EndpointSecurity{
+		{SecurityPolicyURI: ua.SecurityPolicyURIBasic256Sha256, SecurityMode: ua.MessageSecurityModeSignAndEncrypt, SecurityLevel: 11, ServerCertificate: []byte{1}, Endpoint: endpoints[4]},
+		{SecurityPolicyURI: ua.SecurityPolicyURIBasic256Sha256, SecurityMode: ua.MessageSecurityModeSign, SecurityLevel: 5, Endpoint: endpoints[1]},
+		{SecurityPolicyURI: ua.SecurityPolicyURIBasic128Rsa15, SecurityMode: ua.MessageSecurityModeSignAndEncrypt, SecurityLevel: 20, Endpoint: endpoints[3]},
+		{SecurityPolicyURI: ua.SecurityPolicyURINone, SecurityMode: ua.MessageSecurityModeNone, SecurityLevel: 0, Endpoint: endpoints[0]},
+	}
+	require.Equal(t, want, got)
+	require.Equal(t, orig, endpoints, "endpoints must not be modified")
+}
@@ -15,10 +15,10 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"time"
 
 	"github.com/gopcua/opcua"
-	"github.com/gopcua/opcua/id"
 	"github.com/gopcua/opcua/ua"
 )
 
@@ -26,8 +26,8 @@ func main() {
 	var (
 		endpoint1  = flag.String("endpoint1", "opc.tcp://192.168.100.1:4840", "第一个OPC UA服务器端点")
 		endpoint2  = flag.String("endpoint2", "opc.tcp://192.168.100.1:4840", "第二个OPC UA服务器端点")
-		localAddr1 = flag.String("local1", "192.168.100.10:0", "连接第一个设备时使用的本地网卡地址")
-		localAddr2 = flag.String("local2", "192.168.100.20:0", "连接第二个设备时使用的本地网卡地址")
+		localAddr1 = flag.String("local1", "192.168.100.10:0", "连接第一个设备时使用的本地网卡地址或网卡名称（如 eth1）")
+		localAddr2 = flag.String("local2", "192.168.100.20:0", "连接第二个设备时使用的本地网卡地址或网卡名称（如 eth2）")
 		nodeID     = flag.String("node", "i=2258", "要读取的节点ID")
 	)
 	flag.Parse()
@@ -70,11 +70,18 @@ func connectWithLocalAddr(ctx context.Context, endpoint, localAddr string) (*opc
 	opts := []opcua.Option{
 		opcua.SecurityPolicy(ua.SecurityPolicyURINone),
 		opcua.SecurityModeString("None"),
-		opcua.LocalAddr(localAddr), // 指定使用的本地网卡地址
 		opcua.AutoReconnect(true),
 		opcua.ReconnectInterval(5 * time.Second),
 	}
 
+	// 地址格式为 IP:端口 时绑定到该地址，否则按网卡名称绑定。
+	// 按网卡名称绑定时每次连接都会重新解析网卡地址，适用于 DHCP 分配的地址。
+	if _, _, err := net.SplitHostPort(localAddr); err == nil {
+		opts = append(opts, opcua.LocalAddr(localAddr)) // 指定使用的本地网卡地址
+	} else {
+		opts = append(opts, opcua.LocalInterface(localAddr)) // 指定使用的本地网卡名称
+	}
+
 	// 创建客户端
 	c, err := opcua.NewClient(endpoint, opts...)
 	if err != nil {
@@ -167,9 +174,9 @@ func monitorMultipleDevices(ctx context.Context) error {
 				case <-ctx.Done():
 					return
 				case <-ticker.C:
-					// 读取服务器状态节点
-					if err := readNodeValue(ctx, client, "i="+fmt.Sprintf("%d", id.Server_ServerStatus_State), dev.name); err != nil {
-						log.Printf("%s 读取状态失败: %v", dev.name, err)
+					// 检查服务器是否在线
+					if err := client.Ping(ctx); err != nil {
+						log.Printf("%s 状态检查失败: %v", dev.name, err)
 					}
 				}
 			}
@@ -13,7 +13,6 @@ import (
 
 	"github.com/gopcua/opcua"
 	"github.com/gopcua/opcua/debug"
-	"github.com/gopcua/opcua/id"
 	"github.com/gopcua/opcua/ua"
 )
 
@@ -146,75 +145,11 @@ func valueRequest(nodeID *ua.NodeID) *ua.MonitoredItemCreateRequest {
 
 func eventRequest(nodeID *ua.NodeID) (*ua.MonitoredItemCreateRequest, []string) {
 	fieldNames := []string{"EventId", "EventType", "Severity", "Time", "Message"}
-	selects := make([]*ua.SimpleAttributeOperand, len(fieldNames))
-
-	for i, name := range fieldNames {
-		selects[i] = &ua.SimpleAttributeOperand{
-			TypeDefinitionID: ua.NewNumericNodeID(0, id.BaseEventType),
-			BrowsePath:       []*ua.QualifiedName{{NamespaceIndex: 0, Name: name}},
-			AttributeID:      ua.AttributeIDValue,
-		}
-	}
-
-	wheres := &ua.ContentFilter{
-		Elements: []*ua.ContentFilterElement{
-			{
-				FilterOperator: ua.FilterOperatorGreaterThanOrEqual,
-				FilterOperands: []*ua.ExtensionObject{
-					{
-						EncodingMask: 1,
-						TypeID: &ua.ExpandedNodeID{
-							NodeID: ua.NewNumericNodeID(0, id.SimpleAttributeOperand_Encoding_DefaultBinary),
-						},
-						Value: ua.SimpleAttributeOperand{
-							TypeDefinitionID: ua.NewNumericNodeID(0, id.BaseEventType),
-							BrowsePath:       []*ua.QualifiedName{{NamespaceIndex: 0, Name: "Severity"}},
-							AttributeID:      ua.AttributeIDValue,
-						},
-					},
-					{
-						EncodingMask: 1,
-						TypeID: &ua.ExpandedNodeID{
-							NodeID: ua.NewNumericNodeID(0, id.LiteralOperand_Encoding_DefaultBinary),
-						},
-						Value: ua.LiteralOperand{
-							Value: ua.MustVariant(uint16(0)),
-						},
-					},
-				},
-			},
-		},
-	}
-
-	filter := ua.EventFilter{
-		SelectClauses: selects,
-		WhereClause:   wheres,
-	}
-
-	filterExtObj := ua.ExtensionObject{
-		EncodingMask: ua.ExtensionObjectBinary,
-		TypeID: &ua.ExpandedNodeID{
-			NodeID: ua.NewNumericNodeID(0, id.EventFilter_Encoding_DefaultBinary),
-		},
-		Value: filter,
-	}
+	filter := opcua.EventFilter(opcua.EventSelectClauses(fieldNames...), opcua.SeverityFilter(0))
 
 	handle := uint32(42)
-	req := &ua.MonitoredItemCreateRequest{
-		ItemToMonitor: &ua.ReadValueID{
-			NodeID:       nodeID,
-			AttributeID:  ua.AttributeIDEventNotifier,
-			DataEncoding: &ua.QualifiedName{},
-		},
-		MonitoringMode: ua.MonitoringModeReporting,
-		RequestedParameters: &ua.MonitoringParameters{
-			ClientHandle:     handle,
-			DiscardOldest:    true,
-			Filter:           &filterExtObj,
-			QueueSize:        10,
-			SamplingInterval: 1.0,
-		},
-	}
+	req := opcua.NewEventMonitoredItemCreateRequest(nodeID, handle, filter)
+	req.RequestedParameters.SamplingInterval = 1.0
 
 	return req, fieldNames
 }
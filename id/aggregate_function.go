@@ -0,0 +1,26 @@
+// Copyright 2018-2024 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package id
+
+import "strings"
+
+const aggregateFunctionPrefix = "AggregateFunction_"
+
+// AggregateFunctionID returns the id of the standard aggregate function
+// with the given name, e.g. "Average" or "AggregateFunction_Average".
+// The name is matched case-insensitive.
+//
+// See Part 13, 5.4
+func AggregateFunctionID(name string) (uint32, bool) {
+	if len(name) < len(aggregateFunctionPrefix) || !strings.EqualFold(name[:len(aggregateFunctionPrefix)], aggregateFunctionPrefix) {
+		name = aggregateFunctionPrefix + name
+	}
+	for id, s := range nameObject {
+		if strings.HasPrefix(s, aggregateFunctionPrefix) && strings.EqualFold(s, name) {
+			return id, true
+		}
+	}
+	return 0, false
+}
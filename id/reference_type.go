@@ -0,0 +1,19 @@
+// Copyright 2018-2024 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package id
+
+import "strings"
+
+// ReferenceTypeID returns the id of the reference type in namespace
+// zero with the given browse name, e.g. "HasComponent". The name is
+// matched case-insensitive.
+func ReferenceTypeID(name string) (uint32, bool) {
+	for id, s := range nameReferenceType {
+		if strings.EqualFold(s, name) {
+			return id, true
+		}
+	}
+	return 0, false
+}
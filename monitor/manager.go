@@ -0,0 +1,564 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/ua"
+)
+
+// ManagerConfig configures a MonitorManager.
+type ManagerConfig struct {
+	// Subscriptions is the number of subscriptions which share the
+	// monitored items. The default is one.
+	Subscriptions int
+
+	// MaxItemsPerSubscription limits the number of monitored items per
+	// subscription. Zero means that only the limits of the server apply.
+	MaxItemsPerSubscription int
+
+	// Params are the parameters of the subscriptions.
+	Params *opcua.SubscriptionParameters
+}
+
+// ManagerStats contains the distribution of the monitored items of a
+// MonitorManager across its subscriptions and delivery counters.
+type ManagerStats struct {
+	// Items is the number of monitored items.
+	Items int
+
+	// Subscriptions contains the load of every subscription.
+	Subscriptions []SubscriptionLoad
+
+	// Delivered is the number of delivered DataChangeMessages.
+	Delivered uint64
+
+	// Dropped is the number of DataChangeMessages which were dropped
+	// because the notification channel was full.
+	Dropped uint64
+
+	// Rebalances is the number of times the items have been rebalanced.
+	Rebalances uint64
+
+	// Reassigned is the number of items which have been added again
+	// by a rebalance since they were lost.
+	Reassigned uint64
+}
+
+// SubscriptionLoad is the number of monitored items of a subscription.
+type SubscriptionLoad struct {
+	SubscriptionID uint32
+	Items          int
+
+	// Full is true if the server has rejected an item with
+	// StatusBadTooManyMonitoredItems, the subscription has
+	// MaxItemsPerSubscription items or it has been lost and could
+	// not be replaced yet.
+	Full bool
+}
+
+// MonitorManager distributes monitored items across several subscriptions
+// and delivers their data changes to a single channel.
+//
+// A single subscription can become a bottleneck with tens of thousands of
+// monitored items. New items are added to the subscription with the
+// fewest items in batches of at most MaxMonitoredItemsPerCall items per
+// request. A subscription for which the server reports
+// StatusBadTooManyMonitoredItems is considered full and the rejected
+// items are added to the other subscriptions.
+//
+// After the client has reconnected, items which have been lost because
+// the server could not restore them or their subscription are added again
+// to the subscriptions with the fewest items. See Rebalance.
+type MonitorManager struct {
+	client   *opcua.Client
+	cfg      ManagerConfig
+	ch       chan<- *DataChangeMessage
+	notifyCh chan *opcua.PublishNotificationData
+
+	cancel context.CancelFunc
+
+	// mu guards the subscriptions and items.
+	mu    sync.Mutex
+	subs  []*managedSub
+	items map[string]*managedItem
+
+	// handles maps the client handles to the node ids for the
+	// delivery of the notifications.
+	handlesMu  sync.RWMutex
+	handles    map[uint32]*ua.NodeID
+	nextHandle uint32
+
+	delivered  atomic.Uint64
+	dropped    atomic.Uint64
+	rebalances atomic.Uint64
+	reassigned atomic.Uint64
+}
+
+type managedSub struct {
+	sub   *opcua.Subscription
+	items map[uint32]*managedItem // by client handle
+	full  bool
+
+	// stale is true if the client has lost the subscription and it
+	// could not be replaced yet.
+	stale bool
+}
+
+type managedItem struct {
+	nodeID *ua.NodeID
+	handle uint32
+	id     uint32 // from server
+	sub    *managedSub
+}
+
+// NewMonitorManager creates the subscriptions of the manager. The data
+// changes of all monitored items are sent to ch. Messages are dropped if
+// ch is full. The manager rebalances the items every time the client
+// has reconnected until ctx is cancelled or Close is called.
+func NewMonitorManager(ctx context.Context, c *opcua.Client, cfg ManagerConfig, ch chan<- *DataChangeMessage) (*MonitorManager, error) {
+	if ch == nil {
+		return nil, errors.Errorf("notification channel is nil")
+	}
+	if cfg.Subscriptions <= 0 {
+		cfg.Subscriptions = 1
+	}
+	if cfg.Params == nil {
+		cfg.Params = &opcua.SubscriptionParameters{}
+	}
+
+	m := &MonitorManager{
+		client:     c,
+		cfg:        cfg,
+		ch:         ch,
+		notifyCh:   make(chan *opcua.PublishNotificationData, 16*cfg.Subscriptions),
+		items:      make(map[string]*managedItem),
+		handles:    make(map[uint32]*ua.NodeID),
+		nextHandle: 100,
+	}
+	for i := 0; i < cfg.Subscriptions; i++ {
+		s, err := m.subscribe(ctx)
+		if err != nil {
+			m.cancelAll(ctx)
+			return nil, err
+		}
+		m.subs = append(m.subs, s)
+	}
+
+	ctx, m.cancel = context.WithCancel(ctx)
+	go m.pump(ctx)
+	go m.watch(ctx)
+	return m, nil
+}
+
+func (m *MonitorManager) subscribe(ctx context.Context) (*managedSub, error) {
+	params := *m.cfg.Params
+	sub, err := m.client.Subscribe(ctx, &params, m.notifyCh)
+	if err != nil {
+		return nil, err
+	}
+	return &managedSub{sub: sub, items: make(map[uint32]*managedItem)}, nil
+}
+
+func (m *MonitorManager) cancelAll(ctx context.Context) error {
+	var errs []error
+	for _, s := range m.subs {
+		if s.stale {
+			continue
+		}
+		if err := s.sub.Cancel(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	m.subs = nil
+	return errors.Join(errs...)
+}
+
+// Close deletes the subscriptions and stops the delivery of notifications.
+func (m *MonitorManager) Close(ctx context.Context) error {
+	m.cancel()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.items)
+	return m.cancelAll(ctx)
+}
+
+// AddNodes adds nodes defined by their string representation.
+func (m *MonitorManager) AddNodes(ctx context.Context, nodes ...string) error {
+	nodeIDs, err := parseNodeSlice(nodes...)
+	if err != nil {
+		return err
+	}
+	return m.AddNodeIDs(ctx, nodeIDs...)
+}
+
+// AddNodeIDs monitors the value of the nodes. Nodes which are already
+// monitored are ignored. The returned error contains the errors of all
+// nodes which could not be added.
+func (m *MonitorManager) AddNodeIDs(ctx context.Context, nodes ...*ua.NodeID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []*managedItem
+	for _, n := range nodes {
+		if _, ok := m.items[n.String()]; ok {
+			continue
+		}
+		it := &managedItem{nodeID: n}
+		m.items[n.String()] = it
+		items = append(items, it)
+	}
+	return m.add(ctx, items)
+}
+
+// RemoveNodes removes nodes defined by their string representation.
+func (m *MonitorManager) RemoveNodes(ctx context.Context, nodes ...string) error {
+	nodeIDs, err := parseNodeSlice(nodes...)
+	if err != nil {
+		return err
+	}
+	return m.RemoveNodeIDs(ctx, nodeIDs...)
+}
+
+// RemoveNodeIDs stops monitoring the nodes.
+func (m *MonitorManager) RemoveNodeIDs(ctx context.Context, nodes ...*ua.NodeID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	batches := make(map[*managedSub][]uint32)
+	for _, n := range nodes {
+		it, ok := m.items[n.String()]
+		if !ok {
+			continue
+		}
+		delete(m.items, n.String())
+		if it.sub == nil {
+			continue
+		}
+		delete(it.sub.items, it.handle)
+		m.setHandle(it.handle, nil)
+		batches[it.sub] = append(batches[it.sub], it.id)
+	}
+
+	perCall := m.maxItemsPerCall(ctx)
+	var errs []error
+	for _, s := range m.subs {
+		for _, ids := range chunks(batches[s], perCall) {
+			res, err := s.sub.Unmonitor(ctx, ids...)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			for i, status := range res.Results {
+				if status != ua.StatusOK {
+					errs = append(errs, errors.Errorf("monitored item %d: %w", ids[i], status))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Rebalance adds monitored items which have been lost to the
+// subscriptions with the fewest items. Subscriptions which no longer
+// exist are replaced by new ones. A subscription which cannot be
+// replaced keeps its slot and items which do not fit on the remaining
+// subscriptions are kept until the next call. Rebalance is called
+// automatically after the client has reconnected.
+func (m *MonitorManager) Rebalance(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	known := make(map[uint32]bool)
+	for _, id := range m.client.SubscriptionIDs() {
+		known[id] = true
+	}
+
+	// cancel the lost subscriptions before creating new ones since the
+	// server may reuse their ids. The server may still have a
+	// subscription if the client has given up on it. The error is
+	// ignored since it is usually gone.
+	for _, s := range m.subs {
+		if s.stale || known[s.sub.SubscriptionID] {
+			continue
+		}
+		for h, it := range s.items {
+			delete(s.items, h)
+			it.sub = nil
+		}
+		s.sub.Cancel(ctx)
+		s.stale = true
+	}
+
+	var errs []error
+	subs := m.subs[:0]
+	for _, s := range m.subs {
+		if s.stale {
+			ns, err := m.subscribe(ctx)
+			if err != nil {
+				// keep the slot so that the next rebalance replaces it
+				errs = append(errs, errors.Errorf("subscription %d: %w", s.sub.SubscriptionID, err))
+				subs = append(subs, s)
+				continue
+			}
+			subs = append(subs, ns)
+			continue
+		}
+
+		// the ids of recreated monitored items have changed
+		present := make(map[uint32]uint32)
+		for _, info := range s.sub.Items() {
+			present[info.ClientHandle] = info.MonitoredItemID
+		}
+		for h, it := range s.items {
+			id, ok := present[h]
+			if !ok {
+				delete(s.items, h)
+				it.sub = nil
+				continue
+			}
+			it.id = id
+		}
+		subs = append(subs, s)
+	}
+	m.subs = subs
+
+	// items which are not on a subscription including the ones which
+	// could not be added by a previous rebalance
+	var lost []*managedItem
+	for _, it := range m.items {
+		if it.sub == nil {
+			m.setHandle(it.handle, nil)
+			lost = append(lost, it)
+		}
+	}
+
+	m.rebalances.Add(1)
+	m.reassigned.Add(uint64(len(lost)))
+	if err := m.add(ctx, lost); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Stats returns the distribution of the monitored items.
+func (m *MonitorManager) Stats() ManagerStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st := ManagerStats{
+		Items:      len(m.items),
+		Delivered:  m.delivered.Load(),
+		Dropped:    m.dropped.Load(),
+		Rebalances: m.rebalances.Load(),
+		Reassigned: m.reassigned.Load(),
+	}
+	for _, s := range m.subs {
+		st.Subscriptions = append(st.Subscriptions, SubscriptionLoad{
+			SubscriptionID: s.sub.SubscriptionID,
+			Items:          len(s.items),
+			Full:           !m.hasCapacity(s, 0),
+		})
+	}
+	return st
+}
+
+// add creates the monitored items on the subscriptions with the fewest
+// items. Items which a subscription rejects because it is full are
+// added to the remaining subscriptions. m.mu must be held.
+func (m *MonitorManager) add(ctx context.Context, items []*managedItem) error {
+	perCall := m.maxItemsPerCall(ctx)
+
+	var errs []error
+	for len(items) > 0 {
+		batches := make(map[*managedSub][]*managedItem)
+		for _, it := range items {
+			s := m.leastLoaded(batches)
+			if s == nil && m.hasStale() {
+				// keep the item for the next rebalance
+				errs = append(errs, errors.Errorf("node %s: no subscription available: %w", it.nodeID, ua.StatusBadNoSubscription))
+				continue
+			}
+			if s == nil {
+				delete(m.items, it.nodeID.String())
+				errs = append(errs, errors.Errorf("node %s: all subscriptions are full: %w", it.nodeID, ua.StatusBadTooManyMonitoredItems))
+				continue
+			}
+			batches[s] = append(batches[s], it)
+		}
+
+		// items rejected by a full subscription
+		var retry []*managedItem
+		for _, s := range m.subs {
+			for _, batch := range chunks(batches[s], perCall) {
+				reqs := make([]*ua.MonitoredItemCreateRequest, len(batch))
+				for i, it := range batch {
+					it.handle = atomic.AddUint32(&m.nextHandle, 1)
+					m.setHandle(it.handle, it.nodeID)
+					reqs[i] = opcua.NewMonitoredItemCreateRequestWithDefaults(it.nodeID, ua.AttributeIDValue, it.handle)
+				}
+
+				res, err := s.sub.Monitor(ctx, ua.TimestampsToReturnBoth, reqs...)
+				if err == nil && len(res.Results) != len(batch) {
+					err = ua.StatusBadUnknownResponse
+				}
+				if err != nil {
+					errs = append(errs, errors.Errorf("subscription %d: %d items: %w", s.sub.SubscriptionID, len(batch), err))
+				}
+				for i, it := range batch {
+					if err == nil && res.Results[i].StatusCode == ua.StatusOK {
+						it.id = res.Results[i].MonitoredItemID
+						it.sub = s
+						s.items[it.handle] = it
+						continue
+					}
+					m.setHandle(it.handle, nil)
+					switch {
+					case err != nil:
+					case res.Results[i].StatusCode == ua.StatusBadTooManyMonitoredItems:
+						s.full = true
+						retry = append(retry, it)
+						continue
+					default:
+						errs = append(errs, errors.Errorf("node %s: %w", it.nodeID, res.Results[i].StatusCode))
+					}
+					delete(m.items, it.nodeID.String())
+				}
+			}
+		}
+		items = retry
+	}
+	return errors.Join(errs...)
+}
+
+// leastLoaded returns the subscription with the fewest items including
+// the items which are about to be added or nil if all are full.
+func (m *MonitorManager) leastLoaded(pending map[*managedSub][]*managedItem) *managedSub {
+	var best *managedSub
+	for _, s := range m.subs {
+		if !m.hasCapacity(s, len(pending[s])) {
+			continue
+		}
+		if best == nil || len(s.items)+len(pending[s]) < len(best.items)+len(pending[best]) {
+			best = s
+		}
+	}
+	return best
+}
+
+// hasStale returns true if a lost subscription has not been replaced.
+func (m *MonitorManager) hasStale() bool {
+	for _, s := range m.subs {
+		if s.stale {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCapacity returns true if n more items can be added to s.
+func (m *MonitorManager) hasCapacity(s *managedSub, n int) bool {
+	if s.full || s.stale {
+		return false
+	}
+	limit := m.cfg.MaxItemsPerSubscription
+	return limit <= 0 || len(s.items)+n < limit
+}
+
+// maxItemsPerCall returns the MaxMonitoredItemsPerCall operation limit
+// of the server or zero if it is not known.
+func (m *MonitorManager) maxItemsPerCall(ctx context.Context) int {
+	l, err := m.client.OperationLimits(ctx)
+	if err != nil {
+		return 0
+	}
+	return int(l.MaxMonitoredItemsPerCall)
+}
+
+func (m *MonitorManager) setHandle(h uint32, n *ua.NodeID) {
+	m.handlesMu.Lock()
+	defer m.handlesMu.Unlock()
+	if n == nil {
+		delete(m.handles, h)
+		return
+	}
+	m.handles[h] = n
+}
+
+// watch rebalances the items after the client has reconnected.
+func (m *MonitorManager) watch(ctx context.Context) {
+	for {
+		if err := m.client.WaitForState(ctx, opcua.Reconnecting); err != nil {
+			return
+		}
+		if err := m.client.WaitForState(ctx, opcua.Connected); err != nil {
+			return
+		}
+		if err := m.Rebalance(ctx); err != nil {
+			m.send(&DataChangeMessage{Error: err})
+		}
+	}
+}
+
+// pump delivers the data changes of all subscriptions to the channel.
+func (m *MonitorManager) pump(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-m.notifyCh:
+			if msg.Error != nil {
+				m.send(&DataChangeMessage{Error: msg.Error})
+				continue
+			}
+			v, ok := msg.Value.(*ua.DataChangeNotification)
+			if !ok {
+				continue
+			}
+			for _, item := range v.MonitoredItems {
+				m.handlesMu.RLock()
+				nid, ok := m.handles[item.ClientHandle]
+				m.handlesMu.RUnlock()
+
+				out := &DataChangeMessage{}
+				if !ok {
+					out.Error = errors.Errorf("handle %d not found", item.ClientHandle)
+				} else {
+					out.NodeID = nid
+					out.DataValue = item.Value
+				}
+				m.send(out)
+			}
+		}
+	}
+}
+
+func (m *MonitorManager) send(msg *DataChangeMessage) {
+	select {
+	case m.ch <- msg:
+		m.delivered.Add(1)
+	default:
+		m.dropped.Add(1)
+	}
+}
+
+// chunks splits s into slices with at most n elements.
+// A non-positive n returns s as a single chunk.
+func chunks[T any](s []T, n int) [][]T {
+	if len(s) == 0 {
+		return nil
+	}
+	if n <= 0 {
+		return [][]T{s}
+	}
+	var out [][]T
+	for len(s) > n {
+		out = append(out, s[:n])
+		s = s[n:]
+	}
+	return append(out, s)
+}
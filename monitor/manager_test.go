@@ -0,0 +1,161 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/server"
+	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uasc"
+	"github.com/stretchr/testify/require"
+)
+
+// faultServer wraps the subscription services of a server and fails
+// them on demand.
+type faultServer struct {
+	srv *server.Server
+
+	mu sync.Mutex
+	// failSubscribe returns subscriptions without id.
+	failSubscribe bool
+	// shortResults returns no results for created monitored items.
+	shortResults bool
+}
+
+func (f *faultServer) set(failSubscribe, shortResults bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failSubscribe = failSubscribe
+	f.shortResults = shortResults
+}
+
+func (f *faultServer) CreateSubscription(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+	f.mu.Lock()
+	fail := f.failSubscribe
+	f.mu.Unlock()
+	if !fail {
+		return f.srv.SubscriptionService.CreateSubscription(sc, r, reqID)
+	}
+	// a bad service result fails the secure channel. A subscription
+	// without id fails only the request.
+	return &ua.CreateSubscriptionResponse{ResponseHeader: &ua.ResponseHeader{
+		Timestamp:          time.Now(),
+		RequestHandle:      r.Header().RequestHandle,
+		ServiceResult:      ua.StatusOK,
+		ServiceDiagnostics: &ua.DiagnosticInfo{},
+		StringTable:        []string{},
+		AdditionalHeader:   ua.NewExtensionObject(nil),
+	}}, nil
+}
+
+func (f *faultServer) CreateMonitoredItems(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+	f.mu.Lock()
+	short := f.shortResults
+	f.mu.Unlock()
+	res, err := f.srv.MonitoredItemService.CreateMonitoredItems(sc, r, reqID)
+	if err != nil || !short {
+		return res, err
+	}
+	res.(*ua.CreateMonitoredItemsResponse).Results = nil
+	return res, nil
+}
+
+func newTestManager(t *testing.T, ctx context.Context, cfg ManagerConfig) (*faultServer, *opcua.Client, *MonitorManager) {
+	t.Helper()
+
+	srv := server.New(
+		server.EndPoint("localhost", 4841),
+		server.EnableSecurity("None", ua.MessageSecurityModeNone),
+		server.EnableAuthMode(ua.UserTokenTypeAnonymous),
+	)
+	f := &faultServer{srv: srv}
+	// handlers registered before Start replace the default handlers
+	srv.RegisterHandler(id.CreateSubscriptionRequest_Encoding_DefaultBinary, f.CreateSubscription)
+	srv.RegisterHandler(id.CreateMonitoredItemsRequest_Encoding_DefaultBinary, f.CreateMonitoredItems)
+	// the server outlives ctx to handle the requests of the cleanup
+	require.NoError(t, srv.Start(context.Background()), "Start failed")
+	t.Cleanup(func() { srv.Close() })
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4841", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+	require.NoError(t, c.Connect(ctx), "Connect failed")
+	t.Cleanup(func() { c.Close(context.Background()) })
+
+	m, err := NewMonitorManager(ctx, c, cfg, make(chan *DataChangeMessage, 100))
+	require.NoError(t, err, "NewMonitorManager failed")
+	t.Cleanup(func() { m.Close(context.Background()) })
+	return f, c, m
+}
+
+func TestManagerShortResults(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	f, _, m := newTestManager(t, ctx, ManagerConfig{})
+	f.set(false, true)
+
+	err := m.AddNodes(ctx, "i=2258", "i=2259")
+	require.ErrorIs(t, err, ua.StatusBadUnknownResponse)
+
+	st := m.Stats()
+	require.Equal(t, 0, st.Items)
+	require.Equal(t, 0, st.Subscriptions[0].Items)
+}
+
+func TestManagerRebalanceKeepsLostSubscription(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	f, c, m := newTestManager(t, ctx, ManagerConfig{Subscriptions: 2, MaxItemsPerSubscription: 2})
+	nodes := []string{"i=2255", "i=2256", "i=2258", "i=2259"}
+	require.NoError(t, m.AddNodes(ctx, nodes...), "AddNodes failed")
+
+	lost := m.Stats().Subscriptions[0].SubscriptionID
+	require.NoError(t, c.DeleteSubscriptions(ctx, lost), "DeleteSubscriptions failed")
+
+	// the subscription cannot be replaced and the remaining one is full
+	f.set(true, false)
+	err := m.Rebalance(ctx)
+	require.ErrorIs(t, err, ua.StatusBadSubscriptionIDInvalid)
+	require.ErrorIs(t, err, ua.StatusBadNoSubscription)
+
+	st := m.Stats()
+	require.Equal(t, 4, st.Items)
+	require.Len(t, st.Subscriptions, 2)
+	require.Equal(t, lost, st.Subscriptions[0].SubscriptionID)
+	require.Equal(t, 0, st.Subscriptions[0].Items)
+	require.True(t, st.Subscriptions[0].Full)
+	require.Equal(t, 2, st.Subscriptions[1].Items)
+
+	// the next rebalance replaces the subscription and adds the kept items
+	f.set(false, false)
+	require.NoError(t, m.Rebalance(ctx), "Rebalance failed")
+
+	st = m.Stats()
+	require.Equal(t, 4, st.Items)
+	require.Len(t, st.Subscriptions, 2)
+	require.NotEqual(t, lost, st.Subscriptions[0].SubscriptionID)
+	require.Equal(t, 2, st.Subscriptions[0].Items)
+	require.Equal(t, 2, st.Subscriptions[1].Items)
+	require.ElementsMatch(t, []uint32{st.Subscriptions[0].SubscriptionID, st.Subscriptions[1].SubscriptionID}, c.SubscriptionIDs())
+}
+
+func TestChunks(t *testing.T) {
+	tests := []struct {
+		n    int
+		in   []int
+		want [][]int
+	}{
+		{n: 2, in: nil, want: nil},
+		{n: 0, in: []int{1, 2, 3}, want: [][]int{{1, 2, 3}}},
+		{n: 2, in: []int{1, 2}, want: [][]int{{1, 2}}},
+		{n: 2, in: []int{1, 2, 3, 4, 5}, want: [][]int{{1, 2}, {3, 4}, {5}}},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, chunks(tt.in, tt.n))
+	}
+}
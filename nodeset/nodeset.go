@@ -0,0 +1,577 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package nodeset reads information models from NodeSet2 XML files.
+//
+// Parse returns the nodes, references and data types declared in a
+// NodeSet2 file without connecting to a server. The definitions of the
+// structured data types can be registered with the runtime structure
+// codec of the ua package so that values of custom data types can be
+// decoded without reading the type definitions from the server.
+//
+// The node ids use the namespace indexes of the file, i.e. ns=1 refers
+// to the first entry of NamespaceURIs. They match the node ids of the
+// server if the server exposes the namespaces in the same order.
+//
+// See Part 6, Annex F
+package nodeset
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/schema"
+	"github.com/gopcua/opcua/ua"
+)
+
+// defaultEncodingName is the browse name of the
+// default binary encoding object of a data type.
+const defaultEncodingName = "Default Binary"
+
+// NodeSet contains the nodes declared in a NodeSet2 file.
+type NodeSet struct {
+	// NamespaceURIs contains the namespaces of the file.
+	// Namespace index i refers to NamespaceURIs[i-1].
+	NamespaceURIs []string
+
+	// Nodes contains the declared nodes ordered by node class
+	// and by their order in the file.
+	Nodes []*Node
+
+	nodes     map[string]*Node
+	parents   map[string]*ua.NodeID
+	encodings map[string][]*ua.NodeID
+
+	// standard is set for the standard node set.
+	standard bool
+}
+
+// standard is the node set of namespace zero.
+var standard = sync.OnceValues(func() (*NodeSet, error) {
+	s, err := Parse(bytes.NewReader(schema.OpcUaNodeSet2))
+	if err != nil {
+		return nil, err
+	}
+	s.standard = true
+	return s, nil
+})
+
+// Standard returns the node set of namespace zero which is defined by
+// the OPC/UA specification. It is parsed on the first call.
+func Standard() (*NodeSet, error) {
+	return standard()
+}
+
+// Node is a node declared in a NodeSet2 file.
+type Node struct {
+	NodeID       *ua.NodeID
+	NodeClass    ua.NodeClass
+	BrowseName   *ua.QualifiedName
+	DisplayName  string
+	Description  string
+	SymbolicName string
+
+	// ParentNodeID is the parent of objects, variables and methods.
+	ParentNodeID *ua.NodeID
+
+	// DataType is the data type of variables and variable types.
+	DataType *ua.NodeID
+
+	// IsAbstract is set for abstract types.
+	IsAbstract bool
+
+	// References contains the references of the node.
+	References []*Reference
+
+	// Definition is the definition of a data type or nil
+	// if the data type has none.
+	Definition *Definition
+}
+
+// Reference is a reference between two nodes.
+type Reference struct {
+	ReferenceTypeID *ua.NodeID
+	TargetID        *ua.NodeID
+	IsForward       bool
+}
+
+// Definition describes the fields of a structured data type or
+// the values of an enumeration.
+type Definition struct {
+	Name        string
+	IsUnion     bool
+	IsOptionSet bool
+	Fields      []*Field
+}
+
+// Field is a field of a structure or a value of an enumeration.
+type Field struct {
+	Name            string
+	Description     string
+	DataType        *ua.NodeID
+	ValueRank       int32
+	ArrayDimensions []uint32
+	MaxStringLength uint32
+	IsOptional      bool
+	AllowSubTypes   bool
+
+	// Value is the value of an enumeration field.
+	Value int64
+}
+
+// DataTypeDefinition is the definition of a structured data type
+// which can be registered with ua.RegisterStructure.
+type DataTypeDefinition struct {
+	DataTypeID *ua.NodeID
+	Definition *ua.StructureDefinition
+}
+
+// Parse reads a NodeSet2 XML document. Aliases are resolved and
+// reference types can also be given by the browse name of a reference
+// type of namespace zero, e.g. HasComponent.
+func Parse(r io.Reader) (*NodeSet, error) {
+	var doc schema.UANodeSet
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errors.Errorf("invalid nodeset: %s", err)
+	}
+
+	p := &parser{aliases: map[string]string{}}
+	if doc.Aliases != nil {
+		for _, a := range doc.Aliases.Alias {
+			p.aliases[a.AliasAttr] = strings.TrimSpace(a.Value)
+		}
+	}
+
+	s := &NodeSet{
+		nodes:     map[string]*Node{},
+		parents:   map[string]*ua.NodeID{},
+		encodings: map[string][]*ua.NodeID{},
+	}
+	if doc.NamespaceUris != nil {
+		s.NamespaceURIs = doc.NamespaceUris.Uri
+	}
+
+	add := func(n *Node, err error) error {
+		if err != nil {
+			return err
+		}
+		if _, ok := s.nodes[n.NodeID.String()]; ok {
+			return errors.Errorf("duplicate node %s", n.NodeID)
+		}
+		s.nodes[n.NodeID.String()] = n
+		s.Nodes = append(s.Nodes, n)
+		return nil
+	}
+	for _, x := range doc.UAReferenceType {
+		if err := add(p.typeNode(x.UAType, ua.NodeClassReferenceType)); err != nil {
+			return nil, err
+		}
+	}
+	for _, x := range doc.UADataType {
+		n, err := p.dataType(x)
+		if err := add(n, err); err != nil {
+			return nil, err
+		}
+	}
+	for _, x := range doc.UAObjectType {
+		if err := add(p.typeNode(x.UAType, ua.NodeClassObjectType)); err != nil {
+			return nil, err
+		}
+	}
+	for _, x := range doc.UAVariableType {
+		n, err := p.typeNode(x.UAType, ua.NodeClassVariableType)
+		if err == nil {
+			n.DataType, err = p.dataTypeID(x.DataTypeAttr)
+		}
+		if err := add(n, err); err != nil {
+			return nil, err
+		}
+	}
+	for _, x := range doc.UAObject {
+		if err := add(p.instance(x.UAInstance, ua.NodeClassObject)); err != nil {
+			return nil, err
+		}
+	}
+	for _, x := range doc.UAVariable {
+		n, err := p.instance(x.UAInstance, ua.NodeClassVariable)
+		if err == nil {
+			n.DataType, err = p.dataTypeID(x.DataTypeAttr)
+		}
+		if err := add(n, err); err != nil {
+			return nil, err
+		}
+	}
+	for _, x := range doc.UAMethod {
+		if err := add(p.instance(x.UAInstance, ua.NodeClassMethod)); err != nil {
+			return nil, err
+		}
+	}
+	for _, x := range doc.UAView {
+		if err := add(p.instance(x.UAInstance, ua.NodeClassView)); err != nil {
+			return nil, err
+		}
+	}
+
+	s.indexReferences()
+	return s, nil
+}
+
+// indexReferences records the supertypes and the encodings of the nodes.
+// Both references may be declared on either end.
+func (s *NodeSet) indexReferences() {
+	for _, n := range s.Nodes {
+		for _, ref := range n.References {
+			src, dst := n.NodeID, ref.TargetID
+			if !ref.IsForward {
+				src, dst = dst, src
+			}
+			switch {
+			case isReferenceType(ref.ReferenceTypeID, id.HasSubtype):
+				s.parents[dst.String()] = src
+			case isReferenceType(ref.ReferenceTypeID, id.HasEncoding):
+				s.encodings[src.String()] = append(s.encodings[src.String()], dst)
+			}
+		}
+	}
+}
+
+func isReferenceType(n *ua.NodeID, refType uint32) bool {
+	return n.Namespace() == 0 && n.IntID() == refType
+}
+
+// Node returns the node with the given id or nil if the
+// node set does not declare it.
+func (s *NodeSet) Node(n *ua.NodeID) *Node {
+	if n == nil {
+		return nil
+	}
+	return s.nodes[n.String()]
+}
+
+// DataTypes returns the declared data types.
+func (s *NodeSet) DataTypes() []*Node {
+	var types []*Node
+	for _, n := range s.Nodes {
+		if n.NodeClass == ua.NodeClassDataType {
+			types = append(types, n)
+		}
+	}
+	return types
+}
+
+// SuperType returns the id of the supertype of a type or nil if the
+// node set does not declare one. The supertypes of the types of
+// namespace zero are looked up in the standard node set.
+func (s *NodeSet) SuperType(n *ua.NodeID) *ua.NodeID {
+	if p := s.parents[n.String()]; p != nil || s.standard || n.Namespace() != 0 {
+		return p
+	}
+	std, err := standard()
+	if err != nil {
+		return nil
+	}
+	return std.SuperType(n)
+}
+
+// DefaultEncodingID returns the id of the default binary encoding of a
+// data type or nil if the node set does not declare one.
+func (s *NodeSet) DefaultEncodingID(dataTypeID *ua.NodeID) *ua.NodeID {
+	for _, enc := range s.encodings[dataTypeID.String()] {
+		if n := s.Node(enc); n != nil && n.BrowseName.Name == defaultEncodingName {
+			return enc
+		}
+	}
+	return nil
+}
+
+// DataTypeDefinitions returns the definitions of the structured data
+// types with a default binary encoding which are not in namespace zero.
+// The definitions can be registered with ua.RegisterStructure.
+func (s *NodeSet) DataTypeDefinitions() []*DataTypeDefinition {
+	var defs []*DataTypeDefinition
+	for _, n := range s.DataTypes() {
+		if n.NodeID.Namespace() == 0 || n.Definition == nil || s.baseDataType(n.NodeID) != id.Structure {
+			continue
+		}
+		enc := s.DefaultEncodingID(n.NodeID)
+		if enc == nil {
+			continue
+		}
+		defs = append(defs, &DataTypeDefinition{
+			DataTypeID: n.NodeID,
+			Definition: s.structureDefinition(n, enc),
+		})
+	}
+	return defs
+}
+
+// structureDefinition converts the definition of a data type.
+func (s *NodeSet) structureDefinition(n *Node, enc *ua.NodeID) *ua.StructureDefinition {
+	def := &ua.StructureDefinition{
+		DefaultEncodingID: enc,
+		BaseDataType:      s.SuperType(n.NodeID),
+		StructureType:     ua.StructureTypeStructure,
+	}
+	if def.BaseDataType == nil {
+		def.BaseDataType = ua.NewNumericNodeID(0, id.Structure)
+	}
+
+	var optional, subtyped bool
+	for _, f := range n.Definition.Fields {
+		optional = optional || f.IsOptional
+		subtyped = subtyped || f.AllowSubTypes
+		def.Fields = append(def.Fields, &ua.StructureField{
+			Name:            f.Name,
+			Description:     ua.NewLocalizedText(f.Description),
+			DataType:        f.DataType,
+			ValueRank:       f.ValueRank,
+			ArrayDimensions: f.ArrayDimensions,
+			MaxStringLength: f.MaxStringLength,
+			IsOptional:      f.IsOptional,
+		})
+	}
+	switch {
+	case n.Definition.IsUnion && subtyped:
+		def.StructureType = ua.StructureTypeUnionWithSubtypedValues
+	case n.Definition.IsUnion:
+		def.StructureType = ua.StructureTypeUnion
+	case subtyped:
+		def.StructureType = ua.StructureTypeStructureWithSubtypedValues
+	case optional:
+		def.StructureType = ua.StructureTypeStructureWithOptionalFields
+	}
+	return def
+}
+
+// RegisterDataTypes registers the data types which are not in namespace
+// zero with the runtime structure codec of the ua package. Structures
+// are registered with ua.RegisterStructure. Enumerations, subtypes of
+// built-in types and structures without an encoding are registered with
+// ua.RegisterBuiltinDataType.
+func (s *NodeSet) RegisterDataTypes() error {
+	structs := map[string]bool{}
+	for _, d := range s.DataTypeDefinitions() {
+		if err := ua.RegisterStructure(d.DataTypeID, d.Definition); err != nil {
+			return err
+		}
+		structs[d.DataTypeID.String()] = true
+	}
+
+	for _, n := range s.DataTypes() {
+		if n.NodeID.Namespace() == 0 || structs[n.NodeID.String()] {
+			continue
+		}
+		switch base := s.baseDataType(n.NodeID); {
+		case base == id.Structure:
+			ua.RegisterBuiltinDataType(n.NodeID, ua.TypeIDExtensionObject)
+		case base == id.Enumeration:
+			ua.RegisterBuiltinDataType(n.NodeID, ua.TypeIDInt32)
+		case base > 0:
+			ua.RegisterBuiltinDataType(n.NodeID, ua.TypeID(base))
+		}
+	}
+	return nil
+}
+
+// baseDataType walks up the data type hierarchy and returns the id of
+// the first built-in data type, Structure or Enumeration. It returns
+// zero if there is none.
+func (s *NodeSet) baseDataType(n *ua.NodeID) uint32 {
+	for seen := map[string]bool{}; n != nil && !seen[n.String()]; n = s.SuperType(n) {
+		seen[n.String()] = true
+		if n.Namespace() != 0 {
+			continue
+		}
+		switch v := n.IntID(); {
+		case v == id.Structure, v == id.Enumeration:
+			return v
+		case v >= uint32(ua.TypeIDBoolean) && v <= uint32(ua.TypeIDDiagnosticInfo):
+			return v
+		}
+	}
+	return 0
+}
+
+// parser converts the nodes of the schema.
+type parser struct {
+	aliases map[string]string
+}
+
+// nodeID parses a node id or an alias.
+func (p *parser) nodeID(s string) (*ua.NodeID, error) {
+	s = strings.TrimSpace(s)
+	if v, ok := p.aliases[s]; ok {
+		s = v
+	}
+	n, err := ua.ParseNodeID(s)
+	if err != nil {
+		return nil, errors.Errorf("invalid node id %q: %s", s, err)
+	}
+	return n, nil
+}
+
+// dataTypeID parses the data type of a variable or a field.
+// The default is BaseDataType.
+func (p *parser) dataTypeID(s string) (*ua.NodeID, error) {
+	if s == "" {
+		return ua.NewNumericNodeID(0, id.BaseDataType), nil
+	}
+	return p.nodeID(s)
+}
+
+// referenceTypeID parses the type of a reference which may also
+// be the browse name of a reference type of namespace zero.
+func (p *parser) referenceTypeID(s string) (*ua.NodeID, error) {
+	if _, ok := p.aliases[s]; !ok {
+		if v, ok := id.ReferenceTypeID(s); ok {
+			return ua.NewNumericNodeID(0, v), nil
+		}
+	}
+	return p.nodeID(s)
+}
+
+func (p *parser) node(x *schema.UANode, class ua.NodeClass) (*Node, error) {
+	if x == nil {
+		return nil, errors.Errorf("invalid nodeset: missing node")
+	}
+	nid, err := p.nodeID(x.NodeIdAttr)
+	if err != nil {
+		return nil, err
+	}
+	n := &Node{
+		NodeID:       nid,
+		NodeClass:    class,
+		BrowseName:   parseQualifiedName(x.BrowseNameAttr),
+		DisplayName:  localizedText(x.DisplayName),
+		Description:  localizedText(x.Description),
+		SymbolicName: x.SymbolicNameAttr,
+	}
+	if x.References == nil {
+		return n, nil
+	}
+	for _, r := range x.References.Reference {
+		refType, err := p.referenceTypeID(r.ReferenceTypeAttr)
+		if err != nil {
+			return nil, errors.Errorf("node %s: %s", nid, err)
+		}
+		target, err := p.nodeID(r.Value)
+		if err != nil {
+			return nil, errors.Errorf("node %s: %s", nid, err)
+		}
+		n.References = append(n.References, &Reference{
+			ReferenceTypeID: refType,
+			TargetID:        target,
+			IsForward:       r.IsForwardAttr == nil || *r.IsForwardAttr,
+		})
+	}
+	return n, nil
+}
+
+func (p *parser) typeNode(x *schema.UAType, class ua.NodeClass) (*Node, error) {
+	if x == nil {
+		return nil, errors.Errorf("invalid nodeset: missing node")
+	}
+	n, err := p.node(x.UANode, class)
+	if err != nil {
+		return nil, err
+	}
+	n.IsAbstract = x.IsAbstractAttr
+	return n, nil
+}
+
+func (p *parser) instance(x *schema.UAInstance, class ua.NodeClass) (*Node, error) {
+	if x == nil {
+		return nil, errors.Errorf("invalid nodeset: missing node")
+	}
+	n, err := p.node(x.UANode, class)
+	if err != nil {
+		return nil, err
+	}
+	if x.ParentNodeIdAttr != "" {
+		if n.ParentNodeID, err = p.nodeID(x.ParentNodeIdAttr); err != nil {
+			return nil, errors.Errorf("node %s: %s", n.NodeID, err)
+		}
+	}
+	return n, nil
+}
+
+func (p *parser) dataType(x *schema.UADataType) (*Node, error) {
+	n, err := p.typeNode(x.UAType, ua.NodeClassDataType)
+	if err != nil || x.Definition == nil {
+		return n, err
+	}
+	d := x.Definition
+	n.Definition = &Definition{
+		Name:        d.NameAttr,
+		IsUnion:     d.IsUnionAttr,
+		IsOptionSet: d.IsOptionSetAttr,
+	}
+	for _, f := range d.Field {
+		dt, err := p.dataTypeID(f.DataTypeAttr)
+		if err != nil {
+			return nil, errors.Errorf("data type %s: field %s: %s", n.NodeID, f.NameAttr, err)
+		}
+		dims, err := parseArrayDimensions(f.ArrayDimensionsAttr)
+		if err != nil {
+			return nil, errors.Errorf("data type %s: field %s: %s", n.NodeID, f.NameAttr, err)
+		}
+		valueRank := int32(-1)
+		if f.ValueRankAttr != nil {
+			valueRank = int32(*f.ValueRankAttr)
+		}
+		n.Definition.Fields = append(n.Definition.Fields, &Field{
+			Name:            f.NameAttr,
+			Description:     localizedText(f.Description),
+			DataType:        dt,
+			ValueRank:       valueRank,
+			ArrayDimensions: dims,
+			MaxStringLength: f.MaxStringLengthAttr,
+			IsOptional:      f.IsOptionalAttr,
+			AllowSubTypes:   f.AllowSubTypesAttr,
+			Value:           int64(f.ValueAttr),
+		})
+	}
+	return n, nil
+}
+
+// parseQualifiedName parses a browse name with an optional
+// namespace index prefix, e.g. "1:Temperature".
+func parseQualifiedName(s string) *ua.QualifiedName {
+	if i := strings.IndexByte(s, ':'); i > 0 {
+		if ns, err := strconv.ParseUint(s[:i], 10, 16); err == nil {
+			return &ua.QualifiedName{NamespaceIndex: uint16(ns), Name: s[i+1:]}
+		}
+	}
+	return &ua.QualifiedName{Name: s}
+}
+
+// parseArrayDimensions parses a comma separated list of dimensions.
+func parseArrayDimensions(s string) ([]uint32, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var dims []uint32
+	for _, v := range strings.Split(s, ",") {
+		d, err := strconv.ParseUint(strings.TrimSpace(v), 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("invalid array dimensions %q", s)
+		}
+		dims = append(dims, uint32(d))
+	}
+	return dims, nil
+}
+
+// localizedText returns the first text or an empty string.
+func localizedText(t []*schema.LocalizedText) string {
+	if len(t) == 0 {
+		return ""
+	}
+	return t[0].Value
+}
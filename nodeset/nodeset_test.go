@@ -0,0 +1,162 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package nodeset_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/nodeset"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+const testNodeSet = `<?xml version="1.0" encoding="utf-8"?>
+<UANodeSet xmlns="http://opcfoundation.org/UA/2011/03/UANodeSet.xsd">
+  <NamespaceUris>
+    <Uri>urn:example:pumps</Uri>
+  </NamespaceUris>
+  <Aliases>
+    <Alias Alias="Int32">i=6</Alias>
+    <Alias Alias="String">i=12</Alias>
+    <Alias Alias="HasEncoding">i=38</Alias>
+    <Alias Alias="HasSubtype">i=45</Alias>
+  </Aliases>
+  <UADataType NodeId="ns=1;i=3001" BrowseName="1:PumpMode">
+    <DisplayName>PumpMode</DisplayName>
+    <References>
+      <Reference ReferenceType="HasSubtype" IsForward="false">i=29</Reference>
+    </References>
+    <Definition Name="1:PumpMode">
+      <Field Name="Off" Value="0" />
+      <Field Name="On" Value="1" />
+    </Definition>
+  </UADataType>
+  <UADataType NodeId="ns=1;i=3002" BrowseName="1:PumpStatus">
+    <DisplayName>PumpStatus</DisplayName>
+    <References>
+      <Reference ReferenceType="HasSubtype" IsForward="false">i=22</Reference>
+      <Reference ReferenceType="HasEncoding">ns=1;i=5001</Reference>
+    </References>
+    <Definition Name="1:PumpStatus">
+      <Field Name="Speed" DataType="Int32" />
+      <Field Name="Mode" DataType="ns=1;i=3001" />
+      <Field Name="Label" DataType="String" IsOptional="true" />
+      <Field Name="History" DataType="Int32" ValueRank="1" ArrayDimensions="3" />
+    </Definition>
+  </UADataType>
+  <UAObject NodeId="ns=1;i=5001" BrowseName="Default Binary" SymbolicName="DefaultBinary">
+    <DisplayName>Default Binary</DisplayName>
+    <References>
+      <Reference ReferenceType="HasTypeDefinition">i=76</Reference>
+    </References>
+  </UAObject>
+  <UAObject NodeId="ns=1;i=1" BrowseName="1:Pump">
+    <DisplayName>Pump</DisplayName>
+    <Description>A pump</Description>
+    <References>
+      <Reference ReferenceType="Organizes" IsForward="false">i=85</Reference>
+      <Reference ReferenceType="HasComponent">ns=1;s=Pump.Status</Reference>
+    </References>
+  </UAObject>
+  <UAVariable NodeId="ns=1;s=Pump.Status" BrowseName="1:Status" ParentNodeId="ns=1;i=1" DataType="ns=1;i=3002">
+    <DisplayName>Status</DisplayName>
+  </UAVariable>
+</UANodeSet>`
+
+func TestParse(t *testing.T) {
+	s, err := nodeset.Parse(strings.NewReader(testNodeSet))
+	require.NoError(t, err, "Parse failed")
+	require.Equal(t, []string{"urn:example:pumps"}, s.NamespaceURIs)
+	require.Len(t, s.Nodes, 5)
+
+	pump := s.Node(ua.NewNumericNodeID(1, 1))
+	require.NotNil(t, pump)
+	require.Equal(t, ua.NodeClassObject, pump.NodeClass)
+	require.Equal(t, &ua.QualifiedName{NamespaceIndex: 1, Name: "Pump"}, pump.BrowseName)
+	require.Equal(t, "Pump", pump.DisplayName)
+	require.Equal(t, "A pump", pump.Description)
+	require.Equal(t, []*nodeset.Reference{
+		{ReferenceTypeID: ua.NewNumericNodeID(0, id.Organizes), TargetID: ua.MustParseNodeID("i=85"), IsForward: false},
+		{ReferenceTypeID: ua.NewNumericNodeID(0, id.HasComponent), TargetID: ua.NewStringNodeID(1, "Pump.Status"), IsForward: true},
+	}, pump.References)
+
+	status := s.Node(ua.NewStringNodeID(1, "Pump.Status"))
+	require.NotNil(t, status)
+	require.Equal(t, ua.NodeClassVariable, status.NodeClass)
+	require.Equal(t, "ns=1;i=1", status.ParentNodeID.String())
+	require.Equal(t, "ns=1;i=3002", status.DataType.String())
+
+	require.Len(t, s.DataTypes(), 2)
+	statusType := ua.NewNumericNodeID(1, 3002)
+	require.Equal(t, "i=22", s.SuperType(statusType).String())
+	require.Equal(t, "ns=1;i=5001", s.DefaultEncodingID(statusType).String())
+	// supertypes of namespace zero are looked up in the standard node set
+	require.Equal(t, "i=22", s.SuperType(ua.NewNumericNodeID(0, id.Union)).String())
+
+	mode := s.Node(ua.NewNumericNodeID(1, 3001)).Definition
+	require.Len(t, mode.Fields, 2)
+	require.Equal(t, int64(1), mode.Fields[1].Value)
+
+	require.Nil(t, s.Node(ua.NewNumericNodeID(1, 9999)))
+}
+
+func TestDataTypeDefinitions(t *testing.T) {
+	s, err := nodeset.Parse(strings.NewReader(testNodeSet))
+	require.NoError(t, err, "Parse failed")
+
+	statusType := ua.MustParseNodeID("ns=1;i=3002")
+	want := []*nodeset.DataTypeDefinition{{
+		DataTypeID: statusType,
+		Definition: &ua.StructureDefinition{
+			DefaultEncodingID: ua.MustParseNodeID("ns=1;i=5001"),
+			BaseDataType:      ua.MustParseNodeID("i=22"),
+			StructureType:     ua.StructureTypeStructureWithOptionalFields,
+			Fields: []*ua.StructureField{
+				{Name: "Speed", Description: ua.NewLocalizedText(""), DataType: ua.MustParseNodeID("i=6"), ValueRank: -1},
+				{Name: "Mode", Description: ua.NewLocalizedText(""), DataType: ua.MustParseNodeID("ns=1;i=3001"), ValueRank: -1},
+				{Name: "Label", Description: ua.NewLocalizedText(""), DataType: ua.MustParseNodeID("i=12"), ValueRank: -1, IsOptional: true},
+				{Name: "History", Description: ua.NewLocalizedText(""), DataType: ua.MustParseNodeID("i=6"), ValueRank: 1, ArrayDimensions: []uint32{3}},
+			},
+		},
+	}}
+	require.Equal(t, want, s.DataTypeDefinitions())
+
+	require.NoError(t, s.RegisterDataTypes(), "RegisterDataTypes failed")
+	v, err := ua.NewStructure(statusType, map[string]interface{}{
+		"Speed":   int32(1200),
+		"Mode":    int32(1),
+		"History": []int32{1, 2, 3},
+	})
+	require.NoError(t, err, "NewStructure failed")
+
+	b, err := ua.Encode(ua.NewExtensionObject(v))
+	require.NoError(t, err, "Encode failed")
+	var eo ua.ExtensionObject
+	_, err = ua.Decode(b, &eo)
+	require.NoError(t, err, "Decode failed")
+	got, ok := eo.Value.(*ua.Structure)
+	require.True(t, ok, "got %T, want *ua.Structure", eo.Value)
+	require.Equal(t, v.Fields, got.Fields)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		xml  string
+	}{
+		{"invalid xml", `<UANodeSet>`},
+		{"invalid node id", `<UANodeSet><UAObject NodeId="i=abc" BrowseName="A"/></UANodeSet>`},
+		{"invalid reference", `<UANodeSet><UAObject NodeId="i=1" BrowseName="A"><References><Reference ReferenceType="HasComponent">i=abc</Reference></References></UAObject></UANodeSet>`},
+		{"duplicate node", `<UANodeSet><UAObject NodeId="i=1" BrowseName="A"/><UAObject NodeId="i=1" BrowseName="B"/></UANodeSet>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := nodeset.Parse(strings.NewReader(tt.xml))
+			require.Error(t, err)
+		})
+	}
+}
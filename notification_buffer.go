@@ -0,0 +1,125 @@
+package opcua
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy defines what happens to a notification when the
+// notification buffer of a subscription is full.
+type DropPolicy int
+
+const (
+	// BlockOnFull waits until there is room in the buffer. A slow
+	// handler stalls the publish loop of the client which stops sending
+	// publish requests for all subscriptions of the session. The server
+	// then queues the notifications and eventually discards them
+	// according to the queue size of the monitored items.
+	BlockOnFull DropPolicy = iota
+
+	// DropOldest discards the oldest notification in the buffer to make
+	// room for the new one. The handler always sees the latest data.
+	DropOldest
+
+	// DropNewest discards the new notification. The handler sees the
+	// data in the order it was received but misses the latest changes.
+	DropNewest
+)
+
+func (p DropPolicy) String() string {
+	switch p {
+	case BlockOnFull:
+		return "BlockOnFull"
+	case DropOldest:
+		return "DropOldest"
+	case DropNewest:
+		return "DropNewest"
+	default:
+		return "DropPolicy(?)"
+	}
+}
+
+// notificationBuffer is a bounded queue of notifications between the
+// publish loop and the notification channel of a subscription.
+type notificationBuffer struct {
+	ch     chan *PublishNotificationData
+	policy DropPolicy
+
+	// mu serializes the producers for DropOldest so that
+	// only one notification is discarded per push.
+	mu sync.Mutex
+
+	// dropped counts the discarded notifications.
+	dropped *atomic.Uint64
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newNotificationBuffer(size int, policy DropPolicy, dropped *atomic.Uint64) *notificationBuffer {
+	return &notificationBuffer{
+		ch:      make(chan *PublishNotificationData, size),
+		policy:  policy,
+		dropped: dropped,
+		done:    make(chan struct{}),
+	}
+}
+
+// push adds a notification to the buffer according to the drop policy.
+func (b *notificationBuffer) push(ctx context.Context, data *PublishNotificationData) {
+	switch b.policy {
+	case DropNewest:
+		select {
+		case b.ch <- data:
+		default:
+			b.dropped.Add(1)
+		}
+
+	case DropOldest:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for {
+			select {
+			case b.ch <- data:
+				return
+			default:
+			}
+			select {
+			case <-b.ch:
+				b.dropped.Add(1)
+			default:
+			}
+		}
+
+	default:
+		select {
+		case <-ctx.Done():
+		case <-b.done:
+		case b.ch <- data:
+		}
+	}
+}
+
+// forward sends the buffered notifications to ch until the
+// buffer is closed.
+func (b *notificationBuffer) forward(ch chan<- *PublishNotificationData) {
+	for {
+		select {
+		case <-b.done:
+			return
+		case data := <-b.ch:
+			select {
+			case <-b.done:
+				return
+			case ch <- data:
+			}
+		}
+	}
+}
+
+// close stops forwarding. Notifications which are still in the
+// buffer are discarded.
+func (b *notificationBuffer) close() {
+	b.closeOnce.Do(func() { close(b.done) })
+}
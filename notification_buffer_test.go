@@ -0,0 +1,84 @@
+package opcua
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationBuffer(t *testing.T) {
+	notif := func(id uint32) *PublishNotificationData {
+		return &PublishNotificationData{SubscriptionID: id}
+	}
+	drain := func(b *notificationBuffer) []uint32 {
+		var ids []uint32
+		for len(b.ch) > 0 {
+			ids = append(ids, (<-b.ch).SubscriptionID)
+		}
+		return ids
+	}
+
+	t.Run("DropOldest", func(t *testing.T) {
+		var dropped atomic.Uint64
+		b := newNotificationBuffer(2, DropOldest, &dropped)
+		for i := uint32(1); i <= 4; i++ {
+			b.push(context.Background(), notif(i))
+		}
+		require.Equal(t, []uint32{3, 4}, drain(b))
+		require.Equal(t, uint64(2), dropped.Load())
+	})
+
+	t.Run("DropNewest", func(t *testing.T) {
+		var dropped atomic.Uint64
+		b := newNotificationBuffer(2, DropNewest, &dropped)
+		for i := uint32(1); i <= 4; i++ {
+			b.push(context.Background(), notif(i))
+		}
+		require.Equal(t, []uint32{1, 2}, drain(b))
+		require.Equal(t, uint64(2), dropped.Load())
+	})
+
+	t.Run("BlockOnFull", func(t *testing.T) {
+		var dropped atomic.Uint64
+		b := newNotificationBuffer(1, BlockOnFull, &dropped)
+		b.push(context.Background(), notif(1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		b.push(ctx, notif(2))
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded, "push did not block")
+		require.Equal(t, []uint32{1}, drain(b))
+		require.Equal(t, uint64(0), dropped.Load())
+	})
+
+	t.Run("forward", func(t *testing.T) {
+		var dropped atomic.Uint64
+		b := newNotificationBuffer(2, DropOldest, &dropped)
+		ch := make(chan *PublishNotificationData)
+		go b.forward(ch)
+		defer b.close()
+
+		b.push(context.Background(), notif(1))
+		select {
+		case got := <-ch:
+			require.Equal(t, uint32(1), got.SubscriptionID)
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	})
+}
+
+func TestSubscriptionNotificationsDropped(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840", NotificationBuffer(1, DropNewest))
+	require.NoError(t, err, "NewClient failed")
+
+	sub := &Subscription{SubscriptionID: 1, c: c}
+	sub.buf = newNotificationBuffer(c.cfg.notifBufferSize, c.cfg.notifDropPolicy, &sub.stats.notificationsDropped)
+
+	sub.notify(context.Background(), &PublishNotificationData{})
+	sub.notify(context.Background(), &PublishNotificationData{})
+	require.Equal(t, uint64(1), sub.PublishStats().NotificationsDropped)
+}
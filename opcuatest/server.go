@@ -0,0 +1,142 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package opcuatest provides an in-process OPC/UA server for testing
+// clients without an external server.
+//
+// The server is a thin wrapper around the server package and listens on
+// a free port of the loopback interface. It supports the connection and
+// session handshake and the services of the server package, e.g. Read,
+// Write, Browse and subscriptions, without security and with anonymous
+// authentication unless configured otherwise.
+//
+// The package lives outside of package opcua so that clients do not link
+// the server and its embedded nodeset.
+package opcuatest
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/server"
+	"github.com/gopcua/opcua/ua"
+)
+
+// Server is an OPC/UA server listening on a free port of the loopback
+// interface.
+type Server struct {
+	// URL is the endpoint URL of the server, e.g. opc.tcp://127.0.0.1:49152
+	URL string
+
+	srv    *server.Server
+	ns     *server.NodeNameSpace
+	cancel context.CancelFunc
+}
+
+// NewServer starts and returns a new Server. The caller should call
+// Close when finished to shut it down.
+//
+// The server offers the None security policy and anonymous authentication.
+// Additional options, e.g. security policies or a certificate, are applied
+// after the defaults. NewServer panics if the server cannot be started.
+func NewServer(opts ...server.Option) *Server {
+	port, err := freePort()
+	if err != nil {
+		panic(fmt.Sprintf("opcuatest: failed to find a free port: %v", err))
+	}
+
+	opts = append([]server.Option{
+		server.EndPoint("127.0.0.1", port),
+		server.EnableSecurity("None", ua.MessageSecurityModeNone),
+		server.EnableAuthMode(ua.UserTokenTypeAnonymous),
+	}, opts...)
+
+	srv := server.New(opts...)
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := srv.Start(ctx); err != nil {
+		cancel()
+		panic(fmt.Sprintf("opcuatest: failed to start server: %v", err))
+	}
+
+	// the nodes added by the test live in their own namespace which
+	// is referenced from the objects folder of namespace 0.
+	ns := server.NewNodeNameSpace(srv, "opcuatest")
+	root, _ := srv.Namespace(0)
+	root.Objects().AddRef(ns.Objects(), id.HasComponent, true)
+
+	return &Server{
+		URL:    srv.URLs()[0],
+		srv:    srv,
+		ns:     ns,
+		cancel: cancel,
+	}
+}
+
+// freePort returns a port on the loopback interface
+// which is currently not in use.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// Server returns the underlying server for tests which
+// need more control over the address space.
+func (s *Server) Server() *server.Server {
+	return s.srv
+}
+
+// Namespace returns the namespace of the nodes added with AddVariable.
+func (s *Server) Namespace() *server.NodeNameSpace {
+	return s.ns
+}
+
+// AddVariable adds a readable and writable variable with the given
+// name and initial value to the objects folder of the test namespace
+// and returns its node id. The node id is a string node id with the
+// name as identifier.
+func (s *Server) AddVariable(name string, value any) *ua.NodeID {
+	n := s.ns.AddNewVariableStringNode(name, value)
+	s.ns.Objects().AddRef(n, id.HasComponent, true)
+	return n.ID()
+}
+
+// SetValue changes the value of a variable regardless of its access
+// level and notifies the subscribers of the node.
+func (s *Server) SetValue(nodeID *ua.NodeID, value any) error {
+	n := s.ns.Node(nodeID)
+	if n == nil {
+		return fmt.Errorf("opcuatest: node %s not found: %w", nodeID, ua.StatusBadNodeIDUnknown)
+	}
+	if err := n.SetAttribute(ua.AttributeIDValue, server.DataValueFromValue(value)); err != nil {
+		return fmt.Errorf("opcuatest: set value of %s: %w", nodeID, err)
+	}
+	s.srv.ChangeNotification(nodeID)
+	return nil
+}
+
+// Value returns the current value of a variable or nil if the
+// node does not exist or has no value.
+func (s *Server) Value(nodeID *ua.NodeID) any {
+	n := s.ns.Node(nodeID)
+	if n == nil {
+		return nil
+	}
+	dv := n.Value()
+	if dv == nil || dv.Value == nil {
+		return nil
+	}
+	return dv.Value.Value()
+}
+
+// Close shuts down the server and closes all connections.
+func (s *Server) Close() error {
+	defer s.cancel()
+	return s.srv.Close()
+}
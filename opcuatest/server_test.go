@@ -0,0 +1,79 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package opcuatest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/opcuatest"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := opcuatest.NewServer()
+	defer srv.Close()
+
+	temp := srv.AddVariable("temperature", 21.5)
+	require.Equal(t, "ns=1;s=temperature", temp.String())
+
+	c, err := opcua.NewClient(srv.URL, opcua.SecurityMode(ua.MessageSecurityModeNone), opcua.AutoReconnect(false))
+	require.NoError(t, err, "NewClient failed")
+	require.NoError(t, c.Connect(ctx), "Connect failed")
+	defer c.Close(ctx)
+
+	t.Run("read", func(t *testing.T) {
+		v, err := c.Node(temp).Value(ctx)
+		require.NoError(t, err, "Read failed")
+		require.Equal(t, 21.5, v.Value())
+	})
+
+	t.Run("write", func(t *testing.T) {
+		status, err := c.WriteValue(ctx, temp, 22.0)
+		require.NoError(t, err, "WriteValue failed")
+		require.Equal(t, ua.StatusOK, status)
+		require.Equal(t, 22.0, srv.Value(temp))
+	})
+
+	t.Run("browse", func(t *testing.T) {
+		objects := c.Node(srv.Namespace().Objects().ID())
+		children, err := objects.Children(ctx, id.HasComponent, ua.NodeClassVariable)
+		require.NoError(t, err, "Browse failed")
+		require.Len(t, children, 1)
+		require.Equal(t, temp.String(), children[0].ID.String())
+	})
+
+	t.Run("subscribe", func(t *testing.T) {
+		notifs := make(chan *opcua.PublishNotificationData, 10)
+		sub, err := c.Subscribe(ctx, &opcua.SubscriptionParameters{Interval: 50 * time.Millisecond}, notifs)
+		require.NoError(t, err, "Subscribe failed")
+		defer sub.Cancel(ctx)
+
+		dv, updates, err := sub.MonitorWithInitial(ctx, temp, ua.TimestampsToReturnBoth)
+		require.NoError(t, err, "MonitorWithInitial failed")
+		require.Equal(t, 22.0, dv.Value.Value())
+
+		require.NoError(t, srv.SetValue(temp, 23.0), "SetValue failed")
+		select {
+		case dv := <-updates:
+			require.Equal(t, 23.0, dv.Value.Value())
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for update")
+		}
+	})
+
+	t.Run("unknown node", func(t *testing.T) {
+		err := srv.SetValue(ua.NewStringNodeID(1, "unknown"), 1)
+		require.ErrorIs(t, err, ua.StatusBadNodeIDUnknown)
+		require.Nil(t, srv.Value(ua.NewStringNodeID(1, "unknown")))
+	})
+}
@@ -0,0 +1,129 @@
+package opcua
+
+import (
+	"context"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/stats"
+	"github.com/gopcua/opcua/ua"
+)
+
+// OperationLimits contains the operation limits of the server.
+// A value of zero means that the server does not define a limit.
+//
+// See Part 5, 6.3.11
+type OperationLimits struct {
+	MaxNodesPerRead                          uint32
+	MaxNodesPerHistoryReadData               uint32
+	MaxNodesPerHistoryReadEvents             uint32
+	MaxNodesPerWrite                         uint32
+	MaxNodesPerHistoryUpdateData             uint32
+	MaxNodesPerHistoryUpdateEvents           uint32
+	MaxNodesPerMethodCall                    uint32
+	MaxNodesPerBrowse                        uint32
+	MaxNodesPerRegisterNodes                 uint32
+	MaxNodesPerTranslateBrowsePathsToNodeIDs uint32
+	MaxNodesPerNodeManagement                uint32
+	MaxMonitoredItemsPerCall                 uint32
+}
+
+// limitNodes returns the node ids of the operation limits and
+// pointers to the corresponding fields.
+func (l *OperationLimits) limitNodes() []struct {
+	id uint32
+	v  *uint32
+} {
+	return []struct {
+		id uint32
+		v  *uint32
+	}{
+		{id.Server_ServerCapabilities_OperationLimits_MaxNodesPerRead, &l.MaxNodesPerRead},
+		{id.Server_ServerCapabilities_OperationLimits_MaxNodesPerHistoryReadData, &l.MaxNodesPerHistoryReadData},
+		{id.Server_ServerCapabilities_OperationLimits_MaxNodesPerHistoryReadEvents, &l.MaxNodesPerHistoryReadEvents},
+		{id.Server_ServerCapabilities_OperationLimits_MaxNodesPerWrite, &l.MaxNodesPerWrite},
+		{id.Server_ServerCapabilities_OperationLimits_MaxNodesPerHistoryUpdateData, &l.MaxNodesPerHistoryUpdateData},
+		{id.Server_ServerCapabilities_OperationLimits_MaxNodesPerHistoryUpdateEvents, &l.MaxNodesPerHistoryUpdateEvents},
+		{id.Server_ServerCapabilities_OperationLimits_MaxNodesPerMethodCall, &l.MaxNodesPerMethodCall},
+		{id.Server_ServerCapabilities_OperationLimits_MaxNodesPerBrowse, &l.MaxNodesPerBrowse},
+		{id.Server_ServerCapabilities_OperationLimits_MaxNodesPerRegisterNodes, &l.MaxNodesPerRegisterNodes},
+		{id.Server_ServerCapabilities_OperationLimits_MaxNodesPerTranslateBrowsePathsToNodeIDs, &l.MaxNodesPerTranslateBrowsePathsToNodeIDs},
+		{id.Server_ServerCapabilities_OperationLimits_MaxNodesPerNodeManagement, &l.MaxNodesPerNodeManagement},
+		{id.Server_ServerCapabilities_OperationLimits_MaxMonitoredItemsPerCall, &l.MaxMonitoredItemsPerCall},
+	}
+}
+
+// OperationLimits returns the operation limits of the server.
+//
+// The limits are read from the server with a single ReadRequest on the
+// first call and cached afterwards. Limits which the server does not
+// provide are set to zero. Use UpdateOperationLimits to refresh the
+// cached value.
+func (c *Client) OperationLimits(ctx context.Context) (*OperationLimits, error) {
+	if l := c.CachedOperationLimits(); l != nil {
+		return l, nil
+	}
+	if err := c.UpdateOperationLimits(ctx); err != nil {
+		return nil, err
+	}
+	return c.CachedOperationLimits(), nil
+}
+
+// CachedOperationLimits returns the cached operation limits of the
+// server or nil if they have not been read yet.
+func (c *Client) CachedOperationLimits() *OperationLimits {
+	l, _ := c.atomicOperationLimits.Load().(*OperationLimits)
+	return l
+}
+
+// UpdateOperationLimits reads the operation limits from the server
+// and updates the cached value. The cached value is also cleared when
+// the session is recreated after a reconnect since the client may be
+// connected to a different server instance.
+func (c *Client) UpdateOperationLimits(ctx context.Context) error {
+	stats.Client().Add("UpdateOperationLimits", 1)
+
+	l := &OperationLimits{}
+	nodes := l.limitNodes()
+
+	req := &ua.ReadRequest{}
+	for _, n := range nodes {
+		req.NodesToRead = append(req.NodesToRead, &ua.ReadValueID{
+			NodeID:      ua.NewNumericNodeID(0, n.id),
+			AttributeID: ua.AttributeIDValue,
+		})
+	}
+
+	res, err := c.Read(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(res.Results) != len(nodes) {
+		return ua.StatusBadUnknownResponse
+	}
+
+	l.setValues(res.Results)
+	c.setOperationLimits(l)
+	return nil
+}
+
+// setValues sets the limits from the values of the limit nodes in the
+// order of limitNodes. Limits with a bad status, without a value or
+// with a value which is not an UInt32 are left at zero.
+func (l *OperationLimits) setValues(values []*ua.DataValue) {
+	for i, n := range l.limitNodes() {
+		if i >= len(values) {
+			return
+		}
+		dv := values[i]
+		if dv == nil || dv.Status != ua.StatusOK || dv.Value == nil {
+			continue
+		}
+		if v, ok := dv.Value.Value().(uint32); ok {
+			*n.v = v
+		}
+	}
+}
+
+func (c *Client) setOperationLimits(l *OperationLimits) {
+	c.atomicOperationLimits.Store(l)
+}
@@ -0,0 +1,52 @@
+package opcua
+
+import (
+	"testing"
+
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationLimitsSetValues(t *testing.T) {
+	var l OperationLimits
+	values := make([]*ua.DataValue, len(l.limitNodes()))
+	for i := range values {
+		values[i] = &ua.DataValue{Value: ua.MustVariant(uint32(100 + i))}
+	}
+	// missing value
+	values[1] = &ua.DataValue{}
+	values[2] = nil
+	// bad status
+	values[3] = &ua.DataValue{Status: ua.StatusBadNodeIDUnknown, Value: ua.MustVariant(uint32(7))}
+	// wrong type
+	values[4] = &ua.DataValue{Value: ua.MustVariant(int32(7))}
+	values[5] = &ua.DataValue{Value: ua.MustVariant("7")}
+
+	l.setValues(values)
+	require.Equal(t, OperationLimits{
+		MaxNodesPerRead:                          100,
+		MaxNodesPerMethodCall:                    106,
+		MaxNodesPerBrowse:                        107,
+		MaxNodesPerRegisterNodes:                 108,
+		MaxNodesPerTranslateBrowsePathsToNodeIDs: 109,
+		MaxNodesPerNodeManagement:                110,
+		MaxMonitoredItemsPerCall:                 111,
+	}, l)
+
+	// short responses leave the remaining limits unset
+	var short OperationLimits
+	short.setValues(values[:1])
+	require.Equal(t, OperationLimits{MaxNodesPerRead: 100}, short)
+}
+
+func TestCachedOperationLimits(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840")
+	require.NoError(t, err)
+	require.Nil(t, c.CachedOperationLimits())
+
+	c.setOperationLimits(&OperationLimits{MaxNodesPerRead: 5})
+	require.Equal(t, &OperationLimits{MaxNodesPerRead: 5}, c.CachedOperationLimits())
+
+	c.setOperationLimits(nil)
+	require.Nil(t, c.CachedOperationLimits())
+}
@@ -0,0 +1,242 @@
+package opcua
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// DefaultPoolHealthCheckInterval is the time after which an idle client
+// of a Pool is checked with a Read before it is handed out again.
+const DefaultPoolHealthCheckInterval = 10 * time.Second
+
+// ErrPoolClosed is returned by Pool.Acquire after the pool was closed.
+var ErrPoolClosed = errors.New("pool closed")
+
+// Pool maintains up to a fixed number of connected clients to one
+// endpoint which can be shared between goroutines. The clients are
+// created on demand by Acquire and returned to the pool with Release.
+//
+// Clients which have been idle for longer than the health check interval
+// are checked with a Read of the server state before they are handed out.
+// Clients which are no longer connected or fail the check are closed and
+// replaced with a new client.
+type Pool struct {
+	endpoint string
+	opts     []Option
+
+	// healthCheckInterval is the idle time after which a client is
+	// checked before it is handed out.
+	healthCheckInterval time.Duration
+
+	// sem contains a token for every live client.
+	sem chan struct{}
+
+	// idle contains the clients which are not in use.
+	idle chan *pooledClient
+
+	// done is closed by Close to wake up blocked Acquire calls.
+	done chan struct{}
+
+	// mu guards closed and inUse. Clients are only added to idle while
+	// mu is held so that Close does not miss them.
+	mu     sync.Mutex
+	closed bool
+
+	// inUse contains the clients which have been handed out by Acquire
+	// and not yet released.
+	inUse map[*Client]bool
+}
+
+type pooledClient struct {
+	c     *Client
+	since time.Time
+}
+
+// NewPool creates a pool of at most size clients to the endpoint. The
+// clients are created with the given options.
+func NewPool(endpoint string, size int, opts ...Option) (*Pool, error) {
+	if size < 1 {
+		return nil, errors.Errorf("pool size must be positive")
+	}
+	// validate the options once instead of on every new client
+	if _, err := ApplyConfig(opts...); err != nil {
+		return nil, err
+	}
+	return &Pool{
+		endpoint:            endpoint,
+		opts:                opts,
+		healthCheckInterval: DefaultPoolHealthCheckInterval,
+		sem:                 make(chan struct{}, size),
+		idle:                make(chan *pooledClient, size),
+		done:                make(chan struct{}),
+		inUse:               make(map[*Client]bool),
+	}, nil
+}
+
+// Acquire returns a connected client from the pool. It returns an idle
+// client if there is one and creates a new client if the pool is not
+// full. Otherwise, it blocks until a client is released or ctx is done.
+// The client must be returned with Release and must not be closed by
+// the caller.
+func (p *Pool) Acquire(ctx context.Context) (*Client, error) {
+	for {
+		if p.isClosed() {
+			return nil, ErrPoolClosed
+		}
+
+		// prefer idle clients over new clients
+		var pc *pooledClient
+		select {
+		case pc = <-p.idle:
+		default:
+			select {
+			case pc = <-p.idle:
+			case p.sem <- struct{}{}:
+				if p.isClosed() {
+					<-p.sem
+					return nil, ErrPoolClosed
+				}
+				c, err := p.connect(ctx)
+				if err != nil {
+					<-p.sem
+					return nil, err
+				}
+				return p.handOut(ctx, c)
+			case <-p.done:
+				return nil, ErrPoolClosed
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		if p.healthy(ctx, pc) {
+			return p.handOut(ctx, pc.c)
+		}
+		if err := ctx.Err(); err != nil {
+			// the check failed since ctx is done
+			p.put(pc)
+			return nil, err
+		}
+		// the client is replaced on the next iteration
+		p.discard(ctx, pc.c)
+	}
+}
+
+// Release returns a client to the pool. Clients which are no longer
+// connected are closed and replaced by a new client on demand. Clients
+// which have not been acquired from the pool or have already been
+// released are ignored.
+func (p *Pool) Release(c *Client) {
+	if c == nil {
+		return
+	}
+	p.mu.Lock()
+	if !p.inUse[c] {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.inUse, c)
+	if p.closed || c.State() != Connected {
+		p.mu.Unlock()
+		p.discard(context.Background(), c)
+		return
+	}
+	p.idle <- &pooledClient{c: c, since: time.Now()}
+	p.mu.Unlock()
+}
+
+// Close closes the idle clients and the clients which are released
+// afterwards. Acquire returns ErrPoolClosed after Close.
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.done)
+	}
+	p.mu.Unlock()
+
+	for {
+		select {
+		case pc := <-p.idle:
+			p.discard(ctx, pc.c)
+		default:
+			return nil
+		}
+	}
+}
+
+// handOut marks the client as in use. The client is closed if the pool
+// has been closed in the meantime.
+func (p *Pool) handOut(ctx context.Context, c *Client) (*Client, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		p.discard(ctx, c)
+		return nil, ErrPoolClosed
+	}
+	p.inUse[c] = true
+	p.mu.Unlock()
+	return c, nil
+}
+
+// put returns an idle client to the pool or closes it if the pool has
+// been closed.
+func (p *Pool) put(pc *pooledClient) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		p.discard(context.Background(), pc.c)
+		return
+	}
+	p.idle <- pc
+	p.mu.Unlock()
+}
+
+func (p *Pool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+// connect creates and connects a new client.
+func (p *Pool) connect(ctx context.Context) (*Client, error) {
+	c, err := NewClient(p.endpoint, p.opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Connect(ctx); err != nil {
+		c.Close(ctx)
+		return nil, err
+	}
+	return c, nil
+}
+
+// discard closes the client and frees its slot in the pool.
+func (p *Pool) discard(ctx context.Context, c *Client) {
+	c.Close(ctx)
+	<-p.sem
+}
+
+// healthy returns true if the client is connected and, if it has been
+// idle for longer than the health check interval, can read the server
+// state.
+func (p *Pool) healthy(ctx context.Context, pc *pooledClient) bool {
+	if pc.c.State() != Connected {
+		return false
+	}
+	if time.Since(pc.since) < p.healthCheckInterval {
+		return true
+	}
+	res, err := pc.c.Read(ctx, &ua.ReadRequest{
+		NodesToRead: []*ua.ReadValueID{{
+			NodeID:      ua.NewNumericNodeID(0, id.Server_ServerStatus_State),
+			AttributeID: ua.AttributeIDValue,
+		}},
+	})
+	return err == nil && len(res.Results) == 1 && res.Results[0].Status == ua.StatusOK
+}
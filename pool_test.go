@@ -0,0 +1,86 @@
+package opcua
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPool(t *testing.T) {
+	_, err := NewPool("opc.tcp://example.com:4840", 0)
+	require.ErrorContains(t, err, "pool size must be positive")
+
+	_, err = NewPool("opc.tcp://example.com:4840", 1, ReconnectBackoff(0, 0, 0, 0))
+	require.Error(t, err)
+}
+
+func TestPool_AcquireError(t *testing.T) {
+	p, err := NewPool("opc.tcp://127.0.0.1:1", 1)
+	require.NoError(t, err)
+
+	// a failed connect must free the slot of the client
+	ctx := context.Background()
+	for range 2 {
+		_, err := p.Acquire(ctx)
+		require.Error(t, err)
+	}
+	require.Empty(t, p.sem)
+}
+
+func TestPool_Closed(t *testing.T) {
+	p, err := NewPool("opc.tcp://127.0.0.1:1", 1)
+	require.NoError(t, err)
+	require.NoError(t, p.Close(context.Background()))
+
+	_, err = p.Acquire(context.Background())
+	require.ErrorIs(t, err, ErrPoolClosed)
+}
+
+func TestPool_CloseWakesAcquire(t *testing.T) {
+	p, err := NewPool("opc.tcp://127.0.0.1:1", 1)
+	require.NoError(t, err)
+
+	// the only slot is in use
+	p.sem <- struct{}{}
+
+	done := make(chan error)
+	go func() {
+		_, err := p.Acquire(context.Background())
+		done <- err
+	}()
+	require.NoError(t, p.Close(context.Background()))
+	require.ErrorIs(t, <-done, ErrPoolClosed)
+	require.Len(t, p.sem, 1)
+}
+
+func TestPool_Release(t *testing.T) {
+	p, err := NewPool("opc.tcp://127.0.0.1:1", 1)
+	require.NoError(t, err)
+
+	c, err := NewClient("opc.tcp://127.0.0.1:1")
+	require.NoError(t, err)
+
+	// a client which has not been acquired is ignored
+	p.Release(c)
+	require.Empty(t, p.idle)
+
+	// a client which is released twice is only returned once
+	p.sem <- struct{}{}
+	p.inUse[c] = true
+	c.atomicState.Store(Connected)
+	p.Release(c)
+	p.Release(c)
+	require.Len(t, p.idle, 1)
+
+	// a client which is released after Close is closed
+	require.NoError(t, p.Close(context.Background()))
+	require.Empty(t, p.idle)
+	require.Empty(t, p.sem)
+
+	p.sem <- struct{}{}
+	p.inUse[c] = true
+	p.Release(c)
+	require.Empty(t, p.idle)
+	require.Empty(t, p.sem)
+}
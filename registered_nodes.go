@@ -0,0 +1,191 @@
+package opcua
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// RegisterNodeIDs registers the node ids with the server and returns the
+// handles in the same order as nodeIDs. The handles can be used in place
+// of the node ids, e.g. in a ReadValueID, for the lifetime of the session.
+//
+// RegisterNodeIDs is a convenience wrapper around RegisterNodes which
+// keeps its request based signature for compatibility.
+//
+// Part 4, Section 5.8.5
+func (c *Client) RegisterNodeIDs(ctx context.Context, nodeIDs []*ua.NodeID) ([]*ua.NodeID, error) {
+	if len(nodeIDs) == 0 {
+		return nil, nil
+	}
+	res, err := c.RegisterNodes(ctx, &ua.RegisterNodesRequest{NodesToRegister: nodeIDs})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.RegisteredNodeIDs) != len(nodeIDs) {
+		return nil, ua.StatusBadUnknownResponse
+	}
+	return res.RegisteredNodeIDs, nil
+}
+
+// UnregisterNodeIDs unregisters handles which have been returned by
+// RegisterNodeIDs.
+//
+// Part 4, Section 5.8.6
+func (c *Client) UnregisterNodeIDs(ctx context.Context, handles []*ua.NodeID) error {
+	if len(handles) == 0 {
+		return nil
+	}
+	_, err := c.UnregisterNodes(ctx, &ua.UnregisterNodesRequest{NodesToUnregister: handles})
+	return err
+}
+
+// RegisteredNodeSet tracks node ids which have been registered with
+// the server via RegisterNodes. The returned handles can be used in
+// place of the original node ids, e.g. in a ReadValueID, and are
+// unregistered when the set is closed.
+//
+// Servers often return the node id itself as handle. A handle which is
+// returned more than once is therefore reference counted and only
+// unregistered when all registrations have been unregistered.
+//
+// Handles are only valid in the session in which they have been
+// registered. When the client recreates the session after a reconnect
+// the set is emptied and the nodes have to be registered again.
+//
+// Part 4, Section 5.8.5
+type RegisteredNodeSet struct {
+	c *Client
+
+	mu        sync.Mutex
+	sessionID string
+	handles   map[string]*registeredHandle
+}
+
+type registeredHandle struct {
+	handle *ua.NodeID
+	refs   int
+}
+
+// NewRegisteredNodeSet returns an empty set of registered nodes.
+func (c *Client) NewRegisteredNodeSet() *RegisteredNodeSet {
+	return &RegisteredNodeSet{
+		c:       c,
+		handles: make(map[string]*registeredHandle),
+	}
+}
+
+// Register registers the node ids with the server and returns the
+// handles in the same order as nodeIDs.
+func (s *RegisteredNodeSet) Register(ctx context.Context, nodeIDs ...*ua.NodeID) ([]*ua.NodeID, error) {
+	sessionID := s.c.sessionID()
+	handles, err := s.c.RegisterNodeIDs(ctx, nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+	s.add(sessionID, handles)
+	return handles, nil
+}
+
+// Unregister removes one registration of each handle from the set and
+// unregisters the handles without remaining registrations from the
+// server. Handles which are not in the set are ignored.
+func (s *RegisteredNodeSet) Unregister(ctx context.Context, handles ...*ua.NodeID) error {
+	sessionID := s.c.sessionID()
+	removed, release := s.release(sessionID, handles)
+	if err := s.c.UnregisterNodeIDs(ctx, release); err != nil {
+		// keep the registrations so that the caller can retry
+		s.add(sessionID, removed)
+		return err
+	}
+	return nil
+}
+
+// Handles returns the currently registered handles.
+func (s *RegisteredNodeSet) Handles() []*ua.NodeID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkSession(s.c.sessionID())
+	handles := make([]*ua.NodeID, 0, len(s.handles))
+	for _, h := range s.handles {
+		handles = append(handles, h.handle)
+	}
+	return handles
+}
+
+// Close unregisters all handles in the set.
+func (s *RegisteredNodeSet) Close(ctx context.Context) error {
+	sessionID := s.c.sessionID()
+
+	s.mu.Lock()
+	s.checkSession(sessionID)
+	handles := make([]*ua.NodeID, 0, len(s.handles))
+	for _, h := range s.handles {
+		handles = append(handles, h.handle)
+	}
+	s.handles = make(map[string]*registeredHandle)
+	s.mu.Unlock()
+
+	return s.c.UnregisterNodeIDs(ctx, handles)
+}
+
+// add adds one registration of each handle which has been registered
+// in the session with the given id.
+func (s *RegisteredNodeSet) add(sessionID string, handles []*ua.NodeID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkSession(sessionID)
+	for _, h := range handles {
+		k := h.String()
+		if e, ok := s.handles[k]; ok {
+			e.refs++
+			continue
+		}
+		s.handles[k] = &registeredHandle{handle: h, refs: 1}
+	}
+}
+
+// release removes one registration of each handle. It returns the
+// handles whose registration has been removed and the handles without
+// remaining registrations.
+func (s *RegisteredNodeSet) release(sessionID string, handles []*ua.NodeID) (removed, release []*ua.NodeID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkSession(sessionID)
+	for _, h := range handles {
+		k := h.String()
+		e, ok := s.handles[k]
+		if !ok {
+			continue
+		}
+		removed = append(removed, e.handle)
+		if e.refs--; e.refs == 0 {
+			delete(s.handles, k)
+			release = append(release, e.handle)
+		}
+	}
+	return removed, release
+}
+
+// checkSession drops the handles of a previous session since they are
+// no longer valid. s.mu must be held.
+func (s *RegisteredNodeSet) checkSession(sessionID string) {
+	if s.sessionID == sessionID {
+		return
+	}
+	s.sessionID = sessionID
+	s.handles = make(map[string]*registeredHandle)
+}
+
+// sessionID returns the id of the current session or an empty string.
+func (c *Client) sessionID() string {
+	s := c.Session()
+	if s == nil || s.resp == nil || s.resp.SessionID == nil {
+		return ""
+	}
+	return s.resp.SessionID.String()
+}
@@ -0,0 +1,66 @@
+package opcua
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisteredNodeSet(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840")
+	require.NoError(t, err)
+
+	n1, n2 := ua.NewNumericNodeID(1, 1), ua.NewNumericNodeID(1, 2)
+
+	t.Run("reference count", func(t *testing.T) {
+		s := c.NewRegisteredNodeSet()
+		s.add("s1", []*ua.NodeID{n1, n2})
+		s.add("s1", []*ua.NodeID{n1})
+
+		removed, release := s.release("s1", []*ua.NodeID{n1})
+		require.Equal(t, []*ua.NodeID{n1}, removed)
+		require.Empty(t, release, "n1 is still registered once")
+
+		removed, release = s.release("s1", []*ua.NodeID{n1, n2, ua.NewNumericNodeID(1, 3)})
+		require.Equal(t, []*ua.NodeID{n1, n2}, removed)
+		require.Equal(t, []*ua.NodeID{n1, n2}, release)
+		require.Empty(t, s.handles)
+	})
+
+	t.Run("restore after failure", func(t *testing.T) {
+		s := c.NewRegisteredNodeSet()
+		s.add("s1", []*ua.NodeID{n1, n1})
+		removed, _ := s.release("s1", []*ua.NodeID{n1})
+		s.add("s1", removed)
+		require.Equal(t, 2, s.handles[n1.String()].refs)
+	})
+
+	t.Run("session changed", func(t *testing.T) {
+		s := c.NewRegisteredNodeSet()
+		s.add("s1", []*ua.NodeID{n1, n2})
+
+		// handles of the previous session are dropped
+		removed, release := s.release("s2", []*ua.NodeID{n1})
+		require.Empty(t, removed)
+		require.Empty(t, release)
+		require.Empty(t, s.handles)
+
+		s.add("s2", []*ua.NodeID{n2})
+		require.Len(t, s.handles, 1)
+
+		// the client has no session
+		require.Empty(t, s.Handles())
+	})
+}
+
+func TestRegisterNodeIDsEmpty(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840")
+	require.NoError(t, err)
+
+	handles, err := c.RegisterNodeIDs(context.Background(), nil)
+	require.NoError(t, err)
+	require.Nil(t, handles)
+	require.NoError(t, c.UnregisterNodeIDs(context.Background(), nil))
+}
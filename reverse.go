@@ -0,0 +1,133 @@
+package opcua
+
+import (
+	"context"
+	"net"
+	"slices"
+	"sync"
+
+	"github.com/gopcua/opcua/debug"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/uacp"
+)
+
+// ListenReverse listens on listenAddr for servers which establish the
+// connection to the client with the reverse connect handshake, e.g. since
+// they are behind a NAT. For every server which sends a ReverseHello a new
+// client is created with the endpoint url of the server and the options,
+// connected on the established connection and sent on the returned
+// channel. Use ReverseServerURIs to accept only known servers.
+//
+// The clients do not reconnect automatically since the connection can
+// only be established by the server. The listener is closed and the
+// channel is closed when ctx is done. The clients are not closed.
+func ListenReverse(ctx context.Context, listenAddr string, opts ...Option) (<-chan *Client, error) {
+	cfg, err := ApplyConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", listenAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := cfg.sechan.Logger
+	if logger == nil {
+		logger = debug.NewLogger()
+	}
+	logger = logger.With("listenAddr", ln.Addr().String())
+
+	ch := make(chan *Client)
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	go func() {
+		var wg sync.WaitGroup
+		defer func() {
+			wg.Wait()
+			close(ch)
+		}()
+
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Error("reverse connect listener failed", "error", err)
+				}
+				return
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				c, err := connectReverse(ctx, conn, cfg.reverseServerURIs, opts)
+				if err != nil {
+					logger.Warn("reverse connect failed", "remoteAddr", conn.RemoteAddr().String(), "error", err)
+					conn.Close()
+					return
+				}
+				select {
+				case ch <- c:
+				case <-ctx.Done():
+					c.Close(context.Background())
+				}
+			}()
+		}
+	}()
+	return ch, nil
+}
+
+// connectReverse waits for the ReverseHello message of the server on conn
+// and connects a new client on it.
+func connectReverse(ctx context.Context, conn net.Conn, serverURIs []string, opts []Option) (*Client, error) {
+	hctx, cancel := context.WithTimeout(ctx, DefaultDialTimeout)
+	defer cancel()
+
+	rhe, err := uacp.ReceiveReverseHello(hctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if len(serverURIs) > 0 && !slices.Contains(serverURIs, rhe.ServerURI) {
+		return nil, errors.Errorf("reverse connect from unknown server %s", rhe.ServerURI)
+	}
+
+	c, err := NewClient(rhe.EndpointURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// copy the dialer since it can be shared between clients
+	d := *c.cfg.dialer
+	d.ContextDialer = &reverseDialer{conn: conn}
+	c.cfg.dialer = &d
+	c.cfg.sechan.AutoReconnect = false
+
+	if err := c.Connect(ctx); err != nil {
+		c.Close(ctx)
+		return nil, err
+	}
+	return c, nil
+}
+
+// reverseDialer returns the connection which was established by the
+// server for the first dial and fails afterwards.
+type reverseDialer struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (d *reverseDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.conn == nil {
+		return nil, errors.Errorf("reverse connection already used")
+	}
+	c := d.conn
+	d.conn = nil
+	return c, nil
+}
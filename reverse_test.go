@@ -0,0 +1,23 @@
+package opcua
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseDialer(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	d := &reverseDialer{conn: c1}
+	c, err := d.DialContext(context.Background(), "tcp", "example.com:4840")
+	require.NoError(t, err)
+	require.Equal(t, c1, c)
+
+	_, err = d.DialContext(context.Background(), "tcp", "example.com:4840")
+	require.ErrorContains(t, err, "reverse connection already used")
+}
@@ -331,7 +331,7 @@ type DataTypeField struct {
 	NameAttr            string           `xml:"Name,attr"`
 	SymbolicNameAttr    string           `xml:"SymbolicName,attr,omitempty"`
 	DataTypeAttr        string           `xml:"DataType,attr,omitempty"`
-	ValueRankAttr       int              `xml:"ValueRank,attr,omitempty"`
+	ValueRankAttr       *int             `xml:"ValueRank,attr,omitempty"` // EDIT: this was changed from an int to an *int because the default value if this attribute isn't present is -1
 	ArrayDimensionsAttr string           `xml:"ArrayDimensions,attr,omitempty"`
 	MaxStringLengthAttr uint32           `xml:"MaxStringLength,attr,omitempty"`
 	ValueAttr           int              `xml:"Value,attr,omitempty"`
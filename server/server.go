@@ -231,6 +231,23 @@ func (s *Server) Status() *ua.ServerStatusDataType {
 	return status
 }
 
+// RegisteredServer returns the description of the server for the
+// registration with a discovery server, e.g. with
+// opcua.RegisterServerPeriodically.
+func (s *Server) RegisteredServer() *ua.RegisteredServer {
+	return &ua.RegisteredServer{
+		ServerURI:  s.cfg.applicationURI,
+		ProductURI: "urn:github.com:gopcua:server",
+		ServerNames: []*ua.LocalizedText{{
+			EncodingMask: ua.LocalizedTextText,
+			Text:         s.cfg.applicationName,
+		}},
+		ServerType:    ua.ApplicationTypeServer,
+		DiscoveryURLs: s.URLs(),
+		IsOnline:      true,
+	}
+}
+
 // URLs returns opc endpoint that the server is listening on.
 func (s *Server) URLs() []string {
 	return s.cfg.endpoints
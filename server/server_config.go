@@ -6,7 +6,6 @@ package server
 
 import (
 	"crypto/rsa"
-	"crypto/x509"
 	"fmt"
 	"log"
 	"strings"
@@ -46,7 +45,7 @@ func Certificate(cert []byte) Option {
 
 		// Extract the application URI from the certificate.
 		var appURI string
-		x509cert, err := x509.ParseCertificate(cert)
+		x509cert, err := uapolicy.LeafCertificate(cert)
 		if err == nil && len(x509cert.URIs) > 0 {
 			appURI = x509cert.URIs[0].String()
 		}
@@ -3,7 +3,10 @@ package opcua
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gopcua/opcua/debug"
@@ -20,8 +23,23 @@ const (
 	DefaultSubscriptionMaxKeepAliveCount          = 3000
 	DefaultSubscriptionInterval                   = 100 * time.Millisecond
 	DefaultSubscriptionPriority                   = 0
+
+	// DefaultKeepAliveWatchdogMultiplier is the recommended multiple of
+	// the keep-alive interval after which a subscription without publish
+	// responses is considered stale. See KeepAliveWatchdog.
+	DefaultKeepAliveWatchdogMultiplier = 2.0
 )
 
+// ErrKeepAliveTimeout is sent to the notification channel of a
+// subscription which has neither received a notification nor a
+// keep-alive message within the watchdog interval.
+var ErrKeepAliveTimeout = errors.New("subscription missed keep-alive")
+
+// ErrMonitoredItemsNotRecreated is sent to the subscription when some of
+// its monitored items could not be recreated after a reconnect.
+// See Subscription.FailedItems.
+var ErrMonitoredItemsNotRecreated = errors.New("monitored items could not be recreated")
+
 type Subscription struct {
 	SubscriptionID            uint32
 	RevisedPublishingInterval time.Duration
@@ -31,10 +49,73 @@ type Subscription struct {
 	params                    *SubscriptionParameters
 	paramsMu                  sync.Mutex
 	items                     map[uint32]*monitoredItem
+	failedItems               []FailedMonitoredItem
 	itemsMu                   sync.Mutex
 	lastSeq                   uint32
 	nextSeq                   uint32
 	c                         *Client
+	stats                     publishStats
+	buf                       *notificationBuffer
+	routes                    map[uint32]route
+	routesMu                  sync.Mutex
+	nextHandle                uint32
+	lastAlive                 atomic.Int64 // unix nano
+	stale                     atomic.Bool
+	publishingDisabled        atomic.Bool
+
+	// revisedAssumed is set when the revised parameters have not been
+	// reported by the server, e.g. for an attached subscription. The
+	// keep-alive watchdog ignores the subscription until they are.
+	revisedAssumed atomic.Bool
+}
+
+// SubscriptionStats contains client-side statistics about the
+// publish requests and responses of a subscription.
+type SubscriptionStats struct {
+	// PublishRequestsSent is the number of publish requests the client
+	// has sent while the subscription was active. Publish requests are
+	// shared by all subscriptions of a session.
+	PublishRequestsSent uint64
+
+	// PublishResponsesReceived is the number of publish responses
+	// received for the subscription.
+	PublishResponsesReceived uint64
+
+	// NotificationsReceived is the number of publish responses
+	// which contained notification data.
+	NotificationsReceived uint64
+
+	// KeepAlivesReceived is the number of keep-alive messages.
+	KeepAlivesReceived uint64
+
+	// LastSequenceNumber is the sequence number of the last
+	// notification message.
+	LastSequenceNumber uint32
+
+	// LastPublishTime is the time the last publish response was
+	// received. It is zero if no response has been received yet.
+	LastPublishTime time.Time
+
+	// MissedKeepAlives is the number of keep-alive intervals which
+	// have passed since the last publish response was received.
+	MissedKeepAlives uint64
+
+	// NotificationsDropped is the number of notifications which have
+	// been discarded since the notification buffer was full.
+	// See NotificationBuffer.
+	NotificationsDropped uint64
+}
+
+// publishStats contains the counters for SubscriptionStats
+// which are updated by the publish loop.
+type publishStats struct {
+	publishRequestsSent      atomic.Uint64
+	publishResponsesReceived atomic.Uint64
+	notificationsReceived    atomic.Uint64
+	keepAlivesReceived       atomic.Uint64
+	lastSequenceNumber       atomic.Uint32
+	lastPublishTime          atomic.Int64 // unix nano
+	notificationsDropped     atomic.Uint64
 }
 
 type SubscriptionParameters struct {
@@ -72,6 +153,117 @@ func NewMonitoredItemCreateRequestWithDefaults(nodeID *ua.NodeID, attributeID ua
 	}
 }
 
+// NewMonitoredItemCreateRequestWithFilter returns a monitored item create
+// request with default parameters and the given monitoring filter, e.g. a
+// *ua.DataChangeFilter created with DataChangeFilter.
+func NewMonitoredItemCreateRequestWithFilter(nodeID *ua.NodeID, attributeID ua.AttributeID, clientHandle uint32, filter interface{}) *ua.MonitoredItemCreateRequest {
+	req := NewMonitoredItemCreateRequestWithDefaults(nodeID, attributeID, clientHandle)
+	if filter != nil {
+		req.RequestedParameters.Filter = ua.NewExtensionObject(filter)
+	}
+	return req
+}
+
+// DataChangeFilter returns a filter for monitored items which report
+// a data change only if the trigger condition is met and the value
+// has changed by more than the deadband.
+//
+// For ua.DeadbandTypeAbsolute the deadbandValue is the absolute change
+// of the value. For ua.DeadbandTypePercent the deadbandValue is a
+// percentage (0-100) of the EURange of the variable and the server
+// must support the EURange property of the node.
+//
+// Servers which do not support the filter report StatusBadFilterNotAllowed
+// or StatusBadMonitoredItemFilterUnsupported in the result of the
+// individual monitored item.
+//
+// See Part 4, 7.22.2
+func DataChangeFilter(trigger ua.DataChangeTrigger, deadbandType ua.DeadbandType, deadbandValue float64) *ua.DataChangeFilter {
+	return &ua.DataChangeFilter{
+		Trigger:       trigger,
+		DeadbandType:  uint32(deadbandType),
+		DeadbandValue: deadbandValue,
+	}
+}
+
+// DefaultEventFields contains the browse names of the fields of the
+// BaseEventType which are selected by EventSelectClauses if no fields
+// are given.
+var DefaultEventFields = []string{"EventId", "EventType", "SourceName", "Time", "Message", "Severity"}
+
+// EventSelectClauses returns the select clauses for the value attributes
+// of the given fields of the BaseEventType. A field is the browse name of
+// a property of the event type or a browse path with the browse names
+// separated by '/', e.g. "ActiveState/Id". If no fields are given then
+// DefaultEventFields are selected.
+//
+// The values of the fields are reported in the EventFields of the
+// ua.EventFieldList notifications in the same order.
+func EventSelectClauses(fields ...string) []*ua.SimpleAttributeOperand {
+	if len(fields) == 0 {
+		fields = DefaultEventFields
+	}
+	clauses := make([]*ua.SimpleAttributeOperand, len(fields))
+	for i, f := range fields {
+		clauses[i] = eventField(f)
+	}
+	return clauses
+}
+
+// eventField returns the operand for the value of a field of the BaseEventType.
+func eventField(field string) *ua.SimpleAttributeOperand {
+	var path []*ua.QualifiedName
+	for _, name := range strings.Split(field, "/") {
+		path = append(path, &ua.QualifiedName{NamespaceIndex: 0, Name: name})
+	}
+	return &ua.SimpleAttributeOperand{
+		TypeDefinitionID: ua.NewNumericNodeID(0, id.BaseEventType),
+		BrowsePath:       path,
+		AttributeID:      ua.AttributeIDValue,
+	}
+}
+
+// SeverityFilter returns a where clause for an event filter which only
+// selects events with a severity greater than or equal to minSeverity.
+// The severity ranges from 1 (lowest) to 1000 (highest).
+func SeverityFilter(minSeverity uint16) *ua.ContentFilter {
+	return &ua.ContentFilter{
+		Elements: []*ua.ContentFilterElement{{
+			FilterOperator: ua.FilterOperatorGreaterThanOrEqual,
+			FilterOperands: []*ua.ExtensionObject{
+				ua.NewExtensionObject(eventField("Severity")),
+				ua.NewExtensionObject(&ua.LiteralOperand{Value: ua.MustVariant(minSeverity)}),
+			},
+		}},
+	}
+}
+
+// EventFilter returns a filter for monitored items which report events.
+// The select clauses define the event fields which are reported and the
+// optional where clause limits the reported events.
+//
+// See Part 4, 7.22.3
+func EventFilter(selectClauses []*ua.SimpleAttributeOperand, whereClause *ua.ContentFilter) *ua.EventFilter {
+	if whereClause == nil {
+		whereClause = &ua.ContentFilter{}
+	}
+	return &ua.EventFilter{
+		SelectClauses: selectClauses,
+		WhereClause:   whereClause,
+	}
+}
+
+// NewEventMonitoredItemCreateRequest returns a monitored item create
+// request for the events of a node, usually the Server object with the
+// id id.Server which is also used if nodeID is nil. The events are delivered as *ua.EventNotificationList
+// on the notification channel of the subscription.
+func NewEventMonitoredItemCreateRequest(nodeID *ua.NodeID, clientHandle uint32, filter *ua.EventFilter) *ua.MonitoredItemCreateRequest {
+	if nodeID == nil {
+		nodeID = ua.NewNumericNodeID(0, id.Server)
+	}
+	return NewMonitoredItemCreateRequestWithFilter(nodeID, ua.AttributeIDEventNotifier, clientHandle, filter)
+}
+
 type PublishNotificationData struct {
 	SubscriptionID uint32
 	Error          error
@@ -83,9 +275,19 @@ type PublishNotificationData struct {
 func (s *Subscription) Cancel(ctx context.Context) error {
 	stats.Subscription().Add("Cancel", 1)
 	s.c.forgetSubscription(ctx, s.SubscriptionID)
+	s.stopDelivery()
 	return s.delete(ctx)
 }
 
+// stopDelivery stops forwarding buffered notifications and closes the
+// channels of the items created with MonitorWithInitial.
+func (s *Subscription) stopDelivery() {
+	if s.buf != nil {
+		s.buf.close()
+	}
+	s.closeRoutes(nil)
+}
+
 // delete removes the subscription from the server.
 func (s *Subscription) delete(ctx context.Context) error {
 	req := &ua.DeleteSubscriptionsRequest{
@@ -110,6 +312,19 @@ func (s *Subscription) delete(ctx context.Context) error {
 	}
 }
 
+// Modify changes the publishing interval, the lifetime count, the max
+// keep-alive count and the priority of the subscription without
+// recreating it. Parameters which have not been set are set to their
+// default values. The values revised by the server are stored in the
+// Revised fields of the subscription and the publish loop adapts its
+// timeout to the revised publishing interval.
+func (s *Subscription) Modify(ctx context.Context, params SubscriptionParameters) error {
+	_, err := s.ModifySubscription(ctx, params)
+	return err
+}
+
+// ModifySubscription is like Modify but returns the response of the
+// server.
 func (s *Subscription) ModifySubscription(ctx context.Context, params SubscriptionParameters) (*ua.ModifySubscriptionResponse, error) {
 	stats.Subscription().Add("ModifySubscription", 1)
 
@@ -136,14 +351,70 @@ func (s *Subscription) ModifySubscription(ctx context.Context, params Subscripti
 	s.paramsMu.Lock()
 	s.params = &params
 	s.paramsMu.Unlock()
-	// update revised subscription parameters
-	s.RevisedPublishingInterval = time.Duration(res.RevisedPublishingInterval) * time.Millisecond
-	s.RevisedLifetimeCount = res.RevisedLifetimeCount
-	s.RevisedMaxKeepAliveCount = res.RevisedMaxKeepAliveCount
+	s.setRevised(res.RevisedPublishingInterval, res.RevisedLifetimeCount, res.RevisedMaxKeepAliveCount)
 
 	return res, nil
 }
 
+// SetPublishingMode enables or disables the publishing of notifications
+// for the subscription, e.g. to pause the delivery during a maintenance
+// window. The monitored items of a disabled subscription are still
+// sampled and queued by the server but no notifications are sent. The
+// subscription remembers the mode and restores it when it is recreated
+// after a reconnect. See also Client.SetPublishingMode.
+func (s *Subscription) SetPublishingMode(ctx context.Context, enabled bool) error {
+	return s.c.SetPublishingMode(ctx, enabled, s.SubscriptionID)
+}
+
+// PublishingEnabled returns true if publishing is enabled for the
+// subscription as known by the client.
+func (s *Subscription) PublishingEnabled() bool {
+	return !s.publishingDisabled.Load()
+}
+
+// RevisedParameters returns the subscription parameters as revised by
+// the server, e.g. a publishing interval of 250ms for a requested
+// interval of 100ms. The revised values are updated when the
+// subscription is modified or recreated after a reconnect. The max
+// notifications per publish and the priority are not revised by the
+// server and are returned as requested.
+//
+// Unlike the Revised fields of the subscription the method is safe to
+// call while the subscription is modified or recreated.
+func (s *Subscription) RevisedParameters() SubscriptionParameters {
+	s.paramsMu.Lock()
+	p := *s.params
+	s.paramsMu.Unlock()
+
+	s.c.subMux.RLock()
+	defer s.c.subMux.RUnlock()
+	p.Interval = s.RevisedPublishingInterval
+	p.LifetimeCount = s.RevisedLifetimeCount
+	p.MaxKeepAliveCount = s.RevisedMaxKeepAliveCount
+	return p
+}
+
+// setRevised stores the revised subscription parameters and updates the
+// timeout of the publish requests which depends on the keep-alive
+// interval of all subscriptions.
+func (s *Subscription) setRevised(publishingInterval float64, lifetimeCount, maxKeepAliveCount uint32) {
+	s.c.subMux.Lock()
+	defer s.c.subMux.Unlock()
+
+	s.RevisedPublishingInterval = time.Duration(publishingInterval) * time.Millisecond
+	s.RevisedLifetimeCount = lifetimeCount
+	s.RevisedMaxKeepAliveCount = maxKeepAliveCount
+	s.revisedAssumed.Store(false)
+	s.c.updatePublishTimeout_NeedsSubMuxLock()
+}
+
+// Monitor creates monitored items for the subscription.
+//
+// The status of the individual monitored items is reported in the
+// results of the response and a failed item does not fail the whole
+// request, e.g. an item with an unsupported filter reports
+// StatusBadMonitoredItemFilterUnsupported. Only items which have been
+// created successfully are tracked by the subscription.
 func (s *Subscription) Monitor(ctx context.Context, ts ua.TimestampsToReturn, items ...*ua.MonitoredItemCreateRequest) (*ua.CreateMonitoredItemsResponse, error) {
 	stats.Subscription().Add("Monitor", 1)
 	stats.Subscription().Add("MonitoredItems", int64(len(items)))
@@ -163,11 +434,17 @@ func (s *Subscription) Monitor(ctx context.Context, ts ua.TimestampsToReturn, it
 	if err != nil {
 		return nil, err
 	}
+	if len(res.Results) != len(items) {
+		return nil, ua.StatusBadUnknownResponse
+	}
 
 	// store monitored items
 	s.itemsMu.Lock()
 	for i, item := range items {
 		result := res.Results[i]
+		if result.StatusCode != ua.StatusOK {
+			continue
+		}
 		s.items[result.MonitoredItemID] = &monitoredItem{
 			req: item,
 			res: result,
@@ -197,15 +474,468 @@ func (s *Subscription) Unmonitor(ctx context.Context, monitoredItemIDs ...uint32
 	}
 
 	// remove monitored items
+	var handles []uint32
 	s.itemsMu.Lock()
 	for _, id := range monitoredItemIDs {
+		if mi, ok := s.items[id]; ok && mi.req.RequestedParameters != nil {
+			handles = append(handles, mi.req.RequestedParameters.ClientHandle)
+		}
 		delete(s.items, id)
 	}
 	s.itemsMu.Unlock()
+	s.closeRoutes(handles)
 
 	return res, nil
 }
 
+// RemoveMonitoredItems deletes the monitored items with the given client
+// handles on the server and removes them from the subscription. It
+// returns an error if a client handle is unknown or the server could
+// not delete an item.
+func (s *Subscription) RemoveMonitoredItems(ctx context.Context, clientHandles ...uint32) error {
+	ids, err := s.monitoredItemIDs(clientHandles)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	res, err := s.Unmonitor(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if len(res.Results) != len(ids) {
+		return ua.StatusBadUnknownResponse
+	}
+	for i, status := range res.Results {
+		if status != ua.StatusOK {
+			return errors.Errorf("monitored item %d: %w", ids[i], status)
+		}
+	}
+	return nil
+}
+
+// monitoredItemIDs returns the monitored item ids for the client handles.
+func (s *Subscription) monitoredItemIDs(clientHandles []uint32) ([]uint32, error) {
+	s.itemsMu.Lock()
+	defer s.itemsMu.Unlock()
+
+	byHandle := make(map[uint32]uint32, len(s.items))
+	for id, item := range s.items {
+		if item.req.RequestedParameters != nil {
+			byHandle[item.req.RequestedParameters.ClientHandle] = id
+		}
+	}
+
+	ids := make([]uint32, 0, len(clientHandles))
+	for _, h := range clientHandles {
+		id, ok := byHandle[h]
+		if !ok {
+			return nil, fmt.Errorf("sub %d: unknown client handle: %d", s.SubscriptionID, h)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// MonitorWithInitial creates a monitored item for the value of a node
+// and waits for its initial value.
+//
+// The server reports the initial value of a new monitored item in the
+// next publish response and not in the response of the create call.
+// MonitorWithInitial therefore blocks until the first data change
+// notification of the item has been received or ctx is done. It returns
+// the initial value and a channel for the subsequent values. The
+// notifications of the item are not sent to the notification channel of
+// the subscription.
+//
+// The channel is closed when the item is removed with Unmonitor or the
+// subscription is cancelled. The publish loop waits for the receiver
+// of the channel like it waits for the notification channel of the
+// subscription, so the channel must be drained.
+//
+// The client handle of the item is chosen by the subscription from the
+// top of the uint32 range and is not reused by other monitored items of
+// the subscription.
+func (s *Subscription) MonitorWithInitial(ctx context.Context, nodeID *ua.NodeID, ts ua.TimestampsToReturn) (*ua.DataValue, <-chan *ua.DataValue, error) {
+	r := &itemRoute{
+		first:   make(chan *ua.DataValue, 1),
+		updates: make(chan *ua.DataValue, 1),
+		done:    make(chan struct{}),
+	}
+	handle := s.addRoute(r)
+
+	req := NewMonitoredItemCreateRequestWithDefaults(nodeID, ua.AttributeIDValue, handle)
+	res, err := s.Monitor(ctx, ts, req)
+	if err == nil && res.Results[0].StatusCode != ua.StatusOK {
+		err = res.Results[0].StatusCode
+	}
+	if err != nil {
+		s.closeRoutes([]uint32{handle})
+		return nil, nil, err
+	}
+
+	select {
+	case dv := <-r.first:
+		return dv, r.updates, nil
+	case <-ctx.Done():
+		// remove the item since the caller has no way to receive
+		// its values.
+		_, _ = s.Unmonitor(context.WithoutCancel(ctx), res.Results[0].MonitoredItemID)
+		s.closeRoutes([]uint32{handle})
+		return nil, nil, ctx.Err()
+	}
+}
+
+// MonitoredItemResult is the result of creating a monitored item with
+// MonitorMany.
+type MonitoredItemResult struct {
+	// ItemToMonitor is the node attribute of the item.
+	ItemToMonitor *ua.ReadValueID
+
+	// ClientHandle is the handle which is sent with the notifications
+	// of the item.
+	ClientHandle uint32
+
+	// MonitoredItemID is the id assigned by the server.
+	MonitoredItemID uint32
+
+	// StatusCode is the result of creating the item. Items with a
+	// status other than StatusOK have not been created.
+	StatusCode ua.StatusCode
+
+	RevisedSamplingInterval time.Duration
+	RevisedQueueSize        uint32
+}
+
+// MonitorMany creates a monitored item for each node attribute in a
+// single request. The entries specify the node id, the attribute id
+// and an optional index range so that different attributes of the
+// same node can be monitored together. An attribute id of zero
+// monitors the value.
+//
+// The results are aligned with items. A failed item does not fail the
+// whole request but is reported in the status code of its result. The
+// notifications of the items are sent to the notification channel of
+// the subscription with the client handle of the result. The client
+// handles are chosen like for MonitorWithInitial.
+func (s *Subscription) MonitorMany(ctx context.Context, items []*ua.ReadValueID, ts ua.TimestampsToReturn) ([]MonitoredItemResult, error) {
+	for i, rv := range items {
+		if rv == nil || rv.NodeID == nil {
+			return nil, fmt.Errorf("sub %d: item %d has no node id", s.SubscriptionID, i)
+		}
+	}
+
+	used := s.usedClientHandles()
+	s.routesMu.Lock()
+	reqs := make([]*ua.MonitoredItemCreateRequest, len(items))
+	for i, rv := range items {
+		req := NewMonitoredItemCreateRequestWithDefaults(rv.NodeID, rv.AttributeID, s.nextClientHandle(used))
+		req.ItemToMonitor.IndexRange = rv.IndexRange
+		if rv.DataEncoding != nil {
+			req.ItemToMonitor.DataEncoding = rv.DataEncoding
+		}
+		reqs[i] = req
+	}
+	s.routesMu.Unlock()
+
+	res, err := s.Monitor(ctx, ts, reqs...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]MonitoredItemResult, len(items))
+	for i, req := range reqs {
+		r := res.Results[i]
+		results[i] = MonitoredItemResult{
+			ItemToMonitor:           req.ItemToMonitor,
+			ClientHandle:            req.RequestedParameters.ClientHandle,
+			MonitoredItemID:         r.MonitoredItemID,
+			StatusCode:              r.StatusCode,
+			RevisedSamplingInterval: time.Duration(r.RevisedSamplingInterval * float64(time.Millisecond)),
+			RevisedQueueSize:        r.RevisedQueueSize,
+		}
+	}
+	return results, nil
+}
+
+// DefaultCallbackQueueSize is the number of values which are queued for
+// the callback of a monitored item created with MonitorWithCallback.
+const DefaultCallbackQueueSize = 100
+
+// MonitorWithCallback creates a monitored item for the value of a node
+// and calls fn with every value of the item. The notifications of the
+// item are not sent to the notification channel of the subscription.
+//
+// Every item has its own goroutine which calls fn sequentially in the
+// order in which the values have been received. The values are queued
+// between the publish loop and the goroutine so that a slow callback
+// stalls neither the publish loop nor the callbacks of other items. If
+// the queue of DefaultCallbackQueueSize values is full then the oldest
+// value is discarded and counted in the NotificationsDropped statistics
+// of the subscription.
+//
+// The goroutine stops when the item is removed with Unmonitor or the
+// subscription is cancelled. Queued values are discarded and fn is not
+// called afterwards, apart from a call which is already running.
+//
+// The client handle of the item is chosen like for MonitorWithInitial.
+// MonitorWithCallback returns the id of the monitored item.
+//
+// The values contain both timestamps unless the Timestamps option
+// selects otherwise. Other options are ignored.
+func (s *Subscription) MonitorWithCallback(ctx context.Context, nodeID *ua.NodeID, fn func(*ua.DataValue), opts ...ReadOption) (uint32, error) {
+	if fn == nil {
+		return 0, fmt.Errorf("sub %d: callback is nil", s.SubscriptionID)
+	}
+	r := newCallbackRoute(fn, DefaultCallbackQueueSize, &s.stats.notificationsDropped)
+	handle := s.addRoute(r)
+
+	req := NewMonitoredItemCreateRequestWithDefaults(nodeID, ua.AttributeIDValue, handle)
+	res, err := s.Monitor(ctx, newReadConfig(opts...).timestamps, req)
+	if err == nil && res.Results[0].StatusCode != ua.StatusOK {
+		err = res.Results[0].StatusCode
+	}
+	if err != nil {
+		s.closeRoutes([]uint32{handle})
+		return 0, err
+	}
+	go r.run()
+	return res.Results[0].MonitoredItemID, nil
+}
+
+// route delivers the values of a single monitored item.
+type route interface {
+	send(ctx context.Context, dv *ua.DataValue)
+	close()
+}
+
+// itemRoute delivers the values of a single monitored item to a channel
+// instead of the notification channel of the subscription.
+type itemRoute struct {
+	// mu is held while a value is sent so that the channel
+	// is not closed during the send.
+	mu      sync.Mutex
+	closed  bool
+	gotOne  bool
+	first   chan *ua.DataValue
+	updates chan *ua.DataValue
+	done    chan struct{}
+}
+
+// send delivers the value to the first or the updates channel. It
+// blocks until the value has been received, the route is closed or
+// ctx is done.
+func (r *itemRoute) send(ctx context.Context, dv *ua.DataValue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	ch := r.updates
+	if !r.gotOne {
+		ch, r.gotOne = r.first, true
+	}
+	select {
+	case <-ctx.Done():
+	case <-r.done:
+	case ch <- dv:
+	}
+}
+
+// close stops the delivery and closes the updates channel.
+func (r *itemRoute) close() {
+	close(r.done)
+	r.mu.Lock()
+	r.closed = true
+	close(r.updates)
+	r.mu.Unlock()
+}
+
+// callbackRoute delivers the values of a single monitored item to a
+// callback which is called from its own goroutine.
+type callbackRoute struct {
+	fn func(*ua.DataValue)
+	ch chan *ua.DataValue
+
+	// mu serializes the producers so that only one
+	// value is discarded per send.
+	mu sync.Mutex
+
+	// dropped counts the discarded values.
+	dropped *atomic.Uint64
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newCallbackRoute(fn func(*ua.DataValue), size int, dropped *atomic.Uint64) *callbackRoute {
+	return &callbackRoute{
+		fn:      fn,
+		ch:      make(chan *ua.DataValue, size),
+		dropped: dropped,
+		done:    make(chan struct{}),
+	}
+}
+
+// send queues the value for the callback and discards the oldest
+// value if the queue is full. It never blocks.
+func (r *callbackRoute) send(_ context.Context, dv *ua.DataValue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for {
+		select {
+		case <-r.done:
+			return
+		case r.ch <- dv:
+			return
+		default:
+		}
+		select {
+		case <-r.ch:
+			r.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// run calls the callback with the queued values until the
+// route is closed.
+func (r *callbackRoute) run() {
+	for {
+		select {
+		case <-r.done:
+			return
+		case dv := <-r.ch:
+			select {
+			case <-r.done:
+				return
+			default:
+			}
+			r.fn(dv)
+		}
+	}
+}
+
+// close stops the goroutine and discards the queued values.
+func (r *callbackRoute) close() {
+	r.closeOnce.Do(func() { close(r.done) })
+}
+
+// addRoute registers the route under a client handle which is not used
+// by another monitored item of the subscription and returns the handle.
+func (s *Subscription) addRoute(r route) uint32 {
+	used := s.usedClientHandles()
+
+	s.routesMu.Lock()
+	defer s.routesMu.Unlock()
+	if s.routes == nil {
+		s.routes = make(map[uint32]route)
+	}
+	h := s.nextClientHandle(used)
+	s.routes[h] = r
+	return h
+}
+
+// usedClientHandles returns the client handles of the monitored items.
+func (s *Subscription) usedClientHandles() map[uint32]bool {
+	used := make(map[uint32]bool)
+	s.itemsMu.Lock()
+	for _, mi := range s.items {
+		if mi.req.RequestedParameters != nil {
+			used[mi.req.RequestedParameters.ClientHandle] = true
+		}
+	}
+	s.itemsMu.Unlock()
+	return used
+}
+
+// nextClientHandle returns the next free client handle from the top of
+// the uint32 range. routesMu must be held.
+func (s *Subscription) nextClientHandle(used map[uint32]bool) uint32 {
+	for {
+		s.nextHandle--
+		h := s.nextHandle
+		if h == 0 || used[h] || s.routes[h] != nil {
+			continue
+		}
+		return h
+	}
+}
+
+// closeRoutes closes the routes for the given client handles or all
+// routes if handles is nil.
+func (s *Subscription) closeRoutes(handles []uint32) {
+	s.routesMu.Lock()
+	var closing []route
+	if handles == nil {
+		for _, r := range s.routes {
+			closing = append(closing, r)
+		}
+		s.routes = nil
+	}
+	for _, h := range handles {
+		if r, ok := s.routes[h]; ok {
+			closing = append(closing, r)
+			delete(s.routes, h)
+		}
+	}
+	s.routesMu.Unlock()
+
+	for _, r := range closing {
+		r.close()
+	}
+}
+
+// routeDataChanges delivers the values of the monitored items which
+// have a route and returns the notification with the remaining items.
+// It returns nil if all items have been delivered.
+func (s *Subscription) routeDataChanges(ctx context.Context, n *ua.DataChangeNotification) *ua.DataChangeNotification {
+	s.routesMu.Lock()
+	if len(s.routes) == 0 {
+		s.routesMu.Unlock()
+		return n
+	}
+	var (
+		rest    []*ua.MonitoredItemNotification
+		deliver []func()
+	)
+	for _, item := range n.MonitoredItems {
+		r, ok := s.routes[item.ClientHandle]
+		if !ok {
+			rest = append(rest, item)
+			continue
+		}
+		dv := item.Value
+		deliver = append(deliver, func() { r.send(ctx, dv) })
+	}
+	s.routesMu.Unlock()
+
+	// send outside of the lock since the receivers may block
+	for _, f := range deliver {
+		f()
+	}
+
+	switch {
+	case len(deliver) == 0:
+		return n
+	case len(rest) == 0:
+		return nil
+	default:
+		// the diagnostic infos are index aligned with the items
+		// and cannot be assigned to the remaining items.
+		return &ua.DataChangeNotification{MonitoredItems: rest}
+	}
+}
+
+// ModifyMonitoredItems changes the monitoring parameters of monitored
+// items, e.g. the sampling interval, the queue size or the filter.
+//
+// The items keep their ids and queues on the server. The requested
+// parameters and the revised sampling interval and queue size of the
+// items which have been modified successfully are stored for the items.
+// See also SetSamplingInterval.
 func (s *Subscription) ModifyMonitoredItems(ctx context.Context, ts ua.TimestampsToReturn, items ...*ua.MonitoredItemModifyRequest) (*ua.ModifyMonitoredItemsResponse, error) {
 	stats.Subscription().Add("ModifyMonitoredItems", 1)
 	stats.Subscription().Add("ModifiedMonitoredItems", int64(len(items)))
@@ -239,6 +969,9 @@ func (s *Subscription) ModifyMonitoredItems(ctx context.Context, ts ua.Timestamp
 	if err != nil {
 		return nil, err
 	}
+	if len(res.Results) != len(items) {
+		return nil, ua.StatusBadUnknownResponse
+	}
 
 	// update monitored items
 	s.itemsMu.Lock()
@@ -261,6 +994,63 @@ func (s *Subscription) ModifyMonitoredItems(ctx context.Context, ts ua.Timestamp
 	return res, nil
 }
 
+// SetSamplingInterval changes the sampling interval of a monitored item
+// without recreating it so that the item keeps its id and its queue on
+// the server. A negative interval requests the publishing interval of
+// the subscription. The other monitoring parameters are unchanged.
+//
+// The returned result contains the sampling interval and the queue size
+// revised by the server which are also stored for the item.
+func (s *Subscription) SetSamplingInterval(ctx context.Context, monitoredItemID uint32, d time.Duration) (*ua.MonitoredItemModifyResult, error) {
+	s.itemsMu.Lock()
+	item, ok := s.items[monitoredItemID]
+	var (
+		params ua.MonitoringParameters
+		ts     ua.TimestampsToReturn
+	)
+	if ok {
+		if item.req.RequestedParameters != nil {
+			params = *item.req.RequestedParameters
+		}
+		ts = item.ts
+	}
+	s.itemsMu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("sub %d: cannot set sampling interval for unknown monitored item id: %d", s.SubscriptionID, monitoredItemID)
+	}
+
+	params.SamplingInterval = samplingInterval(d)
+	res, err := s.ModifyMonitoredItems(ctx, ts, &ua.MonitoredItemModifyRequest{
+		MonitoredItemID:     monitoredItemID,
+		RequestedParameters: &params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if r := res.Results[0]; r.StatusCode != ua.StatusOK {
+		return nil, r.StatusCode
+	}
+	return res.Results[0], nil
+}
+
+// samplingInterval converts d to a sampling interval in milliseconds.
+// Negative values are converted to -1 which selects the publishing
+// interval.
+func samplingInterval(d time.Duration) float64 {
+	if d < 0 {
+		return -1
+	}
+	return float64(d) / float64(time.Millisecond)
+}
+
+// SetMonitoringMode sets the monitoring mode of monitored items, e.g. to
+// disable sampling temporarily.
+//
+// The status of the individual items is reported in the results of the
+// response. The subscription remembers the mode of the items which have
+// been updated successfully and uses it when the items are recreated
+// after a reconnect. See MonitoringMode.
 func (s *Subscription) SetMonitoringMode(ctx context.Context, monitoringMode ua.MonitoringMode, monitoredItemIDs ...uint32) (*ua.SetMonitoringModeResponse, error) {
 	stats.Subscription().Add("SetMonitoringMode", 1)
 	stats.Subscription().Add("SetMonitoringModeMonitoredItems", int64(len(monitoredItemIDs)))
@@ -291,13 +1081,132 @@ func (s *Subscription) SetMonitoringMode(ctx context.Context, monitoringMode ua.
 	if err != nil {
 		return nil, err
 	}
+	if len(res.Results) != len(monitoredItemIDs) {
+		return nil, ua.StatusBadUnknownResponse
+	}
+
+	// update the monitoring mode of the items
+	s.itemsMu.Lock()
+	for i, id := range monitoredItemIDs {
+		if res.Results[i] != ua.StatusOK {
+			continue
+		}
+		if item, ok := s.items[id]; ok {
+			item.req.MonitoringMode = monitoringMode
+		}
+	}
+	s.itemsMu.Unlock()
 
 	return res, nil
 }
 
+// MonitoringMode returns the current monitoring mode of a monitored item
+// as known by the client. The second return value is false if the item
+// is not monitored by the subscription.
+func (s *Subscription) MonitoringMode(monitoredItemID uint32) (ua.MonitoringMode, bool) {
+	s.itemsMu.Lock()
+	defer s.itemsMu.Unlock()
+
+	item, ok := s.items[monitoredItemID]
+	if !ok {
+		return ua.MonitoringModeDisabled, false
+	}
+	return item.req.MonitoringMode, true
+}
+
+// MonitoredItemInfo describes a monitored item of a subscription.
+type MonitoredItemInfo struct {
+	NodeID      *ua.NodeID
+	AttributeID ua.AttributeID
+
+	// ClientHandle is the handle assigned by the client which is sent
+	// with the notifications of the item.
+	ClientHandle uint32
+
+	// MonitoredItemID is the id assigned by the server.
+	MonitoredItemID uint32
+
+	MonitoringMode          ua.MonitoringMode
+	RevisedSamplingInterval time.Duration
+	RevisedQueueSize        uint32
+}
+
+// Items returns the monitored items of the subscription ordered by
+// their server assigned id.
+func (s *Subscription) Items() []MonitoredItemInfo {
+	s.itemsMu.Lock()
+	defer s.itemsMu.Unlock()
+
+	items := make([]MonitoredItemInfo, 0, len(s.items))
+	for id, item := range s.items {
+		items = append(items, item.info(id))
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].MonitoredItemID < items[j].MonitoredItemID })
+	return items
+}
+
+// ItemByClientHandle returns the monitored item with the given client
+// handle, e.g. to find the node of a notification. The client handles
+// of the items remain the same when the subscription is recreated
+// after a reconnect while the server assigns new monitored item ids.
+func (s *Subscription) ItemByClientHandle(clientHandle uint32) (MonitoredItemInfo, bool) {
+	s.itemsMu.Lock()
+	defer s.itemsMu.Unlock()
+
+	for id, item := range s.items {
+		if p := item.req.RequestedParameters; p != nil && p.ClientHandle == clientHandle {
+			return item.info(id), true
+		}
+	}
+	return MonitoredItemInfo{}, false
+}
+
+// FailedMonitoredItem describes a monitored item which could not be
+// recreated after a reconnect.
+type FailedMonitoredItem struct {
+	MonitoredItemInfo
+
+	// Status is the status code returned by the server.
+	Status ua.StatusCode
+}
+
+// FailedItems returns the monitored items which could not be recreated
+// when the subscription was last recreated, e.g. since their nodes no
+// longer exist. The items have been removed from the subscription.
+func (s *Subscription) FailedItems() []FailedMonitoredItem {
+	s.itemsMu.Lock()
+	defer s.itemsMu.Unlock()
+
+	return append([]FailedMonitoredItem(nil), s.failedItems...)
+}
+
+// info describes the monitored item with the given id.
+func (item *monitoredItem) info(id uint32) MonitoredItemInfo {
+	info := MonitoredItemInfo{
+		MonitoredItemID: id,
+		MonitoringMode:  item.req.MonitoringMode,
+	}
+	if rv := item.req.ItemToMonitor; rv != nil {
+		info.NodeID = rv.NodeID
+		info.AttributeID = rv.AttributeID
+	}
+	if p := item.req.RequestedParameters; p != nil {
+		info.ClientHandle = p.ClientHandle
+	}
+	if item.res != nil {
+		info.RevisedSamplingInterval = time.Duration(item.res.RevisedSamplingInterval * float64(time.Millisecond))
+		info.RevisedQueueSize = item.res.RevisedQueueSize
+	}
+	return info
+}
+
 // SetTriggering sends a request to the server to add and/or remove triggering links from a triggering item.
 // To add links from a triggering item to an item to report provide the server assigned ID(s) in the `add` argument.
 // To remove links from a triggering item to an item to report provide the server assigned ID(s) in the `remove` argument.
+//
+// Items which are linked to a triggering item usually have the monitoring
+// mode MonitoringModeSampling so that they are only reported when the
+// triggering item reports a value. See SetMonitoringMode.
 func (s *Subscription) SetTriggering(ctx context.Context, triggeringItemID uint32, add, remove []uint32) (*ua.SetTriggeringResponse, error) {
 	stats.Subscription().Add("SetTriggering", 1)
 
@@ -317,7 +1226,7 @@ func (s *Subscription) SetTriggering(ctx context.Context, triggeringItemID uint3
 }
 
 func (s *Subscription) publishTimeout() time.Duration {
-	timeout := time.Duration(s.RevisedMaxKeepAliveCount) * s.RevisedPublishingInterval // expected keepalive interval
+	timeout := s.keepAliveInterval()
 	if timeout > uasc.MaxTimeout {
 		return uasc.MaxTimeout
 	}
@@ -328,6 +1237,10 @@ func (s *Subscription) publishTimeout() time.Duration {
 }
 
 func (s *Subscription) notify(ctx context.Context, data *PublishNotificationData) {
+	if s.buf != nil {
+		s.buf.push(ctx, data)
+		return
+	}
 	select {
 	case <-ctx.Done():
 		return
@@ -370,6 +1283,53 @@ func (s *Subscription) Stats(ctx context.Context) (*ua.SubscriptionDiagnosticsDa
 	return nil, errors.Errorf("unable to find SubscriptionDiagnostics for sub=%d", s.SubscriptionID)
 }
 
+// PublishStats returns client-side statistics about the publish
+// requests and responses of the subscription. The values can be used
+// to detect a subscription which is falling behind or has stalled.
+func (s *Subscription) PublishStats() SubscriptionStats {
+	st := SubscriptionStats{
+		PublishRequestsSent:      s.stats.publishRequestsSent.Load(),
+		PublishResponsesReceived: s.stats.publishResponsesReceived.Load(),
+		NotificationsReceived:    s.stats.notificationsReceived.Load(),
+		KeepAlivesReceived:       s.stats.keepAlivesReceived.Load(),
+		LastSequenceNumber:       s.stats.lastSequenceNumber.Load(),
+		NotificationsDropped:     s.stats.notificationsDropped.Load(),
+	}
+	if t := s.stats.lastPublishTime.Load(); t > 0 {
+		st.LastPublishTime = time.Unix(0, t)
+		if d := s.keepAliveInterval(); d > 0 {
+			st.MissedKeepAlives = uint64(time.Since(st.LastPublishTime) / d)
+		}
+	}
+	return st
+}
+
+// Stale returns true if the server has neither sent a notification nor a
+// keep-alive message for the subscription within the watchdog interval.
+// The flag is cleared when the next publish response for the
+// subscription is received. See KeepAliveWatchdog.
+func (s *Subscription) Stale() bool {
+	return s.stale.Load()
+}
+
+// alive records that a publish response has been received.
+func (s *Subscription) alive() {
+	s.resetWatchdog()
+	s.stale.Store(false)
+}
+
+// resetWatchdog restarts the watchdog interval, e.g. after the publish
+// loop has been paused.
+func (s *Subscription) resetWatchdog() {
+	s.lastAlive.Store(time.Now().UnixNano())
+}
+
+// keepAliveInterval returns the interval in which the server sends
+// either a notification or a keep-alive message.
+func (s *Subscription) keepAliveInterval() time.Duration {
+	return time.Duration(s.RevisedMaxKeepAliveCount) * s.RevisedPublishingInterval
+}
+
 func (p *SubscriptionParameters) setDefaults() {
 	if p.MaxNotificationsPerPublish == 0 {
 		p.MaxNotificationsPerPublish = DefaultSubscriptionMaxNotificationsPerPublish
@@ -409,7 +1369,13 @@ func (s *Subscription) recreate_delete(ctx context.Context) error {
 
 // recreate_create is called by the client when it is trying to
 // recreate an existing subscription. This function creates a
-// new subscription with the same parameters as the previous one.
+// new subscription with the same parameters as the previous one
+// and registers it under its new id once the monitored items have
+// been created. Monitored items which are rejected by the server are
+// removed from the subscription and recorded as failed items and their
+// routes are closed.
+//
+// The client must hold subMux.
 func (s *Subscription) recreate_create(ctx context.Context) error {
 	dlog := debug.NewPrefixLogger("sub %d: recreate_create: ", s.SubscriptionID)
 
@@ -421,7 +1387,7 @@ func (s *Subscription) recreate_create(ctx context.Context) error {
 		RequestedPublishingInterval: float64(params.Interval / time.Millisecond),
 		RequestedLifetimeCount:      params.LifetimeCount,
 		RequestedMaxKeepAliveCount:  params.MaxKeepAliveCount,
-		PublishingEnabled:           true,
+		PublishingEnabled:           s.PublishingEnabled(),
 		MaxNotificationsPerPublish:  params.MaxNotificationsPerPublish,
 		Priority:                    params.Priority,
 	}
@@ -437,33 +1403,44 @@ func (s *Subscription) recreate_create(ctx context.Context) error {
 	if status := res.ResponseHeader.ServiceResult; status != ua.StatusOK {
 		return status
 	}
-	dlog.Printf("recreated as subscription %d", res.SubscriptionID)
-	dlog.SetPrefix(fmt.Sprintf("sub %d: recreate: ", res.SubscriptionID))
-
-	s.SubscriptionID = res.SubscriptionID
-	s.RevisedPublishingInterval = time.Duration(res.RevisedPublishingInterval) * time.Millisecond
-	s.RevisedLifetimeCount = res.RevisedLifetimeCount
-	s.RevisedMaxKeepAliveCount = res.RevisedMaxKeepAliveCount
-	s.lastSeq = 0
-	s.nextSeq = 1
-
-	if err := s.c.registerSubscription_NeedsSubMuxLock(s); err != nil {
+	oldID, newID := s.SubscriptionID, res.SubscriptionID
+	dlog.Printf("recreated as subscription %d", newID)
+	dlog.SetPrefix(fmt.Sprintf("sub %d: recreate: ", newID))
+
+	// abort deletes the new subscription from the server so that no
+	// half-built subscription remains when the items cannot be created.
+	// The subscription stays registered under its old id.
+	abort := func(err error) error {
+		dlog.Printf("deleting subscription: %v", err)
+		req := &ua.DeleteSubscriptionsRequest{SubscriptionIDs: []uint32{newID}}
+		var res *ua.DeleteSubscriptionsResponse
+		_ = s.c.Send(ctx, req, func(v ua.Response) error {
+			return safeAssign(v, &res)
+		})
 		return err
 	}
-	dlog.Printf("subscription registered")
 
-	// Sort by timestamp to return
+	// Sort by timestamp to return. The items are recreated with their
+	// original requests so that the client handles remain the same and
+	// notifications can still be mapped to the items. Only the ids of
+	// the monitored items are assigned by the server again.
 	itemsByTimestamps := make(map[ua.TimestampsToReturn][]*ua.MonitoredItemCreateRequest)
 	s.itemsMu.Lock()
 	for _, mi := range s.items {
 		itemsByTimestamps[mi.ts] = append(itemsByTimestamps[mi.ts], mi.req)
 	}
-	s.items = make(map[uint32]*monitoredItem, len(s.items))
 	s.itemsMu.Unlock()
 
+	// the items are replaced only after all of them have been recreated
+	// so that a failed attempt does not lose any items for the next one.
+	recreated := make(map[uint32]*monitoredItem)
+	var (
+		failed        []FailedMonitoredItem
+		failedHandles []uint32
+	)
 	for ts, items := range itemsByTimestamps {
 		req := &ua.CreateMonitoredItemsRequest{
-			SubscriptionID:     s.SubscriptionID,
+			SubscriptionID:     newID,
 			TimestampsToReturn: ts,
 			ItemsToCreate:      items,
 		}
@@ -474,25 +1451,61 @@ func (s *Subscription) recreate_create(ctx context.Context) error {
 		})
 		if err != nil {
 			dlog.Printf("failed to create monitored items: %v", err)
-			return err
+			return abort(err)
 		}
-
-		for _, result := range res.Results {
-			if status := result.StatusCode; status != ua.StatusOK {
-				return status
-			}
+		if len(res.Results) != len(items) {
+			return abort(ua.StatusBadUnknownResponse)
 		}
 
-		s.itemsMu.Lock()
 		for i, item := range items {
-			s.items[res.Results[i].MonitoredItemID] = &monitoredItem{
-				req: item,
-				res: res.Results[i],
-				ts:  ts,
+			mi := &monitoredItem{req: item, res: res.Results[i], ts: ts}
+			if status := res.Results[i].StatusCode; status != ua.StatusOK {
+				dlog.Printf("failed to recreate monitored item: %v", status)
+				failed = append(failed, FailedMonitoredItem{
+					MonitoredItemInfo: mi.info(0),
+					Status:            status,
+				})
+				if item.RequestedParameters != nil {
+					failedHandles = append(failedHandles, item.RequestedParameters.ClientHandle)
+				}
+				continue
 			}
+			recreated[res.Results[i].MonitoredItemID] = mi
 		}
-		s.itemsMu.Unlock()
 	}
+
+	// the server may reuse the id of a subscription which is known to
+	// the client, e.g. after a restart.
+	if other, ok := s.c.subs[newID]; ok && other != s {
+		return abort(errors.Errorf("SubscriptionID %d already registered", newID))
+	}
+	delete(s.c.subs, oldID)
+	s.SubscriptionID = newID
+	s.RevisedPublishingInterval = time.Duration(res.RevisedPublishingInterval) * time.Millisecond
+	s.RevisedLifetimeCount = res.RevisedLifetimeCount
+	s.RevisedMaxKeepAliveCount = res.RevisedMaxKeepAliveCount
+	s.lastSeq = 0
+	s.nextSeq = 1
+	if err := s.c.registerSubscription_NeedsSubMuxLock(s); err != nil {
+		return abort(err)
+	}
+	dlog.Printf("subscription registered")
+
+	s.itemsMu.Lock()
+	s.items = recreated
+	s.failedItems = failed
+	s.itemsMu.Unlock()
+
+	// the routes of the failed items receive no more values.
+	if len(failedHandles) > 0 {
+		s.closeRoutes(failedHandles)
+	}
+
+	if len(failed) > 0 {
+		err := errors.Errorf("sub %d: %d of %d: %w", newID, len(failed), len(failed)+len(recreated), ErrMonitoredItemsNotRecreated)
+		go s.notify(ctx, &PublishNotificationData{SubscriptionID: newID, Error: err})
+	}
+
 	dlog.Printf("subscription successfully recreated")
 
 	return nil
@@ -1,9 +1,14 @@
 package opcua
 
 import (
+	"context"
+	"math"
 	"testing"
+	"time"
 
+	"github.com/gopcua/opcua/id"
 	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
 )
 
 // Running tool: /Users/frank/sdk/go1.17.1/bin/go test -benchmem -run=^$ -bench ^BenchmarkUnmonitorItems$ github.com/gopcua/opcua
@@ -104,3 +109,393 @@ func BenchmarkUnmonitorItems(b *testing.B) {
 		b.Log("src", len(src)) // ensure src and dst are not GC'ed
 	})
 }
+
+func TestSubscriptionPublishStats(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840")
+	require.NoError(t, err, "NewClient failed")
+
+	sub := &Subscription{
+		SubscriptionID:            1,
+		RevisedPublishingInterval: time.Hour,
+		RevisedMaxKeepAliveCount:  1,
+		nextSeq:                   1,
+		c:                         c,
+	}
+
+	keepAlive := &ua.PublishResponse{
+		SubscriptionID:      1,
+		NotificationMessage: &ua.NotificationMessage{SequenceNumber: 1},
+	}
+	notif := &ua.PublishResponse{
+		SubscriptionID: 1,
+		NotificationMessage: &ua.NotificationMessage{
+			SequenceNumber:   1,
+			NotificationData: []*ua.ExtensionObject{{Value: &ua.DataChangeNotification{}}},
+		},
+	}
+
+	c.handleNotification_NeedsSubMuxLock(sub, keepAlive)
+	c.handleNotification_NeedsSubMuxLock(sub, notif)
+
+	st := sub.PublishStats()
+	require.Equal(t, uint64(2), st.PublishResponsesReceived)
+	require.Equal(t, uint64(1), st.KeepAlivesReceived)
+	require.Equal(t, uint64(1), st.NotificationsReceived)
+	require.Equal(t, uint32(1), st.LastSequenceNumber)
+	require.Equal(t, uint64(0), st.MissedKeepAlives)
+	require.False(t, st.LastPublishTime.IsZero())
+}
+
+func TestNewMonitoredItemCreateRequestWithFilter(t *testing.T) {
+	f := DataChangeFilter(ua.DataChangeTriggerStatusValue, ua.DeadbandTypeAbsolute, 0.5)
+	req := NewMonitoredItemCreateRequestWithFilter(ua.NewNumericNodeID(1, 2), 0, 42, f)
+
+	require.Equal(t, ua.AttributeIDValue, req.ItemToMonitor.AttributeID)
+	require.Equal(t, uint32(42), req.RequestedParameters.ClientHandle)
+	require.Equal(t, ua.NewFourByteExpandedNodeID(0, id.DataChangeFilter_Encoding_DefaultBinary), req.RequestedParameters.Filter.TypeID)
+	require.Equal(t, &ua.DataChangeFilter{
+		Trigger:       ua.DataChangeTriggerStatusValue,
+		DeadbandType:  uint32(ua.DeadbandTypeAbsolute),
+		DeadbandValue: 0.5,
+	}, req.RequestedParameters.Filter.Value)
+}
+
+func TestNewEventMonitoredItemCreateRequest(t *testing.T) {
+	f := EventFilter(EventSelectClauses(), SeverityFilter(500))
+	req := NewEventMonitoredItemCreateRequest(nil, 7, f)
+
+	require.Equal(t, ua.NewNumericNodeID(0, id.Server), req.ItemToMonitor.NodeID)
+	require.Equal(t, ua.AttributeIDEventNotifier, req.ItemToMonitor.AttributeID)
+	require.Equal(t, uint32(7), req.RequestedParameters.ClientHandle)
+	require.Equal(t, ua.NewFourByteExpandedNodeID(0, id.EventFilter_Encoding_DefaultBinary), req.RequestedParameters.Filter.TypeID)
+
+	ef := req.RequestedParameters.Filter.Value.(*ua.EventFilter)
+	require.Len(t, ef.SelectClauses, len(DefaultEventFields))
+	for i, name := range DefaultEventFields {
+		require.Equal(t, []*ua.QualifiedName{{Name: name}}, ef.SelectClauses[i].BrowsePath)
+		require.Equal(t, ua.NewNumericNodeID(0, id.BaseEventType), ef.SelectClauses[i].TypeDefinitionID)
+	}
+
+	// the filter must survive the binary encoding
+	b, err := ua.Encode(req)
+	require.NoError(t, err)
+	got := new(ua.MonitoredItemCreateRequest)
+	_, err = ua.Decode(b, got)
+	require.NoError(t, err)
+	gf := got.RequestedParameters.Filter.Value.(*ua.EventFilter)
+	require.Equal(t, ef.SelectClauses, gf.SelectClauses)
+	require.Equal(t, ua.FilterOperatorGreaterThanOrEqual, gf.WhereClause.Elements[0].FilterOperator)
+	require.Equal(t, uint16(500), gf.WhereClause.Elements[0].FilterOperands[1].Value.(*ua.LiteralOperand).Value.Value())
+}
+
+func TestEventSelectClauses(t *testing.T) {
+	got := EventSelectClauses("Severity", "ActiveState/Id")
+	require.Equal(t, []*ua.QualifiedName{{Name: "Severity"}}, got[0].BrowsePath)
+	require.Equal(t, []*ua.QualifiedName{{Name: "ActiveState"}, {Name: "Id"}}, got[1].BrowsePath)
+	require.Equal(t, ua.AttributeIDValue, got[1].AttributeID)
+}
+
+func TestSubscriptionRouteDataChanges(t *testing.T) {
+	sub := &Subscription{SubscriptionID: 1, items: make(map[uint32]*monitoredItem)}
+	r := &itemRoute{
+		first:   make(chan *ua.DataValue, 1),
+		updates: make(chan *ua.DataValue, 1),
+		done:    make(chan struct{}),
+	}
+	h := sub.addRoute(r)
+	require.Equal(t, uint32(math.MaxUint32), h)
+
+	dv := func(v int32) *ua.DataValue { return &ua.DataValue{Value: ua.MustVariant(v)} }
+	other := &ua.MonitoredItemNotification{ClientHandle: 1, Value: dv(1)}
+
+	// the initial value goes to the first channel and the
+	// other item stays in the notification.
+	got := sub.routeDataChanges(context.Background(), &ua.DataChangeNotification{
+		MonitoredItems: []*ua.MonitoredItemNotification{other, {ClientHandle: h, Value: dv(2)}},
+	})
+	require.Equal(t, &ua.DataChangeNotification{MonitoredItems: []*ua.MonitoredItemNotification{other}}, got)
+	require.Equal(t, dv(2), <-r.first)
+
+	// subsequent values go to the updates channel
+	got = sub.routeDataChanges(context.Background(), &ua.DataChangeNotification{
+		MonitoredItems: []*ua.MonitoredItemNotification{{ClientHandle: h, Value: dv(3)}},
+	})
+	require.Nil(t, got)
+	require.Equal(t, dv(3), <-r.updates)
+
+	sub.closeRoutes([]uint32{h})
+	_, ok := <-r.updates
+	require.False(t, ok, "updates not closed")
+
+	n := &ua.DataChangeNotification{MonitoredItems: []*ua.MonitoredItemNotification{other}}
+	require.Same(t, n, sub.routeDataChanges(context.Background(), n))
+}
+
+func TestSubscriptionRouteCallback(t *testing.T) {
+	sub := &Subscription{SubscriptionID: 1, items: make(map[uint32]*monitoredItem)}
+	dv := func(v int32) *ua.DataValue { return &ua.DataValue{Value: ua.MustVariant(v)} }
+	notify := func(h uint32, vals ...int32) *ua.DataChangeNotification {
+		n := &ua.DataChangeNotification{}
+		for _, v := range vals {
+			n.MonitoredItems = append(n.MonitoredItems, &ua.MonitoredItemNotification{ClientHandle: h, Value: dv(v)})
+		}
+		return sub.routeDataChanges(context.Background(), n)
+	}
+
+	t.Run("order", func(t *testing.T) {
+		got := make(chan *ua.DataValue, 3)
+		r := newCallbackRoute(func(dv *ua.DataValue) { got <- dv }, 3, &sub.stats.notificationsDropped)
+		h := sub.addRoute(r)
+		go r.run()
+
+		require.Nil(t, notify(h, 1, 2, 3))
+		require.Equal(t, dv(1), <-got)
+		require.Equal(t, dv(2), <-got)
+		require.Equal(t, dv(3), <-got)
+		sub.closeRoutes([]uint32{h})
+	})
+
+	t.Run("slow callback", func(t *testing.T) {
+		block := make(chan struct{})
+		got := make(chan *ua.DataValue, 3)
+		r := newCallbackRoute(func(dv *ua.DataValue) { <-block; got <- dv }, 2, &sub.stats.notificationsDropped)
+		h := sub.addRoute(r)
+
+		// the goroutine is not running and the queue overflows
+		// without blocking the caller.
+		require.Nil(t, notify(h, 1, 2, 3, 4))
+		require.Equal(t, uint64(2), sub.stats.notificationsDropped.Load())
+
+		go r.run()
+		close(block)
+		require.Equal(t, dv(3), <-got)
+		require.Equal(t, dv(4), <-got)
+
+		sub.closeRoutes([]uint32{h})
+		require.NotNil(t, notify(h, 5), "route not removed")
+	})
+}
+
+func TestSubscriptionSetSamplingInterval(t *testing.T) {
+	t.Run("interval", func(t *testing.T) {
+		require.Equal(t, 250.0, samplingInterval(250*time.Millisecond))
+		require.Equal(t, 0.5, samplingInterval(500*time.Microsecond))
+		require.Equal(t, 0.0, samplingInterval(0))
+		require.Equal(t, -1.0, samplingInterval(-time.Second))
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		sub := &Subscription{SubscriptionID: 1, items: make(map[uint32]*monitoredItem)}
+		_, err := sub.SetSamplingInterval(context.Background(), 7, time.Second)
+		require.EqualError(t, err, "sub 1: cannot set sampling interval for unknown monitored item id: 7")
+	})
+}
+
+func TestSubscriptionSetRevised(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840", RequestTimeout(time.Second))
+	require.NoError(t, err, "NewClient failed")
+
+	sub := &Subscription{SubscriptionID: 1, c: c}
+	c.subs[sub.SubscriptionID] = sub
+
+	sub.setRevised(500, 30, 10)
+	require.Equal(t, 500*time.Millisecond, sub.RevisedPublishingInterval)
+	require.Equal(t, uint32(30), sub.RevisedLifetimeCount)
+	require.Equal(t, uint32(10), sub.RevisedMaxKeepAliveCount)
+	require.Equal(t, 5*time.Second, c.publishTimeout())
+
+	// the request timeout is the lower bound
+	sub.setRevised(50, 30, 10)
+	require.Equal(t, time.Second, c.publishTimeout())
+}
+
+func TestSubscriptionRevisedParameters(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840")
+	require.NoError(t, err, "NewClient failed")
+
+	params := &SubscriptionParameters{Interval: 100 * time.Millisecond, Priority: 7}
+	params.setDefaults()
+	sub := &Subscription{SubscriptionID: 1, c: c, params: params}
+	sub.setRevised(250, 300, 30)
+
+	require.Equal(t, SubscriptionParameters{
+		Interval:                   250 * time.Millisecond,
+		LifetimeCount:              300,
+		MaxKeepAliveCount:          30,
+		MaxNotificationsPerPublish: DefaultSubscriptionMaxNotificationsPerPublish,
+		Priority:                   7,
+	}, sub.RevisedParameters())
+}
+
+func TestSubscriptionPublishingMode(t *testing.T) {
+	c, err := NewClient("opc.tcp://example.com:4840")
+	require.NoError(t, err, "NewClient failed")
+
+	sub := &Subscription{SubscriptionID: 1, c: c}
+	require.True(t, sub.PublishingEnabled(), "new subscription not enabled")
+
+	// no request is sent without subscriptions
+	require.NoError(t, c.SetPublishingMode(context.Background(), false))
+	require.True(t, sub.PublishingEnabled())
+
+	// the mode is unchanged if the request fails
+	err = sub.SetPublishingMode(context.Background(), false)
+	require.Error(t, err)
+	require.True(t, sub.PublishingEnabled())
+}
+
+func TestKeepAliveWatchdog(t *testing.T) {
+	newSub := func(t *testing.T, opts ...Option) (*Client, *Subscription, chan *PublishNotificationData) {
+		c, err := NewClient("opc.tcp://example.com:4840", opts...)
+		require.NoError(t, err, "NewClient failed")
+
+		notifs := make(chan *PublishNotificationData, 1)
+		sub := &Subscription{
+			SubscriptionID:            1,
+			RevisedPublishingInterval: 10 * time.Millisecond,
+			RevisedMaxKeepAliveCount:  3,
+			Notifs:                    notifs,
+			c:                         c,
+		}
+		c.subs[sub.SubscriptionID] = sub
+		return c, sub, notifs
+	}
+
+	watchdog := KeepAliveWatchdog(DefaultKeepAliveWatchdogMultiplier)
+
+	t.Run("alive", func(t *testing.T) {
+		c, sub, _ := newSub(t, watchdog)
+		sub.resetWatchdog()
+		c.checkKeepAlives(context.Background())
+		require.False(t, sub.Stale())
+		require.Empty(t, c.sechanErr)
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		c, sub, notifs := newSub(t, watchdog)
+		sub.lastAlive.Store(time.Now().Add(-time.Second).UnixNano())
+		c.checkKeepAlives(context.Background())
+		require.True(t, sub.Stale())
+		require.Equal(t, ErrKeepAliveTimeout, <-c.sechanErr)
+		require.Equal(t, ErrKeepAliveTimeout, (<-notifs).Error)
+
+		// a stale subscription is only reported once
+		c.checkKeepAlives(context.Background())
+		require.Empty(t, c.sechanErr)
+
+		sub.alive()
+		require.False(t, sub.Stale())
+	})
+
+	t.Run("multiplier", func(t *testing.T) {
+		c, sub, _ := newSub(t, KeepAliveWatchdog(100))
+		sub.lastAlive.Store(time.Now().Add(-time.Second).UnixNano())
+		c.checkKeepAlives(context.Background())
+		require.False(t, sub.Stale())
+	})
+
+	t.Run("assumed parameters", func(t *testing.T) {
+		c, sub, _ := newSub(t, watchdog)
+		sub.revisedAssumed.Store(true)
+		sub.lastAlive.Store(time.Now().Add(-time.Second).UnixNano())
+		c.checkKeepAlives(context.Background())
+		require.False(t, sub.Stale())
+		require.Empty(t, c.sechanErr)
+
+		// the parameters reported by the server enable the watchdog
+		sub.setRevised(10, 30, 3)
+		c.checkKeepAlives(context.Background())
+		require.True(t, sub.Stale())
+	})
+
+	t.Run("without reconnect", func(t *testing.T) {
+		c, sub, notifs := newSub(t, watchdog, AutoReconnect(false))
+		sub.lastAlive.Store(time.Now().Add(-time.Second).UnixNano())
+		c.checkKeepAlives(context.Background())
+		require.True(t, sub.Stale())
+		require.Equal(t, ErrKeepAliveTimeout, (<-notifs).Error)
+		require.Empty(t, c.sechanErr)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		c, sub, _ := newSub(t)
+		sub.lastAlive.Store(time.Now().Add(-time.Hour).UnixNano())
+		c.checkKeepAlives(context.Background())
+		require.False(t, sub.Stale())
+		require.Empty(t, c.sechanErr)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		c, sub, _ := newSub(t, watchdog, KeepAliveWatchdog(0))
+		sub.lastAlive.Store(time.Now().Add(-time.Hour).UnixNano())
+		c.checkKeepAlives(context.Background())
+		require.False(t, sub.Stale())
+		require.Empty(t, c.sechanErr)
+	})
+}
+
+func TestSubscriptionMonitoredItemIDs(t *testing.T) {
+	item := func(handle uint32) *monitoredItem {
+		return &monitoredItem{req: &ua.MonitoredItemCreateRequest{
+			RequestedParameters: &ua.MonitoringParameters{ClientHandle: handle},
+		}}
+	}
+	sub := &Subscription{SubscriptionID: 1, items: map[uint32]*monitoredItem{10: item(1), 20: item(2)}}
+
+	ids, err := sub.monitoredItemIDs([]uint32{2, 1})
+	require.NoError(t, err)
+	require.Equal(t, []uint32{20, 10}, ids)
+
+	_, err = sub.monitoredItemIDs([]uint32{3})
+	require.EqualError(t, err, "sub 1: unknown client handle: 3")
+}
+
+func TestSubscriptionItems(t *testing.T) {
+	a, b := ua.NewStringNodeID(1, "a"), ua.NewStringNodeID(1, "b")
+	sub := &Subscription{items: map[uint32]*monitoredItem{
+		20: {
+			req: &ua.MonitoredItemCreateRequest{
+				ItemToMonitor:       &ua.ReadValueID{NodeID: b, AttributeID: ua.AttributeIDValue},
+				MonitoringMode:      ua.MonitoringModeSampling,
+				RequestedParameters: &ua.MonitoringParameters{ClientHandle: 2},
+			},
+			res: &ua.MonitoredItemCreateResult{MonitoredItemID: 20, RevisedSamplingInterval: 250, RevisedQueueSize: 10},
+		},
+		10: {
+			req: &ua.MonitoredItemCreateRequest{
+				ItemToMonitor:       &ua.ReadValueID{NodeID: a, AttributeID: ua.AttributeIDEventNotifier},
+				MonitoringMode:      ua.MonitoringModeReporting,
+				RequestedParameters: &ua.MonitoringParameters{ClientHandle: 1},
+			},
+			res: &ua.MonitoredItemCreateResult{MonitoredItemID: 10, RevisedSamplingInterval: 0.5, RevisedQueueSize: 1},
+		},
+	}}
+
+	want := []MonitoredItemInfo{
+		{
+			NodeID:                  a,
+			AttributeID:             ua.AttributeIDEventNotifier,
+			ClientHandle:            1,
+			MonitoredItemID:         10,
+			MonitoringMode:          ua.MonitoringModeReporting,
+			RevisedSamplingInterval: 500 * time.Microsecond,
+			RevisedQueueSize:        1,
+		},
+		{
+			NodeID:                  b,
+			AttributeID:             ua.AttributeIDValue,
+			ClientHandle:            2,
+			MonitoredItemID:         20,
+			MonitoringMode:          ua.MonitoringModeSampling,
+			RevisedSamplingInterval: 250 * time.Millisecond,
+			RevisedQueueSize:        10,
+		},
+	}
+	require.Equal(t, want, sub.Items())
+
+	info, ok := sub.ItemByClientHandle(2)
+	require.True(t, ok)
+	require.Equal(t, want[1], info)
+	_, ok = sub.ItemByClientHandle(3)
+	require.False(t, ok)
+}
@@ -0,0 +1,183 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/server"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowseStream(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	req := &ua.BrowseRequest{
+		NodesToBrowse: []*ua.BrowseDescription{
+			{
+				NodeID:          ua.NewNumericNodeID(0, id.RootFolder),
+				ReferenceTypeID: ua.NewNumericNodeID(0, id.HierarchicalReferences),
+				BrowseDirection: ua.BrowseDirectionForward,
+				IncludeSubtypes: true,
+				ResultMask:      uint32(ua.BrowseResultMaskAll),
+			},
+			{
+				NodeID:          ua.NewNumericNodeID(0, id.ObjectsFolder),
+				ReferenceTypeID: ua.NewNumericNodeID(0, id.HierarchicalReferences),
+				BrowseDirection: ua.BrowseDirectionForward,
+				IncludeSubtypes: true,
+				ResultMask:      uint32(ua.BrowseResultMaskAll),
+			},
+		},
+	}
+
+	t.Run("all", func(t *testing.T) {
+		var names []string
+		for ref, err := range c.BrowseStream(ctx, req) {
+			require.NoError(t, err, "BrowseStream failed")
+			names = append(names, ref.BrowseName.Name)
+		}
+		require.Contains(t, names, "Objects")
+		require.Contains(t, names, "Server")
+	})
+
+	t.Run("break", func(t *testing.T) {
+		n := 0
+		for _, err := range c.BrowseStream(ctx, req) {
+			require.NoError(t, err, "BrowseStream failed")
+			n++
+			break
+		}
+		require.Equal(t, 1, n)
+	})
+}
+
+func TestWalk(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	for _, depthFirst := range []bool{false, true} {
+		opts := opcua.WalkOptions{DepthFirst: depthFirst, MaxDepth: 2}
+		seen := map[string]bool{}
+		var paths []string
+		for n, err := range c.Walk(ctx, ua.NewNumericNodeID(0, id.RootFolder), opts) {
+			require.NoError(t, err, "Walk failed")
+			require.False(t, seen[n.NodeID.String()], "node %s visited twice", n.NodeID)
+			require.LessOrEqual(t, n.Depth(), 2)
+			seen[n.NodeID.String()] = true
+
+			var p string
+			for _, qn := range n.Path {
+				p += "/" + qn.Name
+			}
+			paths = append(paths, p)
+		}
+		require.Equal(t, "", paths[0], "root node must be first")
+		require.Contains(t, paths, "/Objects/Server")
+	}
+}
+
+func TestChildren(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	root := ua.NewNumericNodeID(0, id.RootFolder)
+	children, err := c.Children(ctx, root, nil)
+	require.NoError(t, err, "Children failed")
+	require.Contains(t, children, "0:Objects")
+	require.Equal(t, ua.NewNumericNodeID(0, id.ObjectsFolder).String(), children["0:Objects"].NodeID.NodeID.String())
+
+	multi, err := c.ChildrenMulti(ctx, root, ua.NewNumericNodeID(0, id.Organizes))
+	require.NoError(t, err, "ChildrenMulti failed")
+	require.Len(t, multi["0:Objects"], 1)
+
+	objects, err := c.Children(ctx, ua.NewNumericNodeID(0, id.ObjectsFolder), nil)
+	require.NoError(t, err, "Children failed")
+	require.Contains(t, objects, "0:Server")
+
+	none, err := c.Children(ctx, root, ua.NewNumericNodeID(0, id.HasProperty))
+	require.NoError(t, err, "Children failed")
+	require.Empty(t, none)
+}
+
+func TestProperties(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	ns, err := srv.Namespace(1)
+	require.NoError(t, err, "Namespace failed")
+	nodeNS := ns.(*server.NodeNameSpace)
+
+	level := nodeNS.AddNewVariableStringNode("tank_level", 42.0)
+	for name, v := range map[string]any{
+		"EURange":     ua.NewExtensionObject(&ua.Range{Low: 0, High: 100}),
+		"NodeVersion": "3",
+	} {
+		prop := server.NewNode(
+			ua.NewStringNodeID(nodeNS.ID(), "tank_level."+name),
+			map[ua.AttributeID]*ua.DataValue{
+				ua.AttributeIDBrowseName:  server.DataValueFromValue(&ua.QualifiedName{Name: name}),
+				ua.AttributeIDDisplayName: server.DataValueFromValue(ua.NewLocalizedText(name)),
+				ua.AttributeIDNodeClass:   server.DataValueFromValue(uint32(ua.NodeClassVariable)),
+			},
+			nil,
+			func() *ua.DataValue { return server.DataValueFromValue(v) },
+		)
+		nodeNS.AddNode(prop)
+		level.AddRef(prop, id.HasProperty, true)
+	}
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	props, err := c.Properties(ctx, level.ID())
+	require.NoError(t, err, "Properties failed")
+	require.Len(t, props, 2)
+	require.Equal(t, "3", props["0:NodeVersion"].Value.Value())
+	eo, ok := props["0:EURange"].Value.Value().(*ua.ExtensionObject)
+	require.True(t, ok, "got %T, want *ua.ExtensionObject", props["0:EURange"].Value.Value())
+	require.Equal(t, &ua.Range{Low: 0, High: 100}, eo.Value)
+
+	none, err := c.Properties(ctx, ua.NewNumericNodeID(0, id.RootFolder))
+	require.NoError(t, err, "Properties failed")
+	require.Empty(t, none)
+}
@@ -0,0 +1,106 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/server"
+	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uasc"
+	"github.com/stretchr/testify/require"
+)
+
+// cancelServer never answers history reads and
+// records the request handles of Cancel requests.
+type cancelServer struct {
+	reads     chan uint32
+	cancelled chan uint32
+}
+
+func (s *cancelServer) HistoryRead(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+	s.reads <- r.(*ua.HistoryReadRequest).RequestHeader.RequestHandle
+	return nil, nil
+}
+
+func (s *cancelServer) Cancel(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+	req := r.(*ua.CancelRequest)
+	s.cancelled <- req.RequestHandle
+	return &ua.CancelResponse{ResponseHeader: responseHeader(req.RequestHeader), CancelCount: 1}, nil
+}
+
+// TestCancelOnContextDone checks that a Cancel request with the request
+// handle of a pending request is sent when its context is cancelled.
+func TestCancelOnContextDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cs := &cancelServer{reads: make(chan uint32, 1), cancelled: make(chan uint32, 1)}
+	srv := server.New(
+		server.EndPoint("localhost", 4840),
+		server.EnableSecurity("None", ua.MessageSecurityModeNone),
+		server.EnableAuthMode(ua.UserTokenTypeAnonymous),
+	)
+	// handlers registered before Start replace the default handlers
+	srv.RegisterHandler(id.HistoryReadRequest_Encoding_DefaultBinary, cs.HistoryRead)
+	srv.RegisterHandler(id.CancelRequest_Encoding_DefaultBinary, cs.Cancel)
+	require.NoError(t, srv.Start(ctx), "Start failed")
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840",
+		opcua.SecurityMode(ua.MessageSecurityModeNone),
+		opcua.CancelOnContextDone(true),
+	)
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	readCtx, cancelRead := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.HistoryReadRawModified(readCtx, []*ua.HistoryReadValueID{{
+			NodeID:       ua.NewNumericNodeID(1, 1),
+			DataEncoding: &ua.QualifiedName{},
+		}}, &ua.ReadRawModifiedDetails{})
+		done <- err
+	}()
+
+	var handle uint32
+	select {
+	case handle = <-cs.reads:
+	case err := <-done:
+		t.Fatalf("history read returned early: %v", err)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the history read")
+	}
+	cancelRead()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the history read to return")
+	}
+
+	select {
+	case got := <-cs.cancelled:
+		require.Equal(t, handle, got)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the cancel request")
+	}
+
+	n, err := c.Cancel(ctx, 42)
+	require.NoError(t, err, "Cancel failed")
+	require.Equal(t, uint32(1), n)
+	require.Equal(t, uint32(42), <-cs.cancelled)
+	require.Equal(t, 0, c.PendingRequests(), "no requests should be pending")
+}
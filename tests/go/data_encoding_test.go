@@ -0,0 +1,42 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadDataEncoding checks that the requested data encoding is
+// validated against the encodings of the data type.
+func TestReadDataEncoding(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	i32 := &ua.ReadValueID{NodeID: ua.NewStringNodeID(1, "rw_int32"), AttributeID: ua.AttributeIDValue}
+	_, err = c.BatchRead(ctx, []*ua.ReadValueID{i32}, opcua.WithDataEncoding(opcua.DataEncodingJSON))
+	require.True(t, errors.Is(err, ua.StatusBadDataEncodingUnsupported), "got %v", err)
+	require.Nil(t, i32.DataEncoding, "ReadValueID was modified")
+
+	res, err := c.BatchRead(ctx, []*ua.ReadValueID{i32}, opcua.WithDataEncoding(opcua.DataEncodingBinary))
+	require.NoError(t, err, "BatchRead failed")
+	require.Equal(t, int32(5), res[0].Value.Value())
+}
@@ -0,0 +1,62 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFindServers performs an integration test of the discovery
+// services without an open session.
+func TestFindServers(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	servers, err := opcua.FindServers(ctx, "opc.tcp://localhost:4840")
+	require.NoError(t, err, "FindServers failed")
+	require.Len(t, servers, 1)
+	require.Equal(t, ua.ApplicationTypeServer, servers[0].ApplicationType)
+	require.NotEmpty(t, servers[0].DiscoveryURLs)
+
+	_, err = opcua.FindServersOnNetwork(ctx, "opc.tcp://localhost:4840")
+	require.True(t, errors.Is(err, ua.StatusBadServiceUnsupported), "got %v", err)
+}
+
+// TestRegisterServer verifies that RegisterServer falls back to the
+// RegisterServer service and reports that the test server does not
+// implement either service.
+func TestRegisterServer(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	app := srv.RegisteredServer()
+	require.True(t, app.IsOnline)
+	require.NotEmpty(t, app.DiscoveryURLs)
+
+	// the test server has no application uri
+	err := opcua.RegisterServer(ctx, "opc.tcp://localhost:4840", app)
+	require.Error(t, err)
+	app.ServerURI = "urn:gopcua:test"
+
+	err = opcua.RegisterServer(ctx, "opc.tcp://localhost:4840", app)
+	require.True(t, errors.Is(err, ua.StatusBadServiceUnsupported), "got %v", err)
+
+	err = opcua.RegisterServerPeriodically(ctx, "opc.tcp://localhost:4840", app, time.Second)
+	require.True(t, errors.Is(err, ua.StatusBadServiceUnsupported), "got %v", err)
+}
@@ -0,0 +1,240 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/server"
+	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uasc"
+	"github.com/stretchr/testify/require"
+)
+
+// historyServer is a HistoryRead service for raw values with one value
+// per second starting at t0. It pages the values with continuation
+// points, of which at most maxCPs can be in use, and records the
+// released continuation points.
+type historyServer struct {
+	t0     time.Time
+	n      int
+	maxCPs int
+
+	mu       sync.Mutex
+	requests int
+	nextCP   byte
+	pending  map[byte][]*ua.DataValue
+	released [][]byte
+}
+
+func newHistoryServer(t0 time.Time, n, maxCPs int) *historyServer {
+	return &historyServer{t0: t0, n: n, maxCPs: maxCPs, pending: make(map[byte][]*ua.DataValue)}
+}
+
+func (s *historyServer) value(i int) *ua.DataValue {
+	ts := s.t0.Add(time.Duration(i) * time.Second)
+	return &ua.DataValue{
+		EncodingMask:    ua.DataValueValue | ua.DataValueSourceTimestamp | ua.DataValueServerTimestamp,
+		Value:           ua.MustVariant(int32(i)),
+		SourceTimestamp: ts,
+		ServerTimestamp: ts,
+	}
+}
+
+// bound returns the value at t, the value before t if before is set or
+// the value after t. It returns a BadBoundNotFound value if there is
+// no such value.
+func (s *historyServer) bound(t time.Time, before bool) *ua.DataValue {
+	i := int(t.Sub(s.t0) / time.Second)
+	switch {
+	case s.t0.Add(time.Duration(i)*time.Second).Equal(t) && i >= 0 && i < s.n:
+	case before:
+		if t.Before(s.t0) {
+			i = -1
+		}
+	default:
+		i++
+	}
+	if i < 0 || i >= s.n {
+		return &ua.DataValue{
+			EncodingMask:    ua.DataValueStatusCode | ua.DataValueSourceTimestamp,
+			Status:          ua.StatusBadBoundNotFound,
+			SourceTimestamp: t,
+		}
+	}
+	return s.value(i)
+}
+
+// values returns the values of the half-open range [start, end).
+func (s *historyServer) values(d *ua.ReadRawModifiedDetails) []*ua.DataValue {
+	var values []*ua.DataValue
+	if d.ReturnBounds {
+		values = append(values, s.bound(d.StartTime, true))
+	}
+	for i := 0; i < s.n; i++ {
+		ts := s.t0.Add(time.Duration(i) * time.Second)
+		if ts.Before(d.StartTime) || !ts.Before(d.EndTime) {
+			continue
+		}
+		if d.ReturnBounds && ts.Equal(d.StartTime) {
+			continue
+		}
+		values = append(values, s.value(i))
+	}
+	if d.ReturnBounds {
+		values = append(values, s.bound(d.EndTime, false))
+	}
+	return values
+}
+
+func (s *historyServer) HistoryRead(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+	req := r.(*ua.HistoryReadRequest)
+	d := req.HistoryReadDetails.Value.(*ua.ReadRawModifiedDetails)
+	cp := req.NodesToRead[0].ContinuationPoint
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := &ua.HistoryReadResponse{ResponseHeader: responseHeader(req.RequestHeader)}
+	if req.ReleaseContinuationPoints {
+		delete(s.pending, cp[0])
+		s.released = append(s.released, cp)
+		res.Results = []*ua.HistoryReadResult{{StatusCode: ua.StatusOK}}
+		return res, nil
+	}
+	s.requests++
+
+	var values []*ua.DataValue
+	if len(cp) > 0 {
+		values = s.pending[cp[0]]
+		delete(s.pending, cp[0])
+	} else {
+		values = s.values(d)
+	}
+
+	result := &ua.HistoryReadResult{StatusCode: ua.StatusOK}
+	if n := int(d.NumValuesPerNode); n > 0 && len(values) > n {
+		if len(s.pending) >= s.maxCPs {
+			res.Results = []*ua.HistoryReadResult{{StatusCode: ua.StatusBadNoContinuationPoints}}
+			return res, nil
+		}
+		s.nextCP++
+		s.pending[s.nextCP] = values[n:]
+		result.ContinuationPoint = []byte{s.nextCP}
+		values = values[:n]
+	}
+	result.HistoryData = ua.NewExtensionObject(&ua.HistoryData{DataValues: values})
+	res.Results = []*ua.HistoryReadResult{result}
+	return res, nil
+}
+
+// TestHistoryReadRawStream checks that the raw values of a time range are
+// paged with continuation points, that the time range is split when the
+// server has no free continuation points and that an unused continuation
+// point is released.
+func TestHistoryReadRawStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	hs := newHistoryServer(t0, 20, 1)
+	srv := server.New(
+		server.EndPoint("localhost", 4840),
+		server.EnableSecurity("None", ua.MessageSecurityModeNone),
+		server.EnableAuthMode(ua.UserTokenTypeAnonymous),
+	)
+	// handlers registered before Start replace the default handlers
+	srv.RegisterHandler(id.HistoryReadRequest_Encoding_DefaultBinary, hs.HistoryRead)
+	require.NoError(t, srv.Start(ctx), "Start failed")
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	nodeID := ua.NewStringNodeID(1, "history")
+	end := t0.Add(20 * time.Second)
+
+	// read returns the values and the statuses of the bounds
+	read := func(opts ...opcua.HistoryReadOption) ([]int32, []ua.StatusCode) {
+		var values []int32
+		var bounds []ua.StatusCode
+		for v, err := range c.HistoryReadRawStream(ctx, nodeID, t0, end, opts...) {
+			require.NoError(t, err)
+			if v.Status != ua.StatusOK {
+				bounds = append(bounds, v.Status)
+				continue
+			}
+			values = append(values, v.Value.Value().(int32))
+		}
+		return values, bounds
+	}
+
+	all := make([]int32, 20)
+	for i := range all {
+		all[i] = int32(i)
+	}
+
+	t.Run("paging", func(t *testing.T) {
+		hs.requests = 0
+		values, bounds := read(opcua.HistoryNumValuesPerNode(3))
+		require.Equal(t, all, values)
+		require.Empty(t, bounds)
+		require.Equal(t, 7, hs.requests)
+		require.Empty(t, hs.pending)
+	})
+
+	t.Run("split without continuation points", func(t *testing.T) {
+		hs.maxCPs = 0
+		defer func() { hs.maxCPs = 1 }()
+
+		values, bounds := read(opcua.HistoryNumValuesPerNode(3))
+		require.Equal(t, all, values)
+		require.Empty(t, bounds)
+	})
+
+	t.Run("split with bounds", func(t *testing.T) {
+		hs.maxCPs = 0
+		defer func() { hs.maxCPs = 1 }()
+
+		values, bounds := read(opcua.HistoryNumValuesPerNode(3), opcua.HistoryReturnBounds(true))
+		require.Equal(t, all, values)
+		require.Equal(t, []ua.StatusCode{ua.StatusBadBoundNotFound}, bounds)
+	})
+
+	t.Run("split window too small", func(t *testing.T) {
+		hs.maxCPs = 0
+		defer func() { hs.maxCPs = 1 }()
+
+		var err error
+		for _, err = range c.HistoryReadRawStream(ctx, nodeID, t0, end, opcua.HistoryNumValuesPerNode(3), opcua.HistoryMinWindow(time.Minute)) {
+			if err != nil {
+				break
+			}
+		}
+		require.ErrorIs(t, err, ua.StatusBadNoContinuationPoints)
+	})
+
+	t.Run("release on break", func(t *testing.T) {
+		hs.released = nil
+		for v, err := range c.HistoryReadRawStream(ctx, nodeID, t0, end, opcua.HistoryNumValuesPerNode(3)) {
+			require.NoError(t, err)
+			if v.Value.Value().(int32) == 4 {
+				break
+			}
+		}
+		require.Len(t, hs.released, 1)
+		require.Empty(t, hs.pending)
+	})
+}
@@ -0,0 +1,104 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/monitor"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMonitorManager checks the distribution of monitored items across
+// subscriptions, the unified notification stream and the replacement of
+// a lost subscription.
+func TestMonitorManager(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	ch := make(chan *monitor.DataChangeMessage, 100)
+	m, err := monitor.NewMonitorManager(ctx, c, monitor.ManagerConfig{
+		Subscriptions:           2,
+		MaxItemsPerSubscription: 2,
+		Params:                  &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond},
+	}, ch)
+	require.NoError(t, err, "NewMonitorManager failed")
+	defer m.Close(ctx)
+
+	nodes := []string{"ns=1;s=ro_bool", "ns=1;s=rw_bool", "ns=1;s=ro_int32", "ns=1;s=rw_int32"}
+	require.NoError(t, m.AddNodes(ctx, nodes...), "AddNodes failed")
+
+	st := m.Stats()
+	require.Equal(t, 4, st.Items)
+	require.Len(t, st.Subscriptions, 2)
+	for _, s := range st.Subscriptions {
+		require.Equal(t, 2, s.Items)
+		require.True(t, s.Full)
+	}
+
+	// all subscriptions are full
+	err = m.AddNodes(ctx, "i=2258")
+	require.ErrorIs(t, err, ua.StatusBadTooManyMonitoredItems)
+	require.Equal(t, 4, m.Stats().Items)
+
+	// the initial values of all items arrive on the same channel
+	seen := map[string]bool{}
+	for len(seen) < len(nodes) {
+		select {
+		case msg := <-ch:
+			require.NoError(t, msg.Error)
+			seen[msg.NodeID.String()] = true
+		case <-ctx.Done():
+			t.Fatalf("timeout waiting for notifications. got %v", seen)
+		}
+	}
+
+	// the items of a lost subscription move to its replacement
+	require.NoError(t, m.RemoveNodes(ctx, "ns=1;s=ro_bool"), "RemoveNodes failed")
+	lost := m.Stats().Subscriptions[0].SubscriptionID
+	require.NoError(t, c.DeleteSubscriptions(ctx, lost), "DeleteSubscriptions failed")
+	require.NoError(t, m.Rebalance(ctx), "Rebalance failed")
+
+	st = m.Stats()
+	require.Equal(t, 3, st.Items)
+	require.Equal(t, uint64(1), st.Rebalances)
+	require.NotZero(t, st.Reassigned)
+	total := 0
+	for _, s := range st.Subscriptions {
+		require.NotEqual(t, lost, s.SubscriptionID)
+		total += s.Items
+	}
+	require.Equal(t, 3, total)
+
+	status, err := c.WriteValue(ctx, ua.NewStringNodeID(1, "rw_int32"), int32(9))
+	require.NoError(t, err, "WriteValue failed")
+	require.Equal(t, ua.StatusOK, status)
+	for {
+		select {
+		case msg := <-ch:
+			require.NoError(t, msg.Error)
+			if msg.NodeID.String() == "ns=1;s=rw_int32" && msg.Value.Value() == int32(9) {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for update")
+		}
+	}
+}
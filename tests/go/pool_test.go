@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPool performs an integration test for sharing clients with a pool.
+func TestPool(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	p, err := opcua.NewPool("opc.tcp://localhost:4840", 2, opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewPool failed")
+	defer p.Close(ctx)
+
+	c1, err := p.Acquire(ctx)
+	require.NoError(t, err, "Acquire failed")
+	c2, err := p.Acquire(ctx)
+	require.NoError(t, err, "Acquire failed")
+	require.NotSame(t, c1, c2)
+
+	// the pool is exhausted
+	actx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = p.Acquire(actx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// released clients are reused
+	p.Release(c1)
+	c3, err := p.Acquire(ctx)
+	require.NoError(t, err, "Acquire failed")
+	require.Same(t, c1, c3)
+
+	// dead clients are replaced
+	c2.Close(ctx)
+	p.Release(c2)
+	c4, err := p.Acquire(ctx)
+	require.NoError(t, err, "Acquire failed")
+	require.NotSame(t, c2, c4)
+
+	v, err := c4.Node(ua.NewStringNodeID(1, "rw_int32")).Value(ctx)
+	require.NoError(t, err, "Read failed")
+	require.Equal(t, int32(5), v.Value())
+
+	p.Release(c3)
+	p.Release(c4)
+}
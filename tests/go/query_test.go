@@ -0,0 +1,130 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/server"
+	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uasc"
+	"github.com/stretchr/testify/require"
+)
+
+// queryServer is a Query service which returns n data sets
+// in pages of two and records the released continuation points.
+type queryServer struct {
+	n int
+
+	mu       sync.Mutex
+	released [][]byte
+}
+
+func (s *queryServer) page(start int) ([]*ua.QueryDataSet, []byte) {
+	var sets []*ua.QueryDataSet
+	for i := start; i < s.n && i < start+2; i++ {
+		sets = append(sets, &ua.QueryDataSet{
+			NodeID:             ua.NewExpandedNodeID(ua.NewNumericNodeID(1, uint32(i)), "", 0),
+			TypeDefinitionNode: ua.NewExpandedNodeID(ua.NewNumericNodeID(0, id.BaseObjectType), "", 0),
+			Values:             []*ua.Variant{ua.MustVariant(int32(i))},
+		})
+	}
+	if start+2 >= s.n {
+		return sets, nil
+	}
+	return sets, []byte{byte(start + 2)}
+}
+
+func (s *queryServer) QueryFirst(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+	req := r.(*ua.QueryFirstRequest)
+	sets, cp := s.page(0)
+	return &ua.QueryFirstResponse{
+		ResponseHeader:    responseHeader(req.RequestHeader),
+		QueryDataSets:     sets,
+		ContinuationPoint: cp,
+		FilterResult:      &ua.ContentFilterResult{},
+	}, nil
+}
+
+func (s *queryServer) QueryNext(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+	req := r.(*ua.QueryNextRequest)
+	if req.ReleaseContinuationPoint {
+		s.mu.Lock()
+		s.released = append(s.released, req.ContinuationPoint)
+		s.mu.Unlock()
+		return &ua.QueryNextResponse{ResponseHeader: responseHeader(req.RequestHeader)}, nil
+	}
+	sets, cp := s.page(int(req.ContinuationPoint[0]))
+	return &ua.QueryNextResponse{
+		ResponseHeader:           responseHeader(req.RequestHeader),
+		QueryDataSets:            sets,
+		RevisedContinuationPoint: cp,
+	}, nil
+}
+
+func responseHeader(h *ua.RequestHeader) *ua.ResponseHeader {
+	return &ua.ResponseHeader{
+		Timestamp:          time.Now(),
+		RequestHandle:      h.RequestHandle,
+		ServiceDiagnostics: &ua.DiagnosticInfo{},
+		StringTable:        []string{},
+		AdditionalHeader:   ua.NewExtensionObject(nil),
+	}
+}
+
+// TestQueryStream checks that the data sets of a query are paged with
+// QueryNext and that an unused continuation point is released.
+func TestQueryStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	qs := &queryServer{n: 5}
+	srv := server.New(
+		server.EndPoint("localhost", 4840),
+		server.EnableSecurity("None", ua.MessageSecurityModeNone),
+		server.EnableAuthMode(ua.UserTokenTypeAnonymous),
+	)
+	// handlers registered before Start replace the default handlers
+	srv.RegisterHandler(id.QueryFirstRequest_Encoding_DefaultBinary, qs.QueryFirst)
+	srv.RegisterHandler(id.QueryNextRequest_Encoding_DefaultBinary, qs.QueryNext)
+	require.NoError(t, srv.Start(ctx), "Start failed")
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	req := &ua.QueryFirstRequest{
+		NodeTypes: []*ua.NodeTypeDescription{
+			opcua.NewNodeTypeDescription(ua.NewNumericNodeID(0, id.BaseObjectType), true),
+		},
+	}
+
+	var got []int32
+	for ds, err := range c.QueryStream(ctx, req) {
+		require.NoError(t, err)
+		got = append(got, ds.Values[0].Value().(int32))
+	}
+	require.Equal(t, []int32{0, 1, 2, 3, 4}, got)
+	require.Empty(t, qs.released)
+
+	// leaving the loop early releases the continuation point
+	for ds, err := range c.QueryStream(ctx, req) {
+		require.NoError(t, err)
+		if ds.Values[0].Value().(int32) == 2 {
+			break
+		}
+	}
+	require.Equal(t, [][]byte{{4}}, qs.released)
+}
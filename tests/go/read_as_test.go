@@ -0,0 +1,49 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadAs reads values and converts them to Go types.
+func TestReadAs(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	id := ua.NewStringNodeID(1, "rw_int32")
+
+	n, err := opcua.ReadAs[int](ctx, c, id)
+	require.NoError(t, err, "ReadAs[int] failed")
+	require.Equal(t, 5, n)
+
+	f, err := opcua.ReadAs[float64](ctx, c, id)
+	require.NoError(t, err, "ReadAs[float64] failed")
+	require.Equal(t, 5.0, f)
+
+	b, err := opcua.ReadAs[bool](ctx, c, ua.NewStringNodeID(1, "rw_bool"))
+	require.NoError(t, err, "ReadAs[bool] failed")
+	require.True(t, b)
+
+	_, err = opcua.ReadAs[string](ctx, c, id)
+	require.True(t, errors.Is(err, ua.StatusBadTypeMismatch), "got %v", err)
+}
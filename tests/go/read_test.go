@@ -9,6 +9,9 @@ import (
 	"time"
 
 	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/server"
 	"github.com/gopcua/opcua/ua"
 	"github.com/stretchr/testify/require"
 )
@@ -145,3 +148,135 @@ func TestReadPerms(t *testing.T) {
 		})
 	}
 }
+
+// TestReadValue reads single values and checks that bad status codes
+// are returned as errors.
+func TestReadValue(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	v, err := c.ReadValue(ctx, ua.NewStringNodeID(1, "rw_int32"))
+	require.NoError(t, err, "ReadValue failed")
+	require.Equal(t, int32(5), v.Value())
+
+	unknown := ua.NewStringNodeID(1, "unknown")
+	_, err = c.ReadValue(ctx, unknown)
+	require.True(t, errors.Is(err, ua.StatusBadNodeIDUnknown), "got %v", err)
+	require.Contains(t, err.Error(), unknown.String())
+}
+
+// TestReadAttribute reads attributes other than the value.
+func TestReadAttribute(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	id := ua.NewStringNodeID(1, "rw_int32")
+
+	dv, err := c.ReadAttribute(ctx, id, ua.AttributeIDBrowseName)
+	require.NoError(t, err, "ReadAttribute failed")
+	require.Equal(t, "rw_int32", dv.Value.Value().(*ua.QualifiedName).Name)
+
+	name, err := c.DisplayName(ctx, id)
+	require.NoError(t, err, "DisplayName failed")
+	require.Equal(t, "rw_int32", name.Text)
+
+	dt, err := c.DataType(ctx, id)
+	require.NoError(t, err, "DataType failed")
+	require.NotNil(t, dt)
+
+	// the test server does not support the access level attribute
+	_, err = c.AccessLevel(ctx, id)
+	require.True(t, errors.Is(err, ua.StatusBadAttributeIDInvalid), "got %v", err)
+
+	_, err = c.DisplayName(ctx, ua.NewStringNodeID(1, "unknown"))
+	require.True(t, errors.Is(err, ua.StatusBadNodeIDUnknown), "got %v", err)
+}
+
+// TestReadEnum reads the value of an enumeration with its label.
+func TestReadEnum(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	ns, err := srv.Namespace(1)
+	require.NoError(t, err, "Namespace failed")
+	nodeNS := ns.(*server.NodeNameSpace)
+
+	// an enumeration with an EnumStrings property and a variable of that type
+	modeType := server.NewNode(
+		ua.NewStringNodeID(nodeNS.ID(), "PumpMode"),
+		map[ua.AttributeID]*ua.DataValue{
+			ua.AttributeIDBrowseName:  server.DataValueFromValue(&ua.QualifiedName{NamespaceIndex: nodeNS.ID(), Name: "PumpMode"}),
+			ua.AttributeIDDisplayName: server.DataValueFromValue(ua.NewLocalizedText("PumpMode")),
+			ua.AttributeIDNodeClass:   server.DataValueFromValue(uint32(ua.NodeClassDataType)),
+		},
+		nil,
+		nil,
+	)
+	enumStrings := server.NewNode(
+		ua.NewStringNodeID(nodeNS.ID(), "PumpMode.EnumStrings"),
+		map[ua.AttributeID]*ua.DataValue{
+			ua.AttributeIDBrowseName:  server.DataValueFromValue(&ua.QualifiedName{Name: "EnumStrings"}),
+			ua.AttributeIDDisplayName: server.DataValueFromValue(ua.NewLocalizedText("EnumStrings")),
+			ua.AttributeIDNodeClass:   server.DataValueFromValue(uint32(ua.NodeClassVariable)),
+		},
+		nil,
+		func() *ua.DataValue {
+			return server.DataValueFromValue([]*ua.LocalizedText{ua.NewLocalizedText("Off"), ua.NewLocalizedText("On")})
+		},
+	)
+	mode := server.NewNode(
+		ua.NewStringNodeID(nodeNS.ID(), "pump_mode"),
+		map[ua.AttributeID]*ua.DataValue{
+			ua.AttributeIDBrowseName: server.DataValueFromValue(&ua.QualifiedName{NamespaceIndex: nodeNS.ID(), Name: "pump_mode"}),
+			ua.AttributeIDNodeClass:  server.DataValueFromValue(uint32(ua.NodeClassVariable)),
+			ua.AttributeIDDataType:   server.DataValueFromValue(ua.NewExpandedNodeID(modeType.ID(), "", 0)),
+		},
+		nil,
+		func() *ua.DataValue { return server.DataValueFromValue(int32(1)) },
+	)
+	nodeNS.AddNode(modeType)
+	nodeNS.AddNode(enumStrings)
+	nodeNS.AddNode(mode)
+	modeType.AddRef(enumStrings, id.HasProperty, true)
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	v, err := c.ReadEnum(ctx, mode.ID())
+	require.NoError(t, err, "ReadEnum failed")
+	require.Equal(t, int64(1), v.Value)
+	require.Equal(t, "On", v.String())
+
+	_, err = c.ReadEnum(ctx, ua.NewStringNodeID(nodeNS.ID(), "rw_bool"))
+	require.Error(t, err, "Boolean is not an enumeration")
+}
@@ -0,0 +1,434 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/server"
+	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uasc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecreateMonitoredItems checks that the monitored items keep their
+// client handles when the subscription is recreated after the server
+// has been restarted and that notifications resume with these handles.
+func TestRecreateMonitoredItems(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer func() { srv.Close() }()
+
+	time.Sleep(2 * time.Second)
+
+	p, err := newDropProxy("localhost:4840")
+	require.NoError(t, err, "newDropProxy failed")
+	defer p.Close()
+
+	recreated := make(chan *opcua.Subscription, 1)
+	c, err := opcua.NewClient("opc.tcp://"+p.Addr(),
+		opcua.SecurityMode(ua.MessageSecurityModeNone),
+		opcua.ReconnectInterval(100*time.Millisecond),
+		opcua.SubscriptionRecreatedFunc(func(oldID uint32, sub *opcua.Subscription) { recreated <- sub }),
+	)
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	notifs := make(chan *opcua.PublishNotificationData, 10)
+	sub, err := c.Subscribe(ctx, &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}, notifs)
+	require.NoError(t, err, "Subscribe failed")
+	defer sub.Cancel(ctx)
+
+	boolID, int32ID := ua.NewStringNodeID(1, "rw_bool"), ua.NewStringNodeID(1, "rw_int32")
+	res, err := sub.Monitor(ctx, ua.TimestampsToReturnBoth,
+		opcua.NewMonitoredItemCreateRequestWithDefaults(boolID, ua.AttributeIDValue, 100),
+		opcua.NewMonitoredItemCreateRequestWithDefaults(int32ID, ua.AttributeIDValue, 200),
+	)
+	require.NoError(t, err, "Monitor failed")
+	for _, r := range res.Results {
+		require.Equal(t, ua.StatusOK, r.StatusCode)
+	}
+
+	// values returns the next values of the monitored items by client handle
+	values := func() map[uint32]*ua.DataValue {
+		values := make(map[uint32]*ua.DataValue)
+		for len(values) < 2 {
+			select {
+			case n := <-notifs:
+				if n.Error != nil {
+					continue
+				}
+				if dc, ok := n.Value.(*ua.DataChangeNotification); ok {
+					for _, item := range dc.MonitoredItems {
+						values[item.ClientHandle] = item.Value
+					}
+				}
+			case <-ctx.Done():
+				t.Fatal("timeout waiting for values")
+			}
+		}
+		return values
+	}
+	v := values()
+	require.Equal(t, true, v[100].Value.Value())
+	require.Equal(t, int32(5), v[200].Value.Value())
+
+	// drop the connection and restart the server which loses the
+	// session. The client recreates the subscription on the new server.
+	srv.Close()
+	p.Drop()
+	srv = startServer()
+
+	select {
+	case s := <-recreated:
+		require.Same(t, sub, s)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the subscription to be recreated")
+	}
+
+	for _, h := range []uint32{100, 200} {
+		info, ok := sub.ItemByClientHandle(h)
+		require.True(t, ok, "item %d not found", h)
+		require.NotZero(t, info.MonitoredItemID)
+	}
+	info, _ := sub.ItemByClientHandle(100)
+	require.Equal(t, boolID.String(), info.NodeID.String())
+	info, _ = sub.ItemByClientHandle(200)
+	require.Equal(t, int32ID.String(), info.NodeID.String())
+
+	v = values()
+	require.Equal(t, true, v[100].Value.Value())
+	require.Equal(t, int32(5), v[200].Value.Value())
+}
+
+// TestRecreateFailedItems checks that a subscription whose transfer is
+// rejected is recreated and that monitored items which the server
+// rejects are reported as failed items instead of restarting the
+// reconnect. The routes of the failed items are closed.
+func TestRecreateFailedItems(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer func() { srv.Close() }()
+
+	time.Sleep(2 * time.Second)
+
+	p, err := newDropProxy("localhost:4840")
+	require.NoError(t, err, "newDropProxy failed")
+	defer p.Close()
+
+	type recreatedSub struct {
+		oldID uint32
+		sub   *opcua.Subscription
+	}
+	recreated := make(chan recreatedSub, 1)
+	c, err := opcua.NewClient("opc.tcp://"+p.Addr(),
+		opcua.SecurityMode(ua.MessageSecurityModeNone),
+		opcua.ReconnectInterval(100*time.Millisecond),
+		opcua.SubscriptionRecreatedFunc(func(oldID uint32, sub *opcua.Subscription) {
+			recreated <- recreatedSub{oldID, sub}
+		}),
+	)
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	notifs := make(chan *opcua.PublishNotificationData, 10)
+	sub, err := c.Subscribe(ctx, &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}, notifs)
+	require.NoError(t, err, "Subscribe failed")
+	defer sub.Cancel(ctx)
+	oldID := sub.SubscriptionID
+
+	boolID, missingID := ua.NewStringNodeID(1, "rw_bool"), ua.NewStringNodeID(1, "missing")
+	_, err = sub.Monitor(ctx, ua.TimestampsToReturnBoth,
+		opcua.NewMonitoredItemCreateRequestWithDefaults(boolID, ua.AttributeIDValue, 100),
+		opcua.NewMonitoredItemCreateRequestWithDefaults(missingID, ua.AttributeIDValue, 200),
+	)
+	require.NoError(t, err, "Monitor failed")
+
+	// the routed item is rejected as well after the restart
+	routedID := ua.NewStringNodeID(1, "ro_int32")
+	_, updates, err := sub.MonitorWithInitial(ctx, routedID, ua.TimestampsToReturnBoth)
+	require.NoError(t, err, "MonitorWithInitial failed")
+
+	// replace the server with one which rejects the transfer and the
+	// monitored items of the missing and the routed node.
+	var (
+		mu          sync.Mutex
+		transferred []uint32
+	)
+	srv.Close()
+	p.Drop()
+	srv = server.New(
+		server.EndPoint("localhost", 4840),
+		server.EnableSecurity("None", ua.MessageSecurityModeNone),
+		server.EnableAuthMode(ua.UserTokenTypeAnonymous),
+	)
+	srv.RegisterHandler(id.TransferSubscriptionsRequest_Encoding_DefaultBinary, func(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+		req := r.(*ua.TransferSubscriptionsRequest)
+		mu.Lock()
+		transferred = append(transferred, req.SubscriptionIDs...)
+		mu.Unlock()
+		res := &ua.TransferSubscriptionsResponse{ResponseHeader: responseHeader(req.RequestHeader)}
+		for range req.SubscriptionIDs {
+			res.Results = append(res.Results, &ua.TransferResult{StatusCode: ua.StatusBadSubscriptionIDInvalid})
+		}
+		return res, nil
+	})
+	var nextItemID uint32
+	srv.RegisterHandler(id.CreateMonitoredItemsRequest_Encoding_DefaultBinary, func(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+		req := r.(*ua.CreateMonitoredItemsRequest)
+		res := &ua.CreateMonitoredItemsResponse{ResponseHeader: responseHeader(req.RequestHeader)}
+		for _, item := range req.ItemsToCreate {
+			result := &ua.MonitoredItemCreateResult{FilterResult: ua.NewExtensionObject(nil)}
+			switch item.ItemToMonitor.NodeID.String() {
+			case missingID.String(), routedID.String():
+				result.StatusCode = ua.StatusBadNodeIDUnknown
+			default:
+				nextItemID++
+				result.MonitoredItemID = nextItemID
+			}
+			res.Results = append(res.Results, result)
+		}
+		return res, nil
+	})
+	require.NoError(t, srv.Start(ctx), "Start failed")
+
+	select {
+	case r := <-recreated:
+		require.Same(t, sub, r.sub)
+		require.Equal(t, oldID, r.oldID)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the subscription to be recreated")
+	}
+
+	mu.Lock()
+	require.Equal(t, []uint32{oldID}, transferred)
+	mu.Unlock()
+
+	items := sub.Items()
+	require.Len(t, items, 1)
+	require.Equal(t, uint32(100), items[0].ClientHandle)
+
+	failed := sub.FailedItems()
+	require.Len(t, failed, 2)
+	sort.Slice(failed, func(i, j int) bool { return failed[i].ClientHandle < failed[j].ClientHandle })
+	require.Equal(t, uint32(200), failed[0].ClientHandle)
+	require.Equal(t, missingID.String(), failed[0].NodeID.String())
+	require.Equal(t, ua.StatusBadNodeIDUnknown, failed[0].Status)
+	require.Equal(t, routedID.String(), failed[1].NodeID.String())
+
+	// the route of the failed item is closed
+	for closed := false; !closed; {
+		select {
+		case _, ok := <-updates:
+			closed = !ok
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for the updates channel to be closed")
+		}
+	}
+
+	for {
+		select {
+		case n := <-notifs:
+			if !errors.Is(n.Error, opcua.ErrMonitoredItemsNotRecreated) {
+				continue
+			}
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for the failed items to be reported")
+		}
+		break
+	}
+
+	// the client must not recreate the session again
+	time.Sleep(time.Second)
+	require.Equal(t, opcua.Connected, c.State())
+	select {
+	case <-recreated:
+		t.Fatal("subscription recreated again")
+	default:
+	}
+}
+
+// TestRecreateAbort checks that a subscription whose monitored items
+// cannot be created is deleted from the server and from the client
+// instead of leaving a half-built subscription behind.
+func TestRecreateAbort(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer func() { srv.Close() }()
+
+	time.Sleep(2 * time.Second)
+
+	p, err := newDropProxy("localhost:4840")
+	require.NoError(t, err, "newDropProxy failed")
+	defer p.Close()
+
+	recreated := make(chan *opcua.Subscription, 1)
+	c, err := opcua.NewClient("opc.tcp://"+p.Addr(),
+		opcua.SecurityMode(ua.MessageSecurityModeNone),
+		opcua.ReconnectInterval(100*time.Millisecond),
+		opcua.SubscriptionRecreatedFunc(func(oldID uint32, sub *opcua.Subscription) { recreated <- sub }),
+	)
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	notifs := make(chan *opcua.PublishNotificationData, 10)
+	sub, err := c.Subscribe(ctx, &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}, notifs)
+	require.NoError(t, err, "Subscribe failed")
+	oldID := sub.SubscriptionID
+
+	_, err = sub.Monitor(ctx, ua.TimestampsToReturnBoth,
+		opcua.NewMonitoredItemCreateRequestWithDefaults(ua.NewStringNodeID(1, "rw_bool"), ua.AttributeIDValue, 100),
+	)
+	require.NoError(t, err, "Monitor failed")
+
+	// replace the server with one which creates the subscription but
+	// returns no results for the monitored items.
+	const newID = 42
+	var (
+		mu      sync.Mutex
+		deleted []uint32
+	)
+	srv.Close()
+	p.Drop()
+	srv = server.New(
+		server.EndPoint("localhost", 4840),
+		server.EnableSecurity("None", ua.MessageSecurityModeNone),
+		server.EnableAuthMode(ua.UserTokenTypeAnonymous),
+	)
+	srv.RegisterHandler(id.CreateSubscriptionRequest_Encoding_DefaultBinary, func(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+		req := r.(*ua.CreateSubscriptionRequest)
+		return &ua.CreateSubscriptionResponse{
+			ResponseHeader:            responseHeader(req.RequestHeader),
+			SubscriptionID:            newID,
+			RevisedPublishingInterval: req.RequestedPublishingInterval,
+			RevisedLifetimeCount:      req.RequestedLifetimeCount,
+			RevisedMaxKeepAliveCount:  req.RequestedMaxKeepAliveCount,
+		}, nil
+	})
+	srv.RegisterHandler(id.CreateMonitoredItemsRequest_Encoding_DefaultBinary, func(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+		req := r.(*ua.CreateMonitoredItemsRequest)
+		return &ua.CreateMonitoredItemsResponse{ResponseHeader: responseHeader(req.RequestHeader)}, nil
+	})
+	srv.RegisterHandler(id.DeleteSubscriptionsRequest_Encoding_DefaultBinary, func(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+		req := r.(*ua.DeleteSubscriptionsRequest)
+		mu.Lock()
+		deleted = append(deleted, req.SubscriptionIDs...)
+		mu.Unlock()
+		res := &ua.DeleteSubscriptionsResponse{ResponseHeader: responseHeader(req.RequestHeader)}
+		for range req.SubscriptionIDs {
+			res.Results = append(res.Results, ua.StatusOK)
+		}
+		return res, nil
+	})
+	require.NoError(t, srv.Start(ctx), "Start failed")
+
+	for {
+		select {
+		case n := <-notifs:
+			if !errors.Is(n.Error, ua.StatusBadUnknownResponse) {
+				continue
+			}
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for the subscription to fail")
+		}
+		break
+	}
+
+	mu.Lock()
+	require.Contains(t, deleted, uint32(newID))
+	mu.Unlock()
+	require.NotContains(t, c.SubscriptionIDs(), oldID)
+	require.NotContains(t, c.SubscriptionIDs(), uint32(newID))
+	require.Equal(t, oldID, sub.SubscriptionID)
+
+	time.Sleep(time.Second)
+	require.Equal(t, opcua.Connected, c.State())
+	select {
+	case <-recreated:
+		t.Fatal("half-built subscription reported as recreated")
+	default:
+	}
+}
+
+// dropProxy forwards connections to a server and closes them on Drop to
+// simulate a dropped secure channel.
+type dropProxy struct {
+	l      net.Listener
+	target string
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func newDropProxy(target string) (*dropProxy, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return nil, err
+	}
+	p := &dropProxy{l: l, target: target}
+	go p.serve()
+	return p, nil
+}
+
+func (p *dropProxy) Addr() string {
+	return p.l.Addr().String()
+}
+
+func (p *dropProxy) serve() {
+	for {
+		c, err := p.l.Accept()
+		if err != nil {
+			return
+		}
+		s, err := net.Dial("tcp", p.target)
+		if err != nil {
+			c.Close()
+			continue
+		}
+		p.mu.Lock()
+		p.conns = append(p.conns, c, s)
+		p.mu.Unlock()
+		go func() { io.Copy(s, c); s.Close() }()
+		go func() { io.Copy(c, s); c.Close() }()
+	}
+}
+
+// Drop closes all forwarded connections.
+func (p *dropProxy) Drop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range p.conns {
+		c.Close()
+	}
+	p.conns = nil
+}
+
+func (p *dropProxy) Close() {
+	p.l.Close()
+	p.Drop()
+}
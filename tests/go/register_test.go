@@ -0,0 +1,103 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/server"
+	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uasc"
+	"github.com/stretchr/testify/require"
+)
+
+// registerServer is a RegisterNodes service which returns the node ids
+// as handles like many servers do and records the unregistered handles.
+type registerServer struct {
+	mu           sync.Mutex
+	unregistered [][]*ua.NodeID
+}
+
+func (s *registerServer) RegisterNodes(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+	req := r.(*ua.RegisterNodesRequest)
+	return &ua.RegisterNodesResponse{
+		ResponseHeader:    responseHeader(req.RequestHeader),
+		RegisteredNodeIDs: req.NodesToRegister,
+	}, nil
+}
+
+func (s *registerServer) UnregisterNodes(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+	req := r.(*ua.UnregisterNodesRequest)
+	s.mu.Lock()
+	s.unregistered = append(s.unregistered, req.NodesToUnregister)
+	s.mu.Unlock()
+	return &ua.UnregisterNodesResponse{ResponseHeader: responseHeader(req.RequestHeader)}, nil
+}
+
+func (s *registerServer) calls() [][]*ua.NodeID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unregistered
+}
+
+// TestRegisteredNodeSet checks that handles which are returned more
+// than once are only unregistered after the last registration.
+func TestRegisteredNodeSet(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rs := &registerServer{}
+	srv := server.New(
+		server.EndPoint("localhost", 4840),
+		server.EnableSecurity("None", ua.MessageSecurityModeNone),
+		server.EnableAuthMode(ua.UserTokenTypeAnonymous),
+	)
+	// handlers registered before Start replace the default handlers
+	srv.RegisterHandler(id.RegisterNodesRequest_Encoding_DefaultBinary, rs.RegisterNodes)
+	srv.RegisterHandler(id.UnregisterNodesRequest_Encoding_DefaultBinary, rs.UnregisterNodes)
+	require.NoError(t, srv.Start(ctx), "Start failed")
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	n1, n2 := ua.NewStringNodeID(1, "a"), ua.NewStringNodeID(1, "b")
+
+	handles, err := c.RegisterNodeIDs(ctx, []*ua.NodeID{n1})
+	require.NoError(t, err, "RegisterNodeIDs failed")
+	require.Equal(t, []*ua.NodeID{n1}, handles)
+	require.NoError(t, c.UnregisterNodeIDs(ctx, handles), "UnregisterNodeIDs failed")
+	require.Equal(t, [][]*ua.NodeID{{n1}}, rs.calls())
+
+	set := c.NewRegisteredNodeSet()
+	_, err = set.Register(ctx, n1)
+	require.NoError(t, err, "Register failed")
+	_, err = set.Register(ctx, n1, n2)
+	require.NoError(t, err, "Register failed")
+	require.ElementsMatch(t, []*ua.NodeID{n1, n2}, set.Handles())
+
+	// n1 has been registered twice
+	require.NoError(t, set.Unregister(ctx, n1), "Unregister failed")
+	require.Len(t, rs.calls(), 1)
+	require.ElementsMatch(t, []*ua.NodeID{n1, n2}, set.Handles())
+
+	require.NoError(t, set.Unregister(ctx, n1), "Unregister failed")
+	require.Equal(t, []*ua.NodeID{n1}, rs.calls()[1])
+	require.Equal(t, []*ua.NodeID{n2}, set.Handles())
+
+	require.NoError(t, set.Close(ctx), "Close failed")
+	require.Equal(t, []*ua.NodeID{n2}, rs.calls()[2])
+	require.Empty(t, set.Handles())
+}
@@ -0,0 +1,74 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uacp"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListenReverse performs an integration test for the reverse connect
+// handshake. The test server does not support reverse connect so the
+// test initiates the connection to the client, sends the ReverseHello
+// and forwards the connection to the server.
+func TestListenReverse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	clients, err := opcua.ListenReverse(ctx, "127.0.0.1:4850",
+		opcua.SecurityMode(ua.MessageSecurityModeNone),
+		opcua.ReverseServerURIs("urn:gopcua:test"),
+	)
+	require.NoError(t, err, "ListenReverse failed")
+
+	reverseConnect := func(serverURI string) {
+		rc, err := net.Dial("tcp", "127.0.0.1:4850")
+		require.NoError(t, err, "reverse connect failed")
+
+		conn, err := uacp.NewConnFromNetConn(rc, nil)
+		require.NoError(t, err)
+		err = conn.Send("RHEF", &uacp.ReverseHello{ServerURI: serverURI, EndpointURL: "opc.tcp://localhost:4840"})
+		require.NoError(t, err, "sending ReverseHello failed")
+
+		sc, err := net.Dial("tcp", "localhost:4840")
+		require.NoError(t, err, "connecting to the server failed")
+		go func() { io.Copy(sc, rc); sc.Close() }()
+		go func() { io.Copy(rc, sc); rc.Close() }()
+	}
+
+	// connections from unknown servers are rejected
+	reverseConnect("urn:unknown")
+	reverseConnect("urn:gopcua:test")
+
+	select {
+	case c := <-clients:
+		defer c.Close(ctx)
+
+		v, err := c.Node(ua.NewStringNodeID(1, "rw_int32")).Value(ctx)
+		require.NoError(t, err, "Read failed")
+		require.Equal(t, int32(5), v.Value())
+	case <-time.After(10 * time.Second):
+		t.Fatal("no client connected")
+	}
+
+	select {
+	case c := <-clients:
+		c.Close(ctx)
+		t.Fatal("unexpected client")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,88 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSecureChannelRenewal performs an integration test to verify that
+// the renewal of the security token is reported.
+func TestSecureChannelRenewal(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	renewed := make(chan [2]uint32, 1)
+	c, err := opcua.NewClient("opc.tcp://localhost:4840",
+		opcua.SecurityMode(ua.MessageSecurityModeNone),
+		opcua.Lifetime(2*time.Second),
+		opcua.SecureChannelRenewedHandler(func(old, new uint32) {
+			select {
+			case renewed <- [2]uint32{old, new}:
+			default:
+			}
+		}),
+	)
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	info, err := c.SecureChannelInfo()
+	require.NoError(t, err, "SecureChannelInfo failed")
+	require.NotZero(t, info.ChannelID)
+	require.Equal(t, 2*time.Second, info.RevisedLifetime)
+
+	select {
+	case ids := <-renewed:
+		require.Equal(t, info.TokenID, ids[0])
+
+		info2, err := c.SecureChannelInfo()
+		require.NoError(t, err, "SecureChannelInfo failed")
+		require.Equal(t, info.ChannelID, info2.ChannelID)
+		require.Equal(t, ids[1], info2.TokenID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("security token was not renewed")
+	}
+}
+
+// TestEndpoint checks that the endpoint and the certificate of the
+// server are available after connect.
+func TestEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+	require.Nil(t, c.Endpoint())
+	require.Nil(t, c.ServerCertificate())
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	ep := c.Endpoint()
+	require.NotNil(t, ep)
+	require.Equal(t, ua.SecurityPolicyURINone, ep.SecurityPolicyURI)
+	require.Equal(t, ua.MessageSecurityModeNone, ep.SecurityMode)
+	require.Equal(t, "opc.tcp://localhost:4840", ep.EndpointURL)
+
+	// the test server has no certificate
+	require.Nil(t, c.ServerCertificate())
+}
@@ -0,0 +1,153 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/server"
+	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uapolicy"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSecurityPolicies connects to the server with every supported
+// security policy and mode to catch signing and encryption mismatches.
+func TestSecurityPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	cert, key, err := opcua.GenerateCert("urn:gopcua:server", 2048, time.Hour)
+	require.NoError(t, err, "GenerateCert failed")
+
+	srv := startServer(server.Certificate(cert), server.PrivateKey(key))
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	clientCert, clientKey, err := opcua.GenerateCert("urn:gopcua:client", 2048, time.Hour)
+	require.NoError(t, err, "GenerateCert failed")
+
+	for _, uri := range uapolicy.SupportedPolicies() {
+		if uri == ua.SecurityPolicyURINone {
+			continue
+		}
+		for _, mode := range []ua.MessageSecurityMode{ua.MessageSecurityModeSign, ua.MessageSecurityModeSignAndEncrypt} {
+			name := strings.TrimPrefix(uri, ua.SecurityPolicyURIPrefix) + "/" + mode.String()
+			t.Run(name, func(t *testing.T) {
+				c, err := opcua.NewClient("opc.tcp://localhost:4840",
+					opcua.SecurityPolicy(uri),
+					opcua.SecurityMode(mode),
+					opcua.Certificate(clientCert),
+					opcua.PrivateKey(clientKey),
+					opcua.RemoteCertificate(cert),
+					opcua.AuthAnonymous(),
+				)
+				require.NoError(t, err, "NewClient failed")
+
+				err = c.Connect(ctx)
+				require.NoError(t, err, "Connect failed")
+				defer c.Close(ctx)
+
+				v, err := c.ReadValue(ctx, ua.NewStringNodeID(1, "rw_int32"))
+				require.NoError(t, err, "ReadValue failed")
+				require.Equal(t, int32(5), v.Value())
+				require.Equal(t, uri, c.Endpoint().SecurityPolicyURI)
+				require.Equal(t, mode, c.Endpoint().SecurityMode)
+			})
+		}
+	}
+}
+
+// TestCertificateChain connects to the server with a client certificate
+// which is sent together with the certificate of its issuer.
+func TestCertificateChain(t *testing.T) {
+	ctx := context.Background()
+
+	cert, key, err := opcua.GenerateCert("urn:gopcua:server", 2048, time.Hour)
+	require.NoError(t, err, "GenerateCert failed")
+
+	srv := startServer(server.Certificate(cert), server.PrivateKey(key))
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	clientCert, clientKey, err := opcua.GenerateCert("urn:gopcua:client", 2048, time.Hour)
+	require.NoError(t, err, "GenerateCert failed")
+	caCert, _, err := opcua.GenerateCert("urn:gopcua:ca", 2048, time.Hour)
+	require.NoError(t, err, "GenerateCert failed")
+
+	for _, mode := range []ua.MessageSecurityMode{ua.MessageSecurityModeSign, ua.MessageSecurityModeSignAndEncrypt} {
+		t.Run(mode.String(), func(t *testing.T) {
+			c, err := opcua.NewClient("opc.tcp://localhost:4840",
+				opcua.SecurityPolicy(ua.SecurityPolicyURIBasic256Sha256),
+				opcua.SecurityMode(mode),
+				opcua.CertificateChain([][]byte{clientCert, caCert}),
+				opcua.PrivateKey(clientKey),
+				opcua.RemoteCertificate(cert),
+				opcua.AuthAnonymous(),
+			)
+			require.NoError(t, err, "NewClient failed")
+
+			err = c.Connect(ctx)
+			require.NoError(t, err, "Connect failed")
+			defer c.Close(ctx)
+
+			v, err := c.ReadValue(ctx, ua.NewStringNodeID(1, "rw_int32"))
+			require.NoError(t, err, "ReadValue failed")
+			require.Equal(t, int32(5), v.Value())
+		})
+	}
+}
+
+// TestTrustStore rejects an unknown server certificate and connects
+// after the certificate has been moved to the trusted folder.
+func TestTrustStore(t *testing.T) {
+	ctx := context.Background()
+
+	cert, key, err := opcua.GenerateCert("urn:gopcua:server", 2048, time.Hour)
+	require.NoError(t, err, "GenerateCert failed")
+
+	srv := startServer(server.Certificate(cert), server.PrivateKey(key))
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	clientCert, clientKey, err := opcua.GenerateCert("urn:gopcua:client", 2048, time.Hour)
+	require.NoError(t, err, "GenerateCert failed")
+
+	dir := t.TempDir()
+	connect := func() error {
+		c, err := opcua.NewClient("opc.tcp://localhost:4840",
+			opcua.SecurityPolicy(ua.SecurityPolicyURIBasic256Sha256),
+			opcua.SecurityMode(ua.MessageSecurityModeSignAndEncrypt),
+			opcua.Certificate(clientCert),
+			opcua.PrivateKey(clientKey),
+			opcua.RemoteCertificate(cert),
+			opcua.TrustStore(dir),
+			opcua.AutoReconnect(false),
+			opcua.AuthAnonymous(),
+		)
+		require.NoError(t, err, "NewClient failed")
+		if err := c.Connect(ctx); err != nil {
+			return err
+		}
+		return c.Close(ctx)
+	}
+
+	err = connect()
+	require.ErrorIs(t, err, ua.StatusBadCertificateUntrusted)
+
+	rejected, err := filepath.Glob(filepath.Join(dir, "rejected", "certs", "*.der"))
+	require.NoError(t, err)
+	require.Len(t, rejected, 1)
+	require.NoError(t, os.Rename(rejected[0], filepath.Join(dir, "trusted", "certs", "server.der")))
+
+	require.NoError(t, connect(), "Connect failed")
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/gopcua/opcua/ua"
 )
 
-func startServer() *server.Server {
+// startServer starts the test server with additional options,
+// e.g. a server certificate.
+func startServer(extra ...server.Option) *server.Server {
 	var opts []server.Option
 	port := 4840
 
@@ -42,6 +44,7 @@ func startServer() *server.Server {
 	opts = append(opts,
 		server.EndPoint("localhost", port),
 	)
+	opts = append(opts, extra...)
 
 	s := server.New(opts...)
 
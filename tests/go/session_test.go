@@ -0,0 +1,230 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChangeUser performs an integration test to change the user
+// identity of an active session.
+func TestChangeUser(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	err = c.ChangeUser(ctx, ua.UserTokenTypeIssuedToken, opcua.AuthIssuedToken([]byte("token")))
+	require.Error(t, err, "issued tokens are not enabled on the server")
+
+	err = c.ChangeUser(ctx, ua.UserTokenTypeAnonymous, opcua.AuthAnonymous())
+	require.NoError(t, err, "ChangeUser failed")
+
+	v, err := c.Node(ua.NewStringNodeID(1, "rw_int32")).Value(ctx)
+	require.NoError(t, err, "Read failed")
+	require.Equal(t, int32(5), v.Value())
+}
+
+// TestChangeUserReconnect checks that the session is recreated with the
+// changed user identity after the server has been restarted.
+func TestChangeUserReconnect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer func() { srv.Close() }()
+
+	time.Sleep(2 * time.Second)
+
+	p, err := newDropProxy("localhost:4840")
+	require.NoError(t, err, "newDropProxy failed")
+	defer p.Close()
+
+	c, err := opcua.NewClient("opc.tcp://"+p.Addr(),
+		opcua.SecurityMode(ua.MessageSecurityModeNone),
+		opcua.ReconnectInterval(100*time.Millisecond),
+	)
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	err = c.ChangeUser(ctx, ua.UserTokenTypeUserName, opcua.AuthUsername("user", "pass"))
+	require.NoError(t, err, "ChangeUser failed")
+	s := c.Session()
+
+	srv.Close()
+	p.Drop()
+	srv = startServer()
+
+	require.Eventually(t, func() bool {
+		return c.State() == opcua.Connected && c.Session() != nil && c.Session() != s
+	}, 30*time.Second, 100*time.Millisecond, "session not recreated")
+
+	v, err := c.Node(ua.NewStringNodeID(1, "rw_int32")).Value(ctx)
+	require.NoError(t, err, "Read failed")
+	require.Equal(t, int32(5), v.Value())
+}
+
+// TestShutdown checks that a client with an active subscription is
+// closed gracefully.
+func TestShutdown(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+
+	notifs := make(chan *opcua.PublishNotificationData, 10)
+	sub, err := c.Subscribe(ctx, &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}, notifs)
+	require.NoError(t, err, "Subscribe failed")
+
+	req := opcua.NewMonitoredItemCreateRequestWithDefaults(ua.NewStringNodeID(1, "rw_int32"), ua.AttributeIDValue, 1)
+	_, err = sub.Monitor(ctx, ua.TimestampsToReturnBoth, req)
+	require.NoError(t, err, "Monitor failed")
+
+	// wait for a notification which needs to be acknowledged
+	select {
+	case n := <-notifs:
+		require.NoError(t, n.Error)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for notification")
+	}
+
+	sctx, scancel := context.WithTimeout(ctx, 5*time.Second)
+	defer scancel()
+	require.NoError(t, c.Shutdown(sctx), "Shutdown failed")
+	require.NoError(t, sctx.Err(), "Shutdown did not finish in time")
+	require.Equal(t, opcua.Closed, c.State())
+	require.Empty(t, c.SubscriptionIDs())
+}
+
+// TestSessionless checks that a client can read and browse without
+// a session if the server allows it.
+func TestSessionless(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone), opcua.Sessionless(true))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	require.Nil(t, c.Session(), "session created")
+	require.Equal(t, opcua.Connected, c.State())
+	require.NotEmpty(t, c.Namespaces())
+
+	v, err := c.ReadValue(ctx, ua.NewStringNodeID(1, "rw_int32"))
+	require.NoError(t, err, "ReadValue failed")
+	require.Equal(t, int32(5), v.Value())
+
+	refs, err := c.Node(ua.NewNumericNodeID(0, id.ObjectsFolder)).References(ctx, id.HierarchicalReferences, ua.BrowseDirectionForward, ua.NodeClassAll, true)
+	require.NoError(t, err, "References failed")
+	require.NotEmpty(t, refs)
+}
+
+// TestPing checks that Ping succeeds for a connected client and fails
+// after the client has been closed.
+func TestPing(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+
+	require.NoError(t, c.Ping(ctx), "Ping failed")
+
+	c.Close(ctx)
+	require.Error(t, c.Ping(ctx), "Ping succeeded after Close")
+}
+
+// TestServerTime checks the server time and the clock skew which must
+// be small since the server runs on the same host.
+func TestServerTime(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	now, err := c.ServerTime(ctx)
+	require.NoError(t, err, "ServerTime failed")
+	require.WithinDuration(t, time.Now(), now, time.Second)
+
+	skew, err := c.ClockSkew(ctx)
+	require.NoError(t, err, "ClockSkew failed")
+	require.Less(t, skew.Abs(), time.Second)
+}
+
+// TestEndpointURLOverride connects to a server which is reachable under a
+// different address than the endpoint url.
+func TestEndpointURLOverride(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://opcua.invalid:4840",
+		opcua.SecurityMode(ua.MessageSecurityModeNone),
+		opcua.EndpointURLOverride("opc.tcp://localhost:4840"),
+	)
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	v, err := c.Node(ua.NewStringNodeID(1, "rw_int32")).Value(ctx)
+	require.NoError(t, err, "Read failed")
+	require.Equal(t, int32(5), v.Value())
+}
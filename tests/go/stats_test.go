@@ -45,6 +45,7 @@ func TestStats(t *testing.T) {
 		"SecureChannel":    newExpVarInt(3),
 		"Session":          newExpVarInt(4),
 		"State":            newExpVarInt(0),
+		"PendingRequests":  newExpVarInt(0),
 	}
 
 	got := map[string]expvar.Var{}
@@ -0,0 +1,432 @@
+//go:build integration
+// +build integration
+
+package uatest2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/server"
+	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uasc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMonitorWithInitial checks that the initial value of a monitored
+// item is returned synchronously and that updates follow on the channel.
+func TestMonitorWithInitial(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	notifs := make(chan *opcua.PublishNotificationData, 10)
+	sub, err := c.Subscribe(ctx, &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}, notifs)
+	require.NoError(t, err, "Subscribe failed")
+	defer sub.Cancel(ctx)
+
+	id := ua.NewStringNodeID(1, "rw_int32")
+	dv, updates, err := sub.MonitorWithInitial(ctx, id, ua.TimestampsToReturnBoth)
+	require.NoError(t, err, "MonitorWithInitial failed")
+	require.Equal(t, int32(5), dv.Value.Value())
+
+	status, err := c.WriteValue(ctx, id, int32(7))
+	require.NoError(t, err, "WriteValue failed")
+	require.Equal(t, ua.StatusOK, status)
+
+	select {
+	case dv, ok := <-updates:
+		require.True(t, ok, "updates closed")
+		require.Equal(t, int32(7), dv.Value.Value())
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for update")
+	}
+
+	require.Empty(t, notifs, "values were sent to the subscription channel")
+}
+
+// TestMonitorWithCallback checks that the values of a monitored item
+// are delivered to its callback.
+func TestMonitorWithCallback(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	notifs := make(chan *opcua.PublishNotificationData, 10)
+	sub, err := c.Subscribe(ctx, &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}, notifs)
+	require.NoError(t, err, "Subscribe failed")
+	defer sub.Cancel(ctx)
+
+	values := make(chan *ua.DataValue, 10)
+	id := ua.NewStringNodeID(1, "rw_int32")
+	_, err = sub.MonitorWithCallback(ctx, id, func(dv *ua.DataValue) { values <- dv })
+	require.NoError(t, err, "MonitorWithCallback failed")
+
+	next := func() *ua.DataValue {
+		select {
+		case dv := <-values:
+			return dv
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for value")
+			return nil
+		}
+	}
+	require.Equal(t, int32(5), next().Value.Value())
+
+	status, err := c.WriteValue(ctx, id, int32(7))
+	require.NoError(t, err, "WriteValue failed")
+	require.Equal(t, ua.StatusOK, status)
+	require.Equal(t, int32(7), next().Value.Value())
+
+	require.Empty(t, notifs, "values were sent to the subscription channel")
+}
+
+// TestSetMonitoringMode checks that the client tracks the monitoring
+// mode of the monitored items.
+func TestSetMonitoringMode(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	notifs := make(chan *opcua.PublishNotificationData, 10)
+	sub, err := c.Subscribe(ctx, &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}, notifs)
+	require.NoError(t, err, "Subscribe failed")
+	defer sub.Cancel(ctx)
+
+	item := opcua.NewMonitoredItemCreateRequestWithDefaults(ua.NewStringNodeID(1, "rw_int32"), ua.AttributeIDValue, 1)
+	res, err := sub.Monitor(ctx, ua.TimestampsToReturnBoth, item)
+	require.NoError(t, err, "Monitor failed")
+	require.Equal(t, ua.StatusOK, res.Results[0].StatusCode)
+	itemID := res.Results[0].MonitoredItemID
+
+	mode, ok := sub.MonitoringMode(itemID)
+	require.True(t, ok)
+	require.Equal(t, ua.MonitoringModeReporting, mode)
+
+	mres, err := sub.SetMonitoringMode(ctx, ua.MonitoringModeDisabled, itemID)
+	require.NoError(t, err, "SetMonitoringMode failed")
+	require.Equal(t, []ua.StatusCode{ua.StatusOK}, mres.Results)
+
+	mode, ok = sub.MonitoringMode(itemID)
+	require.True(t, ok)
+	require.Equal(t, ua.MonitoringModeDisabled, mode)
+
+	_, ok = sub.MonitoringMode(itemID + 1)
+	require.False(t, ok)
+}
+
+// TestDeleteSubscriptions checks the removal of monitored items by
+// client handle and the bulk deletion of subscriptions.
+func TestDeleteSubscriptions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	params := &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}
+	sub1, err := c.Subscribe(ctx, params, make(chan *opcua.PublishNotificationData, 10))
+	require.NoError(t, err, "Subscribe failed")
+	sub2, err := c.Subscribe(ctx, params, make(chan *opcua.PublishNotificationData, 10))
+	require.NoError(t, err, "Subscribe failed")
+
+	_, err = sub1.Monitor(ctx, ua.TimestampsToReturnBoth,
+		opcua.NewMonitoredItemCreateRequestWithDefaults(ua.NewStringNodeID(1, "rw_int32"), ua.AttributeIDValue, 1),
+		opcua.NewMonitoredItemCreateRequestWithDefaults(ua.NewStringNodeID(1, "rw_bool"), ua.AttributeIDValue, 2),
+	)
+	require.NoError(t, err, "Monitor failed")
+
+	require.NoError(t, sub1.RemoveMonitoredItems(ctx, 1), "RemoveMonitoredItems failed")
+	require.Error(t, sub1.RemoveMonitoredItems(ctx, 1), "removed item is still known")
+
+	err = c.DeleteSubscriptions(ctx, sub1.SubscriptionID, sub2.SubscriptionID)
+	require.NoError(t, err, "DeleteSubscriptions failed")
+	require.Empty(t, c.SubscriptionIDs())
+}
+
+// TestMonitorMany checks that different attributes of the same node
+// are monitored with a single request and that the results are
+// aligned with the items.
+func TestMonitorMany(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	notifs := make(chan *opcua.PublishNotificationData, 10)
+	sub, err := c.Subscribe(ctx, &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}, notifs)
+	require.NoError(t, err, "Subscribe failed")
+	defer sub.Cancel(ctx)
+
+	id := ua.NewStringNodeID(1, "rw_int32")
+	items := []*ua.ReadValueID{
+		{NodeID: id, AttributeID: ua.AttributeIDValue},
+		{NodeID: id, AttributeID: ua.AttributeIDBrowseName},
+	}
+	res, err := sub.MonitorMany(ctx, items, ua.TimestampsToReturnBoth)
+	require.NoError(t, err, "MonitorMany failed")
+	require.Len(t, res, len(items))
+	require.Equal(t, ua.StatusOK, res[0].StatusCode)
+	require.Equal(t, ua.StatusOK, res[1].StatusCode)
+	require.Equal(t, ua.AttributeIDBrowseName, res[1].ItemToMonitor.AttributeID)
+	require.NotEqual(t, res[0].ClientHandle, res[1].ClientHandle)
+	require.Len(t, sub.Items(), 2)
+
+	values := make(map[uint32]*ua.DataValue)
+	for len(values) < 2 {
+		select {
+		case n := <-notifs:
+			require.NoError(t, n.Error)
+			if dc, ok := n.Value.(*ua.DataChangeNotification); ok {
+				for _, item := range dc.MonitoredItems {
+					values[item.ClientHandle] = item.Value
+				}
+			}
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for values")
+		}
+	}
+	require.Equal(t, int32(5), values[res[0].ClientHandle].Value.Value())
+	name, ok := values[res[1].ClientHandle].Value.Value().(*ua.QualifiedName)
+	require.True(t, ok, "browse name is not a qualified name")
+	require.Equal(t, "rw_int32", name.Name)
+
+	_, err = sub.MonitorMany(ctx, []*ua.ReadValueID{{}}, ua.TimestampsToReturnBoth)
+	require.Error(t, err, "item without node id")
+}
+
+// transferHandler returns a TransferSubscriptions handler for the test
+// server which does not support the transfer of subscriptions. It moves
+// them to the session of the request and calls transferred, if set,
+// before it responds.
+func transferHandler(srv *server.Server, transferred func()) server.Handler {
+	return func(sc *uasc.SecureChannel, r ua.Request, reqID uint32) (ua.Response, error) {
+		req := r.(*ua.TransferSubscriptionsRequest)
+		sess := srv.Session(req.RequestHeader)
+		res := &ua.TransferSubscriptionsResponse{ResponseHeader: responseHeader(req.RequestHeader)}
+
+		subs := srv.SubscriptionService
+		subs.Mu.Lock()
+		for _, subID := range req.SubscriptionIDs {
+			sub, ok := subs.Subs[subID]
+			if !ok {
+				res.Results = append(res.Results, &ua.TransferResult{StatusCode: ua.StatusBadSubscriptionIDInvalid})
+				continue
+			}
+			sub.Mu.Lock()
+			sub.Session, sub.Channel = sess, sc
+			sub.Mu.Unlock()
+			res.Results = append(res.Results, &ua.TransferResult{StatusCode: ua.StatusOK})
+		}
+		subs.Mu.Unlock()
+
+		if transferred != nil {
+			transferred()
+		}
+		return res, nil
+	}
+}
+
+// TestAttachSubscriptions checks that the notifications of a subscription
+// which has been transferred from another session are delivered.
+func TestAttachSubscriptions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := server.New(
+		server.EndPoint("localhost", 4840),
+		server.EnableSecurity("None", ua.MessageSecurityModeNone),
+		server.EnableAuthMode(ua.UserTokenTypeAnonymous),
+	)
+	nodeNS := server.NewNodeNameSpace(srv, "NodeNamespace")
+	srv.AddNamespace(nodeNS)
+	nodeNS.Objects().AddRef(nodeNS.AddNewVariableStringNode("rw_int32", int32(5)), id.HasComponent, true)
+
+	srv.RegisterHandler(id.TransferSubscriptionsRequest_Encoding_DefaultBinary, transferHandler(srv, nil))
+	require.NoError(t, srv.Start(ctx), "Start failed")
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	connect := func() *opcua.Client {
+		c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+		require.NoError(t, err, "NewClient failed")
+		require.NoError(t, c.Connect(ctx), "Connect failed")
+		return c
+	}
+
+	// the subscription of the first client is abandoned
+	c1 := connect()
+	defer c1.Close(ctx)
+
+	nodeID := ua.NewStringNodeID(nodeNS.ID(), "rw_int32")
+	sub1, err := c1.Subscribe(ctx, &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}, make(chan *opcua.PublishNotificationData, 10))
+	require.NoError(t, err, "Subscribe failed")
+	_, err = sub1.Monitor(ctx, ua.TimestampsToReturnBoth, opcua.NewMonitoredItemCreateRequestWithDefaults(nodeID, ua.AttributeIDValue, 42))
+	require.NoError(t, err, "Monitor failed")
+
+	c2 := connect()
+	defer c2.Close(ctx)
+
+	notifs := make(chan *opcua.PublishNotificationData, 10)
+	subs, err := c2.AttachSubscriptions(ctx, []uint32{sub1.SubscriptionID, 9999}, &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}, notifs)
+	require.ErrorIs(t, err, ua.StatusBadSubscriptionIDInvalid)
+	require.Len(t, subs, 2)
+	require.NotNil(t, subs[0])
+	require.Nil(t, subs[1])
+	require.Equal(t, sub1.SubscriptionID, subs[0].SubscriptionID)
+	require.Equal(t, []uint32{sub1.SubscriptionID}, c2.SubscriptionIDs())
+
+	_, err = c2.Write(ctx, &ua.WriteRequest{
+		NodesToWrite: []*ua.WriteValue{{
+			NodeID:      nodeID,
+			AttributeID: ua.AttributeIDValue,
+			Value:       &ua.DataValue{EncodingMask: ua.DataValueValue, Value: ua.MustVariant(int32(7))},
+		}},
+	})
+	require.NoError(t, err, "Write failed")
+
+	for {
+		select {
+		case n := <-notifs:
+			require.NoError(t, n.Error)
+			require.Equal(t, sub1.SubscriptionID, n.SubscriptionID)
+			dc, ok := n.Value.(*ua.DataChangeNotification)
+			if !ok || len(dc.MonitoredItems) == 0 || dc.MonitoredItems[0].Value.Value.Value() != int32(7) {
+				continue
+			}
+			require.Equal(t, uint32(42), dc.MonitoredItems[0].ClientHandle)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for the notification of the attached subscription")
+		}
+		break
+	}
+	require.NoError(t, subs[0].Cancel(ctx), "Cancel failed")
+}
+
+// TestAttachSubscriptionsEarlyNotification checks that a notification of
+// an attached subscription which arrives before the transfer response is
+// delivered.
+func TestAttachSubscriptionsEarlyNotification(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := server.New(
+		server.EndPoint("localhost", 4840),
+		server.EnableSecurity("None", ua.MessageSecurityModeNone),
+		server.EnableAuthMode(ua.UserTokenTypeAnonymous),
+	)
+	nodeNS := server.NewNodeNameSpace(srv, "NodeNamespace")
+	srv.AddNamespace(nodeNS)
+	nodeNS.Objects().AddRef(nodeNS.AddNewVariableStringNode("rw_int32", int32(5)), id.HasComponent, true)
+	nodeID := ua.NewStringNodeID(nodeNS.ID(), "rw_int32")
+
+	// change the value after the transfer and wait for its notification
+	// to be published before the transfer response is sent
+	srv.RegisterHandler(id.TransferSubscriptionsRequest_Encoding_DefaultBinary, transferHandler(srv, func() {
+		nodeNS.SetAttribute(nodeID, ua.AttributeIDValue, &ua.DataValue{EncodingMask: ua.DataValueValue, Value: ua.MustVariant(int32(7))})
+		time.Sleep(time.Second)
+	}))
+	require.NoError(t, srv.Start(ctx), "Start failed")
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	connect := func() *opcua.Client {
+		c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+		require.NoError(t, err, "NewClient failed")
+		require.NoError(t, c.Connect(ctx), "Connect failed")
+		return c
+	}
+
+	c1 := connect()
+	defer c1.Close(ctx)
+
+	params := &opcua.SubscriptionParameters{Interval: 100 * time.Millisecond}
+	sub1, err := c1.Subscribe(ctx, params, make(chan *opcua.PublishNotificationData, 10))
+	require.NoError(t, err, "Subscribe failed")
+	_, err = sub1.Monitor(ctx, ua.TimestampsToReturnBoth, opcua.NewMonitoredItemCreateRequestWithDefaults(nodeID, ua.AttributeIDValue, 42))
+	require.NoError(t, err, "Monitor failed")
+
+	// a subscription of the second client keeps publish requests
+	// queued on the server which the attached subscription answers
+	c2 := connect()
+	defer c2.Close(ctx)
+	_, err = c2.Subscribe(ctx, params, make(chan *opcua.PublishNotificationData, 10))
+	require.NoError(t, err, "Subscribe failed")
+	time.Sleep(500 * time.Millisecond)
+
+	notifs := make(chan *opcua.PublishNotificationData, 10)
+	_, err = c2.AttachSubscriptions(ctx, []uint32{sub1.SubscriptionID}, params, notifs)
+	require.NoError(t, err, "AttachSubscriptions failed")
+
+	for {
+		select {
+		case n := <-notifs:
+			require.NoError(t, n.Error)
+			dc, ok := n.Value.(*ua.DataChangeNotification)
+			if !ok || len(dc.MonitoredItems) == 0 || dc.MonitoredItems[0].Value.Value.Value() != int32(7) {
+				continue
+			}
+			require.Equal(t, uint32(42), dc.MonitoredItems[0].ClientHandle)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for the early notification of the attached subscription")
+		}
+		break
+	}
+}
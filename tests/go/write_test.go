@@ -68,6 +68,47 @@ func TestWrite(t *testing.T) {
 	}
 }
 
+// TestWriteValues performs an integration test to write values
+// with the WriteValue and WriteValues helpers.
+func TestWriteValues(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	rwInt := ua.NewStringNodeID(1, "rw_int32")
+	rwBool := ua.NewStringNodeID(1, "rw_bool")
+	roBool := ua.NewStringNodeID(1, "ro_bool")
+
+	status, err := c.WriteValue(ctx, rwInt, int32(7))
+	require.NoError(t, err, "WriteValue failed")
+	require.Equal(t, ua.StatusOK, status)
+	testRead(t, ctx, c, int32(7), rwInt)
+
+	res, err := c.WriteValues(ctx, map[*ua.NodeID]interface{}{
+		rwInt:  &ua.DataValue{Value: ua.MustVariant(int32(8))},
+		rwBool: ua.MustVariant(false),
+		roBool: false,
+	})
+	require.NoError(t, err, "WriteValues failed")
+	require.Equal(t, map[*ua.NodeID]ua.StatusCode{
+		rwInt:  ua.StatusOK,
+		rwBool: ua.StatusOK,
+		roBool: ua.StatusBadUserAccessDenied,
+	}, res)
+	testRead(t, ctx, c, int32(8), rwInt)
+	testRead(t, ctx, c, false, rwBool)
+}
+
 func testWrite(t *testing.T, ctx context.Context, c *opcua.Client, status ua.StatusCode, req *ua.WriteRequest) {
 	t.Helper()
 
@@ -75,3 +116,73 @@ func testWrite(t *testing.T, ctx context.Context, c *opcua.Client, status ua.Sta
 	require.NoError(t, err, "Write failed")
 	require.Equal(t, status, resp.Results[0], "status not equal")
 }
+
+// TestBatchWrite performs an integration test to write values in
+// multiple chunks with BatchWrite.
+func TestBatchWrite(t *testing.T) {
+	ctx := context.Background()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	rwInt := ua.NewStringNodeID(1, "rw_int32")
+	rwBool := ua.NewStringNodeID(1, "rw_bool")
+	unknown := ua.NewStringNodeID(1, "unknown")
+
+	write := func(id *ua.NodeID, v interface{}) *ua.WriteValue {
+		return &ua.WriteValue{
+			NodeID:      id,
+			AttributeID: ua.AttributeIDValue,
+			Value:       &ua.DataValue{EncodingMask: ua.DataValueValue, Value: ua.MustVariant(v)},
+		}
+	}
+	res, err := c.BatchWrite(ctx, []*ua.WriteValue{
+		write(rwInt, int32(9)),
+		write(unknown, int32(1)),
+		write(rwBool, false),
+	}, opcua.WriteChunkSize(1), opcua.WriteConcurrency(2))
+	require.NoError(t, err, "BatchWrite failed")
+	require.Equal(t, []ua.StatusCode{ua.StatusOK, ua.StatusBadNodeIDUnknown, ua.StatusOK}, res)
+	testRead(t, ctx, c, int32(9), rwInt)
+	testRead(t, ctx, c, false, rwBool)
+}
+
+// TestWriteRequestTooLarge checks that a request which exceeds the
+// message size limit of the server fails before it is sent.
+func TestWriteRequestTooLarge(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv := startServer()
+	defer srv.Close()
+
+	time.Sleep(2 * time.Second)
+
+	c, err := opcua.NewClient("opc.tcp://localhost:4840", opcua.SecurityMode(ua.MessageSecurityModeNone))
+	require.NoError(t, err, "NewClient failed")
+
+	err = c.Connect(ctx)
+	require.NoError(t, err, "Connect failed")
+	defer c.Close(ctx)
+
+	max, err := c.MaxRequestSize()
+	require.NoError(t, err, "MaxRequestSize failed")
+	require.NotZero(t, max)
+
+	_, err = c.WriteValue(ctx, ua.NewStringNodeID(1, "rw_bool"), make([]byte, max+1))
+	require.ErrorIs(t, err, ua.StatusBadRequestTooLarge)
+
+	// the connection is still usable
+	status, err := c.WriteValue(ctx, ua.NewStringNodeID(1, "rw_int32"), int32(9))
+	require.NoError(t, err, "WriteValue failed")
+	require.Equal(t, ua.StatusOK, status)
+}
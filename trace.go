@@ -0,0 +1,119 @@
+package opcua
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+// RequestTracer creates a span for every service request of the client.
+//
+// The interface decouples the client from a tracing library. An adapter
+// for an OpenTelemetry trace.Tracer starts the span with the tracer,
+// sets the attributes with the attribute package and records the error
+// and the status of the span in End.
+type RequestTracer interface {
+	// Start creates a span with the given name as child of the span
+	// in ctx and returns a context which contains the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span created by a RequestTracer.
+type Span interface {
+	// SetAttribute sets an attribute of the span. The value is a string,
+	// a bool or an integer.
+	SetAttribute(key string, value any)
+
+	// End completes the span. err is the error of the request or nil.
+	End(err error)
+}
+
+// Attribute keys of the spans of the service requests.
+const (
+	SpanAttrService    = "opcua.service"
+	SpanAttrNodeCount  = "opcua.node_count"
+	SpanAttrStatusCode = "opcua.status_code"
+)
+
+// traceRequest starts a span for the request if the client has a tracer.
+// It returns the context for the request and a function which must be
+// called with the response handler error or the request error to end
+// the span. h is wrapped to record the service result of the response.
+func (c *Client) traceRequest(ctx context.Context, req ua.Request, h func(ua.Response) error) (context.Context, func(ua.Response) error, func(error)) {
+	if c.tracer == nil {
+		return ctx, h, func(error) {}
+	}
+
+	service := serviceName(req)
+	ctx, span := c.tracer.Start(ctx, "opcua."+service)
+	span.SetAttribute(SpanAttrService, service)
+	if n := requestNodeCount(req); n >= 0 {
+		span.SetAttribute(SpanAttrNodeCount, n)
+	}
+
+	status := ua.StatusOK
+	th := func(v ua.Response) error {
+		if v != nil && v.Header() != nil {
+			status = v.Header().ServiceResult
+		}
+		if h == nil {
+			return nil
+		}
+		return h(v)
+	}
+	end := func(err error) {
+		if code, ok := err.(ua.StatusCode); ok {
+			status = code
+		} else if err != nil && status == ua.StatusOK {
+			status = ua.StatusBad
+		}
+		span.SetAttribute(SpanAttrStatusCode, uint32(status))
+		span.End(err)
+	}
+	return ctx, th, end
+}
+
+// serviceName returns the name of the service of a request,
+// e.g. "Read" for a *ua.ReadRequest.
+func serviceName(req ua.Request) string {
+	t := reflect.TypeOf(req)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return strings.TrimSuffix(t.Name(), "Request")
+}
+
+// requestNodeCount returns the number of operations of a request or -1
+// if the service does not operate on a list of nodes.
+func requestNodeCount(req ua.Request) int {
+	switch r := req.(type) {
+	case *ua.ReadRequest:
+		return len(r.NodesToRead)
+	case *ua.WriteRequest:
+		return len(r.NodesToWrite)
+	case *ua.BrowseRequest:
+		return len(r.NodesToBrowse)
+	case *ua.BrowseNextRequest:
+		return len(r.ContinuationPoints)
+	case *ua.TranslateBrowsePathsToNodeIDsRequest:
+		return len(r.BrowsePaths)
+	case *ua.CallRequest:
+		return len(r.MethodsToCall)
+	case *ua.HistoryReadRequest:
+		return len(r.NodesToRead)
+	case *ua.RegisterNodesRequest:
+		return len(r.NodesToRegister)
+	case *ua.UnregisterNodesRequest:
+		return len(r.NodesToUnregister)
+	case *ua.CreateMonitoredItemsRequest:
+		return len(r.ItemsToCreate)
+	case *ua.ModifyMonitoredItemsRequest:
+		return len(r.ItemsToModify)
+	case *ua.DeleteMonitoredItemsRequest:
+		return len(r.MonitoredItemIDs)
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,60 @@
+package opcua
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
+)
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	s := &testSpan{name: name, attrs: map[string]any{}}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+type testSpan struct {
+	name  string
+	attrs map[string]any
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttribute(key string, value any) { s.attrs[key] = value }
+func (s *testSpan) End(err error)                      { s.err, s.ended = err, true }
+
+func TestServiceName(t *testing.T) {
+	require.Equal(t, "Read", serviceName(&ua.ReadRequest{}))
+	require.Equal(t, "TranslateBrowsePathsToNodeIDs", serviceName(&ua.TranslateBrowsePathsToNodeIDsRequest{}))
+}
+
+func TestRequestNodeCount(t *testing.T) {
+	require.Equal(t, 2, requestNodeCount(&ua.ReadRequest{NodesToRead: make([]*ua.ReadValueID, 2)}))
+	require.Equal(t, 1, requestNodeCount(&ua.CallRequest{MethodsToCall: make([]*ua.CallMethodRequest, 1)}))
+	require.Equal(t, -1, requestNodeCount(&ua.PublishRequest{}))
+}
+
+func TestClient_Tracer(t *testing.T) {
+	tr := &testTracer{}
+	c, err := NewClient("opc.tcp://example.com:4840", Tracer(tr))
+	require.NoError(t, err)
+
+	_, err = c.Read(context.Background(), &ua.ReadRequest{NodesToRead: []*ua.ReadValueID{{NodeID: ua.NewNumericNodeID(0, 2258)}}})
+	require.ErrorIs(t, err, ua.StatusBadServerNotConnected)
+
+	require.Len(t, tr.spans, 1)
+	span := tr.spans[0]
+	require.Equal(t, "opcua.Read", span.name)
+	require.True(t, span.ended)
+	require.ErrorIs(t, span.err, ua.StatusBadServerNotConnected)
+	require.Equal(t, map[string]any{
+		SpanAttrService:    "Read",
+		SpanAttrNodeCount:  1,
+		SpanAttrStatusCode: uint32(ua.StatusBadServerNotConnected),
+	}, span.attrs)
+}
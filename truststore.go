@@ -0,0 +1,334 @@
+package opcua
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua/debug"
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uapolicy"
+	"golang.org/x/crypto/ocsp"
+)
+
+// trustStoreDirs are the directories of a certificate store
+// in the layout of Part 12, F.1.
+var trustStoreDirs = []string{
+	filepath.Join("trusted", "certs"),
+	filepath.Join("trusted", "crl"),
+	filepath.Join("issuers", "certs"),
+	filepath.Join("issuers", "crl"),
+	filepath.Join("rejected", "certs"),
+}
+
+// DefaultOCSPTimeout is the timeout of an OCSP query
+// of the trust store if OCSP is enabled.
+const DefaultOCSPTimeout = 10 * time.Second
+
+// maxOCSPResponseSize limits the size of an OCSP response.
+const maxOCSPResponseSize = 1 << 20
+
+// trustStore validates certificates against the trusted and issuer
+// certificates and the revocation lists in a directory.
+type trustStore struct {
+	dir string
+
+	// ocsp enables OCSP queries for certificates
+	// without a revocation list of their issuer.
+	ocsp       bool
+	httpClient *http.Client
+
+	// allowMissingCRL accepts certificates whose issuer has
+	// no revocation list if OCSP is disabled.
+	allowMissingCRL bool
+}
+
+// newTrustStore returns a store for dir and creates the
+// missing directories of the store layout.
+func newTrustStore(dir string) (*trustStore, error) {
+	if dir == "" {
+		return nil, errors.Errorf("trust store directory is empty")
+	}
+	for _, d := range trustStoreDirs {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0o700); err != nil {
+			return nil, errors.Errorf("trust store: %w", err)
+		}
+	}
+	return &trustStore{dir: dir, httpClient: &http.Client{Timeout: DefaultOCSPTimeout}}, nil
+}
+
+// verify validates the certificate and the chain presented by the
+// remote instance. The store is read on every call so that changes by
+// the operator take effect for the next secure channel.
+//
+// A certificate is trusted if it or one of its issuers is in the trusted
+// folder and no certificate of the chain has been revoked by its issuer,
+// see checkRevocation. Certificates of the issuer folder and the chain
+// presented by the remote instance are used to build the chain but are
+// not trusted by themselves. Untrusted certificates are written to the
+// rejected folder.
+func (s *trustStore) verify(cert *x509.Certificate, chains [][]*x509.Certificate) error {
+	trusted, err := s.certificates(filepath.Join("trusted", "certs"))
+	if err != nil {
+		return err
+	}
+	issuers, err := s.certificates(filepath.Join("issuers", "certs"))
+	if err != nil {
+		return err
+	}
+	var crls []*x509.RevocationList
+	for _, d := range []string{filepath.Join("trusted", "crl"), filepath.Join("issuers", "crl")} {
+		l, err := s.revocationLists(d)
+		if err != nil {
+			return err
+		}
+		crls = append(crls, l...)
+	}
+
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	for _, c := range trusted {
+		roots.AddCert(c)
+	}
+	for _, c := range issuers {
+		roots.AddCert(c)
+		intermediates.AddCert(c)
+	}
+	for _, chain := range chains {
+		for _, c := range chain {
+			if !c.Equal(cert) {
+				intermediates.AddCert(c)
+			}
+		}
+	}
+
+	name := cert.Subject.CommonName
+	verified, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		var invalid x509.CertificateInvalidError
+		var unknown x509.UnknownAuthorityError
+		switch {
+		case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+			return errors.Errorf("certificate %q: %s: %w", name, err, ua.StatusBadCertificateTimeInvalid)
+		case errors.As(err, &unknown):
+			s.reject(cert)
+			return errors.Errorf("certificate %q: %w", name, ua.StatusBadCertificateUntrusted)
+		default:
+			return errors.Errorf("certificate %q: %s: %w", name, err, ua.StatusBadCertificateInvalid)
+		}
+	}
+
+	for _, chain := range verified {
+		if !containsAny(trusted, chain) {
+			continue
+		}
+		return s.checkRevocation(chain, crls)
+	}
+	s.reject(cert)
+	return errors.Errorf("certificate %q: %w", name, ua.StatusBadCertificateUntrusted)
+}
+
+// reject writes the certificate to the rejected folder. The operator
+// approves the certificate by moving the file to the trusted folder.
+func (s *trustStore) reject(cert *x509.Certificate) {
+	name := hex.EncodeToString(uapolicy.Thumbprint(cert.Raw)) + ".der"
+	filename := filepath.Join(s.dir, "rejected", "certs", name)
+	if err := os.WriteFile(filename, cert.Raw, 0o600); err != nil {
+		debug.Printf("trust store: cannot store rejected certificate: %s", err)
+	}
+}
+
+// certificates returns the certificates of all DER and PEM
+// encoded files in the folder dir of the store.
+func (s *trustStore) certificates(dir string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	err := s.readDir(dir, func(filename string, b []byte) error {
+		if isPEM(filename) {
+			der, err := decodeCertificatePEM(b)
+			if err != nil {
+				return err
+			}
+			b = der
+		}
+		c, err := x509.ParseCertificates(b)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, c...)
+		return nil
+	})
+	return certs, err
+}
+
+// revocationLists returns the certificate revocation lists of all
+// DER and PEM encoded files in the folder dir of the store.
+func (s *trustStore) revocationLists(dir string) ([]*x509.RevocationList, error) {
+	var crls []*x509.RevocationList
+	err := s.readDir(dir, func(filename string, b []byte) error {
+		if !isPEM(filename) {
+			crl, err := x509.ParseRevocationList(b)
+			if err != nil {
+				return err
+			}
+			crls = append(crls, crl)
+			return nil
+		}
+		for {
+			block, rest := pem.Decode(b)
+			if block == nil {
+				return nil
+			}
+			if block.Type == "X509 CRL" {
+				crl, err := x509.ParseRevocationList(block.Bytes)
+				if err != nil {
+					return err
+				}
+				crls = append(crls, crl)
+			}
+			b = rest
+		}
+	})
+	return crls, err
+}
+
+// readDir calls fn with the name and the content of every
+// file in the folder dir of the store.
+func (s *trustStore) readDir(dir string, fn func(filename string, b []byte) error) error {
+	entries, err := os.ReadDir(filepath.Join(s.dir, dir))
+	if err != nil {
+		return errors.Errorf("trust store: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		filename := filepath.Join(s.dir, dir, e.Name())
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			return errors.Errorf("trust store: %w", err)
+		}
+		if err := fn(filename, b); err != nil {
+			return errors.Errorf("trust store: %s: %s", filename, err)
+		}
+	}
+	return nil
+}
+
+func isPEM(filename string) bool {
+	return strings.HasSuffix(filename, ".pem")
+}
+
+// containsAny returns true if one of the certificates of chain is in certs.
+func containsAny(certs, chain []*x509.Certificate) bool {
+	for _, c := range chain {
+		for _, t := range certs {
+			if c.Equal(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkRevocation returns an error if a certificate of the chain has
+// been revoked by its issuer or if its revocation status cannot be
+// determined.
+//
+// The status is taken from a current CRL of the issuer. Without one the
+// OCSP responders of the certificate are queried if OCSP is enabled.
+// Otherwise the status is unknown unless the issuer has no CRL at all
+// and missing CRLs are allowed.
+func (s *trustStore) checkRevocation(chain []*x509.Certificate, crls []*x509.RevocationList) error {
+	now := time.Now()
+	for i := 0; i < len(chain)-1; i++ {
+		c, issuer := chain[i], chain[i+1]
+
+		var current, expired bool
+		for _, crl := range crls {
+			if crl.CheckSignatureFrom(issuer) != nil {
+				continue
+			}
+			if !crl.NextUpdate.IsZero() && crl.NextUpdate.Before(now) {
+				expired = true
+				continue
+			}
+			current = true
+			for _, e := range crl.RevokedCertificateEntries {
+				if e.SerialNumber.Cmp(c.SerialNumber) == 0 {
+					return errors.Errorf("certificate %q: %w", c.Subject.CommonName, ua.StatusBadCertificateRevoked)
+				}
+			}
+		}
+
+		switch {
+		case current:
+			continue
+		case s.ocsp:
+			if err := s.checkOCSP(c, issuer); err != nil {
+				return err
+			}
+		case expired:
+			return errors.Errorf("certificate %q: crl of issuer %q has expired: %w", c.Subject.CommonName, issuer.Subject.CommonName, ua.StatusBadCertificateRevocationUnknown)
+		case !s.allowMissingCRL:
+			return errors.Errorf("certificate %q: no crl of issuer %q: %w", c.Subject.CommonName, issuer.Subject.CommonName, ua.StatusBadCertificateRevocationUnknown)
+		}
+	}
+	return nil
+}
+
+// checkOCSP queries the OCSP responders of the certificate and returns
+// an error if the certificate has been revoked or if none of the
+// responders knows its status.
+func (s *trustStore) checkOCSP(cert, issuer *x509.Certificate) error {
+	name := cert.Subject.CommonName
+	if len(cert.OCSPServer) == 0 {
+		return errors.Errorf("certificate %q: no ocsp responder: %w", name, ua.StatusBadCertificateRevocationUnknown)
+	}
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return errors.Errorf("certificate %q: %s: %w", name, err, ua.StatusBadCertificateRevocationUnknown)
+	}
+
+	for _, url := range cert.OCSPServer {
+		resp, err := s.queryOCSP(url, req, cert, issuer)
+		if err != nil {
+			debug.Printf("trust store: ocsp query for %q at %s failed: %s", name, url, err)
+			continue
+		}
+		switch resp.Status {
+		case ocsp.Good:
+			return nil
+		case ocsp.Revoked:
+			return errors.Errorf("certificate %q: %w", name, ua.StatusBadCertificateRevoked)
+		}
+	}
+	return errors.Errorf("certificate %q: ocsp status unknown: %w", name, ua.StatusBadCertificateRevocationUnknown)
+}
+
+// queryOCSP sends the OCSP request to the responder at url.
+func (s *trustStore) queryOCSP(url string, req []byte, cert, issuer *x509.Certificate) (*ocsp.Response, error) {
+	resp, err := s.httpClient.Post(url, "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("http status %s", resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	return ocsp.ParseResponseForCert(b, cert, issuer)
+}
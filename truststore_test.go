@@ -0,0 +1,264 @@
+package opcua
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+	"github.com/gopcua/opcua/uapolicy"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *rsa.PrivateKey
+}
+
+var testSerial int64
+
+// newTestCert creates a certificate signed by issuer or
+// a self-signed CA certificate if issuer is nil.
+func newTestCert(t *testing.T, name string, issuer *testCert, notAfter time.Time, ocspServer ...string) *testCert {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	testSerial++
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(testSerial),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  issuer == nil || name == "intermediate",
+		OCSPServer:            ocspServer,
+	}
+	parent, signer := tmpl, key
+	if issuer != nil {
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key}
+}
+
+func TestTrustStore(t *testing.T) {
+	valid := time.Now().Add(time.Hour)
+	ca := newTestCert(t, "ca", nil, valid)
+	intermediate := newTestCert(t, "intermediate", ca, valid)
+	server := newTestCert(t, "server", intermediate, valid)
+	selfSigned := newTestCert(t, "self-signed", nil, valid)
+	expired := newTestCert(t, "expired", nil, time.Now().Add(-time.Minute))
+
+	chain := func(certs ...*testCert) [][]*x509.Certificate {
+		var c []*x509.Certificate
+		for _, x := range certs {
+			c = append(c, x.cert)
+		}
+		return [][]*x509.Certificate{c}
+	}
+	write := func(t *testing.T, dir, folder, name string, b []byte) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, folder, name), b, 0o600))
+	}
+	newStore := func(t *testing.T) *trustStore {
+		t.Helper()
+		s, err := newTrustStore(t.TempDir())
+		require.NoError(t, err)
+		return s
+	}
+	writeCRL := func(t *testing.T, dir, folder string, issuer *testCert, revoked ...*testCert) {
+		t.Helper()
+		var entries []x509.RevocationListEntry
+		for _, c := range revoked {
+			entries = append(entries, x509.RevocationListEntry{SerialNumber: c.cert.SerialNumber, RevocationTime: time.Now()})
+		}
+		crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:                    big.NewInt(1),
+			ThisUpdate:                time.Now().Add(-time.Hour),
+			NextUpdate:                valid,
+			RevokedCertificateEntries: entries,
+		}, issuer.cert, issuer.key)
+		require.NoError(t, err)
+		write(t, dir, folder, issuer.cert.Subject.CommonName+".crl", crl)
+	}
+	rejected := func(t *testing.T, s *trustStore, c *testCert) string {
+		t.Helper()
+		return filepath.Join(s.dir, "rejected", "certs", hex.EncodeToString(uapolicy.Thumbprint(c.cert.Raw))+".der")
+	}
+
+	t.Run("reject and approve", func(t *testing.T) {
+		s := newStore(t)
+		err := s.verify(selfSigned.cert, chain(selfSigned))
+		require.ErrorIs(t, err, ua.StatusBadCertificateUntrusted)
+
+		// the operator approves the certificate
+		filename := rejected(t, s, selfSigned)
+		require.FileExists(t, filename)
+		require.NoError(t, os.Rename(filename, filepath.Join(s.dir, "trusted", "certs", "self-signed.der")))
+		require.NoError(t, s.verify(selfSigned.cert, chain(selfSigned)))
+	})
+
+	t.Run("trusted ca", func(t *testing.T) {
+		s := newStore(t)
+		write(t, s.dir, "trusted/certs", "ca.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+		writeCRL(t, s.dir, "trusted/crl", ca)
+		writeCRL(t, s.dir, "issuers/crl", intermediate)
+		require.NoError(t, s.verify(server.cert, chain(server, intermediate)))
+
+		// the intermediate CA is missing
+		err := s.verify(server.cert, chain(server))
+		require.ErrorIs(t, err, ua.StatusBadCertificateUntrusted)
+
+		write(t, s.dir, "issuers/certs", "intermediate.der", intermediate.cert.Raw)
+		require.NoError(t, s.verify(server.cert, chain(server)))
+	})
+
+	t.Run("issuer is not trusted", func(t *testing.T) {
+		s := newStore(t)
+		write(t, s.dir, "issuers/certs", "ca.der", ca.cert.Raw)
+		err := s.verify(server.cert, chain(server, intermediate))
+		require.ErrorIs(t, err, ua.StatusBadCertificateUntrusted)
+		require.FileExists(t, rejected(t, s, server))
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		s := newStore(t)
+		write(t, s.dir, "trusted/certs", "ca.der", ca.cert.Raw)
+		writeCRL(t, s.dir, "trusted/crl", ca, intermediate)
+		writeCRL(t, s.dir, "issuers/crl", intermediate)
+
+		err := s.verify(server.cert, chain(server, intermediate))
+		require.ErrorIs(t, err, ua.StatusBadCertificateRevoked)
+		require.NoFileExists(t, rejected(t, s, server))
+	})
+
+	t.Run("missing crl", func(t *testing.T) {
+		s := newStore(t)
+		write(t, s.dir, "trusted/certs", "ca.der", ca.cert.Raw)
+		writeCRL(t, s.dir, "trusted/crl", ca)
+
+		// the crl of the intermediate CA is missing
+		err := s.verify(server.cert, chain(server, intermediate))
+		require.ErrorIs(t, err, ua.StatusBadCertificateRevocationUnknown)
+		require.NoFileExists(t, rejected(t, s, server))
+
+		s.allowMissingCRL = true
+		require.NoError(t, s.verify(server.cert, chain(server, intermediate)))
+	})
+
+	t.Run("expired crl", func(t *testing.T) {
+		s := newStore(t)
+		write(t, s.dir, "trusted/certs", "ca.der", ca.cert.Raw)
+		crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:     big.NewInt(1),
+			ThisUpdate: time.Now().Add(-2 * time.Hour),
+			NextUpdate: time.Now().Add(-time.Hour),
+		}, ca.cert, ca.key)
+		require.NoError(t, err)
+		write(t, s.dir, "trusted/crl", "ca.crl", crl)
+
+		err = s.verify(server.cert, chain(server, intermediate))
+		require.ErrorIs(t, err, ua.StatusBadCertificateRevocationUnknown)
+
+		// an expired crl is not a missing crl
+		s.allowMissingCRL = true
+		err = s.verify(server.cert, chain(server, intermediate))
+		require.ErrorIs(t, err, ua.StatusBadCertificateRevocationUnknown)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		s := newStore(t)
+		write(t, s.dir, "trusted/certs", "expired.der", expired.cert.Raw)
+		err := s.verify(expired.cert, chain(expired))
+		require.ErrorIs(t, err, ua.StatusBadCertificateTimeInvalid)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		s := newStore(t)
+		write(t, s.dir, "trusted/certs", "invalid.der", []byte("x"))
+		require.Error(t, s.verify(selfSigned.cert, chain(selfSigned)))
+	})
+}
+
+func TestTrustStoreOCSP(t *testing.T) {
+	valid := time.Now().Add(time.Hour)
+	ca := newTestCert(t, "ca", nil, valid)
+
+	var queries int
+	status := map[int64]int{}
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req, err := ocsp.ParseRequest(b)
+		require.NoError(t, err)
+		st, ok := status[req.SerialNumber.Int64()]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		resp, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+			Status:       st,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   valid,
+			RevokedAt:    time.Now().Add(-time.Minute),
+		}, ca.key)
+		require.NoError(t, err)
+		w.Write(resp)
+	}))
+	defer responder.Close()
+
+	good := newTestCert(t, "good", ca, valid, responder.URL)
+	revoked := newTestCert(t, "revoked", ca, valid, responder.URL)
+	unknown := newTestCert(t, "unknown", ca, valid, responder.URL)
+	noResponder := newTestCert(t, "no responder", ca, valid)
+	status[good.cert.SerialNumber.Int64()] = ocsp.Good
+	status[revoked.cert.SerialNumber.Int64()] = ocsp.Revoked
+
+	s, err := newTrustStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(s.dir, "trusted", "certs", "ca.der"), ca.cert.Raw, 0o600))
+	verify := func(c *testCert) error {
+		return s.verify(c.cert, [][]*x509.Certificate{{c.cert}})
+	}
+
+	// ocsp is disabled by default
+	require.ErrorIs(t, verify(revoked), ua.StatusBadCertificateRevocationUnknown)
+	require.Zero(t, queries)
+
+	s.ocsp = true
+	require.NoError(t, verify(good))
+	require.ErrorIs(t, verify(revoked), ua.StatusBadCertificateRevoked)
+	require.ErrorIs(t, verify(unknown), ua.StatusBadCertificateRevocationUnknown)
+	require.ErrorIs(t, verify(noResponder), ua.StatusBadCertificateRevocationUnknown)
+	require.Equal(t, 3, queries)
+
+	// a current crl takes precedence
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-time.Hour),
+		NextUpdate: valid,
+	}, ca.cert, ca.key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(s.dir, "trusted", "crl", "ca.crl"), crl, 0o600))
+	require.NoError(t, verify(revoked))
+	require.Equal(t, 3, queries)
+}
@@ -152,6 +152,10 @@ func (b *Buffer) ReadBytes() []byte {
 	if n == 0 || n == null {
 		return nil
 	}
+	if err := checkStringLength(int64(n), b.Len()); err != nil {
+		b.err = err
+		return nil
+	}
 	d := b.ReadN(int(n))
 	if b.err != nil {
 		return nil
@@ -178,17 +182,26 @@ func (b *Buffer) ReadStruct(r interface{}) {
 	b.pos += n
 }
 
+// readFunc decodes a value from the remaining bytes with fn
+// and advances the position by the number of decoded bytes.
+func (b *Buffer) readFunc(fn func([]byte) (int, error)) {
+	if b.err != nil {
+		return
+	}
+	n, err := fn(b.buf[b.pos:])
+	if err != nil {
+		b.err = err
+		return
+	}
+	b.pos += n
+}
+
 func (b *Buffer) ReadTime() time.Time {
 	d := b.ReadN(8)
 	if b.err != nil {
 		return time.Time{}
 	}
-	ts := binary.LittleEndian.Uint64(d)
-	if ts == 0 {
-		return time.Time{}
-	}
-	// decode time in "100 nanosecond intervals since January 1, 1601" manner.
-	return time.Unix(0, int64((ts-116444736000000000)*100)).UTC()
+	return TimeFromFiletime(int64(binary.LittleEndian.Uint64(d)))
 }
 
 func (b *Buffer) ReadN(n int) []byte {
@@ -196,7 +209,7 @@ func (b *Buffer) ReadN(n int) []byte {
 		return nil
 	}
 	d := b.buf[b.pos:]
-	if n > len(d) {
+	if n < 0 || n > len(d) {
 		b.err = io.ErrUnexpectedEOF
 		return nil
 	}
@@ -310,11 +323,7 @@ func (b *Buffer) WriteStruct(w interface{}) {
 
 func (b *Buffer) WriteTime(v time.Time) {
 	d := make([]byte, 8)
-	if !v.IsZero() {
-		// encode time in "100 nanosecond intervals since January 1, 1601"
-		ts := uint64(v.UTC().UnixNano()/100 + 116444736000000000)
-		binary.LittleEndian.PutUint64(d, ts)
-	}
+	binary.LittleEndian.PutUint64(d, uint64(FiletimeFromTime(v)))
 	b.Write(d)
 }
 
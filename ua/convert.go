@@ -0,0 +1,178 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"math"
+	"reflect"
+
+	"github.com/gopcua/opcua/errors"
+)
+
+// As converts the value of the variant to the Go type T.
+//
+// Values of type T are returned as is. Numbers are converted to any
+// other number type as long as the value fits into T without loss, e.g.
+// an Int32 to an int or a Float to a float64. A Double is converted to
+// an integer type only if it has no fractional part. A LocalizedText is
+// converted to its text when T is a string, a GUID is converted to a 16
+// byte array like uuid.UUID and the value of an extension object is
+// returned when it has type T. Arrays are converted element by element
+// to a slice of T.
+//
+// The error wraps StatusBadTypeMismatch if the value cannot be
+// converted.
+func As[T any](v *Variant) (T, error) {
+	var zero T
+	if v == nil || v.Value() == nil {
+		return zero, errors.Errorf("cannot convert null value to %T: %w", zero, StatusBadTypeMismatch)
+	}
+	if x, ok := v.Value().(T); ok {
+		return x, nil
+	}
+	rv, err := convertValue(reflect.ValueOf(v.Value()), reflect.TypeFor[T]())
+	if err != nil {
+		return zero, err
+	}
+	return rv.Interface().(T), nil
+}
+
+// convertValue converts src to the type dst following the rules of As.
+func convertValue(src reflect.Value, dst reflect.Type) (reflect.Value, error) {
+	if !src.IsValid() {
+		return reflect.Value{}, errors.Errorf("cannot convert null value to %s: %w", dst, StatusBadTypeMismatch)
+	}
+	if src.Type().AssignableTo(dst) {
+		return src.Convert(dst), nil
+	}
+
+	switch x := src.Interface().(type) {
+	case *Variant:
+		if x != nil {
+			return convertValue(reflect.ValueOf(x.Value()), dst)
+		}
+	case *ExtensionObject:
+		if x != nil {
+			return convertValue(reflect.ValueOf(x.Value), dst)
+		}
+	case *LocalizedText:
+		if x != nil && dst.Kind() == reflect.String {
+			return reflect.ValueOf(x.Text).Convert(dst), nil
+		}
+	case *GUID:
+		if x != nil && isUUIDType(dst) {
+			return reflect.ValueOf(x.UUID()).Convert(dst), nil
+		}
+	}
+
+	switch {
+	case isNumber(src.Kind()) && isNumber(dst.Kind()):
+		return convertNumber(src, dst)
+
+	case src.Kind() == reflect.Slice && dst.Kind() == reflect.Slice:
+		// e.g. ByteArray to []byte
+		if src.Type().Elem() == dst.Elem() {
+			return src.Convert(dst), nil
+		}
+		out := reflect.MakeSlice(dst, src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			v, err := convertValue(src.Index(i), dst.Elem())
+			if err != nil {
+				return reflect.Value{}, errors.Errorf("element %d: %w", i, err)
+			}
+			out.Index(i).Set(v)
+		}
+		return out, nil
+	}
+	return reflect.Value{}, errors.Errorf("cannot convert %s to %s: %w", src.Type(), dst, StatusBadTypeMismatch)
+}
+
+// convertNumber converts the number src to the number type dst and
+// returns an error if the value does not fit into dst.
+func convertNumber(src reflect.Value, dst reflect.Type) (reflect.Value, error) {
+	out := reflect.New(dst).Elem()
+	overflow := func() (reflect.Value, error) {
+		return reflect.Value{}, errors.Errorf("cannot convert %s value %v to %s: %w", src.Type(), src, dst, StatusBadTypeMismatch)
+	}
+
+	switch {
+	case isInt(src.Kind()):
+		n := src.Int()
+		switch {
+		case isInt(dst.Kind()):
+			if out.OverflowInt(n) {
+				return overflow()
+			}
+			out.SetInt(n)
+		case isUint(dst.Kind()):
+			if n < 0 || out.OverflowUint(uint64(n)) {
+				return overflow()
+			}
+			out.SetUint(uint64(n))
+		default:
+			out.SetFloat(float64(n))
+		}
+
+	case isUint(src.Kind()):
+		n := src.Uint()
+		switch {
+		case isInt(dst.Kind()):
+			if n > math.MaxInt64 || out.OverflowInt(int64(n)) {
+				return overflow()
+			}
+			out.SetInt(int64(n))
+		case isUint(dst.Kind()):
+			if out.OverflowUint(n) {
+				return overflow()
+			}
+			out.SetUint(n)
+		default:
+			out.SetFloat(float64(n))
+		}
+
+	default:
+		f := src.Float()
+		switch {
+		case isInt(dst.Kind()):
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 || out.OverflowInt(int64(f)) {
+				return overflow()
+			}
+			out.SetInt(int64(f))
+		case isUint(dst.Kind()):
+			if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 || out.OverflowUint(uint64(f)) {
+				return overflow()
+			}
+			out.SetUint(uint64(f))
+		default:
+			if !math.IsInf(f, 0) && !math.IsNaN(f) && out.OverflowFloat(f) {
+				return overflow()
+			}
+			out.SetFloat(f)
+		}
+	}
+	return out, nil
+}
+
+func isInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isUint(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isNumber(k reflect.Kind) bool {
+	return isInt(k) || isUint(k) || k == reflect.Float32 || k == reflect.Float64
+}
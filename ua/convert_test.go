@@ -0,0 +1,90 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gopcua/opcua/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAs(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("identity", func(t *testing.T) {
+		require.Equal(t, "abc", mustAs[string](t, MustVariant("abc")))
+		require.Equal(t, ts, mustAs[time.Time](t, MustVariant(ts)))
+		require.Equal(t, int32(5), mustAs[int32](t, MustVariant(int32(5))))
+		require.Equal(t, any(true), mustAs[any](t, MustVariant(true)))
+	})
+
+	t.Run("numbers", func(t *testing.T) {
+		require.Equal(t, 5, mustAs[int](t, MustVariant(int32(5))))
+		require.Equal(t, int64(-5), mustAs[int64](t, MustVariant(int8(-5))))
+		require.Equal(t, uint8(255), mustAs[uint8](t, MustVariant(uint32(255))))
+		require.Equal(t, 1.5, mustAs[float64](t, MustVariant(float32(1.5))))
+		require.Equal(t, 3.0, mustAs[float64](t, MustVariant(uint16(3))))
+		require.Equal(t, 2, mustAs[int](t, MustVariant(2.0)))
+		require.Equal(t, uint32(StatusBad), mustAs[uint32](t, MustVariant(StatusBad)))
+	})
+
+	t.Run("other types", func(t *testing.T) {
+		require.Equal(t, "text", mustAs[string](t, MustVariant(NewLocalizedText("text"))))
+		require.Equal(t, []byte{1, 2}, mustAs[[]byte](t, MustVariant(ByteArray{1, 2})))
+
+		u := uuid.MustParse("72962B91-FA75-4AE6-8D28-B404DC7DAF63")
+		require.Equal(t, u, mustAs[uuid.UUID](t, MustVariant(NewGUID(u.String()))))
+
+		tok := &AnonymousIdentityToken{PolicyID: "anonymous"}
+		require.Equal(t, tok, mustAs[*AnonymousIdentityToken](t, MustVariant(NewExtensionObject(tok))))
+	})
+
+	t.Run("arrays", func(t *testing.T) {
+		require.Equal(t, []int{1, 2}, mustAs[[]int](t, MustVariant([]int32{1, 2})))
+		require.Equal(t, []float64{1, 2.5}, mustAs[[]float64](t, MustVariant([]*Variant{MustVariant(int16(1)), MustVariant(2.5)})))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			err  error
+		}{
+			{"null", asErr[int](nil)},
+			{"empty", asErr[int](&Variant{})},
+			{"string to int", asErr[int](MustVariant("5"))},
+			{"int to string", asErr[string](MustVariant(int32(5)))},
+			{"bool to int", asErr[int](MustVariant(true))},
+			{"overflow", asErr[int8](MustVariant(int32(300)))},
+			{"negative to uint", asErr[uint](MustVariant(int32(-1)))},
+			{"fraction to int", asErr[int](MustVariant(1.5))},
+			{"nan to int", asErr[int](MustVariant(math.NaN()))},
+			{"float32 overflow", asErr[float32](MustVariant(math.MaxFloat64))},
+			{"array element", asErr[[]uint8](MustVariant([]int32{1, 256}))},
+			{"scalar to slice", asErr[[]int](MustVariant(int32(1)))},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				require.Error(t, tt.err)
+				require.True(t, errors.Is(tt.err, StatusBadTypeMismatch), "got %v", tt.err)
+			})
+		}
+	})
+}
+
+func mustAs[T any](t *testing.T, v *Variant) T {
+	t.Helper()
+	x, err := As[T](v)
+	require.NoError(t, err)
+	return x
+}
+
+func asErr[T any](v *Variant) error {
+	_, err := As[T](v)
+	return err
+}
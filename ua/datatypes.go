@@ -37,11 +37,19 @@ type DataValue struct {
 }
 
 func (d *DataValue) Decode(b []byte) (int, error) {
+	return d.decode(b, 0)
+}
+
+// decode decodes a data value which is nested in depth other values.
+func (d *DataValue) decode(b []byte, depth int) (int, error) {
+	if err := checkNestingDepth(depth); err != nil {
+		return 0, err
+	}
 	buf := NewBuffer(b)
 	d.EncodingMask = buf.ReadByte()
 	d.Value = new(Variant)
 	if d.Has(DataValueValue) {
-		buf.ReadStruct(d.Value)
+		buf.readFunc(func(b []byte) (int, error) { return d.Value.decode(b, depth+1) })
 	}
 	if d.Has(DataValueStatusCode) {
 		d.Status = StatusCode(buf.ReadUint32())
@@ -112,6 +120,78 @@ func (d *DataValue) UpdateMask() {
 	}
 }
 
+// maxPicoseconds is the largest valid value of the picoseconds of a
+// timestamp in units of 10 picoseconds.
+const maxPicoseconds = 9999
+
+// SourceTime returns the source timestamp with the SourcePicoseconds
+// added. The picoseconds are in units of 10 picoseconds and extend the
+// 100 nanosecond resolution of the encoded timestamp. Fractions of a
+// nanosecond are truncated.
+func (d *DataValue) SourceTime() time.Time {
+	return preciseTime(d.SourceTimestamp, d.SourcePicoseconds)
+}
+
+// ServerTime returns the server timestamp with the ServerPicoseconds
+// added like SourceTime.
+func (d *DataValue) ServerTime() time.Time {
+	return preciseTime(d.ServerTimestamp, d.ServerPicoseconds)
+}
+
+// SetSourceTime sets the source timestamp and the source picoseconds
+// from t so that the full nanosecond resolution of t is encoded. The
+// encoding mask is updated.
+func (d *DataValue) SetSourceTime(t time.Time) {
+	d.SourceTimestamp, d.SourcePicoseconds = splitTime(t)
+	d.UpdateMask()
+}
+
+// SetServerTime sets the server timestamp and the server picoseconds
+// like SetSourceTime.
+func (d *DataValue) SetServerTime(t time.Time) {
+	d.ServerTimestamp, d.ServerPicoseconds = splitTime(t)
+	d.UpdateMask()
+}
+
+// preciseTime adds the picoseconds in units of 10 picoseconds to t.
+func preciseTime(t time.Time, picos uint16) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.Add(time.Duration(min(picos, maxPicoseconds) / 100))
+}
+
+// splitTime splits t into a timestamp with a resolution of 100
+// nanoseconds and the remainder in units of 10 picoseconds.
+func splitTime(t time.Time) (time.Time, uint16) {
+	if t.IsZero() {
+		return t, 0
+	}
+	rem := t.Nanosecond() % 100
+	return t.Add(-time.Duration(rem)), uint16(rem * 100)
+}
+
+// Equal returns true if both data values have the same value and
+// status code. The timestamps are compared as well unless
+// ignoreTimestamps is set, e.g. to detect values which have not
+// changed. The values are compared with Variant.Equal. The encoding
+// mask is not compared since it follows from the other fields.
+func (d *DataValue) Equal(o *DataValue, ignoreTimestamps bool) bool {
+	if d == nil || o == nil {
+		return d == o
+	}
+	if d.Status != o.Status || !d.Value.Equal(o.Value) {
+		return false
+	}
+	if ignoreTimestamps {
+		return true
+	}
+	return d.SourceTimestamp.Equal(o.SourceTimestamp) &&
+		d.SourcePicoseconds == o.SourcePicoseconds &&
+		d.ServerTimestamp.Equal(o.ServerTimestamp) &&
+		d.ServerPicoseconds == o.ServerPicoseconds
+}
+
 // GUID represents GUID in binary stream. It is a 16-byte globally unique identifier.
 //
 // Specification: Part 6, 5.1.3
@@ -143,6 +223,32 @@ func NewGUID(guid string) *GUID {
 	}
 }
 
+// NewGUIDFromBytes creates a new GUID from the 16 bytes of a UUID in the
+// order of its string form, e.g. the bytes of a uuid.UUID. It returns nil
+// if b does not contain 16 bytes.
+func NewGUIDFromBytes(b []byte) *GUID {
+	if len(b) != 16 {
+		return nil
+	}
+	return &GUID{
+		Data1: binary.BigEndian.Uint32(b[:4]),
+		Data2: binary.BigEndian.Uint16(b[4:6]),
+		Data3: binary.BigEndian.Uint16(b[6:8]),
+		Data4: append([]byte(nil), b[8:16]...),
+	}
+}
+
+// UUID returns the 16 bytes of the GUID in the order of its string form.
+// The result can be converted to a uuid.UUID.
+func (g *GUID) UUID() [16]byte {
+	var b [16]byte
+	binary.BigEndian.PutUint32(b[:4], g.Data1)
+	binary.BigEndian.PutUint16(b[4:6], g.Data2)
+	binary.BigEndian.PutUint16(b[6:8], g.Data3)
+	copy(b[8:], g.Data4)
+	return b
+}
+
 func (g *GUID) Decode(b []byte) (int, error) {
 	buf := NewBuffer(b)
 	g.Data1 = buf.ReadUint32()
@@ -7,6 +7,9 @@ package ua
 import (
 	"testing"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDataValue(t *testing.T) {
@@ -151,6 +154,65 @@ func TestDataValueArray(t *testing.T) {
 	RunCodecTest(t, cases)
 }
 
+func TestDataValueEqual(t *testing.T) {
+	now := time.Now()
+	dv := func(v interface{}, status StatusCode, ts time.Time) *DataValue {
+		return &DataValue{Value: MustVariant(v), Status: status, SourceTimestamp: ts, ServerTimestamp: ts}
+	}
+	tests := []struct {
+		name       string
+		a, b       *DataValue
+		eq, eqTime bool
+	}{
+		{"nil", nil, nil, true, true},
+		{"nil and value", nil, dv(int32(1), StatusOK, now), false, false},
+		{"same", dv(int32(1), StatusOK, now), dv(int32(1), StatusOK, now), true, true},
+		{"timestamps", dv(int32(1), StatusOK, now), dv(int32(1), StatusOK, now.Add(time.Second)), true, false},
+		{"value", dv(int32(1), StatusOK, now), dv(int32(2), StatusOK, now), false, false},
+		{"status", dv(int32(1), StatusOK, now), dv(int32(1), StatusUncertain, now), false, false},
+		{"no value", &DataValue{Status: StatusBadNodeIDUnknown}, &DataValue{Status: StatusBadNodeIDUnknown}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.eq, tt.a.Equal(tt.b, true), "ignore timestamps")
+			require.Equal(t, tt.eqTime, tt.a.Equal(tt.b, false), "compare timestamps")
+		})
+	}
+}
+
+func TestDataValuePicoseconds(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+
+	var v DataValue
+	v.SetSourceTime(ts)
+	v.SetServerTime(ts.Add(50))
+	require.Equal(t, time.Date(2024, 5, 6, 7, 8, 9, 123456700, time.UTC), v.SourceTimestamp)
+	require.Equal(t, uint16(8900), v.SourcePicoseconds)
+	require.Equal(t, time.Date(2024, 5, 6, 7, 8, 9, 123456800, time.UTC), v.ServerTimestamp)
+	require.Equal(t, uint16(3900), v.ServerPicoseconds)
+	require.Equal(t, uint8(DataValueSourceTimestamp|DataValueSourcePicoseconds|DataValueServerTimestamp|DataValueServerPicoseconds), v.EncodingMask)
+
+	// the picoseconds are encoded and the full resolution is restored
+	b, err := v.Encode()
+	require.NoError(t, err)
+	var got DataValue
+	_, err = got.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, uint16(8900), got.SourcePicoseconds)
+	require.Equal(t, ts, got.SourceTime())
+	require.Equal(t, ts.Add(50), got.ServerTime())
+
+	// sub-nanosecond fractions are truncated and invalid values are limited
+	require.Equal(t, ts.Add(-89+99), (&DataValue{SourceTimestamp: v.SourceTimestamp, SourcePicoseconds: 9999}).SourceTime())
+	require.Equal(t, ts.Add(-89+99), (&DataValue{SourceTimestamp: v.SourceTimestamp, SourcePicoseconds: 20000}).SourceTime())
+
+	// without picoseconds the timestamp is returned unchanged
+	v.SetSourceTime(v.SourceTimestamp)
+	require.Zero(t, v.SourcePicoseconds)
+	require.False(t, v.Has(DataValueSourcePicoseconds))
+	require.True(t, (&DataValue{}).SourceTime().IsZero())
+}
+
 func TestGUID(t *testing.T) {
 	cases := []CodecTestCase{
 		{
@@ -185,6 +247,31 @@ func TestGUID(t *testing.T) {
 	RunCodecTest(t, cases)
 }
 
+func TestGUIDUUID(t *testing.T) {
+	// spec GUID as sent by a server
+	b := []byte{
+		0x91, 0x2b, 0x96, 0x72,
+		0x75, 0xfa,
+		0xe6, 0x4a,
+		0x8d, 0x28, 0xb4, 0x04, 0xdc, 0x7d, 0xaf, 0x63,
+	}
+	u := uuid.MustParse("72962B91-FA75-4AE6-8D28-B404DC7DAF63")
+
+	g := new(GUID)
+	_, err := g.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, [16]byte(u), g.UUID())
+	require.Equal(t, u.String(), uuid.UUID(g.UUID()).String())
+
+	g = NewGUIDFromBytes(u[:])
+	require.Equal(t, NewGUID(u.String()), g)
+	got, err := g.Encode()
+	require.NoError(t, err)
+	require.Equal(t, b, got)
+
+	require.Nil(t, NewGUIDFromBytes(u[:15]))
+}
+
 func TestLocalizedText(t *testing.T) {
 	cases := []CodecTestCase{
 		{
@@ -0,0 +1,62 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"math"
+	"time"
+)
+
+// DateTime values are encoded as the number of 100 nanosecond intervals
+// since January 1, 1601 UTC, i.e. as Windows file time.
+//
+// Specification: Part 6, 5.2.2.5
+const (
+	// filetimeUnixOffset is the number of seconds between the start of
+	// the file time and the Unix epoch.
+	filetimeUnixOffset = 11644473600
+
+	// filetimeTicksPerSecond is the number of 100 nanosecond intervals
+	// per second.
+	filetimeTicksPerSecond = 10_000_000
+)
+
+var (
+	// filetimeMin is the earliest time which is encoded as a non-zero
+	// value.
+	filetimeMin = time.Date(1601, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// filetimeMax is the time from which on all times are encoded as
+	// the maximum value.
+	filetimeMax = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+)
+
+// TimeFromFiletime returns the UTC time for the number of 100 nanosecond
+// intervals since January 1, 1601 UTC. Zero and negative values return
+// the zero time.
+func TimeFromFiletime(ticks int64) time.Time {
+	if ticks <= 0 {
+		return time.Time{}
+	}
+	sec := ticks/filetimeTicksPerSecond - filetimeUnixOffset
+	nsec := (ticks % filetimeTicksPerSecond) * 100
+	return time.Unix(sec, nsec).UTC()
+}
+
+// FiletimeFromTime returns the number of 100 nanosecond intervals since
+// January 1, 1601 UTC for t. Fractions of 100 nanoseconds are truncated.
+//
+// Like required by the specification the zero time and times up to
+// January 1, 1601 return zero and times from December 31, 9999
+// 23:59:59 UTC on return math.MaxInt64.
+func FiletimeFromTime(t time.Time) int64 {
+	if t.IsZero() || !t.After(filetimeMin) {
+		return 0
+	}
+	if !t.Before(filetimeMax) {
+		return math.MaxInt64
+	}
+	return (t.Unix()+filetimeUnixOffset)*filetimeTicksPerSecond + int64(t.Nanosecond()/100)
+}
@@ -0,0 +1,84 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiletime(t *testing.T) {
+	tests := []struct {
+		name  string
+		t     time.Time
+		ticks int64
+	}{
+		{"zero", time.Time{}, 0},
+		{"epoch", time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC), 0},
+		{"after epoch", time.Date(1601, 1, 1, 0, 0, 0, 100, time.UTC), 1},
+		{"before unix epoch", time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), 116444735990000000},
+		{"unix epoch", time.Unix(0, 0).UTC(), 116444736000000000},
+		{"2018", time.Date(2018, 9, 17, 14, 28, 29, 112000000, time.UTC), 131816681091120000},
+		{"after 2262", time.Date(2500, 1, 1, 0, 0, 0, 0, time.UTC), 283696992000000000},
+		{"before max", time.Date(9999, 12, 31, 23, 59, 58, 999999900, time.UTC), 2650467743989999999},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.ticks, FiletimeFromTime(tt.t), "FiletimeFromTime")
+			if tt.ticks == 0 {
+				require.True(t, TimeFromFiletime(tt.ticks).IsZero(), "TimeFromFiletime")
+				return
+			}
+			got := TimeFromFiletime(tt.ticks)
+			require.Equal(t, tt.t, got, "TimeFromFiletime")
+			require.Equal(t, time.UTC, got.Location())
+		})
+	}
+
+	t.Run("before epoch", func(t *testing.T) {
+		require.Equal(t, int64(0), FiletimeFromTime(time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC)))
+		require.True(t, TimeFromFiletime(-1).IsZero())
+	})
+
+	t.Run("max", func(t *testing.T) {
+		require.Equal(t, int64(math.MaxInt64), FiletimeFromTime(time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)))
+		require.Equal(t, int64(math.MaxInt64), FiletimeFromTime(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+		// the maximum value decodes to a time which encodes as the maximum value again
+		max := TimeFromFiletime(math.MaxInt64)
+		require.Equal(t, 30828, max.Year())
+		require.Equal(t, int64(math.MaxInt64), FiletimeFromTime(max))
+	})
+
+	t.Run("location", func(t *testing.T) {
+		loc := time.FixedZone("UTC+2", 2*60*60)
+		v := time.Date(2018, 9, 17, 16, 28, 29, 112000000, loc)
+		require.Equal(t, int64(131816681091120000), FiletimeFromTime(v))
+	})
+
+	t.Run("data value", func(t *testing.T) {
+		// times outside of the range of UnixNano are encoded correctly
+		v := &DataValue{
+			EncodingMask:    DataValueSourceTimestamp | DataValueServerTimestamp,
+			SourceTimestamp: time.Date(1650, 6, 1, 12, 0, 0, 0, time.UTC),
+			ServerTimestamp: time.Date(2300, 6, 1, 12, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+		}
+		b, err := v.Encode()
+		require.NoError(t, err)
+		var got DataValue
+		_, err = got.Decode(b)
+		require.NoError(t, err)
+		require.Equal(t, v.SourceTimestamp, got.SourceTimestamp)
+		require.Equal(t, v.ServerTimestamp.UTC(), got.ServerTimestamp)
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		v := time.Date(2018, 9, 17, 14, 28, 29, 112000099, time.UTC)
+		require.Equal(t, int64(131816681091120000), FiletimeFromTime(v))
+	})
+}
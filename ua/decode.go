@@ -13,6 +13,20 @@ import (
 	"github.com/gopcua/opcua/errors"
 )
 
+var (
+	// MaxArrayLength limits the number of elements of a decoded array.
+	// Longer arrays are rejected with StatusBadDecodingError before the
+	// array is allocated. Zero means that only the size of the message
+	// limits the length.
+	MaxArrayLength = 0
+
+	// MaxStringLength limits the length of a decoded String, ByteString
+	// or XmlElement in bytes. Longer values are rejected with
+	// StatusBadDecodingError. Zero means that only the size of the
+	// message limits the length.
+	MaxStringLength = 0
+)
+
 var (
 	binaryDecoder = reflect.TypeOf((*BinaryDecoder)(nil)).Elem()
 	timeType      = reflect.TypeOf(time.Time{})
@@ -26,6 +40,19 @@ func isTime(val reflect.Value) bool {
 	return val.CanConvert(timeType)
 }
 
+// maxNestingDepth limits the nesting of Variant, DataValue and
+// DiagnosticInfo values so that malformed messages cannot exhaust
+// the stack. See Part 6, 5.2.2.12 and 5.2.2.16.
+const maxNestingDepth = 100
+
+// checkNestingDepth returns an error if depth exceeds maxNestingDepth.
+func checkNestingDepth(depth int) error {
+	if depth > maxNestingDepth {
+		return errors.Errorf("nesting depth exceeds %d: %w", maxNestingDepth, StatusBadEncodingLimitsExceeded)
+	}
+	return nil
+}
+
 type BinaryDecoder interface {
 	Decode([]byte) (int, error)
 }
@@ -136,13 +163,20 @@ func decodeSlice(b []byte, val reflect.Value, name string) (int, error) {
 	elemType := val.Type().Elem()
 	// fmt.Println("elemType: ", elemType.String())
 
-	// fast path for []byte
+	// fast path for []byte which is a ByteString
 	if elemType.Kind() == reflect.Uint8 {
 		// fmt.Println("decode: []byte fast path")
+		if err := checkStringLength(int64(n), buf.Len()); err != nil {
+			return buf.Pos(), err
+		}
 		val.SetBytes(buf.ReadN(int(n)))
 		return buf.Pos(), buf.Error()
 	}
 
+	if err := checkArrayLength(int(n), buf.Len(), elemType); err != nil {
+		return buf.Pos(), err
+	}
+
 	pos := buf.Pos()
 	// a is a slice of []*Foo
 	a := reflect.MakeSlice(val.Type(), int(n), int(n))
@@ -219,3 +253,36 @@ func decodeArray(b []byte, val reflect.Value, name string) (int, error) {
 
 	return pos, nil
 }
+
+// checkArrayLength returns an error if an array of n elements exceeds
+// MaxArrayLength or cannot be decoded from the remaining bytes. Every
+// element of a binary encoded array except an empty structure takes at
+// least one byte. This prevents huge length prefixes of malformed
+// messages from allocating large slices.
+func checkArrayLength(n, remaining int, elemType reflect.Type) error {
+	if MaxArrayLength > 0 && n > MaxArrayLength {
+		return errors.Errorf("array length %d exceeds the limit of %d: %w", n, MaxArrayLength, StatusBadDecodingError)
+	}
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() == reflect.Struct && elemType.NumField() == 0 {
+		return nil
+	}
+	if n > remaining {
+		return errors.Errorf("array length %d exceeds the remaining %d bytes: %w", n, remaining, StatusBadDecodingError)
+	}
+	return nil
+}
+
+// checkStringLength returns an error if a String or ByteString of n bytes
+// exceeds MaxStringLength or the remaining bytes.
+func checkStringLength(n int64, remaining int) error {
+	if MaxStringLength > 0 && n > int64(MaxStringLength) {
+		return errors.Errorf("string length %d exceeds the limit of %d: %w", n, MaxStringLength, StatusBadDecodingError)
+	}
+	if n > int64(remaining) {
+		return errors.Errorf("string length %d exceeds the remaining %d bytes: %w", n, remaining, StatusBadDecodingError)
+	}
+	return nil
+}
@@ -5,6 +5,8 @@
 package ua
 
 import (
+	"bytes"
+	"io"
 	"reflect"
 	"testing"
 	"time"
@@ -419,3 +421,153 @@ func TestFailDecodeArray(t *testing.T) {
 	_, err := Decode(b, &a)
 	require.Error(t, err, "was expecting error for tryig to decode a stream of bytes with length 3 into an array of size 2")
 }
+
+func TestDecodeMalformed(t *testing.T) {
+	nested := func(prefix []byte, depth int, last ...byte) []byte {
+		b := bytes.Repeat(prefix, depth)
+		return append(b, last...)
+	}
+
+	tests := []struct {
+		name string
+		b    []byte
+		v    interface{}
+		err  error
+	}{
+		{
+			name: "slice length exceeds buffer",
+			b:    []byte{0xf0, 0xff, 0xff, 0x7f, 0x01, 0x00, 0x00, 0x00},
+			v:    new([]*A),
+			err:  StatusBadDecodingError,
+		},
+		{
+			name: "byte slice length exceeds buffer",
+			b:    []byte{0xf0, 0xff, 0xff, 0x7f, 0x01},
+			v:    new([]byte),
+			err:  StatusBadDecodingError,
+		},
+		{
+			name: "truncated struct",
+			b:    []byte{0x01, 0x00},
+			v:    new(A),
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			name: "variant array length exceeds buffer",
+			b:    []byte{0x86, 0xff, 0xff, 0x00, 0x00, 0x01},
+			v:    new(Variant),
+			err:  StatusBadDecodingError,
+		},
+		{
+			name: "negative variant array length",
+			b:    []byte{0x86, 0xfe, 0xff, 0xff, 0xff},
+			v:    new(Variant),
+			err:  StatusBadDecodingError,
+		},
+		{
+			name: "variant array dimensions overflow",
+			b: []byte{
+				0xc6,                   // int32 array with dimensions
+				0x00, 0x00, 0x00, 0x00, // no values
+				0x02, 0x00, 0x00, 0x00, // two dimensions
+				0x00, 0x00, 0x01, 0x00, // 65536
+				0x00, 0x00, 0x01, 0x00, // 65536
+			},
+			v:   new(Variant),
+			err: errUnbalancedSlice,
+		},
+		{
+			name: "variant nesting too deep",
+			b:    nested([]byte{byte(TypeIDVariant)}, maxNestingDepth+1, 0x00),
+			v:    new(Variant),
+			err:  StatusBadEncodingLimitsExceeded,
+		},
+		{
+			name: "data value nesting too deep",
+			b:    nested([]byte{DataValueValue, byte(TypeIDDataValue)}, maxNestingDepth, 0x00),
+			v:    new(DataValue),
+			err:  StatusBadEncodingLimitsExceeded,
+		},
+		{
+			name: "diagnostic info nesting too deep",
+			b:    nested([]byte{DiagnosticInfoInnerDiagnosticInfo}, maxNestingDepth+1, 0x00),
+			v:    new(DiagnosticInfo),
+			err:  StatusBadEncodingLimitsExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.b, tt.v)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	t.Run("max nesting depth", func(t *testing.T) {
+		v := new(Variant)
+		_, err := Decode(nested([]byte{byte(TypeIDVariant)}, maxNestingDepth, 0x00), v)
+		require.NoError(t, err)
+	})
+}
+
+func TestDecodeLimits(t *testing.T) {
+	defer func(a, s int) { MaxArrayLength, MaxStringLength = a, s }(MaxArrayLength, MaxStringLength)
+	MaxArrayLength, MaxStringLength = 2, 4
+
+	tests := []struct {
+		name string
+		b    []byte
+		v    interface{}
+		err  error
+	}{
+		{
+			name: "array within limit",
+			b:    []byte{0x02, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00},
+			v:    new([]uint32),
+		},
+		{
+			name: "array exceeds limit",
+			b:    []byte{0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00},
+			v:    new([]uint32),
+			err:  StatusBadDecodingError,
+		},
+		{
+			name: "variant array exceeds limit",
+			b:    []byte{0x83, 0x03, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03},
+			v:    new(Variant),
+			err:  StatusBadDecodingError,
+		},
+		{
+			name: "string within limit",
+			b:    []byte{0x04, 0x00, 0x00, 0x00, 'a', 'b', 'c', 'd'},
+			v:    new(string),
+		},
+		{
+			name: "string exceeds limit",
+			b:    []byte{0x05, 0x00, 0x00, 0x00, 'a', 'b', 'c', 'd', 'e'},
+			v:    new(string),
+			err:  StatusBadDecodingError,
+		},
+		{
+			name: "byte string exceeds limit",
+			b:    []byte{0x05, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05},
+			v:    new([]byte),
+			err:  StatusBadDecodingError,
+		},
+		{
+			name: "string exceeds buffer",
+			b:    []byte{0x04, 0x00, 0x00, 0x00, 'a'},
+			v:    new(string),
+			err:  StatusBadDecodingError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.b, tt.v)
+			if tt.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -31,6 +31,14 @@ type DiagnosticInfo struct {
 }
 
 func (d *DiagnosticInfo) Decode(b []byte) (int, error) {
+	return d.decode(b, 0)
+}
+
+// decode decodes a diagnostic info which is nested in depth other values.
+func (d *DiagnosticInfo) decode(b []byte, depth int) (int, error) {
+	if err := checkNestingDepth(depth); err != nil {
+		return 0, err
+	}
 	buf := NewBuffer(b)
 	d.EncodingMask = buf.ReadByte()
 	if d.Has(DiagnosticInfoSymbolicID) {
@@ -53,7 +61,7 @@ func (d *DiagnosticInfo) Decode(b []byte) (int, error) {
 	}
 	if d.Has(DiagnosticInfoInnerDiagnosticInfo) {
 		d.InnerDiagnosticInfo = new(DiagnosticInfo)
-		buf.ReadStruct(d.InnerDiagnosticInfo)
+		buf.readFunc(func(b []byte) (int, error) { return d.InnerDiagnosticInfo.decode(b, depth+1) })
 	}
 	return buf.Pos(), buf.Error()
 }
@@ -113,3 +121,63 @@ func (d *DiagnosticInfo) UpdateMask() {
 		d.EncodingMask |= DiagnosticInfoInnerDiagnosticInfo
 	}
 }
+
+// ResolvedDiagnostic is a DiagnosticInfo with the string table indices
+// replaced by the strings they refer to.
+type ResolvedDiagnostic struct {
+	SymbolicID      string
+	NamespaceURI    string
+	Locale          string
+	LocalizedText   string
+	AdditionalInfo  string
+	InnerStatusCode StatusCode
+
+	// Inner is the resolved inner diagnostic info or nil.
+	Inner *ResolvedDiagnostic
+}
+
+// ResolveDiagnostics resolves the string table indices of the diagnostic
+// info and all of its inner diagnostic infos. The string table is sent
+// in the response header. Fields which are not set or have an invalid
+// index are empty.
+func ResolveDiagnostics(diag *DiagnosticInfo, stringTable []string) ResolvedDiagnostic {
+	if diag == nil {
+		return ResolvedDiagnostic{}
+	}
+	lookup := func(mask byte, idx int32) string {
+		if !diag.Has(mask) || idx < 0 || int(idx) >= len(stringTable) {
+			return ""
+		}
+		return stringTable[idx]
+	}
+	r := ResolvedDiagnostic{
+		SymbolicID:    lookup(DiagnosticInfoSymbolicID, diag.SymbolicID),
+		NamespaceURI:  lookup(DiagnosticInfoNamespaceURI, diag.NamespaceURI),
+		Locale:        lookup(DiagnosticInfoLocale, diag.Locale),
+		LocalizedText: lookup(DiagnosticInfoLocalizedText, diag.LocalizedText),
+	}
+	if diag.Has(DiagnosticInfoAdditionalInfo) {
+		r.AdditionalInfo = diag.AdditionalInfo
+	}
+	if diag.Has(DiagnosticInfoInnerStatusCode) {
+		r.InnerStatusCode = diag.InnerStatusCode
+	}
+	if diag.Has(DiagnosticInfoInnerDiagnosticInfo) && diag.InnerDiagnosticInfo != nil {
+		inner := ResolveDiagnostics(diag.InnerDiagnosticInfo, stringTable)
+		r.Inner = &inner
+	}
+	return r
+}
+
+// responseDiagnostic resolves the i-th diagnostic info of a response
+// with the string table of the response header.
+func responseDiagnostic(h *ResponseHeader, diags []*DiagnosticInfo, i int) ResolvedDiagnostic {
+	if i < 0 || i >= len(diags) {
+		return ResolvedDiagnostic{}
+	}
+	var stringTable []string
+	if h != nil {
+		stringTable = h.StringTable
+	}
+	return ResolveDiagnostics(diags[i], stringTable)
+}
@@ -6,6 +6,8 @@ package ua
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestDiagnosticInfo(t *testing.T) {
@@ -134,3 +136,49 @@ func TestDiagnosticInfo(t *testing.T) {
 	}
 	RunCodecTest(t, cases)
 }
+
+func TestResolveDiagnostics(t *testing.T) {
+	stringTable := []string{"http://example.com/", "BadThing", "en", "Something went wrong", "InnerThing"}
+	diag := &DiagnosticInfo{
+		EncodingMask:    DiagnosticInfoSymbolicID | DiagnosticInfoNamespaceURI | DiagnosticInfoLocale | DiagnosticInfoLocalizedText | DiagnosticInfoAdditionalInfo | DiagnosticInfoInnerStatusCode | DiagnosticInfoInnerDiagnosticInfo,
+		SymbolicID:      1,
+		NamespaceURI:    0,
+		Locale:          2,
+		LocalizedText:   3,
+		AdditionalInfo:  "details",
+		InnerStatusCode: StatusBadNodeIDUnknown,
+		InnerDiagnosticInfo: &DiagnosticInfo{
+			EncodingMask:  DiagnosticInfoSymbolicID | DiagnosticInfoLocalizedText,
+			SymbolicID:    4,
+			LocalizedText: 99,
+		},
+	}
+	want := ResolvedDiagnostic{
+		SymbolicID:      "BadThing",
+		NamespaceURI:    "http://example.com/",
+		Locale:          "en",
+		LocalizedText:   "Something went wrong",
+		AdditionalInfo:  "details",
+		InnerStatusCode: StatusBadNodeIDUnknown,
+		Inner:           &ResolvedDiagnostic{SymbolicID: "InnerThing"},
+	}
+	require.Equal(t, want, ResolveDiagnostics(diag, stringTable))
+
+	t.Run("mask not set", func(t *testing.T) {
+		require.Equal(t, ResolvedDiagnostic{}, ResolveDiagnostics(&DiagnosticInfo{SymbolicID: 1}, stringTable))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		require.Equal(t, ResolvedDiagnostic{}, ResolveDiagnostics(nil, stringTable))
+	})
+
+	t.Run("response", func(t *testing.T) {
+		res := &ReadResponse{
+			ResponseHeader:  &ResponseHeader{StringTable: stringTable},
+			DiagnosticInfos: []*DiagnosticInfo{{}, diag},
+		}
+		require.Equal(t, ResolvedDiagnostic{}, res.Diagnostic(0))
+		require.Equal(t, want, res.Diagnostic(1))
+		require.Equal(t, ResolvedDiagnostic{}, res.Diagnostic(2))
+	})
+}
@@ -122,3 +122,28 @@ func FormatSecurityPolicyURI(policy string) string {
 	}
 	return policy
 }
+
+// Has returns true if all bits of mask are set.
+func (a AccessLevelType) Has(mask AccessLevelType) bool {
+	return a&mask == mask
+}
+
+// CurrentRead returns true if the current value can be read.
+func (a AccessLevelType) CurrentRead() bool {
+	return a.Has(AccessLevelTypeCurrentRead)
+}
+
+// CurrentWrite returns true if the current value can be written.
+func (a AccessLevelType) CurrentWrite() bool {
+	return a.Has(AccessLevelTypeCurrentWrite)
+}
+
+// HistoryRead returns true if the history of the value can be read.
+func (a AccessLevelType) HistoryRead() bool {
+	return a.Has(AccessLevelTypeHistoryRead)
+}
+
+// HistoryWrite returns true if the history of the value can be updated.
+func (a AccessLevelType) HistoryWrite() bool {
+	return a.Has(AccessLevelTypeHistoryWrite)
+}
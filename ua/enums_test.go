@@ -0,0 +1,34 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLevelType(t *testing.T) {
+	tests := []struct {
+		a                                                    AccessLevelType
+		currentRead, currentWrite, historyRead, historyWrite bool
+	}{
+		{a: AccessLevelTypeNone},
+		{a: AccessLevelTypeCurrentRead, currentRead: true},
+		{a: AccessLevelTypeCurrentRead | AccessLevelTypeCurrentWrite, currentRead: true, currentWrite: true},
+		{a: AccessLevelTypeHistoryRead | AccessLevelTypeHistoryWrite, historyRead: true, historyWrite: true},
+		{a: 0xff, currentRead: true, currentWrite: true, historyRead: true, historyWrite: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a.String(), func(t *testing.T) {
+			require.Equal(t, tt.currentRead, tt.a.CurrentRead(), "CurrentRead")
+			require.Equal(t, tt.currentWrite, tt.a.CurrentWrite(), "CurrentWrite")
+			require.Equal(t, tt.historyRead, tt.a.HistoryRead(), "HistoryRead")
+			require.Equal(t, tt.historyWrite, tt.a.HistoryWrite(), "HistoryWrite")
+		})
+	}
+	require.True(t, AccessLevelType(3).Has(AccessLevelTypeCurrentRead|AccessLevelTypeCurrentWrite))
+	require.False(t, AccessLevelType(1).Has(AccessLevelTypeCurrentRead|AccessLevelTypeCurrentWrite))
+}
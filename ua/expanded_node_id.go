@@ -7,6 +7,7 @@ package ua
 import (
 	"encoding/base64"
 	"math"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -133,7 +134,7 @@ func (e *ExpandedNodeID) HasServerIndex() bool {
 }
 
 // ParseExpandedNodeID returns a node id from a string definition of the format
-// '{ns,nsu}=<namespace>;{s,i,b,g}=<identifier>'.
+// '[svr=<serverindex>;]{ns,nsu}=<namespace>;{s,i,b,g}=<identifier>'.
 //
 // The 's=' prefix can be omitted for string node ids in namespace 0.
 //
@@ -141,14 +142,33 @@ func (e *ExpandedNodeID) HasServerIndex() bool {
 // and id value is returned.
 //
 // Namespace URIs are resolved to ids from the provided list of namespaces.
+// If the list is nil the namespace URI is kept and the namespace id is zero.
+// Use Client.ResolveExpandedNodeID to resolve it with the namespaces of the
+// server later. Namespace URIs of node ids on other servers are not
+// resolved. Reserved characters like ';' in the URI are percent-encoded.
 func ParseExpandedNodeID(s string, ns []string) (*ExpandedNodeID, error) {
 	if s == "" {
 		return NewTwoByteExpandedNodeID(0), nil
 	}
 
+	// parse server index
+	rest := s
+	var svr uint32
+	if strings.HasPrefix(rest, "svr=") {
+		p := strings.SplitN(rest, ";", 2)
+		if len(p) != 2 {
+			return nil, errors.Errorf("invalid node id: %s", s)
+		}
+		n, err := strconv.ParseUint(p[0][4:], 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("invalid server index: %s", s)
+		}
+		svr, rest = uint32(n), p[1]
+	}
+
 	var nsval, idval string
 
-	p := strings.SplitN(s, ";", 2)
+	p := strings.SplitN(rest, ";", 2)
 	switch len(p) {
 	case 1:
 		nsval, idval = "ns=0", p[0]
@@ -161,15 +181,19 @@ func ParseExpandedNodeID(s string, ns []string) (*ExpandedNodeID, error) {
 	var nsu string
 	switch {
 	case strings.HasPrefix(nsval, "nsu="):
-		if ns == nil {
-			return nil, errors.Errorf("namespace urls require a server NamespaceArray")
+		uri, err := url.PathUnescape(strings.TrimPrefix(nsval, "nsu="))
+		if err != nil || uri == "" {
+			return nil, errors.Errorf("invalid namespace uri: %s", s)
+		}
+		nsu = uri
+		if ns == nil || svr != 0 {
+			break
 		}
 
-		nsuval := strings.TrimPrefix(nsval, "nsu=")
 		ok := false
-		for id, uri := range ns {
-			if uri == nsuval {
-				nsid, nsu = uint16(id), uri
+		for id, x := range ns {
+			if x == uri {
+				nsid = uint16(id)
 				ok = true
 				break
 			}
@@ -201,36 +225,36 @@ func ParseExpandedNodeID(s string, ns []string) (*ExpandedNodeID, error) {
 		}
 		switch {
 		case nsid == 0 && id < 256:
-			return NewExpandedNodeID(NewTwoByteNodeID(byte(id)), "", 0), nil
+			return NewExpandedNodeID(NewTwoByteNodeID(byte(id)), nsu, svr), nil
 		case nsid < 256 && id < math.MaxUint16:
-			return NewExpandedNodeID(NewFourByteNodeID(byte(nsid), uint16(id)), nsu, 0), nil
+			return NewExpandedNodeID(NewFourByteNodeID(byte(nsid), uint16(id)), nsu, svr), nil
 		case id <= math.MaxUint32:
-			return NewExpandedNodeID(NewNumericNodeID(nsid, uint32(id)), nsu, 0), nil
+			return NewExpandedNodeID(NewNumericNodeID(nsid, uint32(id)), nsu, svr), nil
 		default:
 			return nil, errors.Errorf("numeric id out of range (0..2^32-1): %s", s)
 		}
 
 	case strings.HasPrefix(idval, "s="):
-		return NewExpandedNodeID(NewStringNodeID(nsid, idval[2:]), nsu, 0), nil
+		return NewExpandedNodeID(NewStringNodeID(nsid, idval[2:]), nsu, svr), nil
 
 	case strings.HasPrefix(idval, "g="):
 		n := NewGUIDNodeID(nsid, idval[2:])
 		if n == nil || n.StringID() == "" {
 			return nil, errors.Errorf("invalid guid node id: %s", s)
 		}
-		return NewExpandedNodeID(n, nsu, 0), nil
+		return NewExpandedNodeID(n, nsu, svr), nil
 
 	case strings.HasPrefix(idval, "b="):
 		b, err := base64.StdEncoding.DecodeString(idval[2:])
 		if err != nil {
 			return nil, errors.Errorf("invalid opaque node id: %s", s)
 		}
-		return NewExpandedNodeID(NewByteStringNodeID(nsid, b), nsu, 0), nil
+		return NewExpandedNodeID(NewByteStringNodeID(nsid, b), nsu, svr), nil
 
 	case strings.HasPrefix(idval, "ns="):
 		return nil, errors.Errorf("invalid node id: %s", s)
 
 	default:
-		return NewExpandedNodeID(NewStringNodeID(nsid, idval), nsu, 0), nil
+		return NewExpandedNodeID(NewStringNodeID(nsid, idval), nsu, svr), nil
 	}
 }
@@ -78,7 +78,6 @@ func TestParseExpandedNodeID(t *testing.T) {
 		{s: "abc=0;i=2", err: errors.New("invalid node id: abc=0;i=2")},
 		{s: "ns=0;i=1;s=2", err: errors.New("invalid numeric id: ns=0;i=1;s=2")},
 		{s: "ns=0", err: errors.New("invalid node id: ns=0")},
-		{s: "nsu=abc;i=1", err: errors.New("namespace urls require a server NamespaceArray")},
 		{s: "ns=65536;i=1", err: errors.New("namespace id out of range (0..65535): ns=65536;i=1")},
 		{s: "ns=abc;i=1", err: errors.New("invalid namespace id: ns=abc;i=1")},
 		{s: "ns=1;i=abc", err: errors.New("invalid numeric id: ns=1;i=abc")},
@@ -93,7 +92,22 @@ func TestParseExpandedNodeID(t *testing.T) {
 		{s: "nsu=abc;a", ns: []string{"", "abc"}, n: NewExpandedNodeID(NewStringNodeID(1, "a"), "abc", 0)},
 		{s: "nsu=abc;s=a", ns: []string{"", "abc"}, n: NewExpandedNodeID(NewStringNodeID(1, "a"), "abc", 0)},
 
+		// unresolved nsu
+		{s: "nsu=abc;i=1", n: NewExpandedNodeID(NewTwoByteNodeID(1), "abc", 0)},
+		{s: "nsu=http://example.com/;s=a", n: NewExpandedNodeID(NewStringNodeID(0, "a"), "http://example.com/", 0)},
+		{s: "nsu=urn:a%3Bb;i=5", n: NewExpandedNodeID(NewTwoByteNodeID(5), "urn:a;b", 0)},
+
+		// server index
+		{s: "svr=1;i=5", n: NewExpandedNodeID(NewTwoByteNodeID(5), "", 1)},
+		{s: "svr=2;ns=3;s=a", n: NewExpandedNodeID(NewStringNodeID(3, "a"), "", 2)},
+		{s: "svr=1;nsu=abc;i=70000", ns: []string{"", "abc"}, n: NewExpandedNodeID(NewNumericNodeID(0, 70000), "abc", 1)},
+		{s: "svr=0;nsu=abc;i=70000", ns: []string{"", "abc"}, n: NewExpandedNodeID(NewNumericNodeID(1, 70000), "abc", 0)},
+
 		// nsu error flows
+		{s: "nsu=;i=1", err: errors.New("invalid namespace uri: nsu=;i=1")},
+		{s: "nsu=%zz;i=1", err: errors.New("invalid namespace uri: nsu=%zz;i=1")},
+		{s: "svr=x;i=1", err: errors.New("invalid server index: svr=x;i=1")},
+		{s: "svr=1", err: errors.New("invalid node id: svr=1")},
 		{s: "nsu=abc;i=2253", ns: []string{}, err: errors.New("namespace uri nsu=abc not found in the server NamespaceArray []string{}")},
 		{s: "nsu=abc;i=2253", ns: []string{"", "def", "xyz"}, err: errors.New(`namespace uri nsu=abc not found in the server NamespaceArray []string{"", "def", "xyz"}`)},
 	}
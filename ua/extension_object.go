@@ -75,6 +75,12 @@ func (e *ExtensionObject) Decode(b []byte) (int, error) {
 
 	typeID := e.TypeID.NodeID
 	e.Value = eotypes.New(typeID)
+	if e.Value == nil {
+		// structures registered at runtime from their DataTypeDefinition
+		if s := newStructureByEncoding(typeID); s != nil {
+			e.Value = s
+		}
+	}
 	if e.Value == nil {
 		debug.Printf("ua: unknown extension object %s", typeID)
 		return buf.Pos(), buf.Error()
@@ -127,6 +133,8 @@ func ExtensionObjectTypeID(v interface{}) *ExpandedNodeID {
 		return NewFourByteExpandedNodeID(0, id.IssuedIdentityToken_Encoding_DefaultBinary)
 	case *ServerStatusDataType:
 		return NewFourByteExpandedNodeID(0, id.ServerStatusDataType_Encoding_DefaultBinary)
+	case *Structure:
+		return &ExpandedNodeID{NodeID: v.(*Structure).EncodingID()}
 	default:
 		if id := eotypes.Lookup(v); id != nil {
 			return &ExpandedNodeID{NodeID: id}
@@ -24,6 +24,48 @@ func TestExtensionObject(t *testing.T) {
 				0x09, 0x00, 0x00, 0x00, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73,
 			},
 		},
+		{
+			Name: "eu-information",
+			Struct: NewExtensionObject(&EUInformation{
+				NamespaceURI: "urn:u",
+				UnitID:       4408652,
+				DisplayName:  NewLocalizedText("°C"),
+				Description:  NewLocalizedText("degree Celsius"),
+			}),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x79, 0x03,
+				// EncodingMask
+				0x01,
+				// Length
+				0x28, 0x00, 0x00, 0x00,
+				// NamespaceURI
+				0x05, 0x00, 0x00, 0x00, 0x75, 0x72, 0x6e, 0x3a, 0x75,
+				// UnitID
+				0x4c, 0x45, 0x43, 0x00,
+				// DisplayName
+				0x02, 0x03, 0x00, 0x00, 0x00, 0xc2, 0xb0, 0x43,
+				// Description
+				0x02, 0x0e, 0x00, 0x00, 0x00,
+				0x64, 0x65, 0x67, 0x72, 0x65, 0x65, 0x20, 0x43, 0x65, 0x6c, 0x73, 0x69, 0x75, 0x73,
+			},
+		},
+		{
+			Name:   "range",
+			Struct: NewExtensionObject(&Range{Low: 0, High: 100}),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x76, 0x03,
+				// EncodingMask
+				0x01,
+				// Length
+				0x10, 0x00, 0x00, 0x00,
+				// Low
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// High
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x59, 0x40,
+			},
+		},
 	}
 	RunCodecTest(t, cases)
 }
@@ -870,6 +870,11 @@ func (t *RegisterServer2Response) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *RegisterServer2Response) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type ChannelSecurityToken struct {
 	ChannelID       uint32
 	TokenID         uint32
@@ -1042,6 +1047,11 @@ func (t *ActivateSessionResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *ActivateSessionResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type CloseSessionRequest struct {
 	RequestHeader       *RequestHeader
 	DeleteSubscriptions bool
@@ -1244,6 +1254,11 @@ func (t *AddNodesResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *AddNodesResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type AddReferencesItem struct {
 	SourceNodeID    *NodeID
 	ReferenceTypeID *NodeID
@@ -1280,6 +1295,11 @@ func (t *AddReferencesResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *AddReferencesResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type DeleteNodesItem struct {
 	NodeID                 *NodeID
 	DeleteTargetReferences bool
@@ -1312,6 +1332,11 @@ func (t *DeleteNodesResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *DeleteNodesResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type DeleteReferencesItem struct {
 	SourceNodeID        *NodeID
 	ReferenceTypeID     *NodeID
@@ -1347,6 +1372,11 @@ func (t *DeleteReferencesResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *DeleteReferencesResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type ViewDescription struct {
 	ViewID      *NodeID
 	Timestamp   time.Time
@@ -1407,6 +1437,11 @@ func (t *BrowseResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *BrowseResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type BrowseNextRequest struct {
 	RequestHeader             *RequestHeader
 	ReleaseContinuationPoints bool
@@ -1435,6 +1470,11 @@ func (t *BrowseNextResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *BrowseNextResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type RelativePathElement struct {
 	ReferenceTypeID *NodeID
 	IsInverse       bool
@@ -1488,6 +1528,11 @@ func (t *TranslateBrowsePathsToNodeIDsResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *TranslateBrowsePathsToNodeIDsResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type RegisterNodesRequest struct {
 	RequestHeader   *RequestHeader
 	NodesToRegister []*NodeID
@@ -1661,6 +1706,11 @@ func (t *QueryFirstResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *QueryFirstResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type QueryNextRequest struct {
 	RequestHeader            *RequestHeader
 	ReleaseContinuationPoint bool
@@ -1725,6 +1775,11 @@ func (t *ReadResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *ReadResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type HistoryReadValueID struct {
 	NodeID            *NodeID
 	IndexRange        string
@@ -1821,6 +1876,11 @@ func (t *HistoryReadResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *HistoryReadResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type WriteValue struct {
 	NodeID      *NodeID
 	AttributeID AttributeID
@@ -1855,6 +1915,11 @@ func (t *WriteResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *WriteResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type HistoryUpdateDetails struct {
 	NodeID *NodeID
 }
@@ -1928,6 +1993,11 @@ func (t *HistoryUpdateResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *HistoryUpdateResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type CallMethodRequest struct {
 	ObjectID       *NodeID
 	MethodID       *NodeID
@@ -1968,6 +2038,11 @@ func (t *CallResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *CallResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type MonitoringFilter struct{}
 
 type DataChangeFilter struct {
@@ -2061,6 +2136,11 @@ func (t *CreateMonitoredItemsResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *CreateMonitoredItemsResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type MonitoredItemModifyRequest struct {
 	MonitoredItemID     uint32
 	RequestedParameters *MonitoringParameters
@@ -2102,6 +2182,11 @@ func (t *ModifyMonitoredItemsResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *ModifyMonitoredItemsResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type SetMonitoringModeRequest struct {
 	RequestHeader    *RequestHeader
 	SubscriptionID   uint32
@@ -2131,6 +2216,11 @@ func (t *SetMonitoringModeResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *SetMonitoringModeResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type SetTriggeringRequest struct {
 	RequestHeader    *RequestHeader
 	SubscriptionID   uint32
@@ -2191,6 +2281,11 @@ func (t *DeleteMonitoredItemsResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *DeleteMonitoredItemsResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type CreateSubscriptionRequest struct {
 	RequestHeader               *RequestHeader
 	RequestedPublishingInterval float64
@@ -2286,6 +2381,11 @@ func (t *SetPublishingModeResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *SetPublishingModeResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type NotificationMessage struct {
 	SequenceNumber   uint32
 	PublishTime      time.Time
@@ -2358,6 +2458,11 @@ func (t *PublishResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *PublishResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type RepublishRequest struct {
 	RequestHeader            *RequestHeader
 	SubscriptionID           uint32
@@ -2418,6 +2523,11 @@ func (t *TransferSubscriptionsResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *TransferSubscriptionsResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type DeleteSubscriptionsRequest struct {
 	RequestHeader   *RequestHeader
 	SubscriptionIDs []uint32
@@ -2445,6 +2555,11 @@ func (t *DeleteSubscriptionsResponse) SetHeader(h *ResponseHeader) {
 	t.ResponseHeader = h
 }
 
+// Diagnostic returns the resolved diagnostic information of the i-th result.
+func (t *DeleteSubscriptionsResponse) Diagnostic(i int) ResolvedDiagnostic {
+	return responseDiagnostic(t.ResponseHeader, t.DiagnosticInfos, i)
+}
+
 type BuildInfo struct {
 	ProductURI       string
 	ManufacturerName string
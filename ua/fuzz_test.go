@@ -0,0 +1,37 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"sort"
+	"testing"
+)
+
+// FuzzDecode checks that malformed messages of all service types
+// return an error instead of panicking or allocating huge amounts of
+// memory. The seed corpus contains the type ids of all registered
+// service requests and responses followed by a body of zeros.
+func FuzzDecode(f *testing.F) {
+	svcreg.mu.Lock()
+	ids := make([]string, 0, len(svcreg.types))
+	for id := range svcreg.types {
+		ids = append(ids, id)
+	}
+	svcreg.mu.Unlock()
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		typeID := NewExpandedNodeID(MustParseNodeID(id), "", 0)
+		b, err := Encode(typeID)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(append(b, make([]byte, 64)...))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		DecodeService(b)
+	})
+}
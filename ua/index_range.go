@@ -0,0 +1,78 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gopcua/opcua/errors"
+)
+
+// IndexRange is the range of indexes of one dimension of a NumericRange.
+// Min and Max are equal for a single index.
+type IndexRange struct {
+	Min, Max uint32
+}
+
+// Len returns the number of indexes in the range.
+func (r IndexRange) Len() int {
+	return int(r.Max-r.Min) + 1
+}
+
+func (r IndexRange) String() string {
+	if r.Min == r.Max {
+		return strconv.FormatUint(uint64(r.Min), 10)
+	}
+	return strconv.FormatUint(uint64(r.Min), 10) + ":" + strconv.FormatUint(uint64(r.Max), 10)
+}
+
+// ParseIndexRange parses the string representation of a NumericRange
+// which selects a part of an array value, e.g. "10:20" for the elements
+// 10 to 20 of an array or "1:3,4:6" for a part of a matrix. Each
+// dimension is either a single index or a range where the lower bound is
+// less than the upper bound.
+//
+// The error wraps StatusBadIndexRangeInvalid if s is not a valid range.
+//
+// See Part 4, 7.27
+func ParseIndexRange(s string) ([]IndexRange, error) {
+	if s == "" {
+		return nil, errors.Errorf("index range is empty: %w", StatusBadIndexRangeInvalid)
+	}
+	dims := strings.Split(s, ",")
+	ranges := make([]IndexRange, len(dims))
+	for i, d := range dims {
+		lo, hi, isRange := strings.Cut(d, ":")
+		first, err := parseIndex(lo)
+		if err != nil {
+			return nil, errors.Errorf("index range %q: %w", s, err)
+		}
+		last := first
+		if isRange {
+			if last, err = parseIndex(hi); err != nil {
+				return nil, errors.Errorf("index range %q: %w", s, err)
+			}
+			if first >= last {
+				return nil, errors.Errorf("index range %q: lower bound %d must be less than upper bound %d: %w", s, first, last, StatusBadIndexRangeInvalid)
+			}
+		}
+		ranges[i] = IndexRange{Min: first, Max: last}
+	}
+	return ranges, nil
+}
+
+// parseIndex parses a single unsigned index. Signs
+// and surrounding whitespace are not allowed.
+func parseIndex(s string) (uint32, error) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, errors.Errorf("invalid index %q: %w", s, StatusBadIndexRangeInvalid)
+	}
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, errors.Errorf("invalid index %q: %w", s, StatusBadIndexRangeInvalid)
+	}
+	return uint32(n), nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIndexRange(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []IndexRange
+	}{
+		{"5", []IndexRange{{5, 5}}},
+		{"10:20", []IndexRange{{10, 20}}},
+		{"1:3,4:6", []IndexRange{{1, 3}, {4, 6}}},
+		{"0:1,2,3:4", []IndexRange{{0, 1}, {2, 2}, {3, 4}}},
+		{"4294967294:4294967295", []IndexRange{{4294967294, 4294967295}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseIndexRange(tt.s)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, s := range []string{"", ":", "1:", ":2", "2:1", "3:3", "-1", "+1", "1:2:3", "a", "1,", " 1", "1, 2", "4294967296"} {
+		t.Run(s, func(t *testing.T) {
+			_, err := ParseIndexRange(s)
+			require.ErrorIs(t, err, StatusBadIndexRangeInvalid)
+		})
+	}
+}
+
+func TestIndexRange(t *testing.T) {
+	require.Equal(t, "5", IndexRange{5, 5}.String())
+	require.Equal(t, "10:20", IndexRange{10, 20}.String())
+	require.Equal(t, 1, IndexRange{5, 5}.Len())
+	require.Equal(t, 11, IndexRange{10, 20}.Len())
+}
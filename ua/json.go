@@ -0,0 +1,757 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua/debug"
+	"github.com/gopcua/opcua/errors"
+)
+
+// This file implements the reversible form of the OPC UA JSON encoding
+// for the built-in types.
+//
+// Node ids and expanded node ids use their string form and extension
+// objects are identified by the node id of their binary encoding.
+//
+// Specification: Part 6, 5.4
+
+var jsonNull = []byte("null")
+
+func isJSONNull(b []byte) bool {
+	return bytes.Equal(bytes.TrimSpace(b), jsonNull)
+}
+
+type jsonVariant struct {
+	Type       TypeID          `json:"Type"`
+	Body       json.RawMessage `json:"Body,omitempty"`
+	Dimensions []int32         `json:"Dimensions,omitempty"`
+}
+
+// MarshalJSON encodes the variant as a JSON object with the type id and
+// the value. Multi-dimensional arrays are flattened and the dimensions
+// are stored separately. Nil arrays are encoded as empty arrays since
+// JSON null denotes a scalar default value. A null variant is encoded
+// as JSON null.
+func (m *Variant) MarshalJSON() ([]byte, error) {
+	if m == nil || m.Type() == TypeIDNull {
+		return jsonNull, nil
+	}
+
+	v := jsonVariant{Type: m.Type()}
+	if m.Has(VariantArrayDimensions) {
+		v.Dimensions = m.arrayDimensions
+	}
+
+	var body interface{}
+	val := reflect.ValueOf(m.value)
+	switch {
+	case !m.Has(VariantArrayValues):
+		b, err := jsonValue(m.Type(), m.value)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+
+	default:
+		vals := []interface{}{}
+		if err := flattenJSON(m.Type(), val, &vals); err != nil {
+			return nil, err
+		}
+		body = vals
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	v.Body = b
+	return json.Marshal(v)
+}
+
+// flattenJSON appends the JSON values of a one or multi-dimensional
+// slice to vals.
+func flattenJSON(t TypeID, val reflect.Value, vals *[]interface{}) error {
+	if val.Kind() != reflect.Slice || t == TypeIDByteString && val.Type().Elem().Kind() == reflect.Uint8 {
+		v, err := jsonValue(t, val.Interface())
+		if err != nil {
+			return err
+		}
+		*vals = append(*vals, v)
+		return nil
+	}
+	for i := 0; i < val.Len(); i++ {
+		if err := flattenJSON(t, val.Index(i), vals); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a variant from its JSON object.
+func (m *Variant) UnmarshalJSON(b []byte) error {
+	*m = Variant{}
+	if isJSONNull(b) {
+		return nil
+	}
+
+	var v jsonVariant
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v.Type == TypeIDNull {
+		return nil
+	}
+	typ, ok := variantTypeIDToType[v.Type]
+	if !ok {
+		return errors.Errorf("invalid type id: %d", v.Type)
+	}
+
+	body := bytes.TrimSpace(v.Body)
+	if len(body) == 0 || body[0] != '[' {
+		val, err := decodeJSONValue(v.Type, v.Body)
+		if err != nil {
+			return err
+		}
+		m.setType(v.Type)
+		m.value = val
+		return nil
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return err
+	}
+	if len(raw) > MaxVariantArrayLength {
+		return StatusBadEncodingLimitsExceeded
+	}
+
+	sliceType := reflect.SliceOf(typ)
+	if v.Type == TypeIDByte {
+		sliceType = reflect.TypeOf(ByteArray{})
+	}
+	vals := reflect.MakeSlice(sliceType, len(raw), len(raw))
+	for i, r := range raw {
+		val, err := decodeJSONValue(v.Type, r)
+		if err != nil {
+			return err
+		}
+		if val != nil {
+			vals.Index(i).Set(reflect.ValueOf(val))
+		}
+	}
+
+	if len(v.Dimensions) < 2 {
+		return m.set(vals.Interface())
+	}
+
+	count := 1
+	dims := make([]int, len(v.Dimensions))
+	for i, d := range v.Dimensions {
+		if d < 1 {
+			return StatusBadEncodingLimitsExceeded
+		}
+		dims[i] = int(d)
+		count *= dims[i]
+	}
+	if count != vals.Len() {
+		return errUnbalancedSlice
+	}
+	return m.set(split(0, 0, vals.Len(), dims, vals).Interface())
+}
+
+// jsonValue returns the value of a built-in type in a form which
+// encoding/json marshals according to the OPC UA JSON encoding.
+func jsonValue(t TypeID, v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case int64:
+		return strconv.FormatInt(x, 10), nil
+	case uint64:
+		return strconv.FormatUint(x, 10), nil
+	case float32:
+		return jsonFloat(float64(x)), nil
+	case float64:
+		return jsonFloat(x), nil
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano), nil
+	case XMLElement:
+		return string(x), nil
+	case StatusCode:
+		return uint32(x), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x), nil
+	case *GUID, *NodeID, *ExpandedNodeID, *QualifiedName, *LocalizedText, *ExtensionObject, *DataValue, *Variant, *DiagnosticInfo:
+		if reflect.ValueOf(v).IsNil() {
+			return nil, nil
+		}
+		return v, nil
+	case bool, int8, uint8, int16, uint16, int32, uint32, string:
+		return v, nil
+	default:
+		return nil, errors.Errorf("invalid value %T for type %d", v, t)
+	}
+}
+
+// jsonFloat returns the special values NaN and +/- infinity as strings
+// since JSON numbers cannot represent them.
+func jsonFloat(f float64) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	default:
+		return f
+	}
+}
+
+func decodeJSONFloat(b []byte, bits int) (float64, error) {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		switch s {
+		case "NaN":
+			return math.NaN(), nil
+		case "Infinity":
+			return math.Inf(1), nil
+		case "-Infinity":
+			return math.Inf(-1), nil
+		default:
+			return strconv.ParseFloat(s, bits)
+		}
+	}
+	var f float64
+	err := json.Unmarshal(b, &f)
+	return f, err
+}
+
+// decodeJSONInt decodes a JSON number or a number encoded as string.
+func decodeJSONInt(b []byte, v interface{}) error {
+	b = bytes.Trim(bytes.TrimSpace(b), `"`)
+	return json.Unmarshal(b, v)
+}
+
+// decodeJSONValue decodes a single value of a built-in type.
+func decodeJSONValue(t TypeID, b []byte) (interface{}, error) {
+	if len(bytes.TrimSpace(b)) == 0 || isJSONNull(b) {
+		switch t {
+		case TypeIDGUID, TypeIDNodeID, TypeIDExpandedNodeID, TypeIDQualifiedName, TypeIDLocalizedText,
+			TypeIDExtensionObject, TypeIDDataValue, TypeIDVariant, TypeIDDiagnosticInfo:
+			return nil, nil
+		}
+		return reflect.Zero(variantTypeIDToType[t]).Interface(), nil
+	}
+
+	var err error
+	switch t {
+	case TypeIDBoolean:
+		var v bool
+		err = json.Unmarshal(b, &v)
+		return v, err
+	case TypeIDSByte:
+		var v int8
+		err = decodeJSONInt(b, &v)
+		return v, err
+	case TypeIDByte:
+		var v uint8
+		err = decodeJSONInt(b, &v)
+		return v, err
+	case TypeIDInt16:
+		var v int16
+		err = decodeJSONInt(b, &v)
+		return v, err
+	case TypeIDUint16:
+		var v uint16
+		err = decodeJSONInt(b, &v)
+		return v, err
+	case TypeIDInt32:
+		var v int32
+		err = decodeJSONInt(b, &v)
+		return v, err
+	case TypeIDUint32:
+		var v uint32
+		err = decodeJSONInt(b, &v)
+		return v, err
+	case TypeIDInt64:
+		var v int64
+		err = decodeJSONInt(b, &v)
+		return v, err
+	case TypeIDUint64:
+		var v uint64
+		err = decodeJSONInt(b, &v)
+		return v, err
+	case TypeIDFloat:
+		v, err := decodeJSONFloat(b, 32)
+		return float32(v), err
+	case TypeIDDouble:
+		return decodeJSONFloat(b, 64)
+	case TypeIDString:
+		var v string
+		err = json.Unmarshal(b, &v)
+		return v, err
+	case TypeIDDateTime:
+		var v time.Time
+		err = json.Unmarshal(b, &v)
+		return v.UTC(), err
+	case TypeIDByteString:
+		var v []byte
+		err = json.Unmarshal(b, &v)
+		return v, err
+	case TypeIDXMLElement:
+		var v string
+		err = json.Unmarshal(b, &v)
+		return XMLElement(v), err
+	case TypeIDStatusCode:
+		var v uint32
+		err = decodeJSONInt(b, &v)
+		return StatusCode(v), err
+	}
+
+	typ, ok := variantTypeIDToType[t]
+	if !ok || typ.Kind() != reflect.Ptr {
+		return nil, errors.Errorf("invalid type id: %d", t)
+	}
+	v := reflect.New(typ.Elem()).Interface()
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+type jsonDataValue struct {
+	Value             *Variant   `json:"Value,omitempty"`
+	Status            StatusCode `json:"Status,omitempty"`
+	SourceTimestamp   *time.Time `json:"SourceTimestamp,omitempty"`
+	SourcePicoseconds uint16     `json:"SourcePicoseconds,omitempty"`
+	ServerTimestamp   *time.Time `json:"ServerTimestamp,omitempty"`
+	ServerPicoseconds uint16     `json:"ServerPicoseconds,omitempty"`
+}
+
+// MarshalJSON encodes the data value as a JSON object. Fields which
+// are not set in the encoding mask are omitted.
+func (d *DataValue) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return jsonNull, nil
+	}
+	var v jsonDataValue
+	if d.Has(DataValueValue) && d.Value != nil && d.Value.Type() != TypeIDNull {
+		v.Value = d.Value
+	}
+	if d.Has(DataValueStatusCode) {
+		v.Status = d.Status
+	}
+	if d.Has(DataValueSourceTimestamp) {
+		t := d.SourceTimestamp.UTC()
+		v.SourceTimestamp = &t
+	}
+	if d.Has(DataValueSourcePicoseconds) {
+		v.SourcePicoseconds = d.SourcePicoseconds
+	}
+	if d.Has(DataValueServerTimestamp) {
+		t := d.ServerTimestamp.UTC()
+		v.ServerTimestamp = &t
+	}
+	if d.Has(DataValueServerPicoseconds) {
+		v.ServerPicoseconds = d.ServerPicoseconds
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a data value from its JSON object and updates
+// the encoding mask.
+func (d *DataValue) UnmarshalJSON(b []byte) error {
+	if isJSONNull(b) {
+		return nil
+	}
+	var v jsonDataValue
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*d = DataValue{
+		Value:             v.Value,
+		Status:            v.Status,
+		SourcePicoseconds: v.SourcePicoseconds,
+		ServerPicoseconds: v.ServerPicoseconds,
+	}
+	if v.SourceTimestamp != nil {
+		d.SourceTimestamp = v.SourceTimestamp.UTC()
+	}
+	if v.ServerTimestamp != nil {
+		d.ServerTimestamp = v.ServerTimestamp.UTC()
+	}
+	d.UpdateMask()
+	return nil
+}
+
+type jsonExtensionObject struct {
+	TypeID   *ExpandedNodeID `json:"TypeId,omitempty"`
+	Encoding uint8           `json:"Encoding,omitempty"`
+	Body     json.RawMessage `json:"Body,omitempty"`
+}
+
+// MarshalJSON encodes the extension object as a JSON object with the
+// node id of the binary encoding and the JSON encoded value. XML bodies
+// are encoded as string. An empty extension object is encoded as null.
+func (e *ExtensionObject) MarshalJSON() ([]byte, error) {
+	if e == nil || e.EncodingMask == ExtensionObjectEmpty || e.Value == nil {
+		return jsonNull, nil
+	}
+
+	v := jsonExtensionObject{TypeID: e.TypeID}
+	var body interface{} = e.Value
+	if x, ok := e.Value.(*XMLElement); ok {
+		v.Encoding = ExtensionObjectXML
+		body = string(*x)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	v.Body = b
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes an extension object from its JSON object.
+// Bodies of unknown types are ignored like in the binary decoder.
+func (e *ExtensionObject) UnmarshalJSON(b []byte) error {
+	*e = ExtensionObject{}
+	if isJSONNull(b) {
+		e.TypeID = NewTwoByteExpandedNodeID(0)
+		return nil
+	}
+
+	var v jsonExtensionObject
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	e.TypeID = v.TypeID
+	if e.TypeID == nil {
+		e.TypeID = NewTwoByteExpandedNodeID(0)
+	}
+	if len(v.Body) == 0 || isJSONNull(v.Body) {
+		e.UpdateMask()
+		return nil
+	}
+
+	switch v.Encoding {
+	case ExtensionObjectBinary:
+		var body []byte
+		if err := json.Unmarshal(v.Body, &body); err != nil {
+			return err
+		}
+		e.EncodingMask = ExtensionObjectBinary
+		buf := NewBuffer(nil)
+		buf.WriteStruct(e.TypeID)
+		buf.WriteByte(ExtensionObjectBinary)
+		buf.WriteUint32(uint32(len(body)))
+		buf.Write(body)
+		_, err := e.Decode(buf.Bytes())
+		return err
+
+	case ExtensionObjectXML:
+		var s string
+		if err := json.Unmarshal(v.Body, &s); err != nil {
+			return err
+		}
+		x := XMLElement(s)
+		e.Value = &x
+
+	default:
+		typeID := e.TypeID.NodeID
+		e.Value = eotypes.New(typeID)
+		if e.Value == nil {
+			if s := newStructureByEncoding(typeID); s != nil {
+				e.Value = s
+			}
+		}
+		if e.Value == nil {
+			debug.Printf("ua: unknown extension object %s", typeID)
+			return nil
+		}
+		if err := json.Unmarshal(v.Body, e.Value); err != nil {
+			return err
+		}
+	}
+	e.UpdateMask()
+	return nil
+}
+
+// MarshalJSON encodes the expanded node id in its string form with the
+// optional "svr=" and "nsu=" prefixes.
+func (e *ExpandedNodeID) MarshalJSON() ([]byte, error) {
+	if e == nil || e.NodeID == nil {
+		return jsonNull, nil
+	}
+	var sb strings.Builder
+	if e.HasServerIndex() {
+		sb.WriteString("svr=" + strconv.FormatUint(uint64(e.ServerIndex), 10) + ";")
+	}
+	s := e.NodeID.String()
+	if e.HasNamespaceURI() {
+		if i := strings.Index(s, ";"); strings.HasPrefix(s, "ns=") && i > 0 {
+			s = s[i+1:]
+		}
+		sb.WriteString("nsu=" + e.NamespaceURI + ";")
+	}
+	sb.WriteString(s)
+	return json.Marshal(sb.String())
+}
+
+// UnmarshalJSON decodes an expanded node id from its string form.
+func (e *ExpandedNodeID) UnmarshalJSON(b []byte) error {
+	if isJSONNull(b) {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	var idx uint32
+	if strings.HasPrefix(s, "svr=") {
+		p := strings.SplitN(s[4:], ";", 2)
+		if len(p) != 2 {
+			return errors.Errorf("invalid expanded node id: %s", s)
+		}
+		n, err := strconv.ParseUint(p[0], 10, 32)
+		if err != nil {
+			return errors.Errorf("invalid server index: %s", s)
+		}
+		idx, s = uint32(n), p[1]
+	}
+
+	var uri string
+	if strings.HasPrefix(s, "nsu=") {
+		p := strings.SplitN(s[4:], ";", 2)
+		if len(p) != 2 {
+			return errors.Errorf("invalid expanded node id: %s", s)
+		}
+		uri, s = p[0], p[1]
+	}
+
+	n, err := ParseNodeID(s)
+	if err != nil {
+		return err
+	}
+	*e = *NewExpandedNodeID(n, uri, idx)
+	return nil
+}
+
+// MarshalJSON encodes the GUID in its string form.
+func (g *GUID) MarshalJSON() ([]byte, error) {
+	if g == nil {
+		return jsonNull, nil
+	}
+	return json.Marshal(g.String())
+}
+
+// UnmarshalJSON decodes a GUID from its string form.
+func (g *GUID) UnmarshalJSON(b []byte) error {
+	if isJSONNull(b) {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v := NewGUID(s)
+	if v == nil {
+		return errors.Errorf("invalid guid: %s", s)
+	}
+	*g = *v
+	return nil
+}
+
+type jsonQualifiedName struct {
+	Name string `json:"Name,omitempty"`
+	URI  uint16 `json:"Uri,omitempty"`
+}
+
+// MarshalJSON encodes the qualified name as a JSON object with the name
+// and the namespace index.
+func (q *QualifiedName) MarshalJSON() ([]byte, error) {
+	if q == nil {
+		return jsonNull, nil
+	}
+	return json.Marshal(jsonQualifiedName{Name: q.Name, URI: q.NamespaceIndex})
+}
+
+// UnmarshalJSON decodes a qualified name from its JSON object.
+func (q *QualifiedName) UnmarshalJSON(b []byte) error {
+	if isJSONNull(b) {
+		return nil
+	}
+	var v jsonQualifiedName
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*q = QualifiedName{NamespaceIndex: v.URI, Name: v.Name}
+	return nil
+}
+
+type jsonLocalizedText struct {
+	Locale string `json:"Locale,omitempty"`
+	Text   string `json:"Text,omitempty"`
+}
+
+// MarshalJSON encodes the localized text as a JSON object with the
+// locale and the text.
+func (l *LocalizedText) MarshalJSON() ([]byte, error) {
+	if l == nil {
+		return jsonNull, nil
+	}
+	return json.Marshal(jsonLocalizedText{Locale: l.Locale, Text: l.Text})
+}
+
+// UnmarshalJSON decodes a localized text from its JSON object and
+// updates the encoding mask.
+func (l *LocalizedText) UnmarshalJSON(b []byte) error {
+	if isJSONNull(b) {
+		return nil
+	}
+	var v jsonLocalizedText
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*l = LocalizedText{Locale: v.Locale, Text: v.Text}
+	l.UpdateMask()
+	return nil
+}
+
+// MarshalJSON encodes the fields of the structure as a JSON object.
+func (s *Structure) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return jsonNull, nil
+	}
+	if s.def == nil {
+		return nil, errors.Errorf("structure %s has no definition", s.TypeID)
+	}
+
+	fields := make(map[string]interface{}, len(s.Fields))
+	for _, f := range s.def.Fields {
+		v, ok := s.Fields[f.Name]
+		if !ok {
+			continue
+		}
+		fc, err := lookupFieldCodec(f.DataType)
+		if err != nil {
+			return nil, errors.Errorf("field %s: %s", f.Name, err)
+		}
+		if fc.builtin == 0 || v == nil {
+			fields[f.Name] = v
+			continue
+		}
+		if f.ValueRank < 0 {
+			if fields[f.Name], err = jsonValue(fc.builtin, v); err != nil {
+				return nil, errors.Errorf("field %s: %s", f.Name, err)
+			}
+			continue
+		}
+		val := reflect.ValueOf(v)
+		if val.Kind() != reflect.Slice {
+			return nil, errors.Errorf("field %s: invalid value %T, want slice", f.Name, v)
+		}
+		if val.IsNil() {
+			fields[f.Name] = nil
+			continue
+		}
+		vals := []interface{}{}
+		if err := flattenJSON(fc.builtin, val, &vals); err != nil {
+			return nil, errors.Errorf("field %s: %s", f.Name, err)
+		}
+		fields[f.Name] = vals
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes the fields of the structure from a JSON object.
+// The structure must have been created by NewStructure or by decoding
+// an extension object.
+func (s *Structure) UnmarshalJSON(b []byte) error {
+	if isJSONNull(b) {
+		return nil
+	}
+	if s.def == nil {
+		return errors.Errorf("structure %s has no definition", s.TypeID)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	s.Fields = make(map[string]interface{})
+	for _, f := range s.def.Fields {
+		r, ok := raw[f.Name]
+		if !ok {
+			continue
+		}
+		fc, err := lookupFieldCodec(f.DataType)
+		if err != nil {
+			return errors.Errorf("field %s: %s", f.Name, err)
+		}
+
+		if f.ValueRank < 0 {
+			v, err := fc.decodeJSON(r)
+			if err != nil {
+				return errors.Errorf("field %s: %s", f.Name, err)
+			}
+			s.Fields[f.Name] = v
+			continue
+		}
+
+		sliceType := reflect.SliceOf(fc.elemType())
+		if isJSONNull(r) {
+			s.Fields[f.Name] = reflect.Zero(sliceType).Interface()
+			continue
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(r, &elems); err != nil {
+			return errors.Errorf("field %s: %s", f.Name, err)
+		}
+		vals := reflect.MakeSlice(sliceType, len(elems), len(elems))
+		for i, e := range elems {
+			v, err := fc.decodeJSON(e)
+			if err != nil {
+				return errors.Errorf("field %s: %s", f.Name, err)
+			}
+			if v != nil {
+				vals.Index(i).Set(reflect.ValueOf(v))
+			}
+		}
+		s.Fields[f.Name] = vals.Interface()
+	}
+	return nil
+}
+
+func (fc fieldCodec) decodeJSON(b []byte) (interface{}, error) {
+	switch {
+	case fc.eotype != nil:
+		v := reflect.New(fc.eotype.Elem()).Interface()
+		if err := json.Unmarshal(b, v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case fc.structure != nil:
+		v := &Structure{TypeID: fc.structure.dataTypeID, def: fc.structure.def}
+		if err := json.Unmarshal(b, v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	default:
+		return decodeJSONValue(fc.builtin, b)
+	}
+}
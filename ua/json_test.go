@@ -0,0 +1,80 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariantJSON(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC)
+
+	cases := []struct {
+		name string
+		v    *Variant
+		json string
+	}{
+		{"null", &Variant{}, `null`},
+		{"bool", MustVariant(true), `{"Type":1,"Body":true}`},
+		{"int32", MustVariant(int32(-5)), `{"Type":6,"Body":-5}`},
+		{"int64", MustVariant(int64(math.MaxInt64)), `{"Type":8,"Body":"9223372036854775807"}`},
+		{"uint64", MustVariant(uint64(math.MaxUint64)), `{"Type":9,"Body":"18446744073709551615"}`},
+		{"double", MustVariant(1.5), `{"Type":11,"Body":1.5}`},
+		{"double inf", MustVariant(math.Inf(-1)), `{"Type":11,"Body":"-Infinity"}`},
+		{"string", MustVariant("abc"), `{"Type":12,"Body":"abc"}`},
+		{"datetime", MustVariant(ts), `{"Type":13,"Body":"2024-01-02T03:04:05.6Z"}`},
+		{"guid", MustVariant(NewGUID("72962B91-FA75-4AE6-8D28-B404DC7DAF63")), `{"Type":14,"Body":"72962B91-FA75-4AE6-8D28-B404DC7DAF63"}`},
+		{"bytestring", MustVariant([]byte{1, 2, 3}), `{"Type":15,"Body":"AQID"}`},
+		{"nodeid", MustVariant(NewStringNodeID(2, "foo")), `{"Type":17,"Body":"ns=2;s=foo"}`},
+		{"expandednodeid", MustVariant(NewExpandedNodeID(NewTwoByteNodeID(85), "urn:x", 1)), `{"Type":18,"Body":"svr=1;nsu=urn:x;i=85"}`},
+		{"statuscode", MustVariant(StatusBadNodeIDUnknown), `{"Type":19,"Body":2150891520}`},
+		{"qualifiedname", MustVariant(&QualifiedName{NamespaceIndex: 2, Name: "a"}), `{"Type":20,"Body":{"Name":"a","Uri":2}}`},
+		{"localizedtext", MustVariant(NewLocalizedTextWithLocale("b", "en")), `{"Type":21,"Body":{"Locale":"en","Text":"b"}}`},
+		{
+			"extensionobject",
+			MustVariant(NewExtensionObject(&AnonymousIdentityToken{PolicyID: "anonymous"})),
+			`{"Type":22,"Body":{"TypeId":"i=321","Body":{"PolicyID":"anonymous"}}}`,
+		},
+		{"empty array", MustVariant([]int32{}), `{"Type":6,"Body":[]}`},
+		{"byte array", MustVariant(ByteArray{1, 2}), `{"Type":3,"Body":[1,2]}`},
+		{"int64 array", MustVariant([]int64{1, 2}), `{"Type":8,"Body":["1","2"]}`},
+		{"matrix", MustVariant([][]int32{{1, 2, 3}, {4, 5, 6}}), `{"Type":6,"Body":[1,2,3,4,5,6],"Dimensions":[2,3]}`},
+		{"variant array", MustVariant([]*Variant{MustVariant("a"), MustVariant(int32(1))}), `{"Type":24,"Body":[{"Type":12,"Body":"a"},{"Type":6,"Body":1}]}`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b, err := json.Marshal(c.v)
+			require.NoError(t, err)
+			require.JSONEq(t, c.json, string(b))
+
+			v := new(Variant)
+			require.NoError(t, json.Unmarshal(b, v))
+			require.Equal(t, c.v, v)
+		})
+	}
+}
+
+func TestDataValueJSON(t *testing.T) {
+	dv := &DataValue{
+		Value:           MustVariant(int32(42)),
+		Status:          StatusUncertain,
+		SourceTimestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	dv.UpdateMask()
+
+	b, err := json.Marshal(dv)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"Value":{"Type":6,"Body":42},"Status":1073741824,"SourceTimestamp":"2024-01-02T03:04:05Z"}`, string(b))
+
+	got := new(DataValue)
+	require.NoError(t, json.Unmarshal(b, got))
+	require.Equal(t, dv, got)
+}
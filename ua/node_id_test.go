@@ -161,7 +161,7 @@ func TestParseNodeID(t *testing.T) {
 		{s: "abc=0;i=2", err: errors.New("invalid node id: abc=0;i=2")},
 		{s: "ns=0;i=1;s=2", err: errors.New("invalid numeric id: ns=0;i=1;s=2")},
 		{s: "ns=0", err: errors.New("invalid node id: ns=0")},
-		{s: "nsu=abc;i=1", err: errors.New("namespace urls require a server NamespaceArray")},
+		{s: "nsu=abc;i=1", err: errors.New("namespace uris are not supported. use `ua.ParseExpandedNodeID`")},
 		{s: "ns=65536;i=1", err: errors.New("namespace id out of range (0..65535): ns=65536;i=1")},
 		{s: "ns=abc;i=1", err: errors.New("invalid namespace id: ns=abc;i=1")},
 		{s: "ns=1;i=abc", err: errors.New("invalid numeric id: ns=1;i=abc")},
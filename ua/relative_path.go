@@ -0,0 +1,136 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/id"
+)
+
+// ParseRelativePath returns a relative path from its text format as
+// defined in Part 4, A.2, e.g.
+//
+//	/Objects/2:MyDevice/2:Temperature
+//	/2:Block&.Name
+//	<HasComponent>2:Pump.2:Speed
+//	<!Organizes>Objects
+//
+// A '/' follows any hierarchical reference, a '.' follows any
+// aggregates reference and '<RefType>' follows the given reference
+// type including its subtypes. The reference type can be prefixed with
+// '#' to exclude subtypes and with '!' to follow the inverse reference.
+// Reference types are resolved by their browse name in namespace zero
+// and are matched case-insensitive, e.g. <hasComponent>.
+//
+// Reserved characters in browse names must be escaped with '&'.
+// The target name of the last element can be omitted to match
+// all targets.
+func ParseRelativePath(s string) (*RelativePath, error) {
+	p := &RelativePath{}
+	for i := 0; i < len(s); {
+		e := &RelativePathElement{IncludeSubtypes: true}
+		switch s[i] {
+		case '/':
+			e.ReferenceTypeID = NewNumericNodeID(0, id.HierarchicalReferences)
+			i++
+
+		case '.':
+			e.ReferenceTypeID = NewNumericNodeID(0, id.Aggregates)
+			i++
+
+		case '<':
+			i++
+			for ; i < len(s) && (s[i] == '#' || s[i] == '!'); i++ {
+				if s[i] == '#' {
+					e.IncludeSubtypes = false
+				} else {
+					e.IsInverse = true
+				}
+			}
+			qn, n, err := parseBrowseName(s[i:])
+			if err != nil {
+				return nil, err
+			}
+			i += n
+			if i >= len(s) || s[i] != '>' {
+				return nil, errors.Errorf("invalid relative path %q: missing '>'", s)
+			}
+			i++
+			if qn.NamespaceIndex != 0 {
+				return nil, errors.Errorf("invalid relative path %q: reference type %d:%s not in namespace 0", s, qn.NamespaceIndex, qn.Name)
+			}
+			refID, ok := id.ReferenceTypeID(qn.Name)
+			if !ok {
+				return nil, errors.Errorf("invalid relative path %q: unknown reference type %s", s, qn.Name)
+			}
+			e.ReferenceTypeID = NewNumericNodeID(0, refID)
+
+		default:
+			return nil, errors.Errorf("invalid relative path %q: unexpected %q at position %d", s, s[i], i)
+		}
+
+		qn, n, err := parseBrowseName(s[i:])
+		if err != nil {
+			return nil, err
+		}
+		i += n
+		if qn.Name == "" && i < len(s) {
+			return nil, errors.Errorf("invalid relative path %q: missing target name at position %d", s, i)
+		}
+		e.TargetName = qn
+		p.Elements = append(p.Elements, e)
+	}
+	return p, nil
+}
+
+// parseBrowseName parses an escaped browse name with an optional
+// namespace index prefix from the start of s. It returns the browse
+// name and the number of consumed bytes.
+func parseBrowseName(s string) (*QualifiedName, int, error) {
+	var (
+		qn    = &QualifiedName{}
+		name  strings.Builder
+		hasNS bool
+		i     int
+	)
+
+loop:
+	for ; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '/', '.', '<', '>':
+			break loop
+
+		case '&':
+			i++
+			if i >= len(s) {
+				return nil, 0, errors.Errorf("invalid browse name %q: incomplete escape sequence", s)
+			}
+			name.WriteByte(s[i])
+
+		case ':':
+			if hasNS || name.Len() == 0 {
+				return nil, 0, errors.Errorf("invalid browse name %q: unescaped ':'", s)
+			}
+			ns, err := strconv.ParseUint(name.String(), 10, 16)
+			if err != nil {
+				return nil, 0, errors.Errorf("invalid browse name %q: invalid namespace index", s)
+			}
+			qn.NamespaceIndex = uint16(ns)
+			hasNS = true
+			name.Reset()
+
+		case '#', '!':
+			return nil, 0, errors.Errorf("invalid browse name %q: unescaped %q", s, c)
+
+		default:
+			name.WriteByte(c)
+		}
+	}
+	qn.Name = name.String()
+	return qn, i, nil
+}
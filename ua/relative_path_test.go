@@ -0,0 +1,107 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"testing"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRelativePath(t *testing.T) {
+	hier := func(ns uint16, name string) *RelativePathElement {
+		return &RelativePathElement{
+			ReferenceTypeID: NewNumericNodeID(0, id.HierarchicalReferences),
+			IncludeSubtypes: true,
+			TargetName:      &QualifiedName{NamespaceIndex: ns, Name: name},
+		}
+	}
+	aggr := func(ns uint16, name string) *RelativePathElement {
+		return &RelativePathElement{
+			ReferenceTypeID: NewNumericNodeID(0, id.Aggregates),
+			IncludeSubtypes: true,
+			TargetName:      &QualifiedName{NamespaceIndex: ns, Name: name},
+		}
+	}
+
+	cases := []struct {
+		s   string
+		p   *RelativePath
+		err bool
+	}{
+		{s: "", p: &RelativePath{}},
+		{
+			s: "/Objects/2:MyDevice/2:Temperature",
+			p: &RelativePath{Elements: []*RelativePathElement{
+				hier(0, "Objects"),
+				hier(2, "MyDevice"),
+				hier(2, "Temperature"),
+			}},
+		},
+		{
+			s: "/2:Block&.Name.2:Speed",
+			p: &RelativePath{Elements: []*RelativePathElement{
+				hier(2, "Block.Name"),
+				aggr(2, "Speed"),
+			}},
+		},
+		{
+			s: "<hasComponent>2:Pump",
+			p: &RelativePath{Elements: []*RelativePathElement{
+				{
+					ReferenceTypeID: NewNumericNodeID(0, id.HasComponent),
+					IncludeSubtypes: true,
+					TargetName:      &QualifiedName{NamespaceIndex: 2, Name: "Pump"},
+				},
+			}},
+		},
+		{
+			s: "<#!Organizes>Objects",
+			p: &RelativePath{Elements: []*RelativePathElement{
+				{
+					ReferenceTypeID: NewNumericNodeID(0, id.Organizes),
+					IsInverse:       true,
+					IncludeSubtypes: false,
+					TargetName:      &QualifiedName{Name: "Objects"},
+				},
+			}},
+		},
+		{
+			s: "/Objects<HasChild>",
+			p: &RelativePath{Elements: []*RelativePathElement{
+				hier(0, "Objects"),
+				{
+					ReferenceTypeID: NewNumericNodeID(0, id.HasChild),
+					IncludeSubtypes: true,
+					TargetName:      &QualifiedName{},
+				},
+			}},
+		},
+
+		// error flows
+		{s: "Objects", err: true},
+		{s: "/a:b", err: true},
+		{s: "/1:2:b", err: true},
+		{s: "/a#b", err: true},
+		{s: "/a&", err: true},
+		{s: "//b", err: true},
+		{s: "<HasComponent", err: true},
+		{s: "<Foo>a", err: true},
+		{s: "<2:HasComponent>a", err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			p, err := ParseRelativePath(c.s)
+			if c.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.p, p)
+		})
+	}
+}
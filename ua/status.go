@@ -0,0 +1,131 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StatusSeverity is the severity of a status code.
+//
+// Specification: Part 4, 7.39
+type StatusSeverity uint8
+
+const (
+	StatusSeverityGood      StatusSeverity = 0
+	StatusSeverityUncertain StatusSeverity = 1
+	StatusSeverityBad       StatusSeverity = 2
+)
+
+func (s StatusSeverity) String() string {
+	switch s {
+	case StatusSeverityGood:
+		return "Good"
+	case StatusSeverityUncertain:
+		return "Uncertain"
+	default:
+		return "Bad"
+	}
+}
+
+// The bits of a status code. The info bits are only valid
+// if the info type is DataValue.
+const (
+	statusCodeMask         = 0xffff0000
+	statusSubCodeMask      = 0x0fff0000
+	statusStructureChanged = 1 << 15
+	statusSemanticsChanged = 1 << 14
+	statusInfoTypeMask     = 0x0c00
+	statusInfoTypeData     = 0x0400
+	statusOverflow         = 1 << 7
+)
+
+// Severity returns the severity of the status code. Status codes with the
+// reserved severity are treated as bad.
+func (n StatusCode) Severity() StatusSeverity {
+	switch n >> 30 {
+	case 0:
+		return StatusSeverityGood
+	case 1:
+		return StatusSeverityUncertain
+	default:
+		return StatusSeverityBad
+	}
+}
+
+// IsGood returns true if the severity of the status code is good.
+func (n StatusCode) IsGood() bool {
+	return n.Severity() == StatusSeverityGood
+}
+
+// IsUncertain returns true if the severity of the status code is uncertain.
+func (n StatusCode) IsUncertain() bool {
+	return n.Severity() == StatusSeverityUncertain
+}
+
+// IsBad returns true if the severity of the status code is bad.
+func (n StatusCode) IsBad() bool {
+	return n.Severity() == StatusSeverityBad
+}
+
+// Code returns the status code without the info bits, e.g. to compare
+// it with one of the Status constants.
+func (n StatusCode) Code() StatusCode {
+	return n & statusCodeMask
+}
+
+// SubCode returns the sub code of the status code which identifies the
+// condition within its severity.
+func (n StatusCode) SubCode() uint16 {
+	return uint16((n & statusSubCodeMask) >> 16)
+}
+
+// StructureChanged returns true if the structure of the associated data
+// value has changed since the last notification.
+func (n StatusCode) StructureChanged() bool {
+	return n&statusStructureChanged != 0
+}
+
+// SemanticsChanged returns true if the semantics of the associated data
+// value have changed, e.g. the engineering units.
+func (n StatusCode) SemanticsChanged() bool {
+	return n&statusSemanticsChanged != 0
+}
+
+// Overflow returns true if the server had to drop data changes of a
+// monitored item since its queue was full.
+func (n StatusCode) Overflow() bool {
+	return n&statusInfoTypeMask == statusInfoTypeData && n&statusOverflow != 0
+}
+
+// Name returns the name of the Status constant of the status code
+// without the prefix, e.g. "BadNodeIDUnknown". The info bits are
+// ignored. Unknown status codes are returned as hexadecimal number.
+//
+// Use Symbol for the symbolic name of the specification.
+func (n StatusCode) Name() string {
+	if d, ok := StatusCodes[n.Code()]; ok {
+		return strings.TrimPrefix(d.Name, "Status")
+	}
+	return fmt.Sprintf("0x%08X", uint32(n))
+}
+
+// Symbol returns the symbolic name of the status code from the status
+// code table of the specification, e.g. "BadNodeIdUnknown". The info
+// bits are ignored. Unknown status codes are returned as hexadecimal
+// number.
+func (n StatusCode) Symbol() string {
+	if d, ok := StatusCodes[n.Code()]; ok {
+		return d.Symbol
+	}
+	return fmt.Sprintf("0x%08X", uint32(n))
+}
+
+// Description returns the description of the status code from the
+// status code table or an empty string if the status code is unknown.
+func (n StatusCode) Description() string {
+	return StatusCodes[n.Code()].Text
+}
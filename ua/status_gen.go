@@ -12,8 +12,8 @@ import "fmt"
 type StatusCode uint32
 
 func (n StatusCode) Error() string {
-	if d, ok := StatusCodes[n]; ok {
-		return fmt.Sprintf("%s %s (0x%X)", d.Text, d.Name, uint32(n))
+	if d, ok := StatusCodes[n&0xffff0000]; ok {
+		return fmt.Sprintf("%s %s (0x%X)", d.Text, d.Symbol, uint32(n))
 	}
 	return fmt.Sprintf("0x%X", uint32(n))
 }
@@ -287,277 +287,283 @@ var (
 )
 
 type StatusCodeDesc struct {
+	// Name is the name of the Go constant.
 	Name string
+
+	// Symbol is the symbolic name of the specification.
+	Symbol string
+
+	// Text is the description of the status code.
 	Text string
 }
 
 // StatusCodes maps status codes to the status code error types.
 var StatusCodes = map[StatusCode]StatusCodeDesc{
-	StatusOK:                                                       {Name: "OK", Text: ""},
-	StatusUncertain:                                                {Name: "Uncertain", Text: ""},
-	StatusBad:                                                      {Name: "Bad", Text: ""},
-	StatusGood:                                                     {Name: "StatusGood", Text: "The operation succeeded."},
-	StatusUncertain:                                                {Name: "StatusUncertain", Text: "The operation was uncertain."},
-	StatusBad:                                                      {Name: "StatusBad", Text: "The operation failed."},
-	StatusBadUnexpectedError:                                       {Name: "StatusBadUnexpectedError", Text: "An unexpected error occurred."},
-	StatusBadInternalError:                                         {Name: "StatusBadInternalError", Text: "An internal error occurred as a result of a programming or configuration error."},
-	StatusBadOutOfMemory:                                           {Name: "StatusBadOutOfMemory", Text: "Not enough memory to complete the operation."},
-	StatusBadResourceUnavailable:                                   {Name: "StatusBadResourceUnavailable", Text: "An operating system resource is not available."},
-	StatusBadCommunicationError:                                    {Name: "StatusBadCommunicationError", Text: "A low level communication error occurred."},
-	StatusBadEncodingError:                                         {Name: "StatusBadEncodingError", Text: "Encoding halted because of invalid data in the objects being serialized."},
-	StatusBadDecodingError:                                         {Name: "StatusBadDecodingError", Text: "Decoding halted because of invalid data in the stream."},
-	StatusBadEncodingLimitsExceeded:                                {Name: "StatusBadEncodingLimitsExceeded", Text: "The message encoding/decoding limits imposed by the stack have been exceeded."},
-	StatusBadRequestTooLarge:                                       {Name: "StatusBadRequestTooLarge", Text: "The request message size exceeds limits set by the server."},
-	StatusBadResponseTooLarge:                                      {Name: "StatusBadResponseTooLarge", Text: "The response message size exceeds limits set by the client."},
-	StatusBadUnknownResponse:                                       {Name: "StatusBadUnknownResponse", Text: "An unrecognized response was received from the server."},
-	StatusBadTimeout:                                               {Name: "StatusBadTimeout", Text: "The operation timed out."},
-	StatusBadServiceUnsupported:                                    {Name: "StatusBadServiceUnsupported", Text: "The server does not support the requested service."},
-	StatusBadShutdown:                                              {Name: "StatusBadShutdown", Text: "The operation was cancelled because the application is shutting down."},
-	StatusBadServerNotConnected:                                    {Name: "StatusBadServerNotConnected", Text: "The operation could not complete because the client is not connected to the server."},
-	StatusBadServerHalted:                                          {Name: "StatusBadServerHalted", Text: "The server has stopped and cannot process any requests."},
-	StatusBadNothingToDo:                                           {Name: "StatusBadNothingToDo", Text: "No processing could be done because there was nothing to do."},
-	StatusBadTooManyOperations:                                     {Name: "StatusBadTooManyOperations", Text: "The request could not be processed because it specified too many operations."},
-	StatusBadTooManyMonitoredItems:                                 {Name: "StatusBadTooManyMonitoredItems", Text: "The request could not be processed because there are too many monitored items in the subscription."},
-	StatusBadDataTypeIDUnknown:                                     {Name: "StatusBadDataTypeIDUnknown", Text: "The extension object cannot be (de)serialized because the data type id is not recognized."},
-	StatusBadCertificateInvalid:                                    {Name: "StatusBadCertificateInvalid", Text: "The certificate provided as a parameter is not valid."},
-	StatusBadSecurityChecksFailed:                                  {Name: "StatusBadSecurityChecksFailed", Text: "An error occurred verifying security."},
-	StatusBadCertificatePolicyCheckFailed:                          {Name: "StatusBadCertificatePolicyCheckFailed", Text: "The certificate does not meet the requirements of the security policy."},
-	StatusBadCertificateTimeInvalid:                                {Name: "StatusBadCertificateTimeInvalid", Text: "The certificate has expired or is not yet valid."},
-	StatusBadCertificateIssuerTimeInvalid:                          {Name: "StatusBadCertificateIssuerTimeInvalid", Text: "An issuer certificate has expired or is not yet valid."},
-	StatusBadCertificateHostNameInvalid:                            {Name: "StatusBadCertificateHostNameInvalid", Text: "The HostName used to connect to a server does not match a HostName in the certificate."},
-	StatusBadCertificateURIInvalid:                                 {Name: "StatusBadCertificateURIInvalid", Text: "The URI specified in the ApplicationDescription does not match the URI in the certificate."},
-	StatusBadCertificateUseNotAllowed:                              {Name: "StatusBadCertificateUseNotAllowed", Text: "The certificate may not be used for the requested operation."},
-	StatusBadCertificateIssuerUseNotAllowed:                        {Name: "StatusBadCertificateIssuerUseNotAllowed", Text: "The issuer certificate may not be used for the requested operation."},
-	StatusBadCertificateUntrusted:                                  {Name: "StatusBadCertificateUntrusted", Text: "The certificate is not trusted."},
-	StatusBadCertificateRevocationUnknown:                          {Name: "StatusBadCertificateRevocationUnknown", Text: "It was not possible to determine if the certificate has been revoked."},
-	StatusBadCertificateIssuerRevocationUnknown:                    {Name: "StatusBadCertificateIssuerRevocationUnknown", Text: "It was not possible to determine if the issuer certificate has been revoked."},
-	StatusBadCertificateRevoked:                                    {Name: "StatusBadCertificateRevoked", Text: "The certificate has been revoked."},
-	StatusBadCertificateIssuerRevoked:                              {Name: "StatusBadCertificateIssuerRevoked", Text: "The issuer certificate has been revoked."},
-	StatusBadCertificateChainIncomplete:                            {Name: "StatusBadCertificateChainIncomplete", Text: "The certificate chain is incomplete."},
-	StatusBadUserAccessDenied:                                      {Name: "StatusBadUserAccessDenied", Text: "User does not have permission to perform the requested operation."},
-	StatusBadIdentityTokenInvalid:                                  {Name: "StatusBadIdentityTokenInvalid", Text: "The user identity token is not valid."},
-	StatusBadIdentityTokenRejected:                                 {Name: "StatusBadIdentityTokenRejected", Text: "The user identity token is valid but the server has rejected it."},
-	StatusBadSecureChannelIDInvalid:                                {Name: "StatusBadSecureChannelIDInvalid", Text: "The specified secure channel is no longer valid."},
-	StatusBadInvalidTimestamp:                                      {Name: "StatusBadInvalidTimestamp", Text: "The timestamp is outside the range allowed by the server."},
-	StatusBadNonceInvalid:                                          {Name: "StatusBadNonceInvalid", Text: "The nonce does appear to be not a random value or it is not the correct length."},
-	StatusBadSessionIDInvalid:                                      {Name: "StatusBadSessionIDInvalid", Text: "The session id is not valid."},
-	StatusBadSessionClosed:                                         {Name: "StatusBadSessionClosed", Text: "The session was closed by the client."},
-	StatusBadSessionNotActivated:                                   {Name: "StatusBadSessionNotActivated", Text: "The session cannot be used because ActivateSession has not been called."},
-	StatusBadSubscriptionIDInvalid:                                 {Name: "StatusBadSubscriptionIDInvalid", Text: "The subscription id is not valid."},
-	StatusBadRequestHeaderInvalid:                                  {Name: "StatusBadRequestHeaderInvalid", Text: "The header for the request is missing or invalid."},
-	StatusBadTimestampsToReturnInvalid:                             {Name: "StatusBadTimestampsToReturnInvalid", Text: "The timestamps to return parameter is invalid."},
-	StatusBadRequestCancelledByClient:                              {Name: "StatusBadRequestCancelledByClient", Text: "The request was cancelled by the client."},
-	StatusBadTooManyArguments:                                      {Name: "StatusBadTooManyArguments", Text: "Too many arguments were provided."},
-	StatusBadLicenseExpired:                                        {Name: "StatusBadLicenseExpired", Text: "The server requires a license to operate in general or to perform a service or operation, but existing license is expired."},
-	StatusBadLicenseLimitsExceeded:                                 {Name: "StatusBadLicenseLimitsExceeded", Text: "The server has limits on number of allowed operations / objects, based on installed licenses, and these limits where exceeded."},
-	StatusBadLicenseNotAvailable:                                   {Name: "StatusBadLicenseNotAvailable", Text: "The server does not have a license which is required to operate in general or to perform a service or operation."},
-	StatusGoodSubscriptionTransferred:                              {Name: "StatusGoodSubscriptionTransferred", Text: "The subscription was transferred to another session."},
-	StatusGoodCompletesAsynchronously:                              {Name: "StatusGoodCompletesAsynchronously", Text: "The processing will complete asynchronously."},
-	StatusGoodOverload:                                             {Name: "StatusGoodOverload", Text: "Sampling has slowed down due to resource limitations."},
-	StatusGoodClamped:                                              {Name: "StatusGoodClamped", Text: "The value written was accepted but was clamped."},
-	StatusBadNoCommunication:                                       {Name: "StatusBadNoCommunication", Text: "Communication with the data source is defined, but not established, and there is no last known value available."},
-	StatusBadWaitingForInitialData:                                 {Name: "StatusBadWaitingForInitialData", Text: "Waiting for the server to obtain values from the underlying data source."},
-	StatusBadNodeIDInvalid:                                         {Name: "StatusBadNodeIDInvalid", Text: "The syntax of the node id is not valid."},
-	StatusBadNodeIDUnknown:                                         {Name: "StatusBadNodeIDUnknown", Text: "The node id refers to a node that does not exist in the server address space."},
-	StatusBadAttributeIDInvalid:                                    {Name: "StatusBadAttributeIDInvalid", Text: "The attribute is not supported for the specified Node."},
-	StatusBadIndexRangeInvalid:                                     {Name: "StatusBadIndexRangeInvalid", Text: "The syntax of the index range parameter is invalid."},
-	StatusBadIndexRangeNoData:                                      {Name: "StatusBadIndexRangeNoData", Text: "No data exists within the range of indexes specified."},
-	StatusBadDataEncodingInvalid:                                   {Name: "StatusBadDataEncodingInvalid", Text: "The data encoding is invalid."},
-	StatusBadDataEncodingUnsupported:                               {Name: "StatusBadDataEncodingUnsupported", Text: "The server does not support the requested data encoding for the node."},
-	StatusBadNotReadable:                                           {Name: "StatusBadNotReadable", Text: "The access level does not allow reading or subscribing to the Node."},
-	StatusBadNotWritable:                                           {Name: "StatusBadNotWritable", Text: "The access level does not allow writing to the Node."},
-	StatusBadOutOfRange:                                            {Name: "StatusBadOutOfRange", Text: "The value was out of range."},
-	StatusBadNotSupported:                                          {Name: "StatusBadNotSupported", Text: "The requested operation is not supported."},
-	StatusBadNotFound:                                              {Name: "StatusBadNotFound", Text: "A requested item was not found or a search operation ended without success."},
-	StatusBadObjectDeleted:                                         {Name: "StatusBadObjectDeleted", Text: "The object cannot be used because it has been deleted."},
-	StatusBadNotImplemented:                                        {Name: "StatusBadNotImplemented", Text: "Requested operation is not implemented."},
-	StatusBadMonitoringModeInvalid:                                 {Name: "StatusBadMonitoringModeInvalid", Text: "The monitoring mode is invalid."},
-	StatusBadMonitoredItemIDInvalid:                                {Name: "StatusBadMonitoredItemIDInvalid", Text: "The monitoring item id does not refer to a valid monitored item."},
-	StatusBadMonitoredItemFilterInvalid:                            {Name: "StatusBadMonitoredItemFilterInvalid", Text: "The monitored item filter parameter is not valid."},
-	StatusBadMonitoredItemFilterUnsupported:                        {Name: "StatusBadMonitoredItemFilterUnsupported", Text: "The server does not support the requested monitored item filter."},
-	StatusBadFilterNotAllowed:                                      {Name: "StatusBadFilterNotAllowed", Text: "A monitoring filter cannot be used in combination with the attribute specified."},
-	StatusBadStructureMissing:                                      {Name: "StatusBadStructureMissing", Text: "A mandatory structured parameter was missing or null."},
-	StatusBadEventFilterInvalid:                                    {Name: "StatusBadEventFilterInvalid", Text: "The event filter is not valid."},
-	StatusBadContentFilterInvalid:                                  {Name: "StatusBadContentFilterInvalid", Text: "The content filter is not valid."},
-	StatusBadFilterOperatorInvalid:                                 {Name: "StatusBadFilterOperatorInvalid", Text: "An unrecognized operator was provided in a filter."},
-	StatusBadFilterOperatorUnsupported:                             {Name: "StatusBadFilterOperatorUnsupported", Text: "A valid operator was provided, but the server does not provide support for this filter operator."},
-	StatusBadFilterOperandCountMismatch:                            {Name: "StatusBadFilterOperandCountMismatch", Text: "The number of operands provided for the filter operator was less then expected for the operand provided."},
-	StatusBadFilterOperandInvalid:                                  {Name: "StatusBadFilterOperandInvalid", Text: "The operand used in a content filter is not valid."},
-	StatusBadFilterElementInvalid:                                  {Name: "StatusBadFilterElementInvalid", Text: "The referenced element is not a valid element in the content filter."},
-	StatusBadFilterLiteralInvalid:                                  {Name: "StatusBadFilterLiteralInvalid", Text: "The referenced literal is not a valid value."},
-	StatusBadContinuationPointInvalid:                              {Name: "StatusBadContinuationPointInvalid", Text: "The continuation point provide is longer valid."},
-	StatusBadNoContinuationPoints:                                  {Name: "StatusBadNoContinuationPoints", Text: "The operation could not be processed because all continuation points have been allocated."},
-	StatusBadReferenceTypeIDInvalid:                                {Name: "StatusBadReferenceTypeIDInvalid", Text: "The reference type id does not refer to a valid reference type node."},
-	StatusBadBrowseDirectionInvalid:                                {Name: "StatusBadBrowseDirectionInvalid", Text: "The browse direction is not valid."},
-	StatusBadNodeNotInView:                                         {Name: "StatusBadNodeNotInView", Text: "The node is not part of the view."},
-	StatusBadNumericOverflow:                                       {Name: "StatusBadNumericOverflow", Text: "The number was not accepted because of a numeric overflow."},
-	StatusBadServerURIInvalid:                                      {Name: "StatusBadServerURIInvalid", Text: "The ServerUri is not a valid URI."},
-	StatusBadServerNameMissing:                                     {Name: "StatusBadServerNameMissing", Text: "No ServerName was specified."},
-	StatusBadDiscoveryURLMissing:                                   {Name: "StatusBadDiscoveryURLMissing", Text: "No DiscoveryUrl was specified."},
-	StatusBadSempahoreFileMissing:                                  {Name: "StatusBadSempahoreFileMissing", Text: "The semaphore file specified by the client is not valid."},
-	StatusBadRequestTypeInvalid:                                    {Name: "StatusBadRequestTypeInvalid", Text: "The security token request type is not valid."},
-	StatusBadSecurityModeRejected:                                  {Name: "StatusBadSecurityModeRejected", Text: "The security mode does not meet the requirements set by the server."},
-	StatusBadSecurityPolicyRejected:                                {Name: "StatusBadSecurityPolicyRejected", Text: "The security policy does not meet the requirements set by the server."},
-	StatusBadTooManySessions:                                       {Name: "StatusBadTooManySessions", Text: "The server has reached its maximum number of sessions."},
-	StatusBadUserSignatureInvalid:                                  {Name: "StatusBadUserSignatureInvalid", Text: "The user token signature is missing or invalid."},
-	StatusBadApplicationSignatureInvalid:                           {Name: "StatusBadApplicationSignatureInvalid", Text: "The signature generated with the client certificate is missing or invalid."},
-	StatusBadNoValidCertificates:                                   {Name: "StatusBadNoValidCertificates", Text: "The client did not provide at least one software certificate that is valid and meets the profile requirements for the server."},
-	StatusBadIdentityChangeNotSupported:                            {Name: "StatusBadIdentityChangeNotSupported", Text: "The server does not support changing the user identity assigned to the session."},
-	StatusBadRequestCancelledByRequest:                             {Name: "StatusBadRequestCancelledByRequest", Text: "The request was cancelled by the client with the Cancel service."},
-	StatusBadParentNodeIDInvalid:                                   {Name: "StatusBadParentNodeIDInvalid", Text: "The parent node id does not to refer to a valid node."},
-	StatusBadReferenceNotAllowed:                                   {Name: "StatusBadReferenceNotAllowed", Text: "The reference could not be created because it violates constraints imposed by the data model."},
-	StatusBadNodeIDRejected:                                        {Name: "StatusBadNodeIDRejected", Text: "The requested node id was reject because it was either invalid or server does not allow node ids to be specified by the client."},
-	StatusBadNodeIDExists:                                          {Name: "StatusBadNodeIDExists", Text: "The requested node id is already used by another node."},
-	StatusBadNodeClassInvalid:                                      {Name: "StatusBadNodeClassInvalid", Text: "The node class is not valid."},
-	StatusBadBrowseNameInvalid:                                     {Name: "StatusBadBrowseNameInvalid", Text: "The browse name is invalid."},
-	StatusBadBrowseNameDuplicated:                                  {Name: "StatusBadBrowseNameDuplicated", Text: "The browse name is not unique among nodes that share the same relationship with the parent."},
-	StatusBadNodeAttributesInvalid:                                 {Name: "StatusBadNodeAttributesInvalid", Text: "The node attributes are not valid for the node class."},
-	StatusBadTypeDefinitionInvalid:                                 {Name: "StatusBadTypeDefinitionInvalid", Text: "The type definition node id does not reference an appropriate type node."},
-	StatusBadSourceNodeIDInvalid:                                   {Name: "StatusBadSourceNodeIDInvalid", Text: "The source node id does not reference a valid node."},
-	StatusBadTargetNodeIDInvalid:                                   {Name: "StatusBadTargetNodeIDInvalid", Text: "The target node id does not reference a valid node."},
-	StatusBadDuplicateReferenceNotAllowed:                          {Name: "StatusBadDuplicateReferenceNotAllowed", Text: "The reference type between the nodes is already defined."},
-	StatusBadInvalidSelfReference:                                  {Name: "StatusBadInvalidSelfReference", Text: "The server does not allow this type of self reference on this node."},
-	StatusBadReferenceLocalOnly:                                    {Name: "StatusBadReferenceLocalOnly", Text: "The reference type is not valid for a reference to a remote server."},
-	StatusBadNoDeleteRights:                                        {Name: "StatusBadNoDeleteRights", Text: "The server will not allow the node to be deleted."},
-	StatusUncertainReferenceNotDeleted:                             {Name: "StatusUncertainReferenceNotDeleted", Text: "The server was not able to delete all target references."},
-	StatusBadServerIndexInvalid:                                    {Name: "StatusBadServerIndexInvalid", Text: "The server index is not valid."},
-	StatusBadViewIDUnknown:                                         {Name: "StatusBadViewIDUnknown", Text: "The view id does not refer to a valid view node."},
-	StatusBadViewTimestampInvalid:                                  {Name: "StatusBadViewTimestampInvalid", Text: "The view timestamp is not available or not supported."},
-	StatusBadViewParameterMismatch:                                 {Name: "StatusBadViewParameterMismatch", Text: "The view parameters are not consistent with each other."},
-	StatusBadViewVersionInvalid:                                    {Name: "StatusBadViewVersionInvalid", Text: "The view version is not available or not supported."},
-	StatusUncertainNotAllNodesAvailable:                            {Name: "StatusUncertainNotAllNodesAvailable", Text: "The list of references may not be complete because the underlying system is not available."},
-	StatusGoodResultsMayBeIncomplete:                               {Name: "StatusGoodResultsMayBeIncomplete", Text: "The server should have followed a reference to a node in a remote server but did not. The result set may be incomplete."},
-	StatusBadNotTypeDefinition:                                     {Name: "StatusBadNotTypeDefinition", Text: "The provided Nodeid was not a type definition nodeid."},
-	StatusUncertainReferenceOutOfServer:                            {Name: "StatusUncertainReferenceOutOfServer", Text: "One of the references to follow in the relative path references to a node in the address space in another server."},
-	StatusBadTooManyMatches:                                        {Name: "StatusBadTooManyMatches", Text: "The requested operation has too many matches to return."},
-	StatusBadQueryTooComplex:                                       {Name: "StatusBadQueryTooComplex", Text: "The requested operation requires too many resources in the server."},
-	StatusBadNoMatch:                                               {Name: "StatusBadNoMatch", Text: "The requested operation has no match to return."},
-	StatusBadMaxAgeInvalid:                                         {Name: "StatusBadMaxAgeInvalid", Text: "The max age parameter is invalid."},
-	StatusBadSecurityModeInsufficient:                              {Name: "StatusBadSecurityModeInsufficient", Text: "The operation is not permitted over the current secure channel."},
-	StatusBadHistoryOperationInvalid:                               {Name: "StatusBadHistoryOperationInvalid", Text: "The history details parameter is not valid."},
-	StatusBadHistoryOperationUnsupported:                           {Name: "StatusBadHistoryOperationUnsupported", Text: "The server does not support the requested operation."},
-	StatusBadInvalidTimestampArgument:                              {Name: "StatusBadInvalidTimestampArgument", Text: "The defined timestamp to return was invalid."},
-	StatusBadWriteNotSupported:                                     {Name: "StatusBadWriteNotSupported", Text: "The server does not support writing the combination of value, status and timestamps provided."},
-	StatusBadTypeMismatch:                                          {Name: "StatusBadTypeMismatch", Text: "The value supplied for the attribute is not of the same type as the attribute's value."},
-	StatusBadMethodInvalid:                                         {Name: "StatusBadMethodInvalid", Text: "The method id does not refer to a method for the specified object."},
-	StatusBadArgumentsMissing:                                      {Name: "StatusBadArgumentsMissing", Text: "The client did not specify all of the input arguments for the method."},
-	StatusBadNotExecutable:                                         {Name: "StatusBadNotExecutable", Text: "The executable attribute does not allow the execution of the method."},
-	StatusBadTooManySubscriptions:                                  {Name: "StatusBadTooManySubscriptions", Text: "The server has reached its maximum number of subscriptions."},
-	StatusBadTooManyPublishRequests:                                {Name: "StatusBadTooManyPublishRequests", Text: "The server has reached the maximum number of queued publish requests."},
-	StatusBadNoSubscription:                                        {Name: "StatusBadNoSubscription", Text: "There is no subscription available for this session."},
-	StatusBadSequenceNumberUnknown:                                 {Name: "StatusBadSequenceNumberUnknown", Text: "The sequence number is unknown to the server."},
-	StatusGoodRetransmissionQueueNotSupported:                      {Name: "StatusGoodRetransmissionQueueNotSupported", Text: "The Server does not support retransmission queue and acknowledgement of sequence numbers is not available."},
-	StatusBadMessageNotAvailable:                                   {Name: "StatusBadMessageNotAvailable", Text: "The requested notification message is no longer available."},
-	StatusBadInsufficientClientProfile:                             {Name: "StatusBadInsufficientClientProfile", Text: "The client of the current session does not support one or more Profiles that are necessary for the subscription."},
-	StatusBadStateNotActive:                                        {Name: "StatusBadStateNotActive", Text: "The sub-state machine is not currently active."},
-	StatusBadAlreadyExists:                                         {Name: "StatusBadAlreadyExists", Text: "An equivalent rule already exists."},
-	StatusBadTCPServerTooBusy:                                      {Name: "StatusBadTCPServerTooBusy", Text: "The server cannot process the request because it is too busy."},
-	StatusBadTCPMessageTypeInvalid:                                 {Name: "StatusBadTCPMessageTypeInvalid", Text: "The type of the message specified in the header invalid."},
-	StatusBadTCPSecureChannelUnknown:                               {Name: "StatusBadTCPSecureChannelUnknown", Text: "The SecureChannelId and/or TokenId are not currently in use."},
-	StatusBadTCPMessageTooLarge:                                    {Name: "StatusBadTCPMessageTooLarge", Text: "The size of the message chunk specified in the header is too large."},
-	StatusBadTCPNotEnoughResources:                                 {Name: "StatusBadTCPNotEnoughResources", Text: "There are not enough resources to process the request."},
-	StatusBadTCPInternalError:                                      {Name: "StatusBadTCPInternalError", Text: "An internal error occurred."},
-	StatusBadTCPEndpointURLInvalid:                                 {Name: "StatusBadTCPEndpointURLInvalid", Text: "The server does not recognize the QueryString specified."},
-	StatusBadRequestInterrupted:                                    {Name: "StatusBadRequestInterrupted", Text: "The request could not be sent because of a network interruption."},
-	StatusBadRequestTimeout:                                        {Name: "StatusBadRequestTimeout", Text: "Timeout occurred while processing the request."},
-	StatusBadSecureChannelClosed:                                   {Name: "StatusBadSecureChannelClosed", Text: "The secure channel has been closed."},
-	StatusBadSecureChannelTokenUnknown:                             {Name: "StatusBadSecureChannelTokenUnknown", Text: "The token has expired or is not recognized."},
-	StatusBadSequenceNumberInvalid:                                 {Name: "StatusBadSequenceNumberInvalid", Text: "The sequence number is not valid."},
-	StatusBadProtocolVersionUnsupported:                            {Name: "StatusBadProtocolVersionUnsupported", Text: "The applications do not have compatible protocol versions."},
-	StatusBadConfigurationError:                                    {Name: "StatusBadConfigurationError", Text: "There is a problem with the configuration that affects the usefulness of the value."},
-	StatusBadNotConnected:                                          {Name: "StatusBadNotConnected", Text: "The variable should receive its value from another variable, but has never been configured to do so."},
-	StatusBadDeviceFailure:                                         {Name: "StatusBadDeviceFailure", Text: "There has been a failure in the device/data source that generates the value that has affected the value."},
-	StatusBadSensorFailure:                                         {Name: "StatusBadSensorFailure", Text: "There has been a failure in the sensor from which the value is derived by the device/data source."},
-	StatusBadOutOfService:                                          {Name: "StatusBadOutOfService", Text: "The source of the data is not operational."},
-	StatusBadDeadbandFilterInvalid:                                 {Name: "StatusBadDeadbandFilterInvalid", Text: "The deadband filter is not valid."},
-	StatusUncertainNoCommunicationLastUsableValue:                  {Name: "StatusUncertainNoCommunicationLastUsableValue", Text: "Communication to the data source has failed. The variable value is the last value that had a good quality."},
-	StatusUncertainLastUsableValue:                                 {Name: "StatusUncertainLastUsableValue", Text: "Whatever was updating this value has stopped doing so."},
-	StatusUncertainSubstituteValue:                                 {Name: "StatusUncertainSubstituteValue", Text: "The value is an operational value that was manually overwritten."},
-	StatusUncertainInitialValue:                                    {Name: "StatusUncertainInitialValue", Text: "The value is an initial value for a variable that normally receives its value from another variable."},
-	StatusUncertainSensorNotAccurate:                               {Name: "StatusUncertainSensorNotAccurate", Text: "The value is at one of the sensor limits."},
-	StatusUncertainEngineeringUnitsExceeded:                        {Name: "StatusUncertainEngineeringUnitsExceeded", Text: "The value is outside of the range of values defined for this parameter."},
-	StatusUncertainSubNormal:                                       {Name: "StatusUncertainSubNormal", Text: "The value is derived from multiple sources and has less than the required number of Good sources."},
-	StatusGoodLocalOverride:                                        {Name: "StatusGoodLocalOverride", Text: "The value has been overridden."},
-	StatusBadRefreshInProgress:                                     {Name: "StatusBadRefreshInProgress", Text: "This Condition refresh failed, a Condition refresh operation is already in progress."},
-	StatusBadConditionAlreadyDisabled:                              {Name: "StatusBadConditionAlreadyDisabled", Text: "This condition has already been disabled."},
-	StatusBadConditionAlreadyEnabled:                               {Name: "StatusBadConditionAlreadyEnabled", Text: "This condition has already been enabled."},
-	StatusBadConditionDisabled:                                     {Name: "StatusBadConditionDisabled", Text: "Property not available, this condition is disabled."},
-	StatusBadEventIDUnknown:                                        {Name: "StatusBadEventIDUnknown", Text: "The specified event id is not recognized."},
-	StatusBadEventNotAcknowledgeable:                               {Name: "StatusBadEventNotAcknowledgeable", Text: "The event cannot be acknowledged."},
-	StatusBadDialogNotActive:                                       {Name: "StatusBadDialogNotActive", Text: "The dialog condition is not active."},
-	StatusBadDialogResponseInvalid:                                 {Name: "StatusBadDialogResponseInvalid", Text: "The response is not valid for the dialog."},
-	StatusBadConditionBranchAlreadyAcked:                           {Name: "StatusBadConditionBranchAlreadyAcked", Text: "The condition branch has already been acknowledged."},
-	StatusBadConditionBranchAlreadyConfirmed:                       {Name: "StatusBadConditionBranchAlreadyConfirmed", Text: "The condition branch has already been confirmed."},
-	StatusBadConditionAlreadyShelved:                               {Name: "StatusBadConditionAlreadyShelved", Text: "The condition has already been shelved."},
-	StatusBadConditionNotShelved:                                   {Name: "StatusBadConditionNotShelved", Text: "The condition is not currently shelved."},
-	StatusBadShelvingTimeOutOfRange:                                {Name: "StatusBadShelvingTimeOutOfRange", Text: "The shelving time not within an acceptable range."},
-	StatusBadNoData:                                                {Name: "StatusBadNoData", Text: "No data exists for the requested time range or event filter."},
-	StatusBadBoundNotFound:                                         {Name: "StatusBadBoundNotFound", Text: "No data found to provide upper or lower bound value."},
-	StatusBadBoundNotSupported:                                     {Name: "StatusBadBoundNotSupported", Text: "The server cannot retrieve a bound for the variable."},
-	StatusBadDataLost:                                              {Name: "StatusBadDataLost", Text: "Data is missing due to collection started/stopped/lost."},
-	StatusBadDataUnavailable:                                       {Name: "StatusBadDataUnavailable", Text: "Expected data is unavailable for the requested time range due to an un-mounted volume, an off-line archive or tape, or similar reason for temporary unavailability."},
-	StatusBadEntryExists:                                           {Name: "StatusBadEntryExists", Text: "The data or event was not successfully inserted because a matching entry exists."},
-	StatusBadNoEntryExists:                                         {Name: "StatusBadNoEntryExists", Text: "The data or event was not successfully updated because no matching entry exists."},
-	StatusBadTimestampNotSupported:                                 {Name: "StatusBadTimestampNotSupported", Text: "The client requested history using a timestamp format the server does not support (i.e requested ServerTimestamp when server only supports SourceTimestamp)."},
-	StatusGoodEntryInserted:                                        {Name: "StatusGoodEntryInserted", Text: "The data or event was successfully inserted into the historical database."},
-	StatusGoodEntryReplaced:                                        {Name: "StatusGoodEntryReplaced", Text: "The data or event field was successfully replaced in the historical database."},
-	StatusUncertainDataSubNormal:                                   {Name: "StatusUncertainDataSubNormal", Text: "The value is derived from multiple values and has less than the required number of Good values."},
-	StatusGoodNoData:                                               {Name: "StatusGoodNoData", Text: "No data exists for the requested time range or event filter."},
-	StatusGoodMoreData:                                             {Name: "StatusGoodMoreData", Text: "More data is available in the time range beyond the number of values requested."},
-	StatusBadAggregateListMismatch:                                 {Name: "StatusBadAggregateListMismatch", Text: "The requested number of Aggregates does not match the requested number of NodeIds."},
-	StatusBadAggregateNotSupported:                                 {Name: "StatusBadAggregateNotSupported", Text: "The requested Aggregate is not support by the server."},
-	StatusBadAggregateInvalidInputs:                                {Name: "StatusBadAggregateInvalidInputs", Text: "The aggregate value could not be derived due to invalid data inputs."},
-	StatusBadAggregateConfigurationRejected:                        {Name: "StatusBadAggregateConfigurationRejected", Text: "The aggregate configuration is not valid for specified node."},
-	StatusGoodDataIgnored:                                          {Name: "StatusGoodDataIgnored", Text: "The request specifies fields which are not valid for the EventType or cannot be saved by the historian."},
-	StatusBadRequestNotAllowed:                                     {Name: "StatusBadRequestNotAllowed", Text: "The request was rejected by the server because it did not meet the criteria set by the server."},
-	StatusBadRequestNotComplete:                                    {Name: "StatusBadRequestNotComplete", Text: "The request has not been processed by the server yet."},
-	StatusBadTransactionPending:                                    {Name: "StatusBadTransactionPending", Text: "The operation is not allowed because a transaction is in progress."},
-	StatusBadTicketRequired:                                        {Name: "StatusBadTicketRequired", Text: "The device identity needs a ticket before it can be accepted."},
-	StatusBadTicketInvalid:                                         {Name: "StatusBadTicketInvalid", Text: "The device identity needs a ticket before it can be accepted."},
-	StatusBadLocked:                                                {Name: "StatusBadLocked", Text: "The requested operation is not allowed, because the Node is locked by a different application."},
-	StatusGoodEdited:                                               {Name: "StatusGoodEdited", Text: "The value does not come from the real source and has been edited by the server."},
-	StatusGoodPostActionFailed:                                     {Name: "StatusGoodPostActionFailed", Text: "There was an error in execution of these post-actions."},
-	StatusUncertainDominantValueChanged:                            {Name: "StatusUncertainDominantValueChanged", Text: "The related EngineeringUnit has been changed but the Variable Value is still provided based on the previous unit."},
-	StatusGoodDependentValueChanged:                                {Name: "StatusGoodDependentValueChanged", Text: "A dependent value has been changed but the change has not been applied to the device."},
-	StatusBadDominantValueChanged:                                  {Name: "StatusBadDominantValueChanged", Text: "The related EngineeringUnit has been changed but this change has not been applied to the device. The Variable Value is still dependent on the previous unit but its status is currently Bad."},
-	StatusUncertainDependentValueChanged:                           {Name: "StatusUncertainDependentValueChanged", Text: "A dependent value has been changed but the change has not been applied to the device. The quality of the dominant variable is uncertain."},
-	StatusBadDependentValueChanged:                                 {Name: "StatusBadDependentValueChanged", Text: "A dependent value has been changed but the change has not been applied to the device. The quality of the dominant variable is Bad."},
-	StatusGoodEdited_DependentValueChanged:                         {Name: "StatusGoodEdited_DependentValueChanged", Text: "It is delivered with a dominant Variable value when a dependent Variable has changed but the change has not been applied."},
-	StatusGoodEdited_DominantValueChanged:                          {Name: "StatusGoodEdited_DominantValueChanged", Text: "It is delivered with a dependent Variable value when a dominant Variable has changed but the change has not been applied."},
-	StatusGoodEdited_DominantValueChanged_DependentValueChanged:    {Name: "StatusGoodEdited_DominantValueChanged_DependentValueChanged", Text: "It is delivered with a dependent Variable value when a dominant or dependent Variable has changed but change has not been applied."},
-	StatusBadEdited_OutOfRange:                                     {Name: "StatusBadEdited_OutOfRange", Text: "It is delivered with a Variable value when Variable has changed but the value is not legal."},
-	StatusBadInitialValue_OutOfRange:                               {Name: "StatusBadInitialValue_OutOfRange", Text: "It is delivered with a Variable value when a source Variable has changed but the value is not legal."},
-	StatusBadOutOfRange_DominantValueChanged:                       {Name: "StatusBadOutOfRange_DominantValueChanged", Text: "It is delivered with a dependent Variable value when a dominant Variable has changed and the value is not legal."},
-	StatusBadEdited_OutOfRange_DominantValueChanged:                {Name: "StatusBadEdited_OutOfRange_DominantValueChanged", Text: "It is delivered with a dependent Variable value when a dominant Variable has changed, the value is not legal and the change has not been applied."},
-	StatusBadOutOfRange_DominantValueChanged_DependentValueChanged: {Name: "StatusBadOutOfRange_DominantValueChanged_DependentValueChanged", Text: "It is delivered with a dependent Variable value when a dominant or dependent Variable has changed and the value is not legal."},
-	StatusBadEdited_OutOfRange_DominantValueChanged_DependentValueChanged: {Name: "StatusBadEdited_OutOfRange_DominantValueChanged_DependentValueChanged", Text: "It is delivered with a dependent Variable value when a dominant or dependent Variable has changed, the value is not legal and the change has not been applied."},
-	StatusGoodCommunicationEvent:                                          {Name: "StatusGoodCommunicationEvent", Text: "The communication layer has raised an event."},
-	StatusGoodShutdownEvent:                                               {Name: "StatusGoodShutdownEvent", Text: "The system is shutting down."},
-	StatusGoodCallAgain:                                                   {Name: "StatusGoodCallAgain", Text: "The operation is not finished and needs to be called again."},
-	StatusGoodNonCriticalTimeout:                                          {Name: "StatusGoodNonCriticalTimeout", Text: "A non-critical timeout occurred."},
-	StatusBadInvalidArgument:                                              {Name: "StatusBadInvalidArgument", Text: "One or more arguments are invalid."},
-	StatusBadConnectionRejected:                                           {Name: "StatusBadConnectionRejected", Text: "Could not establish a network connection to remote server."},
-	StatusBadDisconnect:                                                   {Name: "StatusBadDisconnect", Text: "The server has disconnected from the client."},
-	StatusBadConnectionClosed:                                             {Name: "StatusBadConnectionClosed", Text: "The network connection has been closed."},
-	StatusBadInvalidState:                                                 {Name: "StatusBadInvalidState", Text: "The operation cannot be completed because the object is closed, uninitialized or in some other invalid state."},
-	StatusBadEndOfStream:                                                  {Name: "StatusBadEndOfStream", Text: "Cannot move beyond end of the stream."},
-	StatusBadNoDataAvailable:                                              {Name: "StatusBadNoDataAvailable", Text: "No data is currently available for reading from a non-blocking stream."},
-	StatusBadWaitingForResponse:                                           {Name: "StatusBadWaitingForResponse", Text: "The asynchronous operation is waiting for a response."},
-	StatusBadOperationAbandoned:                                           {Name: "StatusBadOperationAbandoned", Text: "The asynchronous operation was abandoned by the caller."},
-	StatusBadExpectedStreamToBlock:                                        {Name: "StatusBadExpectedStreamToBlock", Text: "The stream did not return all data requested (possibly because it is a non-blocking stream)."},
-	StatusBadWouldBlock:                                                   {Name: "StatusBadWouldBlock", Text: "Non blocking behaviour is required and the operation would block."},
-	StatusBadSyntaxError:                                                  {Name: "StatusBadSyntaxError", Text: "A value had an invalid syntax."},
-	StatusBadMaxConnectionsReached:                                        {Name: "StatusBadMaxConnectionsReached", Text: "The operation could not be finished because all available connections are in use."},
-	StatusUncertainTransducerInManual:                                     {Name: "StatusUncertainTransducerInManual", Text: "The value may not be accurate because the transducer is in manual mode."},
-	StatusUncertainSimulatedValue:                                         {Name: "StatusUncertainSimulatedValue", Text: "The value is simulated."},
-	StatusUncertainSensorCalibration:                                      {Name: "StatusUncertainSensorCalibration", Text: "The value may not be accurate due to a sensor calibration fault."},
-	StatusUncertainConfigurationError:                                     {Name: "StatusUncertainConfigurationError", Text: "The value may not be accurate due to a configuration issue."},
-	StatusGoodCascadeInitializationAcknowledged:                           {Name: "StatusGoodCascadeInitializationAcknowledged", Text: "The value source supports cascade handshaking and the value has been Initialized based on an initialization request from a cascade secondary."},
-	StatusGoodCascadeInitializationRequest:                                {Name: "StatusGoodCascadeInitializationRequest", Text: "The value source supports cascade handshaking and is requesting initialization of a cascade primary."},
-	StatusGoodCascadeNotInvited:                                           {Name: "StatusGoodCascadeNotInvited", Text: "The value source supports cascade handshaking, however, the source’s current state does not allow for cascade."},
-	StatusGoodCascadeNotSelected:                                          {Name: "StatusGoodCascadeNotSelected", Text: "The value source supports cascade handshaking, however, the source has not selected the corresponding cascade primary for use."},
-	StatusGoodFaultStateActive:                                            {Name: "StatusGoodFaultStateActive", Text: "There is a fault state condition active in the value source."},
-	StatusGoodInitiateFaultState:                                          {Name: "StatusGoodInitiateFaultState", Text: "A fault state condition is being requested of the destination."},
-	StatusGoodCascade:                                                     {Name: "StatusGoodCascade", Text: "The value is accurate, and the signal source supports cascade handshaking."},
-	StatusBadDataSetIDInvalid:                                             {Name: "StatusBadDataSetIDInvalid", Text: "The DataSet specified for the DataSetWriter creation is invalid."},
+	StatusOK:                                                       {Name: "OK", Symbol: "Good", Text: ""},
+	StatusUncertain:                                                {Name: "Uncertain", Symbol: "Uncertain", Text: ""},
+	StatusBad:                                                      {Name: "Bad", Symbol: "Bad", Text: ""},
+	StatusGood:                                                     {Name: "StatusGood", Symbol: "Good", Text: "The operation succeeded."},
+	StatusUncertain:                                                {Name: "StatusUncertain", Symbol: "Uncertain", Text: "The operation was uncertain."},
+	StatusBad:                                                      {Name: "StatusBad", Symbol: "Bad", Text: "The operation failed."},
+	StatusBadUnexpectedError:                                       {Name: "StatusBadUnexpectedError", Symbol: "BadUnexpectedError", Text: "An unexpected error occurred."},
+	StatusBadInternalError:                                         {Name: "StatusBadInternalError", Symbol: "BadInternalError", Text: "An internal error occurred as a result of a programming or configuration error."},
+	StatusBadOutOfMemory:                                           {Name: "StatusBadOutOfMemory", Symbol: "BadOutOfMemory", Text: "Not enough memory to complete the operation."},
+	StatusBadResourceUnavailable:                                   {Name: "StatusBadResourceUnavailable", Symbol: "BadResourceUnavailable", Text: "An operating system resource is not available."},
+	StatusBadCommunicationError:                                    {Name: "StatusBadCommunicationError", Symbol: "BadCommunicationError", Text: "A low level communication error occurred."},
+	StatusBadEncodingError:                                         {Name: "StatusBadEncodingError", Symbol: "BadEncodingError", Text: "Encoding halted because of invalid data in the objects being serialized."},
+	StatusBadDecodingError:                                         {Name: "StatusBadDecodingError", Symbol: "BadDecodingError", Text: "Decoding halted because of invalid data in the stream."},
+	StatusBadEncodingLimitsExceeded:                                {Name: "StatusBadEncodingLimitsExceeded", Symbol: "BadEncodingLimitsExceeded", Text: "The message encoding/decoding limits imposed by the stack have been exceeded."},
+	StatusBadRequestTooLarge:                                       {Name: "StatusBadRequestTooLarge", Symbol: "BadRequestTooLarge", Text: "The request message size exceeds limits set by the server."},
+	StatusBadResponseTooLarge:                                      {Name: "StatusBadResponseTooLarge", Symbol: "BadResponseTooLarge", Text: "The response message size exceeds limits set by the client."},
+	StatusBadUnknownResponse:                                       {Name: "StatusBadUnknownResponse", Symbol: "BadUnknownResponse", Text: "An unrecognized response was received from the server."},
+	StatusBadTimeout:                                               {Name: "StatusBadTimeout", Symbol: "BadTimeout", Text: "The operation timed out."},
+	StatusBadServiceUnsupported:                                    {Name: "StatusBadServiceUnsupported", Symbol: "BadServiceUnsupported", Text: "The server does not support the requested service."},
+	StatusBadShutdown:                                              {Name: "StatusBadShutdown", Symbol: "BadShutdown", Text: "The operation was cancelled because the application is shutting down."},
+	StatusBadServerNotConnected:                                    {Name: "StatusBadServerNotConnected", Symbol: "BadServerNotConnected", Text: "The operation could not complete because the client is not connected to the server."},
+	StatusBadServerHalted:                                          {Name: "StatusBadServerHalted", Symbol: "BadServerHalted", Text: "The server has stopped and cannot process any requests."},
+	StatusBadNothingToDo:                                           {Name: "StatusBadNothingToDo", Symbol: "BadNothingToDo", Text: "No processing could be done because there was nothing to do."},
+	StatusBadTooManyOperations:                                     {Name: "StatusBadTooManyOperations", Symbol: "BadTooManyOperations", Text: "The request could not be processed because it specified too many operations."},
+	StatusBadTooManyMonitoredItems:                                 {Name: "StatusBadTooManyMonitoredItems", Symbol: "BadTooManyMonitoredItems", Text: "The request could not be processed because there are too many monitored items in the subscription."},
+	StatusBadDataTypeIDUnknown:                                     {Name: "StatusBadDataTypeIDUnknown", Symbol: "BadDataTypeIdUnknown", Text: "The extension object cannot be (de)serialized because the data type id is not recognized."},
+	StatusBadCertificateInvalid:                                    {Name: "StatusBadCertificateInvalid", Symbol: "BadCertificateInvalid", Text: "The certificate provided as a parameter is not valid."},
+	StatusBadSecurityChecksFailed:                                  {Name: "StatusBadSecurityChecksFailed", Symbol: "BadSecurityChecksFailed", Text: "An error occurred verifying security."},
+	StatusBadCertificatePolicyCheckFailed:                          {Name: "StatusBadCertificatePolicyCheckFailed", Symbol: "BadCertificatePolicyCheckFailed", Text: "The certificate does not meet the requirements of the security policy."},
+	StatusBadCertificateTimeInvalid:                                {Name: "StatusBadCertificateTimeInvalid", Symbol: "BadCertificateTimeInvalid", Text: "The certificate has expired or is not yet valid."},
+	StatusBadCertificateIssuerTimeInvalid:                          {Name: "StatusBadCertificateIssuerTimeInvalid", Symbol: "BadCertificateIssuerTimeInvalid", Text: "An issuer certificate has expired or is not yet valid."},
+	StatusBadCertificateHostNameInvalid:                            {Name: "StatusBadCertificateHostNameInvalid", Symbol: "BadCertificateHostNameInvalid", Text: "The HostName used to connect to a server does not match a HostName in the certificate."},
+	StatusBadCertificateURIInvalid:                                 {Name: "StatusBadCertificateURIInvalid", Symbol: "BadCertificateUriInvalid", Text: "The URI specified in the ApplicationDescription does not match the URI in the certificate."},
+	StatusBadCertificateUseNotAllowed:                              {Name: "StatusBadCertificateUseNotAllowed", Symbol: "BadCertificateUseNotAllowed", Text: "The certificate may not be used for the requested operation."},
+	StatusBadCertificateIssuerUseNotAllowed:                        {Name: "StatusBadCertificateIssuerUseNotAllowed", Symbol: "BadCertificateIssuerUseNotAllowed", Text: "The issuer certificate may not be used for the requested operation."},
+	StatusBadCertificateUntrusted:                                  {Name: "StatusBadCertificateUntrusted", Symbol: "BadCertificateUntrusted", Text: "The certificate is not trusted."},
+	StatusBadCertificateRevocationUnknown:                          {Name: "StatusBadCertificateRevocationUnknown", Symbol: "BadCertificateRevocationUnknown", Text: "It was not possible to determine if the certificate has been revoked."},
+	StatusBadCertificateIssuerRevocationUnknown:                    {Name: "StatusBadCertificateIssuerRevocationUnknown", Symbol: "BadCertificateIssuerRevocationUnknown", Text: "It was not possible to determine if the issuer certificate has been revoked."},
+	StatusBadCertificateRevoked:                                    {Name: "StatusBadCertificateRevoked", Symbol: "BadCertificateRevoked", Text: "The certificate has been revoked."},
+	StatusBadCertificateIssuerRevoked:                              {Name: "StatusBadCertificateIssuerRevoked", Symbol: "BadCertificateIssuerRevoked", Text: "The issuer certificate has been revoked."},
+	StatusBadCertificateChainIncomplete:                            {Name: "StatusBadCertificateChainIncomplete", Symbol: "BadCertificateChainIncomplete", Text: "The certificate chain is incomplete."},
+	StatusBadUserAccessDenied:                                      {Name: "StatusBadUserAccessDenied", Symbol: "BadUserAccessDenied", Text: "User does not have permission to perform the requested operation."},
+	StatusBadIdentityTokenInvalid:                                  {Name: "StatusBadIdentityTokenInvalid", Symbol: "BadIdentityTokenInvalid", Text: "The user identity token is not valid."},
+	StatusBadIdentityTokenRejected:                                 {Name: "StatusBadIdentityTokenRejected", Symbol: "BadIdentityTokenRejected", Text: "The user identity token is valid but the server has rejected it."},
+	StatusBadSecureChannelIDInvalid:                                {Name: "StatusBadSecureChannelIDInvalid", Symbol: "BadSecureChannelIdInvalid", Text: "The specified secure channel is no longer valid."},
+	StatusBadInvalidTimestamp:                                      {Name: "StatusBadInvalidTimestamp", Symbol: "BadInvalidTimestamp", Text: "The timestamp is outside the range allowed by the server."},
+	StatusBadNonceInvalid:                                          {Name: "StatusBadNonceInvalid", Symbol: "BadNonceInvalid", Text: "The nonce does appear to be not a random value or it is not the correct length."},
+	StatusBadSessionIDInvalid:                                      {Name: "StatusBadSessionIDInvalid", Symbol: "BadSessionIdInvalid", Text: "The session id is not valid."},
+	StatusBadSessionClosed:                                         {Name: "StatusBadSessionClosed", Symbol: "BadSessionClosed", Text: "The session was closed by the client."},
+	StatusBadSessionNotActivated:                                   {Name: "StatusBadSessionNotActivated", Symbol: "BadSessionNotActivated", Text: "The session cannot be used because ActivateSession has not been called."},
+	StatusBadSubscriptionIDInvalid:                                 {Name: "StatusBadSubscriptionIDInvalid", Symbol: "BadSubscriptionIdInvalid", Text: "The subscription id is not valid."},
+	StatusBadRequestHeaderInvalid:                                  {Name: "StatusBadRequestHeaderInvalid", Symbol: "BadRequestHeaderInvalid", Text: "The header for the request is missing or invalid."},
+	StatusBadTimestampsToReturnInvalid:                             {Name: "StatusBadTimestampsToReturnInvalid", Symbol: "BadTimestampsToReturnInvalid", Text: "The timestamps to return parameter is invalid."},
+	StatusBadRequestCancelledByClient:                              {Name: "StatusBadRequestCancelledByClient", Symbol: "BadRequestCancelledByClient", Text: "The request was cancelled by the client."},
+	StatusBadTooManyArguments:                                      {Name: "StatusBadTooManyArguments", Symbol: "BadTooManyArguments", Text: "Too many arguments were provided."},
+	StatusBadLicenseExpired:                                        {Name: "StatusBadLicenseExpired", Symbol: "BadLicenseExpired", Text: "The server requires a license to operate in general or to perform a service or operation, but existing license is expired."},
+	StatusBadLicenseLimitsExceeded:                                 {Name: "StatusBadLicenseLimitsExceeded", Symbol: "BadLicenseLimitsExceeded", Text: "The server has limits on number of allowed operations / objects, based on installed licenses, and these limits where exceeded."},
+	StatusBadLicenseNotAvailable:                                   {Name: "StatusBadLicenseNotAvailable", Symbol: "BadLicenseNotAvailable", Text: "The server does not have a license which is required to operate in general or to perform a service or operation."},
+	StatusGoodSubscriptionTransferred:                              {Name: "StatusGoodSubscriptionTransferred", Symbol: "GoodSubscriptionTransferred", Text: "The subscription was transferred to another session."},
+	StatusGoodCompletesAsynchronously:                              {Name: "StatusGoodCompletesAsynchronously", Symbol: "GoodCompletesAsynchronously", Text: "The processing will complete asynchronously."},
+	StatusGoodOverload:                                             {Name: "StatusGoodOverload", Symbol: "GoodOverload", Text: "Sampling has slowed down due to resource limitations."},
+	StatusGoodClamped:                                              {Name: "StatusGoodClamped", Symbol: "GoodClamped", Text: "The value written was accepted but was clamped."},
+	StatusBadNoCommunication:                                       {Name: "StatusBadNoCommunication", Symbol: "BadNoCommunication", Text: "Communication with the data source is defined, but not established, and there is no last known value available."},
+	StatusBadWaitingForInitialData:                                 {Name: "StatusBadWaitingForInitialData", Symbol: "BadWaitingForInitialData", Text: "Waiting for the server to obtain values from the underlying data source."},
+	StatusBadNodeIDInvalid:                                         {Name: "StatusBadNodeIDInvalid", Symbol: "BadNodeIdInvalid", Text: "The syntax of the node id is not valid."},
+	StatusBadNodeIDUnknown:                                         {Name: "StatusBadNodeIDUnknown", Symbol: "BadNodeIdUnknown", Text: "The node id refers to a node that does not exist in the server address space."},
+	StatusBadAttributeIDInvalid:                                    {Name: "StatusBadAttributeIDInvalid", Symbol: "BadAttributeIdInvalid", Text: "The attribute is not supported for the specified Node."},
+	StatusBadIndexRangeInvalid:                                     {Name: "StatusBadIndexRangeInvalid", Symbol: "BadIndexRangeInvalid", Text: "The syntax of the index range parameter is invalid."},
+	StatusBadIndexRangeNoData:                                      {Name: "StatusBadIndexRangeNoData", Symbol: "BadIndexRangeNoData", Text: "No data exists within the range of indexes specified."},
+	StatusBadDataEncodingInvalid:                                   {Name: "StatusBadDataEncodingInvalid", Symbol: "BadDataEncodingInvalid", Text: "The data encoding is invalid."},
+	StatusBadDataEncodingUnsupported:                               {Name: "StatusBadDataEncodingUnsupported", Symbol: "BadDataEncodingUnsupported", Text: "The server does not support the requested data encoding for the node."},
+	StatusBadNotReadable:                                           {Name: "StatusBadNotReadable", Symbol: "BadNotReadable", Text: "The access level does not allow reading or subscribing to the Node."},
+	StatusBadNotWritable:                                           {Name: "StatusBadNotWritable", Symbol: "BadNotWritable", Text: "The access level does not allow writing to the Node."},
+	StatusBadOutOfRange:                                            {Name: "StatusBadOutOfRange", Symbol: "BadOutOfRange", Text: "The value was out of range."},
+	StatusBadNotSupported:                                          {Name: "StatusBadNotSupported", Symbol: "BadNotSupported", Text: "The requested operation is not supported."},
+	StatusBadNotFound:                                              {Name: "StatusBadNotFound", Symbol: "BadNotFound", Text: "A requested item was not found or a search operation ended without success."},
+	StatusBadObjectDeleted:                                         {Name: "StatusBadObjectDeleted", Symbol: "BadObjectDeleted", Text: "The object cannot be used because it has been deleted."},
+	StatusBadNotImplemented:                                        {Name: "StatusBadNotImplemented", Symbol: "BadNotImplemented", Text: "Requested operation is not implemented."},
+	StatusBadMonitoringModeInvalid:                                 {Name: "StatusBadMonitoringModeInvalid", Symbol: "BadMonitoringModeInvalid", Text: "The monitoring mode is invalid."},
+	StatusBadMonitoredItemIDInvalid:                                {Name: "StatusBadMonitoredItemIDInvalid", Symbol: "BadMonitoredItemIdInvalid", Text: "The monitoring item id does not refer to a valid monitored item."},
+	StatusBadMonitoredItemFilterInvalid:                            {Name: "StatusBadMonitoredItemFilterInvalid", Symbol: "BadMonitoredItemFilterInvalid", Text: "The monitored item filter parameter is not valid."},
+	StatusBadMonitoredItemFilterUnsupported:                        {Name: "StatusBadMonitoredItemFilterUnsupported", Symbol: "BadMonitoredItemFilterUnsupported", Text: "The server does not support the requested monitored item filter."},
+	StatusBadFilterNotAllowed:                                      {Name: "StatusBadFilterNotAllowed", Symbol: "BadFilterNotAllowed", Text: "A monitoring filter cannot be used in combination with the attribute specified."},
+	StatusBadStructureMissing:                                      {Name: "StatusBadStructureMissing", Symbol: "BadStructureMissing", Text: "A mandatory structured parameter was missing or null."},
+	StatusBadEventFilterInvalid:                                    {Name: "StatusBadEventFilterInvalid", Symbol: "BadEventFilterInvalid", Text: "The event filter is not valid."},
+	StatusBadContentFilterInvalid:                                  {Name: "StatusBadContentFilterInvalid", Symbol: "BadContentFilterInvalid", Text: "The content filter is not valid."},
+	StatusBadFilterOperatorInvalid:                                 {Name: "StatusBadFilterOperatorInvalid", Symbol: "BadFilterOperatorInvalid", Text: "An unrecognized operator was provided in a filter."},
+	StatusBadFilterOperatorUnsupported:                             {Name: "StatusBadFilterOperatorUnsupported", Symbol: "BadFilterOperatorUnsupported", Text: "A valid operator was provided, but the server does not provide support for this filter operator."},
+	StatusBadFilterOperandCountMismatch:                            {Name: "StatusBadFilterOperandCountMismatch", Symbol: "BadFilterOperandCountMismatch", Text: "The number of operands provided for the filter operator was less then expected for the operand provided."},
+	StatusBadFilterOperandInvalid:                                  {Name: "StatusBadFilterOperandInvalid", Symbol: "BadFilterOperandInvalid", Text: "The operand used in a content filter is not valid."},
+	StatusBadFilterElementInvalid:                                  {Name: "StatusBadFilterElementInvalid", Symbol: "BadFilterElementInvalid", Text: "The referenced element is not a valid element in the content filter."},
+	StatusBadFilterLiteralInvalid:                                  {Name: "StatusBadFilterLiteralInvalid", Symbol: "BadFilterLiteralInvalid", Text: "The referenced literal is not a valid value."},
+	StatusBadContinuationPointInvalid:                              {Name: "StatusBadContinuationPointInvalid", Symbol: "BadContinuationPointInvalid", Text: "The continuation point provide is longer valid."},
+	StatusBadNoContinuationPoints:                                  {Name: "StatusBadNoContinuationPoints", Symbol: "BadNoContinuationPoints", Text: "The operation could not be processed because all continuation points have been allocated."},
+	StatusBadReferenceTypeIDInvalid:                                {Name: "StatusBadReferenceTypeIDInvalid", Symbol: "BadReferenceTypeIdInvalid", Text: "The reference type id does not refer to a valid reference type node."},
+	StatusBadBrowseDirectionInvalid:                                {Name: "StatusBadBrowseDirectionInvalid", Symbol: "BadBrowseDirectionInvalid", Text: "The browse direction is not valid."},
+	StatusBadNodeNotInView:                                         {Name: "StatusBadNodeNotInView", Symbol: "BadNodeNotInView", Text: "The node is not part of the view."},
+	StatusBadNumericOverflow:                                       {Name: "StatusBadNumericOverflow", Symbol: "BadNumericOverflow", Text: "The number was not accepted because of a numeric overflow."},
+	StatusBadServerURIInvalid:                                      {Name: "StatusBadServerURIInvalid", Symbol: "BadServerUriInvalid", Text: "The ServerUri is not a valid URI."},
+	StatusBadServerNameMissing:                                     {Name: "StatusBadServerNameMissing", Symbol: "BadServerNameMissing", Text: "No ServerName was specified."},
+	StatusBadDiscoveryURLMissing:                                   {Name: "StatusBadDiscoveryURLMissing", Symbol: "BadDiscoveryUrlMissing", Text: "No DiscoveryUrl was specified."},
+	StatusBadSempahoreFileMissing:                                  {Name: "StatusBadSempahoreFileMissing", Symbol: "BadSempahoreFileMissing", Text: "The semaphore file specified by the client is not valid."},
+	StatusBadRequestTypeInvalid:                                    {Name: "StatusBadRequestTypeInvalid", Symbol: "BadRequestTypeInvalid", Text: "The security token request type is not valid."},
+	StatusBadSecurityModeRejected:                                  {Name: "StatusBadSecurityModeRejected", Symbol: "BadSecurityModeRejected", Text: "The security mode does not meet the requirements set by the server."},
+	StatusBadSecurityPolicyRejected:                                {Name: "StatusBadSecurityPolicyRejected", Symbol: "BadSecurityPolicyRejected", Text: "The security policy does not meet the requirements set by the server."},
+	StatusBadTooManySessions:                                       {Name: "StatusBadTooManySessions", Symbol: "BadTooManySessions", Text: "The server has reached its maximum number of sessions."},
+	StatusBadUserSignatureInvalid:                                  {Name: "StatusBadUserSignatureInvalid", Symbol: "BadUserSignatureInvalid", Text: "The user token signature is missing or invalid."},
+	StatusBadApplicationSignatureInvalid:                           {Name: "StatusBadApplicationSignatureInvalid", Symbol: "BadApplicationSignatureInvalid", Text: "The signature generated with the client certificate is missing or invalid."},
+	StatusBadNoValidCertificates:                                   {Name: "StatusBadNoValidCertificates", Symbol: "BadNoValidCertificates", Text: "The client did not provide at least one software certificate that is valid and meets the profile requirements for the server."},
+	StatusBadIdentityChangeNotSupported:                            {Name: "StatusBadIdentityChangeNotSupported", Symbol: "BadIdentityChangeNotSupported", Text: "The server does not support changing the user identity assigned to the session."},
+	StatusBadRequestCancelledByRequest:                             {Name: "StatusBadRequestCancelledByRequest", Symbol: "BadRequestCancelledByRequest", Text: "The request was cancelled by the client with the Cancel service."},
+	StatusBadParentNodeIDInvalid:                                   {Name: "StatusBadParentNodeIDInvalid", Symbol: "BadParentNodeIdInvalid", Text: "The parent node id does not to refer to a valid node."},
+	StatusBadReferenceNotAllowed:                                   {Name: "StatusBadReferenceNotAllowed", Symbol: "BadReferenceNotAllowed", Text: "The reference could not be created because it violates constraints imposed by the data model."},
+	StatusBadNodeIDRejected:                                        {Name: "StatusBadNodeIDRejected", Symbol: "BadNodeIdRejected", Text: "The requested node id was reject because it was either invalid or server does not allow node ids to be specified by the client."},
+	StatusBadNodeIDExists:                                          {Name: "StatusBadNodeIDExists", Symbol: "BadNodeIdExists", Text: "The requested node id is already used by another node."},
+	StatusBadNodeClassInvalid:                                      {Name: "StatusBadNodeClassInvalid", Symbol: "BadNodeClassInvalid", Text: "The node class is not valid."},
+	StatusBadBrowseNameInvalid:                                     {Name: "StatusBadBrowseNameInvalid", Symbol: "BadBrowseNameInvalid", Text: "The browse name is invalid."},
+	StatusBadBrowseNameDuplicated:                                  {Name: "StatusBadBrowseNameDuplicated", Symbol: "BadBrowseNameDuplicated", Text: "The browse name is not unique among nodes that share the same relationship with the parent."},
+	StatusBadNodeAttributesInvalid:                                 {Name: "StatusBadNodeAttributesInvalid", Symbol: "BadNodeAttributesInvalid", Text: "The node attributes are not valid for the node class."},
+	StatusBadTypeDefinitionInvalid:                                 {Name: "StatusBadTypeDefinitionInvalid", Symbol: "BadTypeDefinitionInvalid", Text: "The type definition node id does not reference an appropriate type node."},
+	StatusBadSourceNodeIDInvalid:                                   {Name: "StatusBadSourceNodeIDInvalid", Symbol: "BadSourceNodeIdInvalid", Text: "The source node id does not reference a valid node."},
+	StatusBadTargetNodeIDInvalid:                                   {Name: "StatusBadTargetNodeIDInvalid", Symbol: "BadTargetNodeIdInvalid", Text: "The target node id does not reference a valid node."},
+	StatusBadDuplicateReferenceNotAllowed:                          {Name: "StatusBadDuplicateReferenceNotAllowed", Symbol: "BadDuplicateReferenceNotAllowed", Text: "The reference type between the nodes is already defined."},
+	StatusBadInvalidSelfReference:                                  {Name: "StatusBadInvalidSelfReference", Symbol: "BadInvalidSelfReference", Text: "The server does not allow this type of self reference on this node."},
+	StatusBadReferenceLocalOnly:                                    {Name: "StatusBadReferenceLocalOnly", Symbol: "BadReferenceLocalOnly", Text: "The reference type is not valid for a reference to a remote server."},
+	StatusBadNoDeleteRights:                                        {Name: "StatusBadNoDeleteRights", Symbol: "BadNoDeleteRights", Text: "The server will not allow the node to be deleted."},
+	StatusUncertainReferenceNotDeleted:                             {Name: "StatusUncertainReferenceNotDeleted", Symbol: "UncertainReferenceNotDeleted", Text: "The server was not able to delete all target references."},
+	StatusBadServerIndexInvalid:                                    {Name: "StatusBadServerIndexInvalid", Symbol: "BadServerIndexInvalid", Text: "The server index is not valid."},
+	StatusBadViewIDUnknown:                                         {Name: "StatusBadViewIDUnknown", Symbol: "BadViewIdUnknown", Text: "The view id does not refer to a valid view node."},
+	StatusBadViewTimestampInvalid:                                  {Name: "StatusBadViewTimestampInvalid", Symbol: "BadViewTimestampInvalid", Text: "The view timestamp is not available or not supported."},
+	StatusBadViewParameterMismatch:                                 {Name: "StatusBadViewParameterMismatch", Symbol: "BadViewParameterMismatch", Text: "The view parameters are not consistent with each other."},
+	StatusBadViewVersionInvalid:                                    {Name: "StatusBadViewVersionInvalid", Symbol: "BadViewVersionInvalid", Text: "The view version is not available or not supported."},
+	StatusUncertainNotAllNodesAvailable:                            {Name: "StatusUncertainNotAllNodesAvailable", Symbol: "UncertainNotAllNodesAvailable", Text: "The list of references may not be complete because the underlying system is not available."},
+	StatusGoodResultsMayBeIncomplete:                               {Name: "StatusGoodResultsMayBeIncomplete", Symbol: "GoodResultsMayBeIncomplete", Text: "The server should have followed a reference to a node in a remote server but did not. The result set may be incomplete."},
+	StatusBadNotTypeDefinition:                                     {Name: "StatusBadNotTypeDefinition", Symbol: "BadNotTypeDefinition", Text: "The provided Nodeid was not a type definition nodeid."},
+	StatusUncertainReferenceOutOfServer:                            {Name: "StatusUncertainReferenceOutOfServer", Symbol: "UncertainReferenceOutOfServer", Text: "One of the references to follow in the relative path references to a node in the address space in another server."},
+	StatusBadTooManyMatches:                                        {Name: "StatusBadTooManyMatches", Symbol: "BadTooManyMatches", Text: "The requested operation has too many matches to return."},
+	StatusBadQueryTooComplex:                                       {Name: "StatusBadQueryTooComplex", Symbol: "BadQueryTooComplex", Text: "The requested operation requires too many resources in the server."},
+	StatusBadNoMatch:                                               {Name: "StatusBadNoMatch", Symbol: "BadNoMatch", Text: "The requested operation has no match to return."},
+	StatusBadMaxAgeInvalid:                                         {Name: "StatusBadMaxAgeInvalid", Symbol: "BadMaxAgeInvalid", Text: "The max age parameter is invalid."},
+	StatusBadSecurityModeInsufficient:                              {Name: "StatusBadSecurityModeInsufficient", Symbol: "BadSecurityModeInsufficient", Text: "The operation is not permitted over the current secure channel."},
+	StatusBadHistoryOperationInvalid:                               {Name: "StatusBadHistoryOperationInvalid", Symbol: "BadHistoryOperationInvalid", Text: "The history details parameter is not valid."},
+	StatusBadHistoryOperationUnsupported:                           {Name: "StatusBadHistoryOperationUnsupported", Symbol: "BadHistoryOperationUnsupported", Text: "The server does not support the requested operation."},
+	StatusBadInvalidTimestampArgument:                              {Name: "StatusBadInvalidTimestampArgument", Symbol: "BadInvalidTimestampArgument", Text: "The defined timestamp to return was invalid."},
+	StatusBadWriteNotSupported:                                     {Name: "StatusBadWriteNotSupported", Symbol: "BadWriteNotSupported", Text: "The server does not support writing the combination of value, status and timestamps provided."},
+	StatusBadTypeMismatch:                                          {Name: "StatusBadTypeMismatch", Symbol: "BadTypeMismatch", Text: "The value supplied for the attribute is not of the same type as the attribute's value."},
+	StatusBadMethodInvalid:                                         {Name: "StatusBadMethodInvalid", Symbol: "BadMethodInvalid", Text: "The method id does not refer to a method for the specified object."},
+	StatusBadArgumentsMissing:                                      {Name: "StatusBadArgumentsMissing", Symbol: "BadArgumentsMissing", Text: "The client did not specify all of the input arguments for the method."},
+	StatusBadNotExecutable:                                         {Name: "StatusBadNotExecutable", Symbol: "BadNotExecutable", Text: "The executable attribute does not allow the execution of the method."},
+	StatusBadTooManySubscriptions:                                  {Name: "StatusBadTooManySubscriptions", Symbol: "BadTooManySubscriptions", Text: "The server has reached its maximum number of subscriptions."},
+	StatusBadTooManyPublishRequests:                                {Name: "StatusBadTooManyPublishRequests", Symbol: "BadTooManyPublishRequests", Text: "The server has reached the maximum number of queued publish requests."},
+	StatusBadNoSubscription:                                        {Name: "StatusBadNoSubscription", Symbol: "BadNoSubscription", Text: "There is no subscription available for this session."},
+	StatusBadSequenceNumberUnknown:                                 {Name: "StatusBadSequenceNumberUnknown", Symbol: "BadSequenceNumberUnknown", Text: "The sequence number is unknown to the server."},
+	StatusGoodRetransmissionQueueNotSupported:                      {Name: "StatusGoodRetransmissionQueueNotSupported", Symbol: "GoodRetransmissionQueueNotSupported", Text: "The Server does not support retransmission queue and acknowledgement of sequence numbers is not available."},
+	StatusBadMessageNotAvailable:                                   {Name: "StatusBadMessageNotAvailable", Symbol: "BadMessageNotAvailable", Text: "The requested notification message is no longer available."},
+	StatusBadInsufficientClientProfile:                             {Name: "StatusBadInsufficientClientProfile", Symbol: "BadInsufficientClientProfile", Text: "The client of the current session does not support one or more Profiles that are necessary for the subscription."},
+	StatusBadStateNotActive:                                        {Name: "StatusBadStateNotActive", Symbol: "BadStateNotActive", Text: "The sub-state machine is not currently active."},
+	StatusBadAlreadyExists:                                         {Name: "StatusBadAlreadyExists", Symbol: "BadAlreadyExists", Text: "An equivalent rule already exists."},
+	StatusBadTCPServerTooBusy:                                      {Name: "StatusBadTCPServerTooBusy", Symbol: "BadTcpServerTooBusy", Text: "The server cannot process the request because it is too busy."},
+	StatusBadTCPMessageTypeInvalid:                                 {Name: "StatusBadTCPMessageTypeInvalid", Symbol: "BadTcpMessageTypeInvalid", Text: "The type of the message specified in the header invalid."},
+	StatusBadTCPSecureChannelUnknown:                               {Name: "StatusBadTCPSecureChannelUnknown", Symbol: "BadTcpSecureChannelUnknown", Text: "The SecureChannelId and/or TokenId are not currently in use."},
+	StatusBadTCPMessageTooLarge:                                    {Name: "StatusBadTCPMessageTooLarge", Symbol: "BadTcpMessageTooLarge", Text: "The size of the message chunk specified in the header is too large."},
+	StatusBadTCPNotEnoughResources:                                 {Name: "StatusBadTCPNotEnoughResources", Symbol: "BadTcpNotEnoughResources", Text: "There are not enough resources to process the request."},
+	StatusBadTCPInternalError:                                      {Name: "StatusBadTCPInternalError", Symbol: "BadTcpInternalError", Text: "An internal error occurred."},
+	StatusBadTCPEndpointURLInvalid:                                 {Name: "StatusBadTCPEndpointURLInvalid", Symbol: "BadTcpEndpointUrlInvalid", Text: "The server does not recognize the QueryString specified."},
+	StatusBadRequestInterrupted:                                    {Name: "StatusBadRequestInterrupted", Symbol: "BadRequestInterrupted", Text: "The request could not be sent because of a network interruption."},
+	StatusBadRequestTimeout:                                        {Name: "StatusBadRequestTimeout", Symbol: "BadRequestTimeout", Text: "Timeout occurred while processing the request."},
+	StatusBadSecureChannelClosed:                                   {Name: "StatusBadSecureChannelClosed", Symbol: "BadSecureChannelClosed", Text: "The secure channel has been closed."},
+	StatusBadSecureChannelTokenUnknown:                             {Name: "StatusBadSecureChannelTokenUnknown", Symbol: "BadSecureChannelTokenUnknown", Text: "The token has expired or is not recognized."},
+	StatusBadSequenceNumberInvalid:                                 {Name: "StatusBadSequenceNumberInvalid", Symbol: "BadSequenceNumberInvalid", Text: "The sequence number is not valid."},
+	StatusBadProtocolVersionUnsupported:                            {Name: "StatusBadProtocolVersionUnsupported", Symbol: "BadProtocolVersionUnsupported", Text: "The applications do not have compatible protocol versions."},
+	StatusBadConfigurationError:                                    {Name: "StatusBadConfigurationError", Symbol: "BadConfigurationError", Text: "There is a problem with the configuration that affects the usefulness of the value."},
+	StatusBadNotConnected:                                          {Name: "StatusBadNotConnected", Symbol: "BadNotConnected", Text: "The variable should receive its value from another variable, but has never been configured to do so."},
+	StatusBadDeviceFailure:                                         {Name: "StatusBadDeviceFailure", Symbol: "BadDeviceFailure", Text: "There has been a failure in the device/data source that generates the value that has affected the value."},
+	StatusBadSensorFailure:                                         {Name: "StatusBadSensorFailure", Symbol: "BadSensorFailure", Text: "There has been a failure in the sensor from which the value is derived by the device/data source."},
+	StatusBadOutOfService:                                          {Name: "StatusBadOutOfService", Symbol: "BadOutOfService", Text: "The source of the data is not operational."},
+	StatusBadDeadbandFilterInvalid:                                 {Name: "StatusBadDeadbandFilterInvalid", Symbol: "BadDeadbandFilterInvalid", Text: "The deadband filter is not valid."},
+	StatusUncertainNoCommunicationLastUsableValue:                  {Name: "StatusUncertainNoCommunicationLastUsableValue", Symbol: "UncertainNoCommunicationLastUsableValue", Text: "Communication to the data source has failed. The variable value is the last value that had a good quality."},
+	StatusUncertainLastUsableValue:                                 {Name: "StatusUncertainLastUsableValue", Symbol: "UncertainLastUsableValue", Text: "Whatever was updating this value has stopped doing so."},
+	StatusUncertainSubstituteValue:                                 {Name: "StatusUncertainSubstituteValue", Symbol: "UncertainSubstituteValue", Text: "The value is an operational value that was manually overwritten."},
+	StatusUncertainInitialValue:                                    {Name: "StatusUncertainInitialValue", Symbol: "UncertainInitialValue", Text: "The value is an initial value for a variable that normally receives its value from another variable."},
+	StatusUncertainSensorNotAccurate:                               {Name: "StatusUncertainSensorNotAccurate", Symbol: "UncertainSensorNotAccurate", Text: "The value is at one of the sensor limits."},
+	StatusUncertainEngineeringUnitsExceeded:                        {Name: "StatusUncertainEngineeringUnitsExceeded", Symbol: "UncertainEngineeringUnitsExceeded", Text: "The value is outside of the range of values defined for this parameter."},
+	StatusUncertainSubNormal:                                       {Name: "StatusUncertainSubNormal", Symbol: "UncertainSubNormal", Text: "The value is derived from multiple sources and has less than the required number of Good sources."},
+	StatusGoodLocalOverride:                                        {Name: "StatusGoodLocalOverride", Symbol: "GoodLocalOverride", Text: "The value has been overridden."},
+	StatusBadRefreshInProgress:                                     {Name: "StatusBadRefreshInProgress", Symbol: "BadRefreshInProgress", Text: "This Condition refresh failed, a Condition refresh operation is already in progress."},
+	StatusBadConditionAlreadyDisabled:                              {Name: "StatusBadConditionAlreadyDisabled", Symbol: "BadConditionAlreadyDisabled", Text: "This condition has already been disabled."},
+	StatusBadConditionAlreadyEnabled:                               {Name: "StatusBadConditionAlreadyEnabled", Symbol: "BadConditionAlreadyEnabled", Text: "This condition has already been enabled."},
+	StatusBadConditionDisabled:                                     {Name: "StatusBadConditionDisabled", Symbol: "BadConditionDisabled", Text: "Property not available, this condition is disabled."},
+	StatusBadEventIDUnknown:                                        {Name: "StatusBadEventIDUnknown", Symbol: "BadEventIdUnknown", Text: "The specified event id is not recognized."},
+	StatusBadEventNotAcknowledgeable:                               {Name: "StatusBadEventNotAcknowledgeable", Symbol: "BadEventNotAcknowledgeable", Text: "The event cannot be acknowledged."},
+	StatusBadDialogNotActive:                                       {Name: "StatusBadDialogNotActive", Symbol: "BadDialogNotActive", Text: "The dialog condition is not active."},
+	StatusBadDialogResponseInvalid:                                 {Name: "StatusBadDialogResponseInvalid", Symbol: "BadDialogResponseInvalid", Text: "The response is not valid for the dialog."},
+	StatusBadConditionBranchAlreadyAcked:                           {Name: "StatusBadConditionBranchAlreadyAcked", Symbol: "BadConditionBranchAlreadyAcked", Text: "The condition branch has already been acknowledged."},
+	StatusBadConditionBranchAlreadyConfirmed:                       {Name: "StatusBadConditionBranchAlreadyConfirmed", Symbol: "BadConditionBranchAlreadyConfirmed", Text: "The condition branch has already been confirmed."},
+	StatusBadConditionAlreadyShelved:                               {Name: "StatusBadConditionAlreadyShelved", Symbol: "BadConditionAlreadyShelved", Text: "The condition has already been shelved."},
+	StatusBadConditionNotShelved:                                   {Name: "StatusBadConditionNotShelved", Symbol: "BadConditionNotShelved", Text: "The condition is not currently shelved."},
+	StatusBadShelvingTimeOutOfRange:                                {Name: "StatusBadShelvingTimeOutOfRange", Symbol: "BadShelvingTimeOutOfRange", Text: "The shelving time not within an acceptable range."},
+	StatusBadNoData:                                                {Name: "StatusBadNoData", Symbol: "BadNoData", Text: "No data exists for the requested time range or event filter."},
+	StatusBadBoundNotFound:                                         {Name: "StatusBadBoundNotFound", Symbol: "BadBoundNotFound", Text: "No data found to provide upper or lower bound value."},
+	StatusBadBoundNotSupported:                                     {Name: "StatusBadBoundNotSupported", Symbol: "BadBoundNotSupported", Text: "The server cannot retrieve a bound for the variable."},
+	StatusBadDataLost:                                              {Name: "StatusBadDataLost", Symbol: "BadDataLost", Text: "Data is missing due to collection started/stopped/lost."},
+	StatusBadDataUnavailable:                                       {Name: "StatusBadDataUnavailable", Symbol: "BadDataUnavailable", Text: "Expected data is unavailable for the requested time range due to an un-mounted volume, an off-line archive or tape, or similar reason for temporary unavailability."},
+	StatusBadEntryExists:                                           {Name: "StatusBadEntryExists", Symbol: "BadEntryExists", Text: "The data or event was not successfully inserted because a matching entry exists."},
+	StatusBadNoEntryExists:                                         {Name: "StatusBadNoEntryExists", Symbol: "BadNoEntryExists", Text: "The data or event was not successfully updated because no matching entry exists."},
+	StatusBadTimestampNotSupported:                                 {Name: "StatusBadTimestampNotSupported", Symbol: "BadTimestampNotSupported", Text: "The client requested history using a timestamp format the server does not support (i.e requested ServerTimestamp when server only supports SourceTimestamp)."},
+	StatusGoodEntryInserted:                                        {Name: "StatusGoodEntryInserted", Symbol: "GoodEntryInserted", Text: "The data or event was successfully inserted into the historical database."},
+	StatusGoodEntryReplaced:                                        {Name: "StatusGoodEntryReplaced", Symbol: "GoodEntryReplaced", Text: "The data or event field was successfully replaced in the historical database."},
+	StatusUncertainDataSubNormal:                                   {Name: "StatusUncertainDataSubNormal", Symbol: "UncertainDataSubNormal", Text: "The value is derived from multiple values and has less than the required number of Good values."},
+	StatusGoodNoData:                                               {Name: "StatusGoodNoData", Symbol: "GoodNoData", Text: "No data exists for the requested time range or event filter."},
+	StatusGoodMoreData:                                             {Name: "StatusGoodMoreData", Symbol: "GoodMoreData", Text: "More data is available in the time range beyond the number of values requested."},
+	StatusBadAggregateListMismatch:                                 {Name: "StatusBadAggregateListMismatch", Symbol: "BadAggregateListMismatch", Text: "The requested number of Aggregates does not match the requested number of NodeIds."},
+	StatusBadAggregateNotSupported:                                 {Name: "StatusBadAggregateNotSupported", Symbol: "BadAggregateNotSupported", Text: "The requested Aggregate is not support by the server."},
+	StatusBadAggregateInvalidInputs:                                {Name: "StatusBadAggregateInvalidInputs", Symbol: "BadAggregateInvalidInputs", Text: "The aggregate value could not be derived due to invalid data inputs."},
+	StatusBadAggregateConfigurationRejected:                        {Name: "StatusBadAggregateConfigurationRejected", Symbol: "BadAggregateConfigurationRejected", Text: "The aggregate configuration is not valid for specified node."},
+	StatusGoodDataIgnored:                                          {Name: "StatusGoodDataIgnored", Symbol: "GoodDataIgnored", Text: "The request specifies fields which are not valid for the EventType or cannot be saved by the historian."},
+	StatusBadRequestNotAllowed:                                     {Name: "StatusBadRequestNotAllowed", Symbol: "BadRequestNotAllowed", Text: "The request was rejected by the server because it did not meet the criteria set by the server."},
+	StatusBadRequestNotComplete:                                    {Name: "StatusBadRequestNotComplete", Symbol: "BadRequestNotComplete", Text: "The request has not been processed by the server yet."},
+	StatusBadTransactionPending:                                    {Name: "StatusBadTransactionPending", Symbol: "BadTransactionPending", Text: "The operation is not allowed because a transaction is in progress."},
+	StatusBadTicketRequired:                                        {Name: "StatusBadTicketRequired", Symbol: "BadTicketRequired", Text: "The device identity needs a ticket before it can be accepted."},
+	StatusBadTicketInvalid:                                         {Name: "StatusBadTicketInvalid", Symbol: "BadTicketInvalid", Text: "The device identity needs a ticket before it can be accepted."},
+	StatusBadLocked:                                                {Name: "StatusBadLocked", Symbol: "BadLocked", Text: "The requested operation is not allowed, because the Node is locked by a different application."},
+	StatusGoodEdited:                                               {Name: "StatusGoodEdited", Symbol: "GoodEdited", Text: "The value does not come from the real source and has been edited by the server."},
+	StatusGoodPostActionFailed:                                     {Name: "StatusGoodPostActionFailed", Symbol: "GoodPostActionFailed", Text: "There was an error in execution of these post-actions."},
+	StatusUncertainDominantValueChanged:                            {Name: "StatusUncertainDominantValueChanged", Symbol: "UncertainDominantValueChanged", Text: "The related EngineeringUnit has been changed but the Variable Value is still provided based on the previous unit."},
+	StatusGoodDependentValueChanged:                                {Name: "StatusGoodDependentValueChanged", Symbol: "GoodDependentValueChanged", Text: "A dependent value has been changed but the change has not been applied to the device."},
+	StatusBadDominantValueChanged:                                  {Name: "StatusBadDominantValueChanged", Symbol: "BadDominantValueChanged", Text: "The related EngineeringUnit has been changed but this change has not been applied to the device. The Variable Value is still dependent on the previous unit but its status is currently Bad."},
+	StatusUncertainDependentValueChanged:                           {Name: "StatusUncertainDependentValueChanged", Symbol: "UncertainDependentValueChanged", Text: "A dependent value has been changed but the change has not been applied to the device. The quality of the dominant variable is uncertain."},
+	StatusBadDependentValueChanged:                                 {Name: "StatusBadDependentValueChanged", Symbol: "BadDependentValueChanged", Text: "A dependent value has been changed but the change has not been applied to the device. The quality of the dominant variable is Bad."},
+	StatusGoodEdited_DependentValueChanged:                         {Name: "StatusGoodEdited_DependentValueChanged", Symbol: "GoodEdited_DependentValueChanged", Text: "It is delivered with a dominant Variable value when a dependent Variable has changed but the change has not been applied."},
+	StatusGoodEdited_DominantValueChanged:                          {Name: "StatusGoodEdited_DominantValueChanged", Symbol: "GoodEdited_DominantValueChanged", Text: "It is delivered with a dependent Variable value when a dominant Variable has changed but the change has not been applied."},
+	StatusGoodEdited_DominantValueChanged_DependentValueChanged:    {Name: "StatusGoodEdited_DominantValueChanged_DependentValueChanged", Symbol: "GoodEdited_DominantValueChanged_DependentValueChanged", Text: "It is delivered with a dependent Variable value when a dominant or dependent Variable has changed but change has not been applied."},
+	StatusBadEdited_OutOfRange:                                     {Name: "StatusBadEdited_OutOfRange", Symbol: "BadEdited_OutOfRange", Text: "It is delivered with a Variable value when Variable has changed but the value is not legal."},
+	StatusBadInitialValue_OutOfRange:                               {Name: "StatusBadInitialValue_OutOfRange", Symbol: "BadInitialValue_OutOfRange", Text: "It is delivered with a Variable value when a source Variable has changed but the value is not legal."},
+	StatusBadOutOfRange_DominantValueChanged:                       {Name: "StatusBadOutOfRange_DominantValueChanged", Symbol: "BadOutOfRange_DominantValueChanged", Text: "It is delivered with a dependent Variable value when a dominant Variable has changed and the value is not legal."},
+	StatusBadEdited_OutOfRange_DominantValueChanged:                {Name: "StatusBadEdited_OutOfRange_DominantValueChanged", Symbol: "BadEdited_OutOfRange_DominantValueChanged", Text: "It is delivered with a dependent Variable value when a dominant Variable has changed, the value is not legal and the change has not been applied."},
+	StatusBadOutOfRange_DominantValueChanged_DependentValueChanged: {Name: "StatusBadOutOfRange_DominantValueChanged_DependentValueChanged", Symbol: "BadOutOfRange_DominantValueChanged_DependentValueChanged", Text: "It is delivered with a dependent Variable value when a dominant or dependent Variable has changed and the value is not legal."},
+	StatusBadEdited_OutOfRange_DominantValueChanged_DependentValueChanged: {Name: "StatusBadEdited_OutOfRange_DominantValueChanged_DependentValueChanged", Symbol: "BadEdited_OutOfRange_DominantValueChanged_DependentValueChanged", Text: "It is delivered with a dependent Variable value when a dominant or dependent Variable has changed, the value is not legal and the change has not been applied."},
+	StatusGoodCommunicationEvent:                                          {Name: "StatusGoodCommunicationEvent", Symbol: "GoodCommunicationEvent", Text: "The communication layer has raised an event."},
+	StatusGoodShutdownEvent:                                               {Name: "StatusGoodShutdownEvent", Symbol: "GoodShutdownEvent", Text: "The system is shutting down."},
+	StatusGoodCallAgain:                                                   {Name: "StatusGoodCallAgain", Symbol: "GoodCallAgain", Text: "The operation is not finished and needs to be called again."},
+	StatusGoodNonCriticalTimeout:                                          {Name: "StatusGoodNonCriticalTimeout", Symbol: "GoodNonCriticalTimeout", Text: "A non-critical timeout occurred."},
+	StatusBadInvalidArgument:                                              {Name: "StatusBadInvalidArgument", Symbol: "BadInvalidArgument", Text: "One or more arguments are invalid."},
+	StatusBadConnectionRejected:                                           {Name: "StatusBadConnectionRejected", Symbol: "BadConnectionRejected", Text: "Could not establish a network connection to remote server."},
+	StatusBadDisconnect:                                                   {Name: "StatusBadDisconnect", Symbol: "BadDisconnect", Text: "The server has disconnected from the client."},
+	StatusBadConnectionClosed:                                             {Name: "StatusBadConnectionClosed", Symbol: "BadConnectionClosed", Text: "The network connection has been closed."},
+	StatusBadInvalidState:                                                 {Name: "StatusBadInvalidState", Symbol: "BadInvalidState", Text: "The operation cannot be completed because the object is closed, uninitialized or in some other invalid state."},
+	StatusBadEndOfStream:                                                  {Name: "StatusBadEndOfStream", Symbol: "BadEndOfStream", Text: "Cannot move beyond end of the stream."},
+	StatusBadNoDataAvailable:                                              {Name: "StatusBadNoDataAvailable", Symbol: "BadNoDataAvailable", Text: "No data is currently available for reading from a non-blocking stream."},
+	StatusBadWaitingForResponse:                                           {Name: "StatusBadWaitingForResponse", Symbol: "BadWaitingForResponse", Text: "The asynchronous operation is waiting for a response."},
+	StatusBadOperationAbandoned:                                           {Name: "StatusBadOperationAbandoned", Symbol: "BadOperationAbandoned", Text: "The asynchronous operation was abandoned by the caller."},
+	StatusBadExpectedStreamToBlock:                                        {Name: "StatusBadExpectedStreamToBlock", Symbol: "BadExpectedStreamToBlock", Text: "The stream did not return all data requested (possibly because it is a non-blocking stream)."},
+	StatusBadWouldBlock:                                                   {Name: "StatusBadWouldBlock", Symbol: "BadWouldBlock", Text: "Non blocking behaviour is required and the operation would block."},
+	StatusBadSyntaxError:                                                  {Name: "StatusBadSyntaxError", Symbol: "BadSyntaxError", Text: "A value had an invalid syntax."},
+	StatusBadMaxConnectionsReached:                                        {Name: "StatusBadMaxConnectionsReached", Symbol: "BadMaxConnectionsReached", Text: "The operation could not be finished because all available connections are in use."},
+	StatusUncertainTransducerInManual:                                     {Name: "StatusUncertainTransducerInManual", Symbol: "UncertainTransducerInManual", Text: "The value may not be accurate because the transducer is in manual mode."},
+	StatusUncertainSimulatedValue:                                         {Name: "StatusUncertainSimulatedValue", Symbol: "UncertainSimulatedValue", Text: "The value is simulated."},
+	StatusUncertainSensorCalibration:                                      {Name: "StatusUncertainSensorCalibration", Symbol: "UncertainSensorCalibration", Text: "The value may not be accurate due to a sensor calibration fault."},
+	StatusUncertainConfigurationError:                                     {Name: "StatusUncertainConfigurationError", Symbol: "UncertainConfigurationError", Text: "The value may not be accurate due to a configuration issue."},
+	StatusGoodCascadeInitializationAcknowledged:                           {Name: "StatusGoodCascadeInitializationAcknowledged", Symbol: "GoodCascadeInitializationAcknowledged", Text: "The value source supports cascade handshaking and the value has been Initialized based on an initialization request from a cascade secondary."},
+	StatusGoodCascadeInitializationRequest:                                {Name: "StatusGoodCascadeInitializationRequest", Symbol: "GoodCascadeInitializationRequest", Text: "The value source supports cascade handshaking and is requesting initialization of a cascade primary."},
+	StatusGoodCascadeNotInvited:                                           {Name: "StatusGoodCascadeNotInvited", Symbol: "GoodCascadeNotInvited", Text: "The value source supports cascade handshaking, however, the source’s current state does not allow for cascade."},
+	StatusGoodCascadeNotSelected:                                          {Name: "StatusGoodCascadeNotSelected", Symbol: "GoodCascadeNotSelected", Text: "The value source supports cascade handshaking, however, the source has not selected the corresponding cascade primary for use."},
+	StatusGoodFaultStateActive:                                            {Name: "StatusGoodFaultStateActive", Symbol: "GoodFaultStateActive", Text: "There is a fault state condition active in the value source."},
+	StatusGoodInitiateFaultState:                                          {Name: "StatusGoodInitiateFaultState", Symbol: "GoodInitiateFaultState", Text: "A fault state condition is being requested of the destination."},
+	StatusGoodCascade:                                                     {Name: "StatusGoodCascade", Symbol: "GoodCascade", Text: "The value is accurate, and the signal source supports cascade handshaking."},
+	StatusBadDataSetIDInvalid:                                             {Name: "StatusBadDataSetIDInvalid", Symbol: "BadDataSetIdInvalid", Text: "The DataSet specified for the DataSetWriter creation is invalid."},
 }
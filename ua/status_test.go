@@ -0,0 +1,57 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		code             StatusCode
+		severity         StatusSeverity
+		subCode          uint16
+		structureChanged bool
+		overflow         bool
+		name             string
+		symbol           string
+	}{
+		{code: StatusOK, severity: StatusSeverityGood, name: "Good", symbol: "Good"},
+		{code: StatusGoodOverload, severity: StatusSeverityGood, subCode: 0x2f, name: "GoodOverload", symbol: "GoodOverload"},
+		{code: StatusUncertainLastUsableValue, severity: StatusSeverityUncertain, subCode: 0x90, name: "UncertainLastUsableValue", symbol: "UncertainLastUsableValue"},
+		{code: StatusBadNodeIDUnknown, severity: StatusSeverityBad, subCode: 0x34, name: "BadNodeIDUnknown", symbol: "BadNodeIdUnknown"},
+		// overflow is only valid with the DataValue info type
+		{code: StatusOK | 0x0480, severity: StatusSeverityGood, overflow: true, name: "Good", symbol: "Good"},
+		{code: StatusOK | 0x0080, severity: StatusSeverityGood, name: "Good", symbol: "Good"},
+		{code: StatusBadNodeIDUnknown | 0x8000, severity: StatusSeverityBad, subCode: 0x34, structureChanged: true, name: "BadNodeIDUnknown", symbol: "BadNodeIdUnknown"},
+		// reserved severity
+		{code: 0xc0010000, severity: StatusSeverityBad, subCode: 0x001, name: "0xC0010000", symbol: "0xC0010000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.severity, tt.code.Severity(), "Severity")
+			require.Equal(t, tt.severity == StatusSeverityGood, tt.code.IsGood(), "IsGood")
+			require.Equal(t, tt.severity == StatusSeverityUncertain, tt.code.IsUncertain(), "IsUncertain")
+			require.Equal(t, tt.severity == StatusSeverityBad, tt.code.IsBad(), "IsBad")
+			require.Equal(t, tt.subCode, tt.code.SubCode(), "SubCode")
+			require.Equal(t, tt.structureChanged, tt.code.StructureChanged(), "StructureChanged")
+			require.Equal(t, tt.overflow, tt.code.Overflow(), "Overflow")
+			require.Equal(t, tt.name, tt.code.Name(), "Name")
+			require.Equal(t, tt.symbol, tt.code.Symbol(), "Symbol")
+		})
+	}
+}
+
+func TestStatusCodeDescription(t *testing.T) {
+	require.Equal(t, "The node id refers to a node that does not exist in the server address space.", StatusBadNodeIDUnknown.Description())
+	require.Equal(t, "", StatusCode(0xc0010000).Description())
+
+	// the error includes the symbol and ignores the info bits
+	require.Equal(t, "The node id refers to a node that does not exist in the server address space. BadNodeIdUnknown (0x80340000)", StatusBadNodeIDUnknown.Error())
+	require.Equal(t, "The node id refers to a node that does not exist in the server address space. BadNodeIdUnknown (0x80348000)", (StatusBadNodeIDUnknown | 0x8000).Error())
+	require.Equal(t, "0xC0010000", StatusCode(0xc0010000).Error())
+}
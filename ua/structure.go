@@ -0,0 +1,402 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/id"
+)
+
+// structures contains the data types which are encoded and
+// decoded at runtime from their DataTypeDefinition.
+var structures = &dataTypeRegistry{
+	types:     make(map[string]*dataTypeInfo),
+	encodings: make(map[string]*dataTypeInfo),
+}
+
+// dataTypeInfo describes how values of a data type are encoded.
+type dataTypeInfo struct {
+	// dataTypeID is the node id of the data type.
+	dataTypeID *NodeID
+
+	// builtin is the built-in type used for encoding values of
+	// simple data types and enumerations.
+	builtin TypeID
+
+	// def is the definition of a structured data type.
+	def *StructureDefinition
+}
+
+type dataTypeRegistry struct {
+	mu        sync.RWMutex
+	types     map[string]*dataTypeInfo
+	encodings map[string]*dataTypeInfo
+}
+
+func (r *dataTypeRegistry) add(t *dataTypeInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[t.dataTypeID.String()] = t
+	if t.def != nil {
+		r.encodings[t.def.DefaultEncodingID.String()] = t
+	}
+}
+
+func (r *dataTypeRegistry) byDataType(n *NodeID) *dataTypeInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.types[n.String()]
+}
+
+func (r *dataTypeRegistry) byEncoding(n *NodeID) *dataTypeInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.encodings[n.String()]
+}
+
+// RegisterStructure registers the definition of a structured data type.
+// Extension objects with the default binary encoding of the definition
+// which have no registered Go type are decoded into a *Structure.
+//
+// Nested fields must have a built-in data type or a data type which
+// has been registered with RegisterStructure or RegisterBuiltinDataType.
+func RegisterStructure(dataTypeID *NodeID, def *StructureDefinition) error {
+	if dataTypeID == nil || def == nil {
+		return errors.Errorf("invalid structure definition")
+	}
+	if def.DefaultEncodingID == nil {
+		return errors.Errorf("structure %s has no default encoding", dataTypeID)
+	}
+	structures.add(&dataTypeInfo{dataTypeID: dataTypeID, def: def})
+	return nil
+}
+
+// RegisterBuiltinDataType registers a data type whose values are encoded
+// as a built-in type, e.g. enumerations as TypeIDInt32 or subtypes of
+// built-in types.
+func RegisterBuiltinDataType(dataTypeID *NodeID, t TypeID) {
+	structures.add(&dataTypeInfo{dataTypeID: dataTypeID, builtin: t})
+}
+
+// Structure is a value of a structured data type whose layout is only
+// known at runtime from its StructureDefinition.
+//
+// Specification: Part 6, 5.2.6 and 5.2.7
+type Structure struct {
+	// TypeID is the node id of the data type.
+	TypeID *NodeID
+
+	// Fields contains the field values by field name. Optional fields
+	// which are not set and the unselected fields of a union are not
+	// present.
+	//
+	// Scalar values have the Go type of the built-in type, a *Structure
+	// or the registered Go type of the extension object. Arrays are
+	// slices of these types.
+	Fields map[string]interface{}
+
+	def *StructureDefinition
+}
+
+// NewStructure returns a new value of a data type registered with
+// RegisterStructure.
+func NewStructure(dataTypeID *NodeID, fields map[string]interface{}) (*Structure, error) {
+	t := structures.byDataType(dataTypeID)
+	if t == nil || t.def == nil {
+		return nil, errors.Errorf("unknown structure %s", dataTypeID)
+	}
+	if fields == nil {
+		fields = make(map[string]interface{})
+	}
+	return &Structure{TypeID: t.dataTypeID, Fields: fields, def: t.def}, nil
+}
+
+// newStructureByEncoding returns an empty structure for an extension
+// object with the given encoding id or nil if it is not registered.
+func newStructureByEncoding(encodingID *NodeID) *Structure {
+	t := structures.byEncoding(encodingID)
+	if t == nil {
+		return nil
+	}
+	return &Structure{TypeID: t.dataTypeID, def: t.def}
+}
+
+// EncodingID returns the default binary encoding id of the structure.
+func (s *Structure) EncodingID() *NodeID {
+	if s.def == nil {
+		return nil
+	}
+	return s.def.DefaultEncodingID
+}
+
+func (s *Structure) Decode(b []byte) (int, error) {
+	if s.def == nil {
+		return 0, errors.Errorf("structure %s has no definition", s.TypeID)
+	}
+
+	buf := NewBuffer(b)
+	s.Fields = make(map[string]interface{})
+
+	switch s.def.StructureType {
+	case StructureTypeStructure:
+		for _, f := range s.def.Fields {
+			s.Fields[f.Name] = decodeField(buf, f)
+		}
+
+	case StructureTypeStructureWithOptionalFields:
+		mask := buf.ReadUint32()
+		bit := 0
+		for _, f := range s.def.Fields {
+			if f.IsOptional {
+				set := mask&(1<<bit) != 0
+				bit++
+				if !set {
+					continue
+				}
+			}
+			s.Fields[f.Name] = decodeField(buf, f)
+		}
+
+	case StructureTypeUnion:
+		sel := buf.ReadUint32()
+		if sel > uint32(len(s.def.Fields)) {
+			return buf.Pos(), errors.Errorf("invalid union field %d for %s", sel, s.TypeID)
+		}
+		if sel > 0 {
+			f := s.def.Fields[sel-1]
+			s.Fields[f.Name] = decodeField(buf, f)
+		}
+
+	default:
+		return buf.Pos(), errors.Errorf("unsupported structure type %s for %s", s.def.StructureType, s.TypeID)
+	}
+	return buf.Pos(), buf.Error()
+}
+
+func (s *Structure) Encode() ([]byte, error) {
+	if s == nil || s.def == nil {
+		return nil, errors.Errorf("structure has no definition")
+	}
+
+	buf := NewBuffer(nil)
+
+	switch s.def.StructureType {
+	case StructureTypeStructure:
+		for _, f := range s.def.Fields {
+			encodeField(buf, f, s.Fields[f.Name])
+		}
+
+	case StructureTypeStructureWithOptionalFields:
+		var mask uint32
+		bit := 0
+		for _, f := range s.def.Fields {
+			if !f.IsOptional {
+				continue
+			}
+			if _, ok := s.Fields[f.Name]; ok {
+				mask |= 1 << bit
+			}
+			bit++
+		}
+		buf.WriteUint32(mask)
+		for _, f := range s.def.Fields {
+			v, ok := s.Fields[f.Name]
+			if f.IsOptional && !ok {
+				continue
+			}
+			encodeField(buf, f, v)
+		}
+
+	case StructureTypeUnion:
+		var sel uint32
+		for i, f := range s.def.Fields {
+			if _, ok := s.Fields[f.Name]; ok {
+				sel = uint32(i + 1)
+				break
+			}
+		}
+		buf.WriteUint32(sel)
+		if sel > 0 {
+			f := s.def.Fields[sel-1]
+			encodeField(buf, f, s.Fields[f.Name])
+		}
+
+	default:
+		return nil, errors.Errorf("unsupported structure type %s for %s", s.def.StructureType, s.TypeID)
+	}
+	return buf.Bytes(), buf.Error()
+}
+
+// fieldCodec describes how the values of a field are encoded.
+type fieldCodec struct {
+	// builtin is the built-in type of the field or zero.
+	builtin TypeID
+
+	// structure is the data type of a nested structure which is
+	// decoded at runtime.
+	structure *dataTypeInfo
+
+	// eotype is the registered Go type of a nested structure.
+	eotype reflect.Type
+}
+
+// lookupFieldCodec determines the encoding of a field with the given data type.
+func lookupFieldCodec(dataType *NodeID) (fieldCodec, error) {
+	if dataType == nil {
+		return fieldCodec{}, errors.Errorf("field has no data type")
+	}
+
+	// IntID is zero for non-numeric node ids
+	if dataType.Namespace() == 0 {
+		switch n := dataType.IntID(); {
+		case n >= uint32(TypeIDBoolean) && n <= uint32(TypeIDDiagnosticInfo):
+			return fieldCodec{builtin: TypeID(n)}, nil
+		case n == id.Enumeration:
+			return fieldCodec{builtin: TypeIDInt32}, nil
+		case n == id.Number || n == id.Integer || n == id.UInteger:
+			return fieldCodec{builtin: TypeIDVariant}, nil
+		}
+	}
+
+	t := structures.byDataType(dataType)
+	switch {
+	case t == nil:
+		return fieldCodec{}, errors.Errorf("unknown data type %s", dataType)
+	case t.def == nil:
+		return fieldCodec{builtin: t.builtin}, nil
+	}
+
+	// prefer the registered Go type for structures which have one
+	if v := eotypes.New(t.def.DefaultEncodingID); v != nil {
+		return fieldCodec{eotype: reflect.TypeOf(v)}, nil
+	}
+	return fieldCodec{structure: t}, nil
+}
+
+// elemType returns the Go type of a single value of the field.
+func (fc fieldCodec) elemType() reflect.Type {
+	switch {
+	case fc.eotype != nil:
+		return fc.eotype
+	case fc.structure != nil:
+		return reflect.TypeOf(new(Structure))
+	default:
+		return variantTypeIDToType[fc.builtin]
+	}
+}
+
+func (fc fieldCodec) decode(buf *Buffer) interface{} {
+	switch {
+	case fc.eotype != nil:
+		v := reflect.New(fc.eotype.Elem()).Interface()
+		buf.ReadStruct(v)
+		return v
+	case fc.structure != nil:
+		v := &Structure{TypeID: fc.structure.dataTypeID, def: fc.structure.def}
+		buf.ReadStruct(v)
+		return v
+	default:
+		v := new(Variant)
+		v.setType(fc.builtin)
+		return v.decodeValue(buf, 0)
+	}
+}
+
+func (fc fieldCodec) encode(buf *Buffer, v interface{}) {
+	if t := fc.elemType(); reflect.TypeOf(v) != t {
+		buf.err = errors.Errorf("invalid value %T, want %v", v, t)
+		return
+	}
+	switch {
+	case fc.eotype != nil, fc.structure != nil:
+		buf.WriteStruct(v)
+	default:
+		new(Variant).encodeValue(buf, v)
+	}
+}
+
+func decodeField(buf *Buffer, f *StructureField) interface{} {
+	if buf.Error() != nil {
+		return nil
+	}
+	fc, err := lookupFieldCodec(f.DataType)
+	if err != nil {
+		buf.err = errors.Errorf("field %s: %s", f.Name, err)
+		return nil
+	}
+
+	switch {
+	case f.ValueRank < 0:
+		return fc.decode(buf)
+	case f.ValueRank > 1:
+		buf.err = errors.Errorf("field %s: multi-dimensional arrays are not supported", f.Name)
+		return nil
+	}
+
+	n := buf.ReadInt32()
+	if int(n) > MaxVariantArrayLength {
+		buf.err = StatusBadEncodingLimitsExceeded
+		return nil
+	}
+	sliceType := reflect.SliceOf(fc.elemType())
+	if n < 0 {
+		return reflect.Zero(sliceType).Interface()
+	}
+	if err := checkArrayLength(int(n), buf.Len(), fc.elemType()); err != nil {
+		buf.err = errors.Errorf("field %s: %w", f.Name, err)
+		return nil
+	}
+	vals := reflect.MakeSlice(sliceType, int(n), int(n))
+	for i := 0; i < int(n) && buf.Error() == nil; i++ {
+		if v := fc.decode(buf); v != nil {
+			vals.Index(i).Set(reflect.ValueOf(v))
+		}
+	}
+	return vals.Interface()
+}
+
+func encodeField(buf *Buffer, f *StructureField, v interface{}) {
+	if buf.Error() != nil {
+		return
+	}
+	fc, err := lookupFieldCodec(f.DataType)
+	if err != nil {
+		buf.err = errors.Errorf("field %s: %s", f.Name, err)
+		return
+	}
+
+	switch {
+	case f.ValueRank < 0:
+		if v == nil {
+			v = reflect.Zero(fc.elemType()).Interface()
+		}
+		fc.encode(buf, v)
+		return
+	case f.ValueRank > 1:
+		buf.err = errors.Errorf("field %s: multi-dimensional arrays are not supported", f.Name)
+		return
+	}
+
+	if v == nil {
+		buf.WriteInt32(-1)
+		return
+	}
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Slice {
+		buf.err = errors.Errorf("field %s: invalid value %T, want slice", f.Name, v)
+		return
+	}
+	if val.IsNil() {
+		buf.WriteInt32(-1)
+		return
+	}
+	buf.WriteInt32(int32(val.Len()))
+	for i := 0; i < val.Len(); i++ {
+		fc.encode(buf, val.Index(i).Interface())
+	}
+}
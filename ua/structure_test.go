@@ -0,0 +1,114 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"testing"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructure(t *testing.T) {
+	pointID := NewFourByteNodeID(2, 1000)
+	require.NoError(t, RegisterStructure(pointID, &StructureDefinition{
+		DefaultEncodingID: NewFourByteNodeID(2, 1001),
+		StructureType:     StructureTypeStructureWithOptionalFields,
+		Fields: []*StructureField{
+			{Name: "X", DataType: NewTwoByteNodeID(id.Int32), ValueRank: -1},
+			{Name: "Label", DataType: NewTwoByteNodeID(id.String), ValueRank: -1, IsOptional: true},
+			{Name: "Mode", DataType: NewFourByteNodeID(2, 1002), ValueRank: -1, IsOptional: true},
+		},
+	}))
+	RegisterBuiltinDataType(NewFourByteNodeID(2, 1002), TypeIDInt32)
+
+	lineID := NewFourByteNodeID(2, 2000)
+	require.NoError(t, RegisterStructure(lineID, &StructureDefinition{
+		DefaultEncodingID: NewFourByteNodeID(2, 2001),
+		StructureType:     StructureTypeStructure,
+		Fields: []*StructureField{
+			{Name: "Points", DataType: pointID, ValueRank: 1},
+			{Name: "Range", DataType: NewFourByteNodeID(0, id.Range), ValueRank: -1},
+		},
+	}))
+	// Range has a Go type which is used instead of the definition
+	require.NoError(t, RegisterStructure(NewFourByteNodeID(0, id.Range), &StructureDefinition{
+		DefaultEncodingID: NewFourByteNodeID(0, id.Range_Encoding_DefaultBinary),
+	}))
+
+	point := func(fields map[string]interface{}) *Structure {
+		s, err := NewStructure(pointID, fields)
+		require.NoError(t, err)
+		return s
+	}
+	line, err := NewStructure(lineID, map[string]interface{}{
+		"Points": []*Structure{
+			point(map[string]interface{}{"X": int32(1)}),
+			point(map[string]interface{}{"X": int32(2), "Mode": int32(3)}),
+		},
+		"Range": &Range{Low: 1, High: 2},
+	})
+	require.NoError(t, err)
+
+	cases := []CodecTestCase{
+		{
+			Name:   "nested",
+			Struct: NewExtensionObject(line),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x02, 0xd1, 0x07,
+				// EncodingMask
+				0x01,
+				// Length
+				0x28, 0x00, 0x00, 0x00,
+				// Points
+				0x02, 0x00, 0x00, 0x00,
+				// Points[0]: mask, X
+				0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+				// Points[1]: mask, X, Mode
+				0x02, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00,
+				// Range
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+			},
+		},
+	}
+	RunCodecTest(t, cases)
+}
+
+func TestStructureUnion(t *testing.T) {
+	unionID := NewFourByteNodeID(2, 3000)
+	require.NoError(t, RegisterStructure(unionID, &StructureDefinition{
+		DefaultEncodingID: NewFourByteNodeID(2, 3001),
+		StructureType:     StructureTypeUnion,
+		Fields: []*StructureField{
+			{Name: "Number", DataType: NewTwoByteNodeID(id.Double), ValueRank: -1},
+			{Name: "Text", DataType: NewTwoByteNodeID(id.String), ValueRank: -1},
+		},
+	}))
+
+	s, err := NewStructure(unionID, map[string]interface{}{"Text": "a"})
+	require.NoError(t, err)
+
+	cases := []CodecTestCase{
+		{
+			Name:   "union",
+			Struct: NewExtensionObject(s),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x02, 0xb9, 0x0b,
+				// EncodingMask
+				0x01,
+				// Length
+				0x09, 0x00, 0x00, 0x00,
+				// SwitchField
+				0x02, 0x00, 0x00, 0x00,
+				// Text
+				0x01, 0x00, 0x00, 0x00, 0x61,
+			},
+		},
+	}
+	RunCodecTest(t, cases)
+}
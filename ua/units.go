@@ -0,0 +1,152 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+// UNECEUnitsURI is the namespace uri of EUInformation values whose unit
+// id is derived from a UNECE common code.
+//
+// Specification: Part 8, 5.6.3
+const UNECEUnitsURI = "http://www.opcfoundation.org/UA/units/un/cefact"
+
+type uneceUnit struct {
+	symbol string
+	name   string
+}
+
+// uneceUnits contains the frequently used units of UNECE Recommendation
+// No. 20 by their common code.
+var uneceUnits = map[string]uneceUnit{
+	// length and area
+	"MTR": {"m", "metre"},
+	"MMT": {"mm", "millimetre"},
+	"CMT": {"cm", "centimetre"},
+	"KMT": {"km", "kilometre"},
+	"MTK": {"m²", "square metre"},
+
+	// volume and flow
+	"LTR": {"l", "litre"},
+	"MTQ": {"m³", "cubic metre"},
+	"L2":  {"l/min", "litre per minute"},
+	"MQH": {"m³/h", "cubic metre per hour"},
+	"MQS": {"m³/s", "cubic metre per second"},
+
+	// mass and mass flow
+	"GRM": {"g", "gram"},
+	"KGM": {"kg", "kilogram"},
+	"TNE": {"t", "tonne"},
+	"KGS": {"kg/s", "kilogram per second"},
+
+	// time and frequency
+	"C26": {"ms", "millisecond"},
+	"SEC": {"s", "second"},
+	"MIN": {"min", "minute"},
+	"HUR": {"h", "hour"},
+	"HTZ": {"Hz", "hertz"},
+
+	// velocity
+	"MTS": {"m/s", "metre per second"},
+	"KMH": {"km/h", "kilometre per hour"},
+
+	// temperature
+	"CEL": {"°C", "degree Celsius"},
+	"FAH": {"°F", "degree Fahrenheit"},
+	"KEL": {"K", "kelvin"},
+
+	// pressure
+	"PAL": {"Pa", "pascal"},
+	"KPA": {"kPa", "kilopascal"},
+	"MBR": {"mbar", "millibar"},
+	"BAR": {"bar", "bar"},
+	"PS":  {"psi", "pound-force per square inch"},
+
+	// force, energy and power
+	"NEW": {"N", "newton"},
+	"NU":  {"N·m", "newton metre"},
+	"JOU": {"J", "joule"},
+	"KWH": {"kW·h", "kilowatt hour"},
+	"WTT": {"W", "watt"},
+	"KWT": {"kW", "kilowatt"},
+	"MAW": {"MW", "megawatt"},
+
+	// electricity
+	"AMP": {"A", "ampere"},
+	"4K":  {"mA", "milliampere"},
+	"VLT": {"V", "volt"},
+	"2Z":  {"mV", "millivolt"},
+	"OHM": {"Ω", "ohm"},
+
+	// miscellaneous
+	"P1":  {"%", "percent"},
+	"DD":  {"°", "degree"},
+	"C81": {"rad", "radian"},
+	"LUX": {"lx", "lux"},
+}
+
+// UNECEUnitID returns the unit id of a UNECE common code, e.g. 4408652
+// for "CEL" (degree Celsius). Each character of the code is stored in one
+// byte of the unit id. Codes with more than three characters are not
+// valid and return -1.
+func UNECEUnitID(code string) int32 {
+	if code == "" || len(code) > 3 {
+		return -1
+	}
+	var id int32
+	for i := 0; i < len(code); i++ {
+		id = id<<8 | int32(code[i])
+	}
+	return id
+}
+
+// UNECECode returns the UNECE common code of a unit id. It is the
+// inverse of UNECEUnitID.
+func UNECECode(unitID int32) string {
+	if unitID <= 0 || unitID > 0xffffff {
+		return ""
+	}
+	var b []byte
+	for ; unitID > 0; unitID >>= 8 {
+		b = append([]byte{byte(unitID & 0xff)}, b...)
+	}
+	return string(b)
+}
+
+// UnitSymbol returns the symbol of the UNECE unit with the given unit id,
+// e.g. "°C" for degree Celsius, and false if the unit is not known.
+func UnitSymbol(unitID int32) (string, bool) {
+	u, ok := uneceUnits[UNECECode(unitID)]
+	return u.symbol, ok
+}
+
+// NewEUInformation returns the engineering units of the UNECE unit with
+// the given common code, e.g. "CEL" for degree Celsius. The display name
+// is the symbol and the description the name of the unit. Unknown codes
+// use the code as display name and have no description.
+func NewEUInformation(code string) *EUInformation {
+	u, ok := uneceUnits[code]
+	if !ok {
+		u = uneceUnit{symbol: code}
+	}
+	return &EUInformation{
+		NamespaceURI: UNECEUnitsURI,
+		UnitID:       UNECEUnitID(code),
+		DisplayName:  NewLocalizedText(u.symbol),
+		Description:  NewLocalizedText(u.name),
+	}
+}
+
+// Symbol returns the symbol of the engineering units. The symbol of a
+// known UNECE unit is taken from the unit table, otherwise the display
+// name is returned.
+func (e *EUInformation) Symbol() string {
+	if e.NamespaceURI == UNECEUnitsURI {
+		if s, ok := UnitSymbol(e.UnitID); ok {
+			return s
+		}
+	}
+	if e.DisplayName == nil {
+		return ""
+	}
+	return e.DisplayName.Text
+}
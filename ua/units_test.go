@@ -0,0 +1,56 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ua
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUNECEUnitID(t *testing.T) {
+	tests := []struct {
+		code string
+		id   int32
+	}{
+		{"CEL", 4408652},
+		{"4K", 13387},
+		{"P1", 20529},
+		{"", -1},
+		{"ABCD", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			require.Equal(t, tt.id, UNECEUnitID(tt.code))
+			if tt.id > 0 {
+				require.Equal(t, tt.code, UNECECode(tt.id))
+			}
+		})
+	}
+	require.Equal(t, "", UNECECode(-1))
+	require.Equal(t, "", UNECECode(0x01000000))
+}
+
+func TestUnitSymbol(t *testing.T) {
+	s, ok := UnitSymbol(UNECEUnitID("CEL"))
+	require.True(t, ok)
+	require.Equal(t, "°C", s)
+
+	_, ok = UnitSymbol(UNECEUnitID("XXX"))
+	require.False(t, ok)
+
+	eu := NewEUInformation("KPA")
+	require.Equal(t, &EUInformation{
+		NamespaceURI: UNECEUnitsURI,
+		UnitID:       UNECEUnitID("KPA"),
+		DisplayName:  NewLocalizedText("kPa"),
+		Description:  NewLocalizedText("kilopascal"),
+	}, eu)
+	require.Equal(t, "kPa", eu.Symbol())
+
+	// other namespaces use the display name
+	require.Equal(t, "rpm", (&EUInformation{NamespaceURI: "urn:units", UnitID: UNECEUnitID("CEL"), DisplayName: NewLocalizedText("rpm")}).Symbol())
+	require.Equal(t, "", (&EUInformation{}).Symbol())
+}
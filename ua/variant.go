@@ -5,8 +5,10 @@
 package ua
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
+	"slices"
 	"time"
 
 	"github.com/gopcua/opcua/errors"
@@ -61,8 +63,11 @@ type Variant struct {
 	value interface{}
 }
 
+// NewVariant creates a variant for a value of a built-in type or a slice
+// of one. 16 byte arrays like uuid.UUID are stored as GUID.
 func NewVariant(v interface{}) (*Variant, error) {
 	va := &Variant{}
+	v = guidValue(v)
 	if !isBuiltinType(v) {
 		return nil, fmt.Errorf("trying to create a variant from a type that it is not supported: %s", reflect.ValueOf(v).Type().Name())
 	}
@@ -72,6 +77,41 @@ func NewVariant(v interface{}) (*Variant, error) {
 	return va, nil
 }
 
+// guidValue converts 16 byte arrays like uuid.UUID and slices of them
+// to GUID values. Other values are returned as is.
+func guidValue(v interface{}) interface{} {
+	val := reflect.ValueOf(v)
+	if !val.IsValid() {
+		return v
+	}
+	switch {
+	case isUUIDType(val.Type()):
+		return uuidToGUID(val)
+	case val.Kind() == reflect.Slice && isUUIDType(val.Type().Elem()):
+		if val.IsNil() {
+			return []*GUID(nil)
+		}
+		guids := make([]*GUID, val.Len())
+		for i := range guids {
+			guids[i] = uuidToGUID(val.Index(i))
+		}
+		return guids
+	default:
+		return v
+	}
+}
+
+// isUUIDType returns true for 16 byte arrays.
+func isUUIDType(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8
+}
+
+func uuidToGUID(val reflect.Value) *GUID {
+	var b [16]byte
+	reflect.Copy(reflect.ValueOf(b[:]), val)
+	return NewGUIDFromBytes(b[:])
+}
+
 func MustVariant(v interface{}) *Variant {
 	va, err := NewVariant(v)
 	if err != nil {
@@ -80,6 +120,109 @@ func MustVariant(v interface{}) *Variant {
 	return va
 }
 
+// NewMatrixVariant creates a variant for a multi-dimensional array with
+// the given dimensions. The value is either a flat slice which contains
+// the elements in row-major order or a nested slice like [][]float64.
+// The number of elements must be equal to the product of the dimensions
+// and a nested slice must have the given dimensions.
+func NewMatrixVariant(v interface{}, dimensions []uint32) (*Variant, error) {
+	if len(dimensions) == 0 {
+		return nil, errors.Errorf("matrix without dimensions")
+	}
+	dims := make([]int, len(dimensions))
+	want := 1
+	for i, d := range dimensions {
+		if d == 0 {
+			return nil, errors.Errorf("array dimension %d is zero", i)
+		}
+		if d > uint32(MaxVariantArrayLength) {
+			return nil, errors.Errorf("array dimension %d too large: %d", i, d)
+		}
+		dims[i] = int(d)
+		want *= int(d)
+	}
+	if want > MaxVariantArrayLength {
+		return nil, errors.Errorf("array too large: %d elements", want)
+	}
+
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Slice {
+		return nil, errors.Errorf("matrix value must be a slice, got %T", v)
+	}
+	et, dim, count, err := sliceDim(val)
+	if err != nil {
+		return nil, err
+	}
+	if len(dim) == 0 {
+		return nil, errors.Errorf("matrix value must be an array, got %T", v)
+	}
+	if int(count) != want {
+		return nil, errors.Errorf("matrix has %d elements but dimensions %v require %d", count, dimensions, want)
+	}
+	if len(dim) > 1 && !slices.Equal(dim, toInt32s(dims)) {
+		return nil, errors.Errorf("matrix has dimensions %v, want %v", dim, dimensions)
+	}
+
+	flat := flattenSlice(val, et)
+	if len(dims) == 1 {
+		return NewVariant(flat.Interface())
+	}
+	return NewVariant(split(0, 0, flat.Len(), dims, flat).Interface())
+}
+
+func toInt32s(a []int) []int32 {
+	b := make([]int32, len(a))
+	for i, v := range a {
+		b[i] = int32(v)
+	}
+	return b
+}
+
+// flattenSlice returns a one-dimensional slice of type []et with the
+// elements of the one or multi-dimensional slice val in row-major order.
+func flattenSlice(val reflect.Value, et reflect.Type) reflect.Value {
+	if val.Type().Elem() == et {
+		return val
+	}
+	flat := reflect.MakeSlice(reflect.SliceOf(et), 0, 0)
+	for i := 0; i < val.Len(); i++ {
+		flat = reflect.AppendSlice(flat, flattenSlice(val.Index(i), et))
+	}
+	return flat
+}
+
+// Matrix returns the value of an array as a nested slice with the array
+// dimensions of the variant, e.g. a [][]float64 for a two-dimensional
+// array of doubles. One-dimensional arrays are returned unchanged.
+func (m *Variant) Matrix() (interface{}, error) {
+	if !m.Has(VariantArrayValues) {
+		return nil, errors.Errorf("variant value is not an array")
+	}
+	if len(m.arrayDimensions) < 2 {
+		return m.value, nil
+	}
+
+	val := reflect.ValueOf(m.value)
+	et, dim, count, err := sliceDim(val)
+	if err != nil {
+		return nil, err
+	}
+	dims := make([]int, len(m.arrayDimensions))
+	want := 1
+	for i, d := range m.arrayDimensions {
+		dims[i] = int(d)
+		want *= int(d)
+	}
+	if int(count) != want {
+		return nil, errUnbalancedSlice
+	}
+	if slices.Equal(dim, m.arrayDimensions) {
+		return m.value, nil
+	}
+	flat := flattenSlice(val, et)
+	return split(0, 0, flat.Len(), dims, flat).Interface(), nil
+}
+
 func (m *Variant) EncodingMask() byte {
 	return m.mask
 }
@@ -116,6 +259,14 @@ func (m *Variant) Value() interface{} {
 
 // Decode implements the codec interface.
 func (m *Variant) Decode(b []byte) (int, error) {
+	return m.decode(b, 0)
+}
+
+// decode decodes a variant which is nested in depth other values.
+func (m *Variant) decode(b []byte, depth int) (int, error) {
+	if err := checkNestingDepth(depth); err != nil {
+		return 0, err
+	}
 	buf := NewBuffer(b)
 	m.mask = buf.ReadByte()
 
@@ -132,7 +283,7 @@ func (m *Variant) Decode(b []byte) (int, error) {
 
 	// read single value and return
 	if !m.Has(VariantArrayValues) {
-		m.value = m.decodeValue(buf)
+		m.value = m.decodeValue(buf, depth)
 		return buf.Pos(), buf.Error()
 	}
 
@@ -144,6 +295,12 @@ func (m *Variant) Decode(b []byte) (int, error) {
 	if n > MaxVariantArrayLength {
 		return buf.Pos(), StatusBadEncodingLimitsExceeded
 	}
+	if n < -1 {
+		return buf.Pos(), errors.Errorf("invalid array length %d: %w", n, StatusBadDecodingError)
+	}
+	if err := checkArrayLength(n, buf.Len(), typ); err != nil {
+		return buf.Pos(), err
+	}
 
 	// get the type for the slice
 	sliceType := reflect.SliceOf(typ)
@@ -161,8 +318,8 @@ func (m *Variant) Decode(b []byte) (int, error) {
 	// decode a slice with values
 	default:
 		vals = reflect.MakeSlice(sliceType, n, n)
-		for i := 0; i < n; i++ {
-			vals.Index(i).Set(reflect.ValueOf(m.decodeValue(buf)))
+		for i := 0; i < n && buf.Error() == nil; i++ {
+			vals.Index(i).Set(reflect.ValueOf(m.decodeValue(buf, depth)))
 		}
 	}
 
@@ -172,6 +329,9 @@ func (m *Variant) Decode(b []byte) (int, error) {
 		if m.arrayDimensionsLength < 0 {
 			return buf.Pos(), StatusBadEncodingLimitsExceeded
 		}
+		if int(m.arrayDimensionsLength) > buf.Len()/4 {
+			return buf.Pos(), errors.Errorf("array dimensions length %d exceeds the remaining %d bytes: %w", m.arrayDimensionsLength, buf.Len(), StatusBadDecodingError)
+		}
 		m.arrayDimensions = make([]int32, m.arrayDimensionsLength)
 		for i := 0; i < int(m.arrayDimensionsLength); i++ {
 			m.arrayDimensions[i] = buf.ReadInt32()
@@ -191,11 +351,16 @@ func (m *Variant) Decode(b []byte) (int, error) {
 	// validate that the total number of elements
 	// matches the product of the array dimensions
 	if m.arrayDimensionsLength > 0 {
-		count := int32(1)
+		// use int64 and stop early since the product
+		// of the dimensions can overflow an int32.
+		count := int64(1)
 		for i := range m.arrayDimensions {
-			count *= m.arrayDimensions[i]
+			count *= int64(m.arrayDimensions[i])
+			if count > int64(m.arrayLength) {
+				return buf.Pos(), errUnbalancedSlice
+			}
 		}
-		if count != m.arrayLength {
+		if count != int64(m.arrayLength) {
 			return buf.Pos(), errUnbalancedSlice
 		}
 	}
@@ -250,7 +415,8 @@ func split(level, i, j int, dims []int, vals reflect.Value) reflect.Value {
 }
 
 // decodeValue reads a single value of the base type from the buffer.
-func (m *Variant) decodeValue(buf *Buffer) interface{} {
+// depth is the nesting depth of the variant.
+func (m *Variant) decodeValue(buf *Buffer, depth int) interface{} {
 	switch m.Type() {
 	case TypeIDBoolean:
 		return buf.ReadBool()
@@ -310,17 +476,15 @@ func (m *Variant) decodeValue(buf *Buffer) interface{} {
 		return v
 	case TypeIDDataValue:
 		v := new(DataValue)
-		buf.ReadStruct(v)
+		buf.readFunc(func(b []byte) (int, error) { return v.decode(b, depth+1) })
 		return v
 	case TypeIDVariant:
-		// todo(fs): limit recursion depth to 100
 		v := new(Variant)
-		buf.ReadStruct(v)
+		buf.readFunc(func(b []byte) (int, error) { return v.decode(b, depth+1) })
 		return v
 	case TypeIDDiagnosticInfo:
-		// todo(fs): limit recursion depth to 100
 		v := new(DiagnosticInfo)
-		buf.ReadStruct(v)
+		buf.readFunc(func(b []byte) (int, error) { return v.decode(b, depth+1) })
 		return v
 	default:
 		return nil
@@ -509,6 +673,30 @@ func (m *Variant) set(v interface{}) error {
 	return nil
 }
 
+// Equal returns true if both variants have the same type, array
+// dimensions and value. The values are compared by their binary
+// encoding so that arrays, nested variants and extension objects are
+// compared deeply and floating point values with the same bits, e.g.
+// NaN, are equal. Values which cannot be encoded are compared with
+// reflect.DeepEqual. Two nil variants are equal.
+func (m *Variant) Equal(o *Variant) bool {
+	if m == nil || o == nil {
+		return m == o
+	}
+	if m.mask != o.mask || m.arrayLength != o.arrayLength || !slices.Equal(m.arrayDimensions, o.arrayDimensions) {
+		return false
+	}
+	a, err := m.Encode()
+	if err != nil {
+		return reflect.DeepEqual(m.value, o.value)
+	}
+	b, err := o.Encode()
+	if err != nil {
+		return reflect.DeepEqual(m.value, o.value)
+	}
+	return bytes.Equal(a, b)
+}
+
 // todo(fs): this should probably be StringValue or we need to handle all types
 // todo(fs): and recursion
 func (m *Variant) String() string {
@@ -6,10 +6,12 @@ package ua
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -167,6 +169,40 @@ func TestVariant(t *testing.T) {
 				0x8d, 0x28, 0xb4, 0x04, 0xdc, 0x7d, 0xaf, 0x63,
 			},
 		},
+		{
+			Name:   "GUID from uuid.UUID",
+			Struct: MustVariant(uuid.MustParse("72962B91-FA75-4AE6-8D28-B404DC7DAF63")),
+			Bytes: []byte{
+				// variant encoding mask
+				0x0e,
+				// data1 (inverse order)
+				0x91, 0x2b, 0x96, 0x72,
+				// data2 (inverse order)
+				0x75, 0xfa,
+				// data3 (inverse order)
+				0xe6, 0x4a,
+				// data4 (same order)
+				0x8d, 0x28, 0xb4, 0x04, 0xdc, 0x7d, 0xaf, 0x63,
+			},
+		},
+		{
+			Name:   "[]GUID from []uuid.UUID",
+			Struct: MustVariant([]uuid.UUID{uuid.MustParse("72962B91-FA75-4AE6-8D28-B404DC7DAF63")}),
+			Bytes: []byte{
+				// variant encoding mask
+				0x8e,
+				// array length
+				0x01, 0x00, 0x00, 0x00,
+				// data1 (inverse order)
+				0x91, 0x2b, 0x96, 0x72,
+				// data2 (inverse order)
+				0x75, 0xfa,
+				// data3 (inverse order)
+				0xe6, 0x4a,
+				// data4 (same order)
+				0x8d, 0x28, 0xb4, 0x04, 0xdc, 0x7d, 0xaf, 0x63,
+			},
+		},
 		{
 			Name:   "ByteString",
 			Struct: MustVariant([]byte{0x01, 0x02, 0x03}),
@@ -788,6 +824,172 @@ func TestSliceDim(t *testing.T) {
 	}
 }
 
+func TestNewMatrixVariant(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		dims []uint32
+		want interface{}
+		err  bool
+	}{
+		{
+			name: "flat",
+			v:    []float64{1, 2, 3, 4, 5, 6},
+			dims: []uint32{2, 3},
+			want: [][]float64{{1, 2, 3}, {4, 5, 6}},
+		},
+		{
+			name: "nested",
+			v:    [][]float64{{1, 2, 3}, {4, 5, 6}},
+			dims: []uint32{2, 3},
+			want: [][]float64{{1, 2, 3}, {4, 5, 6}},
+		},
+		{
+			name: "three dimensions",
+			v:    []int32{1, 2, 3, 4, 5, 6, 7, 8},
+			dims: []uint32{2, 2, 2},
+			want: [][][]int32{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}},
+		},
+		{
+			name: "one dimension",
+			v:    []int32{1, 2, 3},
+			dims: []uint32{3},
+			want: []int32{1, 2, 3},
+		},
+		{
+			name: "empty dimension",
+			v:    []int32{},
+			dims: []uint32{2, 0},
+			err:  true,
+		},
+		{
+			name: "wrong count",
+			v:    []float64{1, 2, 3, 4, 5},
+			dims: []uint32{2, 3},
+			err:  true,
+		},
+		{
+			name: "wrong dimensions",
+			v:    [][]float64{{1, 2}, {3, 4}, {5, 6}},
+			dims: []uint32{2, 3},
+			err:  true,
+		},
+		{
+			name: "no dimensions",
+			v:    []float64{1},
+			err:  true,
+		},
+		{
+			name: "scalar",
+			v:    1.0,
+			dims: []uint32{1},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewMatrixVariant(tt.v, tt.dims)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, v.Value())
+			if len(tt.dims) > 1 {
+				require.Len(t, v.ArrayDimensions(), len(tt.dims))
+				for i, d := range tt.dims {
+					require.Equal(t, int32(d), v.ArrayDimensions()[i])
+				}
+			}
+
+			// round trip through the binary encoding
+			b, err := v.Encode()
+			require.NoError(t, err)
+			got := new(Variant)
+			_, err = got.Decode(b)
+			require.NoError(t, err)
+			m, err := got.Matrix()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, m)
+		})
+	}
+}
+
+func TestVariantMatrix(t *testing.T) {
+	// a flat value with dimensions is reshaped
+	v := &Variant{
+		mask:                  byte(TypeIDInt32) | VariantArrayValues | VariantArrayDimensions,
+		arrayLength:           4,
+		arrayDimensionsLength: 2,
+		arrayDimensions:       []int32{2, 2},
+		value:                 []int32{1, 2, 3, 4},
+	}
+	m, err := v.Matrix()
+	require.NoError(t, err)
+	require.Equal(t, [][]int32{{1, 2}, {3, 4}}, m)
+
+	_, err = MustVariant(int32(1)).Matrix()
+	require.Error(t, err)
+}
+
+func TestVariantEqual(t *testing.T) {
+	matrix := func(v interface{}) *Variant {
+		m, err := NewMatrixVariant(v, []uint32{2, 2})
+		require.NoError(t, err)
+		return m
+	}
+	tests := []struct {
+		name string
+		a, b *Variant
+		eq   bool
+	}{
+		{"nil", nil, nil, true},
+		{"nil and value", nil, MustVariant(int32(1)), false},
+		{"null", &Variant{}, &Variant{}, true},
+		{"same value", MustVariant(int32(1)), MustVariant(int32(1)), true},
+		{"different value", MustVariant(int32(1)), MustVariant(int32(2)), false},
+		{"different type", MustVariant(int32(1)), MustVariant(int64(1)), false},
+		{"nan", MustVariant(math.NaN()), MustVariant(math.NaN()), true},
+		{"array", MustVariant([]string{"a", "b"}), MustVariant([]string{"a", "b"}), true},
+		{"different array", MustVariant([]string{"a", "b"}), MustVariant([]string{"a", "c"}), false},
+		{"array and scalar", MustVariant([]int32{1}), MustVariant(int32(1)), false},
+		{"matrix", matrix([]int32{1, 2, 3, 4}), matrix([]int32{1, 2, 3, 4}), true},
+		{"matrix and array", matrix([]int32{1, 2, 3, 4}), MustVariant([]int32{1, 2, 3, 4}), false},
+		{"node id", MustVariant(NewStringNodeID(1, "a")), MustVariant(NewStringNodeID(1, "a")), true},
+		{
+			"nested variant",
+			MustVariant([]*Variant{MustVariant(int32(1)), MustVariant("a")}),
+			MustVariant([]*Variant{MustVariant(int32(1)), MustVariant("a")}),
+			true,
+		},
+		{
+			"different nested variant",
+			MustVariant([]*Variant{MustVariant(int32(1))}),
+			MustVariant([]*Variant{MustVariant(int64(1))}),
+			false,
+		},
+		{
+			"extension object",
+			MustVariant(NewExtensionObject(&AnonymousIdentityToken{PolicyID: "a"})),
+			MustVariant(NewExtensionObject(&AnonymousIdentityToken{PolicyID: "a"})),
+			true,
+		},
+		{
+			"different extension object",
+			MustVariant(NewExtensionObject(&AnonymousIdentityToken{PolicyID: "a"})),
+			MustVariant(NewExtensionObject(&AnonymousIdentityToken{PolicyID: "b"})),
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.eq, tt.a.Equal(tt.b))
+			require.Equal(t, tt.eq, tt.b.Equal(tt.a))
+		})
+	}
+}
+
 func TestVariantUnsupportedType(t *testing.T) {
 	tests := []interface{}{int(5), uint(5)}
 	for _, v := range tests {
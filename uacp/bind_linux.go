@@ -0,0 +1,40 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux
+
+package uacp
+
+import (
+	"syscall"
+
+	"github.com/gopcua/opcua/debug"
+	"github.com/gopcua/opcua/errors"
+)
+
+// bindToDevice returns a net.Dialer Control function which binds the
+// socket to the network interface with SO_BINDTODEVICE after calling
+// control. Binding requires CAP_NET_RAW and is skipped without it.
+func bindToDevice(name string, control func(network, address string, c syscall.RawConn) error) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		if control != nil {
+			if err := control(network, address, c); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		cerr := c.Control(func(fd uintptr) {
+			err = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, name)
+		})
+		if cerr != nil {
+			return cerr
+		}
+		if errors.Is(err, syscall.EPERM) {
+			debug.Printf("uacp: not permitted to bind socket to interface %s", name)
+			return nil
+		}
+		return err
+	}
+}
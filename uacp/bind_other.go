@@ -0,0 +1,15 @@
+// Copyright 2018-2020 opcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build !linux
+
+package uacp
+
+import "syscall"
+
+// bindToDevice returns control since binding a socket to a network
+// interface is only supported on Linux.
+func bindToDevice(name string, control func(network, address string, c syscall.RawConn) error) func(network, address string, c syscall.RawConn) error {
+	return control
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/url"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -56,36 +57,69 @@ func nextid() uint32 {
 	return atomic.AddUint32(&connid, 1)
 }
 
+// ContextDialer establishes network connections. It is implemented by
+// net.Dialer and can be used to connect through a proxy or a tunnel.
+type ContextDialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
 // Dialer establishes a connection to an endpoint.
 type Dialer struct {
 	// Dialer establishes the TCP connection. Defaults to net.Dialer.
 	Dialer *net.Dialer
 
+	// ContextDialer establishes the connection instead of Dialer if it
+	// is set. Dialer and LocalInterface are ignored in that case.
+	ContextDialer ContextDialer
+
 	// ClientACK defines the connection parameters requested by the client.
 	// Defaults to DefaultClientACK.
 	ClientACK *Acknowledge
+
+	// LocalInterface is the name of the network interface, e.g. "eth1",
+	// which is used for the connection. The local address is resolved
+	// from the addresses of the interface every time a connection is
+	// established and replaces Dialer.LocalAddr. On Linux the socket is
+	// also bound to the interface if the process has the privileges.
+	LocalInterface string
+
+	// DialEndpoint is an endpoint url whose host and port are used for
+	// the network connection instead of the ones of the endpoint url
+	// passed to Dial, e.g. for servers behind a load balancer or NAT
+	// which advertise an address that is not reachable by the client.
+	// The endpoint url passed to Dial is still sent in the Hello message.
+	DialEndpoint string
 }
 
 func (d *Dialer) Dial(ctx context.Context, endpoint string) (*Conn, error) {
 	debug.Printf("uacp: connecting to %s", endpoint)
 
-	_, raddr, err := ResolveEndpoint(ctx, endpoint)
-	if err != nil {
-		return nil, err
+	addr := endpoint
+	if d.DialEndpoint != "" {
+		addr = d.DialEndpoint
 	}
 
-	dl := d.Dialer
-	if dl == nil {
-		dl = &net.Dialer{}
-
+	// a custom dialer gets the unresolved address since it may
+	// connect through a proxy which resolves the address remotely.
+	var (
+		raddr *url.URL
+		err   error
+	)
+	if d.ContextDialer != nil {
+		raddr, err = parseEndpoint(addr)
+	} else {
+		_, raddr, err = ResolveEndpoint(ctx, addr)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	c, err := dl.DialContext(ctx, "tcp", raddr.Host)
+	c, err := d.DialContext(ctx, "tcp", raddr.Host)
 	if err != nil {
 		return nil, err
 	}
 
-	conn, err := NewConn(c.(*net.TCPConn), d.ClientACK)
+	conn, err := NewConnFromNetConn(c, d.ClientACK)
 	if err != nil {
 		c.Close()
 		return nil, err
@@ -100,6 +134,118 @@ func (d *Dialer) Dial(ctx context.Context, endpoint string) (*Conn, error) {
 	return conn, nil
 }
 
+// DialContext establishes the network connection to the address without
+// the UACP handshake. It uses the ContextDialer if it is set and the
+// Dialer otherwise.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if d.ContextDialer != nil {
+		return d.ContextDialer.DialContext(ctx, network, address)
+	}
+
+	dl := d.Dialer
+	if dl == nil {
+		dl = &net.Dialer{}
+	}
+
+	if d.LocalInterface != "" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		laddr, err := interfaceAddr(d.LocalInterface, net.ParseIP(host))
+		if err != nil {
+			return nil, err
+		}
+		ifdl := *dl
+		ifdl.LocalAddr = laddr
+		ifdl.Control = bindToDevice(d.LocalInterface, dl.Control)
+		dl = &ifdl
+	}
+
+	return dl.DialContext(ctx, network, address)
+}
+
+// ReceiveReverseHello reads the ReverseHello message which a server sends
+// after it has established a connection to a client for the reverse
+// connect handshake. The client then continues with the Hello message on
+// the same connection, e.g. with a Dialer which returns c.
+func ReceiveReverseHello(ctx context.Context, c net.Conn) (*ReverseHello, error) {
+	conn := newConn(c, DefaultClientACK)
+
+	// set a deadline if there is one
+	if dl, ok := ctx.Deadline(); ok {
+		c.SetDeadline(dl)
+		defer c.SetDeadline(time.Time{})
+	}
+
+	b, err := conn.Receive()
+	if err != nil {
+		return nil, err
+	}
+
+	msgtyp := string(b[:4])
+	switch msgtyp {
+	case "RHEF":
+		rhe := new(ReverseHello)
+		if _, err := rhe.Decode(b[hdrlen:]); err != nil {
+			return nil, errors.Errorf("uacp: decode RHE failed: %s", err)
+		}
+		debug.Printf("uacp %d: recv %#v", conn.id, rhe)
+		return rhe, nil
+
+	case "ERRF":
+		errf := new(Error)
+		if _, err := errf.Decode(b[hdrlen:]); err != nil {
+			return nil, errors.Errorf("uacp: decode ERR failed: %s", err)
+		}
+		return nil, errf
+
+	default:
+		conn.SendError(ua.StatusBadTCPMessageTypeInvalid)
+		return nil, errors.Errorf("uacp: invalid reverse hello packet %q", msgtyp)
+	}
+}
+
+// interfaceAddr returns a local address of the named network interface
+// with the same IP version as the remote address. An IPv4 address is
+// returned if the remote address is not an IP address.
+func interfaceAddr(name string, remote net.IP) (*net.TCPAddr, error) {
+	ifi, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, errors.Errorf("local interface %s: %w", name, err)
+	}
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return nil, errors.Errorf("local interface %s: %w", name, err)
+	}
+
+	wantV4 := remote == nil || remote.To4() != nil
+	var found net.IP
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok || (ipnet.IP.To4() != nil) != wantV4 {
+			continue
+		}
+		// prefer global addresses over link-local addresses
+		if found == nil || found.IsLinkLocalUnicast() && !ipnet.IP.IsLinkLocalUnicast() {
+			found = ipnet.IP
+		}
+	}
+	if found == nil {
+		version := "IPv6"
+		if wantV4 {
+			version = "IPv4"
+		}
+		return nil, errors.Errorf("local interface %s has no %s address", name, version)
+	}
+
+	laddr := &net.TCPAddr{IP: found}
+	if found.IsLinkLocalUnicast() {
+		laddr.Zone = name
+	}
+	return laddr, nil
+}
+
 // Dial uses the default dialer to establish a connection to the endpoint
 func Dial(ctx context.Context, endpoint string) (*Conn, error) {
 	d := &Dialer{}
@@ -150,7 +296,7 @@ func (l *Listener) Accept(ctx context.Context) (*Conn, error) {
 	if err != nil {
 		return nil, err
 	}
-	conn := &Conn{TCPConn: c, id: nextid(), ack: l.ack}
+	conn := newConn(c, l.ack)
 	if err := conn.srvhandshake(l.endpoint); err != nil {
 		c.Close()
 		return nil, err
@@ -174,43 +320,142 @@ func (l *Listener) Endpoint() string {
 }
 
 type Conn struct {
+	// TCPConn is the TCP connection. It is nil for other connections
+	// which have been created with NewConnFromNetConn and the TCP specific
+	// methods return an error for them.
 	*net.TCPConn
+
+	// nc is the network connection which is used for reading and
+	// writing. It is the same as TCPConn for TCP connections.
+	nc net.Conn
+
 	id  uint32
 	ack *Acknowledge
 
+	// lim contains the receive limits which have been announced to the
+	// peer in the Hello message of a client or the Acknowledge message
+	// of a server. For server connections lim and ack are the same.
+	lim *Acknowledge
+
+	// peer contains the receive limits which the peer announced in its
+	// Hello or Acknowledge message. They apply to the messages sent to
+	// the peer and are nil until the handshake has completed.
+	peer *Acknowledge
+
 	closeOnce sync.Once
 }
 
 func NewConn(c *net.TCPConn, ack *Acknowledge) (*Conn, error) {
+	if c == nil {
+		return nil, fmt.Errorf("no connection")
+	}
+	return NewConnFromNetConn(c, ack)
+}
+
+// NewConnFromNetConn returns a UACP connection for any stream oriented
+// network connection, e.g. of a tunnel or a proxy.
+func NewConnFromNetConn(c net.Conn, ack *Acknowledge) (*Conn, error) {
 	if c == nil {
 		return nil, fmt.Errorf("no connection")
 	}
 	if ack == nil {
 		ack = DefaultClientACK
 	}
-	return &Conn{TCPConn: c, id: nextid(), ack: ack}, nil
+	return newConn(c, ack), nil
+}
+
+func newConn(c net.Conn, ack *Acknowledge) *Conn {
+	conn := &Conn{id: nextid(), ack: ack, lim: ack}
+	conn.setConn(c)
+	return conn
+}
+
+func (c *Conn) setConn(nc net.Conn) {
+	c.nc = nc
+	c.TCPConn, _ = nc.(*net.TCPConn)
+}
+
+func (c *Conn) Read(b []byte) (int, error) {
+	return c.nc.Read(b)
+}
+
+func (c *Conn) Write(b []byte) (int, error) {
+	return c.nc.Write(b)
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	return c.nc.LocalAddr()
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.nc.RemoteAddr()
+}
+
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.nc.SetDeadline(t)
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.nc.SetReadDeadline(t)
+}
+
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.nc.SetWriteDeadline(t)
 }
 
 func (c *Conn) ID() uint32 {
 	return c.id
 }
 
+// ReceiveBufSize returns the maximum size of a chunk the connection
+// accepts which is the size announced to the peer.
 func (c *Conn) ReceiveBufSize() uint32 {
-	return c.ack.ReceiveBufSize
+	return c.lim.ReceiveBufSize
 }
 
 func (c *Conn) SendBufSize() uint32 {
 	return c.ack.SendBufSize
 }
 
+// MaxMessageSize returns the maximum size of a message the connection
+// accepts. This is the size announced to the peer or, if that is zero,
+// the size negotiated during the handshake.
 func (c *Conn) MaxMessageSize() uint32 {
+	if c.lim.MaxMessageSize > 0 {
+		return c.lim.MaxMessageSize
+	}
 	return c.ack.MaxMessageSize
 }
 
+// MaxChunkCount returns the maximum number of chunks of a message the
+// connection accepts. This is the count announced to the peer or, if
+// that is zero, the count negotiated during the handshake.
 func (c *Conn) MaxChunkCount() uint32 {
+	if c.lim.MaxChunkCount > 0 {
+		return c.lim.MaxChunkCount
+	}
 	return c.ack.MaxChunkCount
 }
 
+// MaxSendMessageSize returns the maximum size of a message the peer
+// accepts as announced during the handshake. Zero means no limit.
+func (c *Conn) MaxSendMessageSize() uint32 {
+	if c.peer == nil {
+		return 0
+	}
+	return c.peer.MaxMessageSize
+}
+
+// MaxSendChunkCount returns the maximum number of chunks of a message
+// the peer accepts as announced during the handshake. Zero means no
+// limit.
+func (c *Conn) MaxSendChunkCount() uint32 {
+	if c.peer == nil {
+		return 0
+	}
+	return c.peer.MaxChunkCount
+}
+
 func (c *Conn) Close() (err error) {
 	err = io.EOF
 	c.closeOnce.Do(func() { err = c.close() })
@@ -219,16 +464,16 @@ func (c *Conn) Close() (err error) {
 
 func (c *Conn) close() error {
 	debug.Printf("uacp %d: close", c.id)
-	return c.TCPConn.Close()
+	return c.nc.Close()
 }
 
 func (c *Conn) Handshake(ctx context.Context, endpoint string) error {
 	hel := &Hello{
-		Version:        c.ack.Version,
-		ReceiveBufSize: c.ack.ReceiveBufSize,
-		SendBufSize:    c.ack.SendBufSize,
-		MaxMessageSize: c.ack.MaxMessageSize,
-		MaxChunkCount:  c.ack.MaxChunkCount,
+		Version:        c.lim.Version,
+		ReceiveBufSize: c.lim.ReceiveBufSize,
+		SendBufSize:    c.lim.SendBufSize,
+		MaxMessageSize: c.lim.MaxMessageSize,
+		MaxChunkCount:  c.lim.MaxChunkCount,
 		EndpointURL:    endpoint,
 	}
 
@@ -268,6 +513,7 @@ func (c *Conn) Handshake(ctx context.Context, endpoint string) error {
 			debug.Printf("uacp %d: server has no message size limit. Using %d", c.id, ack.MaxMessageSize)
 		}
 		c.ack = ack
+		c.peer = ack
 		debug.Printf("uacp %d: recv %#v", c.id, ack)
 		return nil
 
@@ -312,6 +558,13 @@ func (c *Conn) srvhandshake(endpoint string) error {
 			c.SendError(ua.StatusBadTCPInternalError)
 			return err
 		}
+		c.peer = &Acknowledge{
+			Version:        hel.Version,
+			ReceiveBufSize: hel.ReceiveBufSize,
+			SendBufSize:    hel.SendBufSize,
+			MaxMessageSize: hel.MaxMessageSize,
+			MaxChunkCount:  hel.MaxChunkCount,
+		}
 		debug.Printf("uacp %d: recv %#v", c.id, hel)
 		return nil
 
@@ -332,7 +585,7 @@ func (c *Conn) srvhandshake(endpoint string) error {
 		if err != nil {
 			return err
 		}
-		c.TCPConn = c2.(*net.TCPConn)
+		c.setConn(c2)
 		debug.Printf("uacp %d: recv %#v", c.id, rhe)
 		return nil
 
@@ -359,7 +612,7 @@ const hdrlen = 8
 func (c *Conn) Receive() ([]byte, error) {
 	// TODO(kung-foo): allow user-specified buffer
 	// TODO(kung-foo): sync.Pool
-	b := make([]byte, c.ack.ReceiveBufSize)
+	b := make([]byte, c.lim.ReceiveBufSize)
 
 	if _, err := io.ReadFull(c, b[:hdrlen]); err != nil {
 		// todo(fs): do not wrap this error since it hides io.EOF
@@ -372,8 +625,8 @@ func (c *Conn) Receive() ([]byte, error) {
 		return nil, errors.Errorf("uacp: header decode failed: %s", err)
 	}
 
-	if h.MessageSize > c.ack.ReceiveBufSize {
-		return nil, errors.Errorf("uacp: message too large: %d > %d bytes. MsgType=%s, ChunkType=%c", h.MessageSize, c.ack.ReceiveBufSize, h.MessageType, h.ChunkType)
+	if h.MessageSize > c.lim.ReceiveBufSize {
+		return nil, errors.Errorf("uacp: message too large: %d > %d bytes. MsgType=%s, ChunkType=%c", h.MessageSize, c.lim.ReceiveBufSize, h.MessageType, h.ChunkType)
 	}
 	if h.MessageSize < hdrlen {
 		return nil, errors.Errorf("uacp: message too small: %d bytes. MsgType=%s, ChunkType=%c.", h.MessageSize, h.MessageType, h.ChunkType)
@@ -166,3 +166,168 @@ NEXT:
 	got = got[:n]
 	require.Equal(t, want, got)
 }
+
+func TestHandshakeLimits(t *testing.T) {
+	ep := "opc.tcp://127.0.0.1:4840/foo/bar"
+	srvACK := &Acknowledge{
+		ReceiveBufSize: 8192,
+		SendBufSize:    8192,
+		MaxChunkCount:  16,
+		MaxMessageSize: 1 << 16,
+	}
+	ln, err := Listen(context.Background(), ep, srvACK)
+	require.NoError(t, err, "Listen failed")
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	srvc := make(chan *Conn, 1)
+	go func() {
+		c, err := ln.Accept(ctx)
+		if err == nil {
+			defer c.Close()
+			srvc <- c
+			<-ctx.Done()
+		}
+	}()
+
+	// the client accepts larger chunks than the server and
+	// announces no message size limit.
+	d := &Dialer{ClientACK: &Acknowledge{
+		ReceiveBufSize: 0xffff,
+		SendBufSize:    0xffff,
+		MaxChunkCount:  4,
+	}}
+	c, err := d.Dial(ctx, ep)
+	require.NoError(t, err, "Dial failed")
+	defer c.Close()
+
+	require.Equal(t, uint32(0xffff), c.ReceiveBufSize(), "ReceiveBufSize")
+	require.Equal(t, uint32(4), c.MaxChunkCount(), "MaxChunkCount")
+	require.Equal(t, uint32(1<<16), c.MaxMessageSize(), "MaxMessageSize")
+
+	// the send limits are the limits of the peer
+	require.Equal(t, uint32(16), c.MaxSendChunkCount(), "client MaxSendChunkCount")
+	require.Equal(t, uint32(1<<16), c.MaxSendMessageSize(), "client MaxSendMessageSize")
+	select {
+	case s := <-srvc:
+		require.Equal(t, uint32(4), s.MaxSendChunkCount(), "server MaxSendChunkCount")
+		require.Equal(t, uint32(0), s.MaxSendMessageSize(), "server MaxSendMessageSize")
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for server connection")
+	}
+}
+
+func TestInterfaceAddr(t *testing.T) {
+	ifis, err := net.Interfaces()
+	require.NoError(t, err)
+	var lo string
+	for _, ifi := range ifis {
+		if ifi.Flags&net.FlagLoopback != 0 {
+			lo = ifi.Name
+			break
+		}
+	}
+	if lo == "" {
+		t.Skip("no loopback interface")
+	}
+
+	t.Run("ipv4", func(t *testing.T) {
+		addr, err := interfaceAddr(lo, net.ParseIP("127.0.0.1"))
+		require.NoError(t, err)
+		require.True(t, addr.IP.IsLoopback(), "got %s", addr)
+	})
+
+	t.Run("unknown interface", func(t *testing.T) {
+		_, err := interfaceAddr("opcua-none", net.ParseIP("127.0.0.1"))
+		require.ErrorContains(t, err, "local interface opcua-none")
+	})
+}
+
+func TestDialLocalInterface(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	d := &Dialer{LocalInterface: "opcua-none"}
+	_, err := d.Dial(ctx, "opc.tcp://127.0.0.1:4840")
+	require.ErrorContains(t, err, "local interface opcua-none")
+}
+
+type recordingDialer struct {
+	addrs []string
+}
+
+func (d *recordingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.addrs = append(d.addrs, address)
+	var nd net.Dialer
+	return nd.DialContext(ctx, network, address)
+}
+
+func TestDialContextDialer(t *testing.T) {
+	ep := "opc.tcp://127.0.0.1:4842"
+	ln, err := Listen(context.Background(), ep, nil)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	go func() {
+		c, err := ln.Accept(ctx)
+		if err == nil {
+			c.Close()
+		}
+	}()
+
+	rd := &recordingDialer{}
+	d := &Dialer{ContextDialer: rd, LocalInterface: "opcua-none"}
+	c, err := d.Dial(ctx, ep)
+	require.NoError(t, err)
+	defer c.Close()
+	require.Equal(t, []string{"127.0.0.1:4842"}, rd.addrs)
+}
+
+func TestDialEndpoint(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	hello := make(chan *Hello, 1)
+	go func() {
+		nc, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer nc.Close()
+		c, err := NewConnFromNetConn(nc, nil)
+		if err != nil {
+			return
+		}
+		b, err := c.Receive()
+		if err != nil {
+			return
+		}
+		h := new(Hello)
+		if _, err := h.Decode(b[hdrlen:]); err == nil {
+			hello <- h
+		}
+	}()
+
+	ep := "opc.tcp://10.0.0.5:4840/ua"
+	rd := &recordingDialer{}
+	d := &Dialer{ContextDialer: rd, DialEndpoint: "opc.tcp://" + ln.Addr().String()}
+	_, err = d.Dial(ctx, ep)
+	require.Error(t, err, "the server closes the connection")
+	require.Equal(t, []string{ln.Addr().String()}, rd.addrs)
+
+	select {
+	case h := <-hello:
+		require.Equal(t, ep, h.EndpointURL)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for hello")
+	}
+}
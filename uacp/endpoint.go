@@ -18,32 +18,27 @@ const defaultPort = "4840"
 //
 // Expected format of input is "opc.tcp://<addr[:port]/path/to/somewhere"
 func ResolveEndpoint(ctx context.Context, endpoint string) (network string, u *url.URL, err error) {
-	u, err = url.Parse(endpoint)
+	u, err = parseEndpoint(endpoint)
 	if err != nil {
 		return
 	}
 
-	if u.Scheme != "opc.tcp" {
-		err = errors.Errorf("unsupported scheme %s", u.Scheme)
-		return
-	}
-
 	network = "tcp"
 
-	port := u.Port()
-	if port == "" {
-		port = defaultPort
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		return
 	}
 
 	var resolver net.Resolver
 
-	addrs, err := resolver.LookupIPAddr(ctx, u.Hostname())
+	addrs, err := resolver.LookupIPAddr(ctx, host)
 	if err != nil {
 		return
 	}
 
 	if len(addrs) == 0 {
-		err = errors.Errorf("could not resolve address %s", u.Hostname())
+		err = errors.Errorf("could not resolve address %s", host)
 		return
 	}
 
@@ -51,3 +46,23 @@ func ResolveEndpoint(ctx context.Context, endpoint string) (network string, u *u
 
 	return
 }
+
+// parseEndpoint parses the endpoint url and adds the default port to the
+// host if the url has none.
+func parseEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "opc.tcp" {
+		return nil, errors.Errorf("unsupported scheme %s", u.Scheme)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	u.Host = net.JoinHostPort(u.Hostname(), port)
+	return u, nil
+}
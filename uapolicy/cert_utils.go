@@ -8,21 +8,47 @@ import (
 	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/x509"
+
+	"github.com/gopcua/opcua/errors"
 )
 
-// Thumbprint returns the thumbprint of a DER-encoded certificate
+// Thumbprint returns the thumbprint of a DER-encoded certificate. If c
+// contains a certificate chain then the thumbprint of the leaf
+// certificate is returned.
 func Thumbprint(c []byte) []byte {
+	if cert, err := LeafCertificate(c); err == nil {
+		c = cert.Raw
+	}
 	thumbprint := sha1.Sum(c)
 
 	return thumbprint[:]
 }
 
 // PublicKey returns the RSA PublicKey from a DER-encoded certificate
+// or the leaf certificate of a certificate chain.
 func PublicKey(c []byte) (*rsa.PublicKey, error) {
-	cert, err := x509.ParseCertificate(c)
+	cert, err := LeafCertificate(c)
 	if err != nil {
 		return nil, err
 	}
 
-	return cert.PublicKey.(*rsa.PublicKey), nil
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("certificate has no RSA public key")
+	}
+	return key, nil
+}
+
+// LeafCertificate parses a DER-encoded certificate or a certificate
+// chain of concatenated DER-encoded certificates and returns the first
+// certificate which is the leaf certificate of the chain.
+func LeafCertificate(c []byte) (*x509.Certificate, error) {
+	certs, err := x509.ParseCertificates(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, errors.Errorf("no certificate")
+	}
+	return certs[0], nil
 }
@@ -23,6 +23,11 @@ func TestSupportedPolicies(t *testing.T) {
 	}
 	sort.Strings(want)
 	require.Equal(t, want, got)
+
+	// all security policies of the specification must be supported
+	for name, uri := range ua.SecurityPolicyURIs {
+		require.Contains(t, got, uri, "security policy %s not supported", name)
+	}
 }
 
 func TestGenerateKeysLength(t *testing.T) {
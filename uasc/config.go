@@ -6,6 +6,8 @@ package uasc
 
 import (
 	"crypto/rsa"
+	"crypto/x509"
+	"log/slog"
 	"time"
 
 	"github.com/gopcua/opcua/ua"
@@ -49,6 +51,21 @@ type Config struct {
 	// Used to encrypt the message chunks in the OpenSecureChannel phase.
 	RemoteCertificate []byte
 
+	// VerifyCertificate is called with the certificate and the certificate chain
+	// presented by the remote instance in the OpenSecureChannel response.
+	// The secure channel is not opened if it returns an error. May be nil.
+	VerifyCertificate func(cert *x509.Certificate, chain [][]*x509.Certificate) error
+
+	// TokenRenewedFunc is called with the ids of the old and the new
+	// security token after the security token of a client channel has
+	// been renewed. It is called from the receive path of the channel and
+	// must not block. May be nil.
+	TokenRenewedFunc func(oldTokenID, newTokenID uint32)
+
+	// Logger receives the structured log records of the secure channel.
+	// If it is nil the records are written to the debug logger.
+	Logger *slog.Logger
+
 	// RequestIDSeed is the initial value for RequestID counter in each new SecureChannel
 	RequestIDSeed uint32
 
@@ -80,6 +97,16 @@ type Config struct {
 	// RequestTimeout is timeout duration for all synchronous requests over SecureChannel.
 	// If the Server doesn't respond within RequestTimeout time, Client returns StatusBadTimeout
 	RequestTimeout time.Duration
+
+	// AuditEntryID is called for every request to set the AuditEntryId
+	// of the request header which the server writes to its audit log.
+	// It is not called if the request header already has one. May be nil.
+	AuditEntryID func() string
+
+	// ReturnDiagnostics is the mask of the diagnostics the server shall
+	// return for every request. A non-zero mask in the request header
+	// takes precedence.
+	ReturnDiagnostics uint32
 }
 
 // SessionConfig is a set of common configurations used in Session.
@@ -145,6 +172,10 @@ type SessionConfig struct {
 	// todo: storing passwords in memory seems wrong
 	AuthPassword string
 
+	// Stored version of the issued token data, e.g. a JWT, to authenticate against a server.
+	// It is encrypted with the server nonce before it is sent.
+	AuthTokenData []byte
+
 	// PolicyURI to use when encrypting secrets for the User Identity Token
 	// Could be different from the secure channel's policy
 	AuthPolicyURI string
@@ -5,12 +5,14 @@
 package uasc
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
 	"github.com/gopcua/opcua/id"
 
 	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMessage(t *testing.T) {
@@ -248,3 +250,49 @@ func TestMessage(t *testing.T) {
 	}
 	RunCodecTest(t, cases)
 }
+
+func TestEncodeChunks(t *testing.T) {
+	s := &SecureChannel{cfg: &Config{}}
+	instance := &channelInstance{sc: s}
+	req := &ua.WriteRequest{
+		RequestHeader: &ua.RequestHeader{
+			AuthenticationToken: ua.NewTwoByteNodeID(0),
+			Timestamp:           time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+			AdditionalHeader:    ua.NewExtensionObject(nil),
+		},
+		NodesToWrite: []*ua.WriteValue{
+			{
+				NodeID:      ua.NewStringNodeID(1, "blob"),
+				AttributeID: ua.AttributeIDValue,
+				Value: &ua.DataValue{
+					EncodingMask: ua.DataValueValue,
+					Value:        ua.MustVariant(bytes.Repeat([]byte{0xab}, 1000)),
+				},
+			},
+		},
+	}
+	m := instance.newMessage(req, id.WriteRequest_Encoding_DefaultBinary, 1)
+
+	chunks, err := m.EncodeChunks(100)
+	require.NoError(t, err)
+	require.Len(t, chunks, 11)
+
+	// the bodies of the chunks form the encoded request
+	var body []byte
+	for i, b := range chunks {
+		c := new(MessageChunk)
+		_, err := c.Decode(b)
+		require.NoError(t, err)
+		require.Equal(t, uint32(len(b)), c.Header.MessageSize)
+		if i < len(chunks)-1 {
+			require.Equal(t, byte(ChunkTypeIntermediate), c.Header.ChunkType)
+			require.Len(t, b, 100+symmetricChunkHeaderSize)
+		} else {
+			require.Equal(t, byte(ChunkTypeFinal), c.Header.ChunkType)
+		}
+		body = append(body, b[symmetricChunkHeaderSize:]...)
+	}
+	_, v, err := ua.DecodeService(body)
+	require.NoError(t, err)
+	require.Equal(t, req, v)
+}
@@ -8,10 +8,11 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
-	"crypto/x509"
 	"encoding/binary"
+	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"math"
 	"net"
 	"strings"
@@ -21,6 +22,7 @@ import (
 
 	"github.com/gopcua/opcua/debug"
 	"github.com/gopcua/opcua/errors"
+	"github.com/gopcua/opcua/stats"
 	"github.com/gopcua/opcua/ua"
 	"github.com/gopcua/opcua/uacp"
 	"github.com/gopcua/opcua/uapolicy"
@@ -109,6 +111,9 @@ type SecureChannel struct {
 	// cfg is the configuration for the secure channel.
 	cfg *Config
 
+	// logger receives the structured log records of the channel.
+	logger *slog.Logger
+
 	// time returns the current time. When not set it defaults to time.Now().
 	time func() time.Time
 
@@ -133,12 +138,17 @@ type SecureChannel struct {
 	rcvLocker  *conditionLocker
 	pendingReq sync.WaitGroup
 
-	// handles maps requestIDs to response channels
-	handlers   map[uint32]chan *MessageBody
+	// handlers maps requestIDs to the pending requests which wait
+	// for their response.
+	handlers   map[uint32]*pendingRequest
 	handlersMu sync.Mutex
 
 	// chunks maintains a temporary list of chunks for a given request ID
-	chunks   map[uint32][]*MessageChunk
+	chunks map[uint32][]*MessageChunk
+
+	// dropped contains the request IDs of messages which exceeded the
+	// receive limits. Their remaining chunks are discarded.
+	dropped  map[uint32]struct{}
 	chunksMu sync.Mutex
 
 	// openingInstance is a temporary var that allows the dispatcher know how to handle a open channel request
@@ -218,10 +228,16 @@ func newSecureChannel(endpoint string, c *uacp.Conn, cfg *Config, kind channelKi
 		return nil, errors.Errorf("invalid channel config: Security policy '%s' requires a private key", cfg.SecurityPolicyURI)
 	}
 
+	logger := cfg.Logger
+	if logger == nil {
+		logger = debug.NewLogger()
+	}
+
 	s := &SecureChannel{
 		endpointURL: endpoint,
 		c:           c,
 		cfg:         cfg,
+		logger:      logger.With("endpoint", endpoint),
 		requestID:   cfg.RequestIDSeed,
 		kind:        kind,
 		// secureChannelID: secureChannelID,
@@ -234,14 +250,37 @@ func newSecureChannel(endpoint string, c *uacp.Conn, cfg *Config, kind channelKi
 		disconnected: make(chan struct{}),
 		instances:    make(map[uint32][]*channelInstance),
 		chunks:       make(map[uint32][]*MessageChunk),
-		handlers:     make(map[uint32]chan *MessageBody),
+		dropped:      make(map[uint32]struct{}),
+		handlers:     make(map[uint32]*pendingRequest),
 	}
 
 	return s, nil
 }
 
 func (s *SecureChannel) RemoteAddr() net.Addr {
-	return s.c.TCPConn.RemoteAddr()
+	return s.c.RemoteAddr()
+}
+
+// SecurityToken describes the security token of a secure channel.
+type SecurityToken struct {
+	ChannelID       uint32
+	TokenID         uint32
+	CreatedAt       time.Time
+	RevisedLifetime time.Duration
+}
+
+// SecurityToken returns the currently active security token.
+func (s *SecureChannel) SecurityToken() (SecurityToken, error) {
+	instance, err := s.getActiveChannelInstance()
+	if err != nil {
+		return SecurityToken{}, err
+	}
+	return SecurityToken{
+		ChannelID:       instance.secureChannelID,
+		TokenID:         instance.securityTokenID,
+		CreatedAt:       instance.createdAt,
+		RevisedLifetime: instance.revisedLifetime,
+	}, nil
 }
 
 func (s *SecureChannel) getActiveChannelInstance() (*channelInstance, error) {
@@ -267,7 +306,10 @@ func (s *SecureChannel) dispatcher() {
 			return
 		default:
 			msg := s.Receive(ctx)
-			if msg.Err != nil {
+
+			// responses which exceed the receive limits only fail the
+			// request and not the channel.
+			if msg.Err != nil && msg.Err != ua.StatusBadResponseTooLarge {
 				select {
 				case <-s.closing:
 					return
@@ -330,6 +372,10 @@ func (s *SecureChannel) Receive(ctx context.Context) *MessageBody {
 				return &MessageBody{Err: err}
 			}
 
+			var oerr *openError
+			if errors.As(err, &oerr) {
+				return &MessageBody{RequestID: oerr.reqID, Err: oerr.err}
+			}
 			if err != nil {
 				return &MessageBody{Err: err}
 			}
@@ -354,6 +400,7 @@ func (s *SecureChannel) Receive(ctx context.Context) *MessageBody {
 			switch hdr.ChunkType {
 			case 'A':
 				delete(s.chunks, reqID)
+				delete(s.dropped, reqID)
 				s.chunksMu.Unlock()
 
 				msga := new(MessageAbort)
@@ -366,31 +413,43 @@ func (s *SecureChannel) Receive(ctx context.Context) *MessageBody {
 				return &MessageBody{RequestID: reqID, Err: ua.StatusCode(msga.ErrorCode)}
 
 			case 'C':
+				if _, ok := s.dropped[reqID]; ok {
+					s.chunksMu.Unlock()
+					continue
+				}
 				s.chunks[reqID] = append(s.chunks[reqID], chunk)
-				if n := len(s.chunks[reqID]); uint32(n) > s.c.MaxChunkCount() {
+				if err := s.checkReceiveLimits(reqID, s.chunks[reqID]); err != nil {
 					delete(s.chunks, reqID)
+					s.dropped[reqID] = struct{}{}
 					s.chunksMu.Unlock()
-					msg.Err = errors.Errorf("too many chunks: %d > %d", n, s.c.MaxChunkCount())
+					msg.Err = err
 					return msg
 				}
 				s.chunksMu.Unlock()
 				continue
 			}
 
+			// the error for a dropped message has already been returned
+			if _, ok := s.dropped[reqID]; ok {
+				delete(s.dropped, reqID)
+				s.chunksMu.Unlock()
+				continue
+			}
+
 			// merge chunks
 			all := append(s.chunks[reqID], chunk)
 			delete(s.chunks, reqID)
 
 			s.chunksMu.Unlock()
 
-			b, err := mergeChunks(all)
-			if err != nil {
+			if err := s.checkReceiveLimits(reqID, all); err != nil {
 				msg.Err = err
 				return msg
 			}
 
-			if uint32(len(b)) > s.c.MaxMessageSize() {
-				msg.Err = errors.Errorf("message too large: %d > %d", uint32(len(b)), s.c.MaxMessageSize())
+			b, err := mergeChunks(all)
+			if err != nil {
+				msg.Err = err
 				return msg
 			}
 
@@ -402,8 +461,7 @@ func (s *SecureChannel) Receive(ctx context.Context) *MessageBody {
 			// cannot get to the RequestHandle in the ResponseHeader.
 			// To fix this we must a) decode the ResponseHeader separately
 			// and subsequently remove it and the TypeID from all service
-			// structs and tests. We also need to add a deadline to all
-			// handlers and check them periodically to time them out.
+			// structs and tests.
 			_, body, err := ua.DecodeService(b)
 			if err != nil {
 				msg.Err = err
@@ -436,6 +494,93 @@ func (s *SecureChannel) Receive(ctx context.Context) *MessageBody {
 	}
 }
 
+// checkReceiveLimits returns an error if the chunks of a message exceed
+// the chunk count or the message size which have been announced to the
+// peer. The error is BadResponseTooLarge for client channels and
+// BadRequestTooLarge for server channels.
+func (s *SecureChannel) checkReceiveLimits(reqID uint32, chunks []*MessageChunk) error {
+	tooLarge := ua.StatusBadResponseTooLarge
+	if s.kind == server {
+		tooLarge = ua.StatusBadRequestTooLarge
+	}
+
+	if max := s.c.MaxChunkCount(); max > 0 && uint32(len(chunks)) > max {
+		debug.Printf("uasc %d/%d: too many chunks: %d > %d", s.c.ID(), reqID, len(chunks), max)
+		return tooLarge
+	}
+
+	var n uint32
+	for _, c := range chunks {
+		n += uint32(len(c.Data))
+	}
+	if max := s.c.MaxMessageSize(); max > 0 && n > max {
+		debug.Printf("uasc %d/%d: message too large: %d > %d bytes", s.c.ID(), reqID, n, max)
+		return tooLarge
+	}
+	return nil
+}
+
+// symmetricChunkHeaderSize is the size of the message header, the
+// symmetric security header and the sequence header of a MSG chunk.
+const symmetricChunkHeaderSize = 24
+
+// checkSendLimits returns an error wrapping BadRequestTooLarge if the
+// chunks of a request exceed the chunk count or the message size which
+// the peer announced during the handshake. The chunks must be MSG
+// chunks which have not been signed or encrypted.
+func (s *SecureChannel) checkSendLimits(req ua.Request, chunks [][]byte) error {
+	if max := s.c.MaxSendChunkCount(); max > 0 && uint32(len(chunks)) > max {
+		return errors.Errorf("uasc: %T needs %d chunks but the peer accepts %d: %w", req, len(chunks), max, ua.StatusBadRequestTooLarge)
+	}
+
+	var n uint64
+	for _, c := range chunks {
+		n += uint64(len(c) - symmetricChunkHeaderSize)
+	}
+	if max := s.c.MaxSendMessageSize(); max > 0 && n > uint64(max) {
+		return errors.Errorf("uasc: %T has %d bytes but the peer accepts %d: %w", req, n, max, ua.StatusBadRequestTooLarge)
+	}
+	return nil
+}
+
+// MaxSendMessageSize returns the maximum size of an encoded message
+// body which the peer accepts. This is the maximum message size or the
+// maximum number of chunks times the body size of a chunk, whichever is
+// smaller. Zero means no limit.
+func (s *SecureChannel) MaxSendMessageSize() uint32 {
+	max := s.c.MaxSendMessageSize()
+	count := s.c.MaxSendChunkCount()
+	if count == 0 {
+		return max
+	}
+	instance, err := s.getActiveChannelInstance()
+	if err != nil {
+		return max
+	}
+	instance.Lock()
+	bodySize := instance.maxBodySize
+	instance.Unlock()
+
+	if n := uint64(count) * uint64(bodySize); n > 0 && (max == 0 || n < uint64(max)) {
+		return uint32(min(n, math.MaxUint32))
+	}
+	return max
+}
+
+// openError is returned by readChunk when the OpenSecureChannel response
+// cannot be accepted, e.g. because the server certificate is not trusted.
+// The request id of the encrypted response is not known at this point and
+// is taken from the opening instance so that the error is returned to the
+// pending request instead of letting it time out.
+type openError struct {
+	reqID uint32
+	err   error
+}
+
+func (e *openError) Error() string { return e.err.Error() }
+
+func (e *openError) Unwrap() error { return e.err }
+
 func (s *SecureChannel) readChunk() (*MessageChunk, error) {
 	// read a full message from the underlying conn.
 	b, err := s.c.Receive()
@@ -481,23 +626,29 @@ func (s *SecureChannel) readChunk() (*MessageChunk, error) {
 			s.cfg.RemoteCertificate = m.AsymmetricSecurityHeader.SenderCertificate
 			debug.Printf("uasc %d: setting securityPolicy to %s", s.c.ID(), m.SecurityPolicyURI)
 
-			remoteCert, err := x509.ParseCertificate(s.cfg.RemoteCertificate)
+			remoteCert, err := s.verifyRemoteCertificate(s.cfg.RemoteCertificate)
 			if err != nil {
-				return nil, err
+				return nil, &openError{reqID: s.openingInstance.openRequestID, err: err}
 			}
 			remoteKey, ok := remoteCert.PublicKey.(*rsa.PublicKey)
 			if !ok {
-				return nil, ua.StatusBadCertificateInvalid
+				return nil, &openError{reqID: s.openingInstance.openRequestID, err: ua.StatusBadCertificateInvalid}
 			}
 			algo, err := uapolicy.Asymmetric(s.cfg.SecurityPolicyURI, s.openingInstance.sc.cfg.LocalKey, remoteKey)
 			if err != nil {
-				return nil, err
+				return nil, &openError{reqID: s.openingInstance.openRequestID, err: err}
 			}
 
 			s.openingInstance.algo = algo
 
-			// For OpenSecureChannel asymmetric encryption is always used
-			s.cfg.SecurityMode = ua.MessageSecurityModeSignAndEncrypt
+			// For OpenSecureChannel asymmetric encryption is always used.
+			// The server does not know the security mode before it has
+			// decoded the request. Do not change the mode of a channel
+			// which already has one since a Sign-only channel must not
+			// encrypt its messages.
+			if s.cfg.SecurityMode == ua.MessageSecurityModeNone {
+				s.cfg.SecurityMode = ua.MessageSecurityModeSignAndEncrypt
+			}
 		}
 
 		decryptWith = s.openingInstance
@@ -605,7 +756,7 @@ func (s *SecureChannel) open(ctx context.Context, instance *channelInstance, req
 		localKey = s.cfg.LocalKey
 		// todo(dh): move this into the uapolicy package proper or
 		// adjust the Asymmetric method to receive a certificate instead
-		remoteCert, err := x509.ParseCertificate(s.cfg.RemoteCertificate)
+		remoteCert, err := uapolicy.LeafCertificate(s.cfg.RemoteCertificate)
 		if err != nil {
 			return err
 		}
@@ -643,6 +794,7 @@ func (s *SecureChannel) open(ctx context.Context, instance *channelInstance, req
 
 	reqID := s.nextRequestID()
 
+	s.openingInstance.openRequestID = reqID
 	s.openingInstance.algo = algo
 	s.openingInstance.SetMaximumBodySize(int(s.c.SendBufSize()))
 
@@ -689,17 +841,26 @@ func (s *SecureChannel) handleOpenSecureChannelResponse(resp *ua.OpenSecureChann
 	instance.SetMaximumBodySize(int(s.c.SendBufSize()))
 
 	s.instancesMu.Lock()
-	defer s.instancesMu.Unlock()
-
 	s.instances[resp.SecurityToken.ChannelID] = append(
 		s.instances[resp.SecurityToken.ChannelID],
 		s.openingInstance,
 	)
-
+	prev := s.activeInstance
 	s.activeInstance = instance
+	s.instancesMu.Unlock()
 
 	debug.Printf("uasc %d: received security token. channelID=%d tokenID=%d createdAt=%s lifetime=%s", s.c.ID(), instance.secureChannelID, instance.securityTokenID, instance.createdAt.Format(time.RFC3339), instance.revisedLifetime)
 
+	msg := "secure channel opened"
+	if prev != nil {
+		msg = "security token renewed"
+	}
+	s.logger.Info(msg,
+		"channelID", instance.secureChannelID,
+		"tokenID", instance.securityTokenID,
+		"lifetime", instance.revisedLifetime,
+	)
+
 	// depending on whether the channel is used in a client
 	// or a server we need to trigger different behavior.
 	// client channels trigger token renewals and need to cleanup old
@@ -710,6 +871,10 @@ func (s *SecureChannel) handleOpenSecureChannelResponse(resp *ua.OpenSecureChann
 		go s.scheduleRenewal(instance)
 		go s.scheduleExpiration(instance)
 
+		if prev != nil && s.cfg.TokenRenewedFunc != nil {
+			s.cfg.TokenRenewedFunc(prev.securityTokenID, instance.securityTokenID)
+		}
+
 	case server:
 		go s.scheduleExpiration(instance)
 	}
@@ -764,7 +929,7 @@ func (s *SecureChannel) handleOpenSecureChannelRequest(reqID uint32, svc ua.Requ
 		localKey = s.cfg.LocalKey
 		// todo(dh): move this into the uapolicy package proper or
 		// adjust the Asymmetric method to receive a certificate instead
-		remoteCert, err := x509.ParseCertificate(s.cfg.RemoteCertificate)
+		remoteCert, err := uapolicy.LeafCertificate(s.cfg.RemoteCertificate)
 		if err != nil {
 			return err
 		}
@@ -923,10 +1088,6 @@ func (s *SecureChannel) sendRequestWithTimeout(
 		return nil
 	}
 
-	// `+ timeoutLeniency` to give the server a chance to respond to TimeoutHint
-	timer := time.NewTimer(timeout + timeoutLeniency)
-	defer timer.Stop()
-
 	select {
 	case <-ctx.Done():
 		s.popHandler(reqID)
@@ -942,21 +1103,84 @@ func (s *SecureChannel) sendRequestWithTimeout(
 			return msg.Err
 		}
 		return h(msg.Response())
-	case <-timer.C:
-		s.popHandler(reqID)
-		return ua.StatusBadTimeout
 	}
 }
 
+// pendingRequest is a request which waits for its response. The
+// request fails with BadTimeout when the timer fires before the
+// response arrives.
+type pendingRequest struct {
+	ch      chan *MessageBody
+	timer   *time.Timer
+	service string
+	timeout time.Duration
+}
+
+// addHandler registers a pending request for reqID which times out
+// after timeout.
+func (s *SecureChannel) addHandler(reqID uint32, req ua.Request, timeout time.Duration) (chan *MessageBody, error) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	if s.handlers[reqID] != nil {
+		return nil, errors.Errorf("error: duplicate handler registration for request id %d", reqID)
+	}
+
+	p := &pendingRequest{
+		ch:      make(chan *MessageBody, 1),
+		service: fmt.Sprintf("%T", req),
+		timeout: timeout,
+	}
+	p.timer = time.AfterFunc(timeout, func() { s.expireHandler(reqID, p) })
+	s.handlers[reqID] = p
+	stats.Client().Add("PendingRequests", 1)
+	return p.ch, nil
+}
+
+// expireHandler removes the pending request p and fails it with
+// BadTimeout unless the response has already arrived.
+func (s *SecureChannel) expireHandler(reqID uint32, p *pendingRequest) {
+	s.handlersMu.Lock()
+	if s.handlers[reqID] != p {
+		s.handlersMu.Unlock()
+		return
+	}
+	delete(s.handlers, reqID)
+	s.handlersMu.Unlock()
+	stats.Client().Add("PendingRequests", -1)
+
+	s.logger.Warn("request timed out",
+		"service", p.service,
+		"requestID", reqID,
+		"timeout", p.timeout,
+	)
+
+	// the channel is of size one and nobody else can send to it
+	// since the request has been removed.
+	p.ch <- &MessageBody{RequestID: reqID, Err: ua.StatusBadTimeout}
+}
+
 func (s *SecureChannel) popHandler(reqID uint32) (chan *MessageBody, bool) {
 	s.handlersMu.Lock()
 	defer s.handlersMu.Unlock()
 
-	ch, ok := s.handlers[reqID]
-	if ok {
-		delete(s.handlers, reqID)
+	p, ok := s.handlers[reqID]
+	if !ok {
+		return nil, false
 	}
-	return ch, ok
+	p.timer.Stop()
+	delete(s.handlers, reqID)
+	stats.Client().Add("PendingRequests", -1)
+	return p.ch, true
+}
+
+// PendingRequests returns the number of requests which are waiting for
+// their response. Requests are removed when the response arrives, the
+// request is cancelled or it times out.
+func (s *SecureChannel) PendingRequests() int {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+	return len(s.handlers)
 }
 
 func (s *SecureChannel) Renew(ctx context.Context) error {
@@ -1001,32 +1225,43 @@ func (s *SecureChannel) sendAsyncWithTimeout(
 		return nil, err
 	}
 
+	// encode the request before registering the handler so that
+	// requests which cannot be sent do not leave a handler behind.
+	chunks, err := m.EncodeChunks(instance.maxBodySize)
+	if err != nil {
+		return nil, err
+	}
+	if m.Header.MessageType == "MSG" {
+		if err := s.checkSendLimits(req, chunks); err != nil {
+			return nil, err
+		}
+	}
+
 	var resp chan *MessageBody
 
 	if respRequired {
-		// register the handler if a callback was passed
-		resp = make(chan *MessageBody, 1)
-
-		s.handlersMu.Lock()
-
-		if s.handlers[reqID] != nil {
-			s.handlersMu.Unlock()
-			return nil, errors.Errorf("error: duplicate handler registration for request id %d", reqID)
+		// register the handler if a callback was passed. The request
+		// times out after the TimeoutHint which is sent to the server
+		// `+ timeoutLeniency` to give the server a chance to respond.
+		resp, err = s.addHandler(reqID, req, timeout+timeoutLeniency)
+		if err != nil {
+			return nil, err
 		}
-
-		s.handlers[reqID] = resp
-		s.handlersMu.Unlock()
 	}
 
-	chunks, err := m.EncodeChunks(instance.maxBodySize)
-	if err != nil {
+	// remove the handler if the request could not be sent since
+	// there will be no response.
+	fail := func(err error) (<-chan *MessageBody, error) {
+		if respRequired {
+			s.popHandler(reqID)
+		}
 		return nil, err
 	}
 
 	for i, chunk := range chunks {
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return fail(ctx.Err())
 		default:
 		}
 		if i > 0 { // fix sequence number on subsequent chunks
@@ -1036,14 +1271,14 @@ func (s *SecureChannel) sendAsyncWithTimeout(
 
 		chunk, err = instance.signAndEncrypt(m, chunk)
 		if err != nil {
-			return nil, err
+			return fail(err)
 		}
 
 		// send the message
 		var n int
 		s.c.SetWriteDeadline(time.Now().Add(timeout))
 		if n, err = s.c.Write(chunk); err != nil {
-			return nil, err
+			return fail(err)
 		}
 		s.c.SetWriteDeadline(time.Time{})
 
@@ -1052,6 +1287,11 @@ func (s *SecureChannel) sendAsyncWithTimeout(
 
 		debug.Printf("uasc %d/%d: send %T with %d bytes", s.c.ID(), reqID, req, len(chunk))
 	}
+	s.logger.Debug("request sent",
+		"service", fmt.Sprintf("%T", req),
+		"requestID", reqID,
+		"channelID", instance.secureChannelID,
+	)
 
 	return resp, nil
 }
@@ -1181,6 +1421,7 @@ func (s *SecureChannel) Close() (err error) {
 
 func (s *SecureChannel) close() error {
 	debug.Printf("uasc %d: Close()", s.c.ID())
+	s.logger.Info("secure channel closed")
 
 	defer func() {
 		close(s.closing)
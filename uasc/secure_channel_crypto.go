@@ -9,6 +9,7 @@ import (
 	"crypto/x509"
 	"encoding/binary"
 
+	"github.com/gopcua/opcua/errors"
 	"github.com/gopcua/opcua/ua"
 	"github.com/gopcua/opcua/uapolicy"
 )
@@ -19,7 +20,7 @@ func (s *SecureChannel) NewSessionSignature(cert, nonce []byte) ([]byte, string,
 		return nil, "", nil
 	}
 
-	remoteX509Cert, err := x509.ParseCertificate(cert)
+	remoteX509Cert, err := uapolicy.LeafCertificate(cert)
 	if err != nil {
 		return nil, "", err
 	}
@@ -30,7 +31,9 @@ func (s *SecureChannel) NewSessionSignature(cert, nonce []byte) ([]byte, string,
 		return nil, "", err
 	}
 
-	sig, err := enc.Signature(append(cert, nonce...))
+	// the signature is calculated over the leaf certificate
+	// if the remote instance sent a certificate chain.
+	sig, err := enc.Signature(concat(remoteX509Cert.Raw, nonce))
 	if err != nil {
 		return nil, "", err
 	}
@@ -40,12 +43,16 @@ func (s *SecureChannel) NewSessionSignature(cert, nonce []byte) ([]byte, string,
 }
 
 // VerifySessionSignature checks the integrity of a Create/Activate Session response's signature
+//
+// If the local certificate is a certificate chain then the signature is
+// verified over the leaf certificate first and over the complete chain
+// if that fails since implementations differ in this regard.
 func (s *SecureChannel) VerifySessionSignature(cert, nonce, signature []byte) error {
 	if s.cfg.SecurityMode == ua.MessageSecurityModeNone {
 		return nil
 	}
 
-	remoteX509Cert, err := x509.ParseCertificate(cert)
+	remoteX509Cert, err := uapolicy.LeafCertificate(cert)
 	if err != nil {
 		return err
 	}
@@ -55,26 +62,34 @@ func (s *SecureChannel) VerifySessionSignature(cert, nonce, signature []byte) er
 	if err != nil {
 		return err
 	}
-	err = enc.VerifySignature(append(s.cfg.Certificate, nonce...), signature)
-	if err != nil {
-		return err
-	}
 
-	return nil
+	local := s.cfg.Certificate
+	if leaf, err := uapolicy.LeafCertificate(local); err == nil && len(leaf.Raw) < len(local) {
+		if err := enc.VerifySignature(concat(leaf.Raw, nonce), signature); err == nil {
+			return nil
+		}
+	}
+	return enc.VerifySignature(concat(local, nonce), signature)
 }
 
 // EncryptUserPassword issues a new signature for the client to send in ActivateSessionRequest
 func (s *SecureChannel) EncryptUserPassword(policyURI, password string, cert, nonce []byte) ([]byte, string, error) {
+	return s.EncryptUserTokenData(policyURI, []byte(password), cert, nonce)
+}
+
+// EncryptUserTokenData encrypts the secret of a user identity token, e.g. the token data
+// of an issued identity token, for the client to send in ActivateSessionRequest
+func (s *SecureChannel) EncryptUserTokenData(policyURI string, data, cert, nonce []byte) ([]byte, string, error) {
 	// If the User ID Token's policy was null, then default to the secure channel's policy
 	if policyURI == "" {
 		policyURI = s.cfg.SecurityPolicyURI
 	}
 
 	if policyURI == ua.SecurityPolicyURINone {
-		return []byte(password), "", nil
+		return data, "", nil
 	}
 
-	remoteX509Cert, err := x509.ParseCertificate(cert)
+	remoteX509Cert, err := uapolicy.LeafCertificate(cert)
 	if err != nil {
 		return nil, "", err
 	}
@@ -85,10 +100,10 @@ func (s *SecureChannel) EncryptUserPassword(policyURI, password string, cert, no
 		return nil, "", err
 	}
 
-	l := len(password) + len(nonce)
+	l := len(data) + len(nonce)
 	secret := make([]byte, 4)
 	binary.LittleEndian.PutUint32(secret, uint32(l))
-	secret = append(secret, []byte(password)...)
+	secret = append(secret, data...)
 	secret = append(secret, nonce...)
 	pass, err := enc.Encrypt(secret)
 	if err != nil {
@@ -103,6 +118,12 @@ func (s *SecureChannel) EncryptUserPassword(policyURI, password string, cert, no
 // The security policy for the SecureChannel is used if policyURI value is null or empty
 // https://reference.opcfoundation.org/Core/Part4/v104/docs/7.37
 func (s *SecureChannel) NewUserTokenSignature(policyURI string, cert, nonce []byte) ([]byte, string, error) {
+	return s.NewUserTokenSignatureWithKey(policyURI, s.cfg.UserKey, cert, nonce)
+}
+
+// NewUserTokenSignatureWithKey is like NewUserTokenSignature but signs with
+// the given user key instead of the one of the configuration.
+func (s *SecureChannel) NewUserTokenSignatureWithKey(policyURI string, userKey *rsa.PrivateKey, cert, nonce []byte) ([]byte, string, error) {
 	if policyURI == "" {
 		policyURI = s.cfg.SecurityPolicyURI
 	}
@@ -111,18 +132,18 @@ func (s *SecureChannel) NewUserTokenSignature(policyURI string, cert, nonce []by
 		return nil, "", nil
 	}
 
-	remoteX509Cert, err := x509.ParseCertificate(cert)
+	remoteX509Cert, err := uapolicy.LeafCertificate(cert)
 	if err != nil {
 		return nil, "", err
 	}
 	remoteKey := remoteX509Cert.PublicKey.(*rsa.PublicKey)
 
-	enc, err := uapolicy.Asymmetric(policyURI, s.cfg.UserKey, remoteKey)
+	enc, err := uapolicy.Asymmetric(policyURI, userKey, remoteKey)
 	if err != nil {
 		return nil, "", err
 	}
 
-	sig, err := enc.Signature(append(cert, nonce...))
+	sig, err := enc.Signature(concat(remoteX509Cert.Raw, nonce))
 	if err != nil {
 		return nil, "", err
 	}
@@ -130,3 +151,30 @@ func (s *SecureChannel) NewUserTokenSignature(policyURI string, cert, nonce []by
 
 	return sig, sigAlg, nil
 }
+
+// verifyRemoteCertificate parses the certificate chain presented by the
+// remote instance and passes it to the VerifyCertificate callback. It
+// returns the leaf certificate which is the first certificate of the chain.
+func (s *SecureChannel) verifyRemoteCertificate(der []byte) (*x509.Certificate, error) {
+	chain, err := x509.ParseCertificates(der)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) == 0 {
+		return nil, ua.StatusBadCertificateInvalid
+	}
+	if s.cfg.VerifyCertificate == nil {
+		return chain[0], nil
+	}
+	if err := s.cfg.VerifyCertificate(chain[0], [][]*x509.Certificate{chain}); err != nil {
+		return nil, errors.Errorf("sechan: remote certificate rejected: %w", err)
+	}
+	return chain[0], nil
+}
+
+// concat returns a new slice with the contents of a and b. The
+// certificates parsed from a chain share the memory of the chain
+// and must not be appended to directly.
+func concat(a, b []byte) []byte {
+	return append(append(make([]byte, 0, len(a)+len(b)), a...), b...)
+}
@@ -36,6 +36,10 @@ type channelInstance struct {
 	algo            *uapolicy.EncryptionAlgorithm
 	maxBodySize     uint32
 
+	// openRequestID is the request id of the OpenSecureChannel
+	// request of an opening instance.
+	openRequestID uint32
+
 	bytesSent uint64 // atomic.Load/Store - needs to be aligned for 32bit systems
 	// bytesReceived    uint64
 	messagesSent uint32 // atomic.Load/Store
@@ -72,6 +76,19 @@ func (c *channelInstance) newRequestMessage(req ua.Request, reqID uint32, authTo
 		AuthenticationToken: authToken,
 		Timestamp:           c.sc.timeNow(),
 		RequestHandle:       reqID, // TODO: can I cheat like this?
+		ReturnDiagnostics:   c.sc.cfg.ReturnDiagnostics,
+	}
+
+	// keep the audit and diagnostics fields set by the caller
+	if h := req.Header(); h != nil {
+		reqHdr.AuditEntryID = h.AuditEntryID
+		if h.ReturnDiagnostics != 0 {
+			reqHdr.ReturnDiagnostics = h.ReturnDiagnostics
+		}
+		reqHdr.AdditionalHeader = h.AdditionalHeader
+	}
+	if reqHdr.AuditEntryID == "" && c.sc.cfg.AuditEntryID != nil {
+		reqHdr.AuditEntryID = c.sc.cfg.AuditEntryID()
 	}
 
 	if timeout > 0 && timeout < c.sc.cfg.RequestTimeout {
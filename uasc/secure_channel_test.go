@@ -1,14 +1,18 @@
 package uasc
 
 import (
+	"context"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/binary"
 	"encoding/pem"
 	"fmt"
 	"math"
+	"net"
 	"testing"
 	"time"
 
+	"github.com/gopcua/opcua/debug"
 	"github.com/gopcua/opcua/id"
 	uatest "github.com/gopcua/opcua/tests/python"
 	"github.com/gopcua/opcua/ua"
@@ -105,6 +109,85 @@ func TestNewRequestMessage(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "audit-and-diagnostics",
+			sechan: buildSecureChannel(
+				&SecureChannel{
+					cfg: &Config{
+						AuditEntryID:      func() string { return "audit" },
+						ReturnDiagnostics: ua.ServiceLevelAll,
+					},
+					time: fixedTime,
+				},
+				&channelInstance{},
+			),
+			req: &ua.ReadRequest{},
+			m: &Message{
+				MessageHeader: &MessageHeader{
+					Header: &Header{
+						MessageType: MessageTypeMessage,
+						ChunkType:   ChunkTypeFinal,
+					},
+					SymmetricSecurityHeader: &SymmetricSecurityHeader{},
+					SequenceHeader: &SequenceHeader{
+						SequenceNumber: 1,
+						RequestID:      1,
+					},
+				},
+				TypeID: ua.NewFourByteExpandedNodeID(0, id.ReadRequest_Encoding_DefaultBinary),
+				Service: &ua.ReadRequest{
+					RequestHeader: &ua.RequestHeader{
+						AuthenticationToken: ua.NewTwoByteNodeID(0),
+						Timestamp:           fixedTime(),
+						RequestHandle:       1,
+						ReturnDiagnostics:   ua.ServiceLevelAll,
+						AuditEntryID:        "audit",
+					},
+				},
+			},
+		},
+		{
+			name: "request-header-overrides",
+			sechan: buildSecureChannel(
+				&SecureChannel{
+					cfg: &Config{
+						AuditEntryID:      func() string { return "audit" },
+						ReturnDiagnostics: ua.ServiceLevelAll,
+					},
+					time: fixedTime,
+				},
+				&channelInstance{},
+			),
+			req: &ua.ReadRequest{
+				RequestHeader: &ua.RequestHeader{
+					AuditEntryID:      "request",
+					ReturnDiagnostics: ua.ReturnDiagnosticsAll,
+				},
+			},
+			m: &Message{
+				MessageHeader: &MessageHeader{
+					Header: &Header{
+						MessageType: MessageTypeMessage,
+						ChunkType:   ChunkTypeFinal,
+					},
+					SymmetricSecurityHeader: &SymmetricSecurityHeader{},
+					SequenceHeader: &SequenceHeader{
+						SequenceNumber: 1,
+						RequestID:      1,
+					},
+				},
+				TypeID: ua.NewFourByteExpandedNodeID(0, id.ReadRequest_Encoding_DefaultBinary),
+				Service: &ua.ReadRequest{
+					RequestHeader: &ua.RequestHeader{
+						AuthenticationToken: ua.NewTwoByteNodeID(0),
+						Timestamp:           fixedTime(),
+						RequestHandle:       1,
+						ReturnDiagnostics:   ua.ReturnDiagnosticsAll,
+						AuditEntryID:        "request",
+					},
+				},
+			},
+		},
 		{
 			name: "counter-rollover",
 			sechan: buildSecureChannel(
@@ -332,3 +415,242 @@ func TestNewSecureChannel(t *testing.T) {
 		require.ErrorContains(t, err, "invalid channel config: Security policy 'http://opcfoundation.org/UA/SecurityPolicy#Basic256' requires a private key")
 	})
 }
+
+func TestVerifyRemoteCertificate(t *testing.T) {
+	der := func(host string) []byte {
+		t.Helper()
+		certPEM, _, err := uatest.GenerateCert(host, 2048, 24*time.Hour)
+		require.NoError(t, err)
+		block, _ := pem.Decode(certPEM)
+		return block.Bytes
+	}
+	leaf, ca := der("leaf"), der("ca")
+	presented := append(append([]byte{}, leaf...), ca...)
+
+	t.Run("no callback", func(t *testing.T) {
+		s := &SecureChannel{cfg: &Config{}}
+		cert, err := s.verifyRemoteCertificate(presented)
+		require.NoError(t, err)
+		require.Equal(t, leaf, cert.Raw)
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		var gotCert *x509.Certificate
+		var gotChain [][]*x509.Certificate
+		s := &SecureChannel{cfg: &Config{
+			VerifyCertificate: func(cert *x509.Certificate, chain [][]*x509.Certificate) error {
+				gotCert, gotChain = cert, chain
+				return nil
+			},
+		}}
+		cert, err := s.verifyRemoteCertificate(presented)
+		require.NoError(t, err)
+		require.Equal(t, leaf, cert.Raw)
+		require.Equal(t, leaf, gotCert.Raw)
+		require.Len(t, gotChain, 1)
+		require.Len(t, gotChain[0], 2)
+		require.Equal(t, leaf, gotChain[0][0].Raw)
+		require.Equal(t, ca, gotChain[0][1].Raw)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		s := &SecureChannel{cfg: &Config{
+			VerifyCertificate: func(*x509.Certificate, [][]*x509.Certificate) error {
+				return ua.StatusBadCertificateUntrusted
+			},
+		}}
+		_, err := s.verifyRemoteCertificate(leaf)
+		require.ErrorIs(t, err, ua.StatusBadCertificateUntrusted)
+	})
+}
+
+func TestEncryptUserTokenData(t *testing.T) {
+	certPEM, keyPEM, err := uatest.GenerateCert("localhost", 2048, 24*time.Hour)
+	require.NoError(t, err)
+	certblock, _ := pem.Decode(certPEM)
+	keyblock, _ := pem.Decode(keyPEM)
+	serverKey, err := x509.ParsePKCS1PrivateKey(keyblock.Bytes)
+	require.NoError(t, err)
+
+	data, nonce := []byte("header.payload.signature"), []byte{1, 2, 3, 4}
+
+	t.Run("none", func(t *testing.T) {
+		s := &SecureChannel{cfg: &Config{SecurityPolicyURI: ua.SecurityPolicyURINone}}
+		got, alg, err := s.EncryptUserTokenData("", data, certblock.Bytes, nonce)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+		require.Equal(t, "", alg)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		s := &SecureChannel{cfg: &Config{SecurityPolicyURI: ua.SecurityPolicyURINone}}
+		got, alg, err := s.EncryptUserTokenData(ua.SecurityPolicyURIBasic256Sha256, data, certblock.Bytes, nonce)
+		require.NoError(t, err)
+		require.NotEmpty(t, alg)
+
+		dec, err := uapolicy.Asymmetric(ua.SecurityPolicyURIBasic256Sha256, serverKey, nil)
+		require.NoError(t, err)
+		plain, err := dec.Decrypt(got)
+		require.NoError(t, err)
+		require.Equal(t, uint32(len(data)+len(nonce)), binary.LittleEndian.Uint32(plain))
+		require.Equal(t, append(append([]byte{}, data...), nonce...), plain[4:])
+	})
+}
+
+func TestCheckReceiveLimits(t *testing.T) {
+	nc, _ := net.Pipe()
+	conn, err := uacp.NewConnFromNetConn(nc, &uacp.Acknowledge{MaxChunkCount: 2, MaxMessageSize: 8})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	chunk := func(n int) *MessageChunk {
+		return &MessageChunk{Data: make([]byte, n)}
+	}
+
+	tests := []struct {
+		name   string
+		kind   channelKind
+		chunks []*MessageChunk
+		err    error
+	}{
+		{"within limits", client, []*MessageChunk{chunk(4), chunk(4)}, nil},
+		{"too many chunks", client, []*MessageChunk{chunk(1), chunk(1), chunk(1)}, ua.StatusBadResponseTooLarge},
+		{"message too large", client, []*MessageChunk{chunk(4), chunk(5)}, ua.StatusBadResponseTooLarge},
+		{"server", server, []*MessageChunk{chunk(9)}, ua.StatusBadRequestTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SecureChannel{c: conn, kind: tt.kind}
+			require.Equal(t, tt.err, s.checkReceiveLimits(1, tt.chunks))
+		})
+	}
+}
+
+func TestCheckSendLimits(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// the peer accepts two chunks and 8 bytes
+	nc, peer := net.Pipe()
+	go func() {
+		ack := &uacp.Acknowledge{ReceiveBufSize: 0xffff, SendBufSize: 0xffff, MaxChunkCount: 2, MaxMessageSize: 8}
+		pc, err := uacp.NewConnFromNetConn(peer, ack)
+		if err != nil {
+			return
+		}
+		if _, err := pc.Receive(); err != nil {
+			return
+		}
+		pc.Send("ACKF", ack)
+	}()
+	conn, err := uacp.NewConnFromNetConn(nc, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.Handshake(ctx, "opc.tcp://127.0.0.1:4840"))
+
+	chunk := func(n int) []byte {
+		return make([]byte, symmetricChunkHeaderSize+n)
+	}
+
+	tests := []struct {
+		name   string
+		chunks [][]byte
+		err    error
+	}{
+		{"within limits", [][]byte{chunk(4), chunk(4)}, nil},
+		{"too many chunks", [][]byte{chunk(1), chunk(1), chunk(1)}, ua.StatusBadRequestTooLarge},
+		{"message too large", [][]byte{chunk(4), chunk(5)}, ua.StatusBadRequestTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SecureChannel{c: conn, kind: client}
+			err := s.checkSendLimits(&ua.WriteRequest{}, tt.chunks)
+			if tt.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	t.Run("max send message size", func(t *testing.T) {
+		s := &SecureChannel{c: conn, kind: client}
+		require.Equal(t, uint32(8), s.MaxSendMessageSize())
+	})
+}
+
+func TestPendingRequests(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// the peer reads all requests but never responds
+	nc, peer := net.Pipe()
+	go func() {
+		pc, err := uacp.NewConnFromNetConn(peer, uacp.DefaultServerACK)
+		if err != nil {
+			return
+		}
+		if _, err := pc.Receive(); err != nil {
+			return
+		}
+		pc.Send("ACKF", uacp.DefaultServerACK)
+		for {
+			if _, err := pc.Receive(); err != nil {
+				return
+			}
+		}
+	}()
+	conn, err := uacp.NewConnFromNetConn(nc, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.Handshake(ctx, "opc.tcp://127.0.0.1:4840"))
+
+	s := &SecureChannel{
+		c:            conn,
+		kind:         client,
+		cfg:          &Config{SecurityMode: ua.MessageSecurityModeNone},
+		time:         time.Now,
+		logger:       debug.NewLogger(),
+		disconnected: make(chan struct{}),
+		handlers:     make(map[uint32]*pendingRequest),
+	}
+	instance := newChannelInstance(s)
+	instance.maxBodySize = 0xffff
+
+	ignore := func(ua.Response) error { return nil }
+
+	t.Run("timeout", func(t *testing.T) {
+		err := s.sendRequestWithTimeout(ctx, &ua.ReadRequest{}, 1, instance, nil, 10*time.Millisecond, ignore)
+		require.ErrorIs(t, err, ua.StatusBadTimeout)
+		require.Equal(t, 0, s.PendingRequests())
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		err := s.sendRequestWithTimeout(ctx, &ua.ReadRequest{}, 2, instance, nil, time.Hour, ignore)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, 0, s.PendingRequests())
+	})
+
+	t.Run("pending", func(t *testing.T) {
+		ch, err := s.sendAsyncWithTimeout(ctx, &ua.ReadRequest{}, 3, instance, nil, true, time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, 1, s.PendingRequests())
+
+		_, err = s.sendAsyncWithTimeout(ctx, &ua.ReadRequest{}, 3, instance, nil, true, time.Hour)
+		require.Error(t, err, "duplicate request id")
+
+		got, ok := s.popHandler(3)
+		require.True(t, ok)
+		require.Equal(t, (<-chan *MessageBody)(got), ch)
+		require.Equal(t, 0, s.PendingRequests())
+	})
+
+	t.Run("send failure", func(t *testing.T) {
+		peer.Close()
+		err := s.sendRequestWithTimeout(ctx, &ua.ReadRequest{}, 4, instance, nil, time.Hour, ignore)
+		require.Error(t, err)
+		require.Equal(t, 0, s.PendingRequests())
+	})
+}